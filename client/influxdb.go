@@ -48,6 +48,10 @@ type Query struct {
 	//
 	// Chunked must be set to true for this option to be used.
 	ChunkSize int
+
+	// Parameters maps the names of the bound parameters used in Command,
+	// such as $host, to their values.
+	Parameters map[string]interface{}
 }
 
 // ParseConnectionString will parse a string to create a valid connection URL
@@ -186,35 +190,7 @@ func (c *Client) Query(q Query) (*Response, error) {
 // QueryContext sends a command to the server and returns the Response
 // It uses a context that can be cancelled by the command line client
 func (c *Client) QueryContext(ctx context.Context, q Query) (*Response, error) {
-	u := c.url
-
-	u.Path = "query"
-	values := u.Query()
-	values.Set("q", q.Command)
-	values.Set("db", q.Database)
-	if q.Chunked {
-		values.Set("chunked", "true")
-		if q.ChunkSize > 0 {
-			values.Set("chunk_size", strconv.Itoa(q.ChunkSize))
-		}
-	}
-	if c.precision != "" {
-		values.Set("epoch", c.precision)
-	}
-	u.RawQuery = values.Encode()
-
-	req, err := http.NewRequest("POST", u.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("User-Agent", c.userAgent)
-	if c.username != "" {
-		req.SetBasicAuth(c.username, c.password)
-	}
-
-	req = req.WithContext(ctx)
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.query(ctx, q)
 	if err != nil {
 		return nil, err
 	}
@@ -259,6 +235,71 @@ func (c *Client) QueryContext(ctx context.Context, q Query) (*Response, error) {
 	return &response, nil
 }
 
+// QueryStream sends a command to the server with chunking enabled and returns
+// a ChunkedResponse that reads the results as they are received, so they are
+// never all held in memory. The caller must close the ChunkedResponse.
+func (c *Client) QueryStream(ctx context.Context, q Query) (*ChunkedResponse, error) {
+	q.Chunked = true
+	resp, err := c.query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
+		var response Response
+		dec := json.NewDecoder(resp.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&response); err == nil && response.Error() != nil {
+			return nil, response.Error()
+		}
+		return nil, fmt.Errorf("received status code %d from server", resp.StatusCode)
+	}
+	return NewChunkedResponse(resp.Body), nil
+}
+
+// query sends a command to the server and returns the HTTP response.
+// The caller must close the response body.
+func (c *Client) query(ctx context.Context, q Query) (*http.Response, error) {
+	u := c.url
+
+	u.Path = "query"
+	values := u.Query()
+	values.Set("q", q.Command)
+	values.Set("db", q.Database)
+	if q.Chunked {
+		values.Set("chunked", "true")
+		if q.ChunkSize > 0 {
+			values.Set("chunk_size", strconv.Itoa(q.ChunkSize))
+		}
+	}
+	if len(q.Parameters) > 0 {
+		params, err := json.Marshal(q.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		values.Set("params", string(params))
+	}
+	if c.precision != "" {
+		values.Set("epoch", c.precision)
+	}
+	u.RawQuery = values.Encode()
+
+	req, err := http.NewRequest("POST", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	req = req.WithContext(ctx)
+
+	return c.httpClient.Do(req)
+}
+
 // Write takes BatchPoints and allows for writing of multiple points with defaults
 // If successful, error is nil and Response is nil
 // If an error occurs, Response may contain additional information if populated.
@@ -543,11 +584,15 @@ type ChunkedResponse struct {
 	dec    *json.Decoder
 	duplex *duplexReader
 	buf    bytes.Buffer
+	closer io.Closer
 }
 
 // NewChunkedResponse reads a stream and produces responses from the stream.
 func NewChunkedResponse(r io.Reader) *ChunkedResponse {
 	resp := &ChunkedResponse{}
+	if c, ok := r.(io.Closer); ok {
+		resp.closer = c
+	}
 	resp.duplex = &duplexReader{r: r, w: &resp.buf}
 	resp.dec = json.NewDecoder(resp.duplex)
 	resp.dec.UseNumber()
@@ -571,6 +616,14 @@ func (r *ChunkedResponse) NextResponse() (*Response, error) {
 	return &response, nil
 }
 
+// Close closes the underlying stream if it is an io.Closer.
+func (r *ChunkedResponse) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
 // Point defines the fields that will be written to the database
 // Measurement, Time, and Fields are required
 // Precision can be specified if the time is in epoch format (integer).
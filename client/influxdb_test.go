@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/client"
+	"github.com/influxdata/influxdb/models"
 )
 
 func BenchmarkWrite(b *testing.B) {
@@ -194,6 +195,34 @@ func TestClient_ChunkedQuery(t *testing.T) {
 	}
 }
 
+func TestClient_Query_Parameters(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, exp := r.FormValue("params"), `{"host":"server01","limit":10}`; got != exp {
+			t.Errorf("unexpected params: expected %s, actual %s", exp, got)
+		}
+		var data client.Response
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(data)
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	config := client.Config{URL: *u}
+	c, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error.  expected %v, actual %v", nil, err)
+	}
+
+	query := client.Query{
+		Command:    "SELECT * FROM cpu WHERE host = $host LIMIT $limit",
+		Parameters: map[string]interface{}{"host": "server01", "limit": 10},
+	}
+	_, err = c.Query(query)
+	if err != nil {
+		t.Fatalf("unexpected error.  expected %v, actual %v", nil, err)
+	}
+}
+
 func TestClient_QueryContext(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var data client.Response
@@ -274,6 +303,70 @@ func TestClient_ChunkedQuery_WithContext(t *testing.T) {
 	}
 }
 
+func TestClient_QueryStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("chunked") != "true" {
+			t.Errorf("expected chunked query, got %q", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusOK)
+		enc := json.NewEncoder(w)
+		_ = enc.Encode(client.Response{Results: []client.Result{{Series: []models.Row{{Name: "cpu"}}}}})
+		_ = enc.Encode(client.Response{Results: []client.Result{{Series: []models.Row{{Name: "mem"}}}}})
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	config := client.Config{URL: *u}
+	c, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error.  expected %v, actual %v", nil, err)
+	}
+
+	cr, err := c.QueryStream(context.Background(), client.Query{})
+	if err != nil {
+		t.Fatalf("unexpected error.  expected %v, actual %v", nil, err)
+	}
+	defer cr.Close()
+
+	var names []string
+	for {
+		resp, err := cr.NextResponse()
+		if err != nil {
+			t.Fatalf("unexpected error.  expected %v, actual %v", nil, err)
+		} else if resp == nil {
+			break
+		}
+		for _, result := range resp.Results {
+			for _, row := range result.Series {
+				names = append(names, row.Name)
+			}
+		}
+	}
+	if got, exp := strings.Join(names, ","), "cpu,mem"; got != exp {
+		t.Errorf("unexpected series: expected %s, actual %s", exp, got)
+	}
+}
+
+func TestClient_QueryStream_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(&client.Response{Err: errors.New("error parsing query")})
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	config := client.Config{URL: *u}
+	c, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error.  expected %v, actual %v", nil, err)
+	}
+
+	_, err = c.QueryStream(context.Background(), client.Query{})
+	if err == nil || err.Error() != "error parsing query" {
+		t.Fatalf("unexpected error.  expected %v, actual %v", "error parsing query", err)
+	}
+}
+
 func TestClient_BasicAuth(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		u, p, ok := r.BasicAuth()
@@ -3,6 +3,7 @@ package client // import "github.com/influxdata/influxdb/client/v2"
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -44,8 +45,25 @@ type HTTPConfig struct {
 	// TLSConfig allows the user to set their own TLS config for the HTTP
 	// Client. If set, this option overrides InsecureSkipVerify.
 	TLSConfig *tls.Config
+
+	// WriteFormat is the format of the body of writes, defaults to
+	// LineProtocolFormat.
+	WriteFormat WriteFormat
 }
 
+// WriteFormat is the format of the body of writes.
+type WriteFormat int
+
+const (
+	// LineProtocolFormat writes points as line protocol.
+	LineProtocolFormat WriteFormat = iota
+
+	// ColumnarFormat writes points grouped by series, with the values of
+	// each field in a column. It is smaller and parsed faster than line
+	// protocol, but requires a server that supports it.
+	ColumnarFormat
+)
+
 // BatchPointsConfig is the config data needed to create an instance of the BatchPoints struct.
 type BatchPointsConfig struct {
 	// Precision is the write precision of the points, defaults to "ns".
@@ -78,6 +96,24 @@ type Client interface {
 	Close() error
 }
 
+// ContextClient is a Client whose writes and queries can be aborted with a
+// context. The clients returned by NewHTTPClient and NewUDPClient implement
+// ContextClient.
+type ContextClient interface {
+	Client
+
+	// WriteContext is like Write but the write is aborted when ctx is done.
+	WriteContext(ctx context.Context, bp BatchPoints) error
+
+	// QueryContext is like Query but the query is aborted when ctx is done.
+	QueryContext(ctx context.Context, q Query) (*Response, error)
+
+	// StreamQuery makes a chunked InfluxDB Query on the database and returns
+	// a Stream yielding the series as they are received. This will fail if
+	// using the UDP client.
+	StreamQuery(ctx context.Context, q Query) (*Stream, error)
+}
+
 // NewHTTPClient returns a new Client from the provided config.
 // Client is safe for concurrent use by multiple goroutines.
 func NewHTTPClient(conf HTTPConfig) (Client, error) {
@@ -107,6 +143,7 @@ func NewHTTPClient(conf HTTPConfig) (Client, error) {
 		username:  conf.Username,
 		password:  conf.Password,
 		useragent: conf.UserAgent,
+		format:    conf.WriteFormat,
 		httpClient: &http.Client{
 			Timeout:   conf.Timeout,
 			Transport: tr,
@@ -174,6 +211,7 @@ type client struct {
 	username   string
 	password   string
 	useragent  string
+	format     WriteFormat
 	httpClient *http.Client
 	transport  *http.Transport
 }
@@ -354,15 +392,29 @@ func NewPointFrom(pt models.Point) *Point {
 }
 
 func (c *client) Write(bp BatchPoints) error {
+	return c.WriteContext(context.Background(), bp)
+}
+
+func (c *client) WriteContext(ctx context.Context, bp BatchPoints) error {
 	var b bytes.Buffer
+	var contentType string
 
-	for _, p := range bp.Points() {
-		if _, err := b.WriteString(p.pt.PrecisionString(bp.Precision())); err != nil {
+	if c.format == ColumnarFormat {
+		data, err := encodeColumnar(bp.Points(), bp.Precision())
+		if err != nil {
 			return err
 		}
+		b.Write(data)
+		contentType = columnarContentType
+	} else {
+		for _, p := range bp.Points() {
+			if _, err := b.WriteString(p.pt.PrecisionString(bp.Precision())); err != nil {
+				return err
+			}
 
-		if err := b.WriteByte('\n'); err != nil {
-			return err
+			if err := b.WriteByte('\n'); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -372,7 +424,7 @@ func (c *client) Write(bp BatchPoints) error {
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "")
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("User-Agent", c.useragent)
 	if c.username != "" {
 		req.SetBasicAuth(c.username, c.password)
@@ -384,6 +436,7 @@ func (c *client) Write(bp BatchPoints) error {
 	params.Set("precision", bp.Precision())
 	params.Set("consistency", bp.WriteConsistency())
 	req.URL.RawQuery = params.Encode()
+	req = req.WithContext(ctx)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -397,13 +450,31 @@ func (c *client) Write(bp BatchPoints) error {
 	}
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		var err = fmt.Errorf(string(body))
-		return err
+		return &WriteError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	return nil
 }
 
+// WriteError is returned by Write when the server responds with a non-success
+// status code. A 4xx status means the server rejected some or all of the points
+// (for example a partial write or a field type conflict) and retrying the same
+// batch will not succeed.
+type WriteError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error returns the body of the server response.
+func (e *WriteError) Error() string {
+	return e.Body
+}
+
+// Temporary returns true if the write may succeed if it is retried.
+func (e *WriteError) Temporary() bool {
+	return e.StatusCode >= http.StatusInternalServerError
+}
+
 // Query defines a query to send to the server.
 type Query struct {
 	Command    string
@@ -472,6 +543,88 @@ type Result struct {
 
 // Query sends a command to the server and returns the Response.
 func (c *client) Query(q Query) (*Response, error) {
+	return c.QueryContext(context.Background(), q)
+}
+
+// QueryContext sends a command to the server and returns the Response.
+// The request is cancelled when ctx is done.
+func (c *client) QueryContext(ctx context.Context, q Query) (*Response, error) {
+	resp, err := c.query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response Response
+	if q.Chunked {
+		cr := NewChunkedResponse(resp.Body)
+		for {
+			r, err := cr.NextResponse()
+			if err != nil {
+				// If we got an error while decoding the response, send that back.
+				return nil, err
+			}
+
+			if r == nil {
+				break
+			}
+
+			response.Results = append(response.Results, r.Results...)
+			if r.Err != "" {
+				response.Err = r.Err
+				break
+			}
+		}
+	} else {
+		dec := json.NewDecoder(resp.Body)
+		dec.UseNumber()
+		decErr := dec.Decode(&response)
+
+		// ignore this error if we got an invalid status code
+		if decErr != nil && decErr.Error() == "EOF" && resp.StatusCode != http.StatusOK {
+			decErr = nil
+		}
+		// If we got a valid decode error, send that back
+		if decErr != nil {
+			return nil, fmt.Errorf("unable to decode json: received status code %d err: %s", resp.StatusCode, decErr)
+		}
+	}
+
+	// If we don't have an error in our json response, and didn't get statusOK
+	// then send back an error
+	if resp.StatusCode != http.StatusOK && response.Error() == nil {
+		return &response, fmt.Errorf("received status code %d from server", resp.StatusCode)
+	}
+	return &response, nil
+}
+
+// StreamQuery sends a command to the server with chunking enabled and returns
+// a Stream over the series in the response. The caller must close the Stream.
+// Cancelling ctx closes the underlying connection.
+func (c *client) StreamQuery(ctx context.Context, q Query) (*Stream, error) {
+	q.Chunked = true
+	resp, err := c.query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
+		var response Response
+		dec := json.NewDecoder(resp.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&response); err == nil && response.Error() != nil {
+			return nil, response.Error()
+		}
+		return nil, fmt.Errorf("received status code %d from server", resp.StatusCode)
+	}
+	return newStream(ctx, resp.Body), nil
+}
+
+// query sends a command to the server and returns the response once it has
+// been checked to come from InfluxDB. The caller must close the response body.
+func (c *client) query(ctx context.Context, q Query) (*http.Response, error) {
 	u := c.url
 	u.Path = "query"
 
@@ -508,17 +661,18 @@ func (c *client) Query(q Query) (*Response, error) {
 		params.Set("epoch", q.Precision)
 	}
 	req.URL.RawQuery = params.Encode()
+	req = req.WithContext(ctx)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	// If we lack a X-Influxdb-Version header, then we didn't get a response from influxdb
 	// but instead some other service. If the error code is also a 500+ code, then some
 	// downstream loadbalancer/proxy/etc had an issue and we should report that.
 	if resp.Header.Get("X-Influxdb-Version") == "" && resp.StatusCode >= http.StatusInternalServerError {
+		defer resp.Body.Close()
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil || len(body) == 0 {
 			return nil, fmt.Errorf("received status code %d from downstream server", resp.StatusCode)
@@ -532,6 +686,7 @@ func (c *client) Query(q Query) (*Response, error) {
 	if cType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); cType != "application/json" {
 		// Read up to 1kb of the body to help identify downstream errors and limit the impact of things
 		// like downstream serving a large file
+		defer resp.Body.Close()
 		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
 		if err != nil || len(body) == 0 {
 			return nil, fmt.Errorf("expected json response, got %q, with status: %v", cType, resp.StatusCode)
@@ -540,47 +695,7 @@ func (c *client) Query(q Query) (*Response, error) {
 		return nil, fmt.Errorf("expected json response, got %q, with status: %v and response body: %q", cType, resp.StatusCode, body)
 	}
 
-	var response Response
-	if q.Chunked {
-		cr := NewChunkedResponse(resp.Body)
-		for {
-			r, err := cr.NextResponse()
-			if err != nil {
-				// If we got an error while decoding the response, send that back.
-				return nil, err
-			}
-
-			if r == nil {
-				break
-			}
-
-			response.Results = append(response.Results, r.Results...)
-			if r.Err != "" {
-				response.Err = r.Err
-				break
-			}
-		}
-	} else {
-		dec := json.NewDecoder(resp.Body)
-		dec.UseNumber()
-		decErr := dec.Decode(&response)
-
-		// ignore this error if we got an invalid status code
-		if decErr != nil && decErr.Error() == "EOF" && resp.StatusCode != http.StatusOK {
-			decErr = nil
-		}
-		// If we got a valid decode error, send that back
-		if decErr != nil {
-			return nil, fmt.Errorf("unable to decode json: received status code %d err: %s", resp.StatusCode, decErr)
-		}
-	}
-
-	// If we don't have an error in our json response, and didn't get statusOK
-	// then send back an error
-	if resp.StatusCode != http.StatusOK && response.Error() == nil {
-		return &response, fmt.Errorf("received status code %d from server", resp.StatusCode)
-	}
-	return &response, nil
+	return resp, nil
 }
 
 // duplexReader reads responses and writes it to another writer while
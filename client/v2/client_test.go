@@ -11,6 +11,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/influxdata/influxdb/columnar"
 )
 
 func TestUDPClient_Query(t *testing.T) {
@@ -614,6 +616,80 @@ func TestClient_Write(t *testing.T) {
 	}
 }
 
+func TestClient_Write_Columnar(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if typ := r.Header.Get("Content-Type"); typ != columnar.ContentType {
+			t.Errorf("unexpected content type: %s", typ)
+		}
+
+		var req columnar.WriteRequest
+		if err := req.Unmarshal(in); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		// The fields missing from points are nulls.
+		exp := []*columnar.Column{
+			{Name: "v1", FloatValues: []float64{2, 3}},
+			{Name: "v2", IntegerValues: []int64{2}, Nulls: []bool{false, true}},
+		}
+		if len(req.Series) != 1 || !reflect.DeepEqual(req.Series[0].Columns, exp) {
+			t.Errorf("unexpected series: %v", req.Series)
+		}
+
+		points, err := req.Points(r.URL.Query().Get("precision"))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if len(points) != 2 {
+			t.Fatalf("unexpected number of points: %d", len(points))
+		}
+		if have, want := points[0].String(), `m0,host=server01 v1=2,v2=2i 0`; have != want {
+			t.Errorf("unexpected point: %s != %s", have, want)
+		} else if have, want := points[1].String(), `m0,host=server01 v1=3 1000000000`; have != want {
+			t.Errorf("unexpected point: %s != %s", have, want)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c, _ := NewHTTPClient(HTTPConfig{Addr: ts.URL, WriteFormat: ColumnarFormat})
+	defer c.Close()
+
+	bp, err := NewBatchPoints(BatchPointsConfig{Precision: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := map[string]string{"host": "server01"}
+	for i, fields := range []map[string]interface{}{
+		{"v1": float64(2), "v2": int64(2)},
+		{"v1": float64(3)},
+	} {
+		pt, err := NewPoint("m0", tags, fields, time.Unix(int64(i), 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bp.AddPoint(pt)
+	}
+	if err := c.Write(bp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fields of a series must keep their type.
+	bp, _ = NewBatchPoints(BatchPointsConfig{})
+	for _, v := range []interface{}{float64(1), int64(2)} {
+		pt, err := NewPoint("m0", nil, map[string]interface{}{"v": v}, time.Unix(0, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bp.AddPoint(pt)
+	}
+	if err := c.Write(bp); err == nil || err.Error() != `conflicting types for field "v" of series m0` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestClient_UserAgent(t *testing.T) {
 	receivedUserAgent := ""
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,325 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/escape"
+)
+
+// columnarContentType is the content type of writes in the columnar format.
+const columnarContentType = "application/x-protobuf"
+
+// wireBytes is the protocol buffer wire type of length-delimited fields.
+const wireBytes = 2
+
+// columnarSeries holds the points of a series in the columnar format, as the
+// Series message of columnar/columnar.proto.
+type columnarSeries struct {
+	measurement string
+	tags        models.Tags
+	timestamps  []int64
+	columns     []columnarColumn
+
+	types []models.FieldType
+	index map[string]int
+}
+
+// columnarColumn holds the values of a field, as the Column message of
+// columnar/columnar.proto.
+type columnarColumn struct {
+	name      string
+	floats    []float64
+	integers  []int64
+	unsigneds []uint64
+	strings   []string
+	booleans  []bool
+	nulls     []bool
+}
+
+// encodeColumnar returns the columnar encoding of points, a WriteRequest
+// message of columnar/columnar.proto, with timestamps in the precision.
+// Points without a timestamp get the current time.
+func encodeColumnar(points []*Point, precision string) ([]byte, error) {
+	mul := models.GetPrecisionMultiplier(precision)
+	now := time.Now()
+
+	var series []*columnarSeries
+	index := make(map[string]*columnarSeries)
+	for _, p := range points {
+		s := index[string(p.pt.Key())]
+		if s == nil {
+			s = &columnarSeries{
+				measurement: string(p.pt.Name()),
+				tags:        p.pt.Tags(),
+				index:       make(map[string]int),
+			}
+			index[string(p.pt.Key())] = s
+			series = append(series, s)
+		}
+
+		t := p.pt.Time()
+		if t.IsZero() {
+			t = now
+		}
+		if err := s.add(t.UnixNano()/mul, p.pt); err != nil {
+			return nil, err
+		}
+	}
+
+	var sz int
+	for _, s := range series {
+		for i := range s.columns {
+			s.columns[i].fill(len(s.timestamps))
+		}
+		sz += messageSize(s.size())
+	}
+
+	b := make([]byte, 0, sz)
+	for _, s := range series {
+		b = appendMessage(b, 1, s.size())
+		b = s.append(b)
+	}
+	return b, nil
+}
+
+// add adds a row of the fields of p at the timestamp ts.
+func (s *columnarSeries) add(ts int64, p models.Point) error {
+	row := len(s.timestamps)
+	s.timestamps = append(s.timestamps, ts)
+
+	iter := p.FieldIterator()
+	for iter.Next() {
+		name := string(escape.Unescape(iter.FieldKey()))
+		i, ok := s.index[name]
+		if !ok {
+			i = len(s.columns)
+			s.index[name] = i
+			s.columns = append(s.columns, columnarColumn{name: name})
+			s.types = append(s.types, iter.Type())
+		} else if s.types[i] != iter.Type() {
+			return fmt.Errorf("conflicting types for field %q of series %s", name, p.Key())
+		}
+
+		c := &s.columns[i]
+		c.fill(row)
+		if len(c.nulls) > 0 {
+			c.nulls = append(c.nulls, false)
+		}
+
+		switch iter.Type() {
+		case models.Float:
+			v, err := iter.FloatValue()
+			if err != nil {
+				return err
+			}
+			c.floats = append(c.floats, v)
+		case models.Integer:
+			v, err := iter.IntegerValue()
+			if err != nil {
+				return err
+			}
+			c.integers = append(c.integers, v)
+		case models.Unsigned:
+			v, err := iter.UnsignedValue()
+			if err != nil {
+				return err
+			}
+			c.unsigneds = append(c.unsigneds, v)
+		case models.String:
+			c.strings = append(c.strings, iter.StringValue())
+		case models.Boolean:
+			v, err := iter.BooleanValue()
+			if err != nil {
+				return err
+			}
+			c.booleans = append(c.booleans, v)
+		}
+	}
+	return nil
+}
+
+func (s *columnarSeries) size() int {
+	n := stringSize(s.measurement)
+	for _, t := range s.tags {
+		n += messageSize(stringSize(string(t.Key)) + stringSize(string(t.Value)))
+	}
+	n += packedSize(varintsSize(s.timestamps))
+	for i := range s.columns {
+		n += messageSize(s.columns[i].size())
+	}
+	return n
+}
+
+func (s *columnarSeries) append(b []byte) []byte {
+	b = appendString(b, 1, s.measurement)
+	for _, t := range s.tags {
+		b = appendMessage(b, 2, stringSize(string(t.Key))+stringSize(string(t.Value)))
+		b = appendString(b, 1, string(t.Key))
+		b = appendString(b, 2, string(t.Value))
+	}
+	if len(s.timestamps) > 0 {
+		b = appendMessage(b, 3, varintsSize(s.timestamps))
+		for _, v := range s.timestamps {
+			b = appendVarint(b, uint64(v))
+		}
+	}
+	for i := range s.columns {
+		b = appendMessage(b, 4, s.columns[i].size())
+		b = s.columns[i].append(b)
+	}
+	return b
+}
+
+// fill marks the rows of c up to n without a value as nulls.
+func (c *columnarColumn) fill(n int) {
+	rows := len(c.nulls)
+	if rows == 0 {
+		rows = len(c.floats) + len(c.integers) + len(c.unsigneds) + len(c.strings) + len(c.booleans)
+	}
+	if rows == n {
+		return
+	}
+
+	if len(c.nulls) == 0 {
+		c.nulls = make([]bool, rows, n)
+	}
+	for ; rows < n; rows++ {
+		c.nulls = append(c.nulls, true)
+	}
+}
+
+func (c *columnarColumn) size() int {
+	n := stringSize(c.name)
+	n += packedSize(8 * len(c.floats))
+	n += packedSize(varintsSize(c.integers))
+	var sz int
+	for _, v := range c.unsigneds {
+		sz += varintSize(v)
+	}
+	n += packedSize(sz)
+	for _, v := range c.strings {
+		n += messageSize(len(v))
+	}
+	n += packedSize(len(c.booleans))
+	n += packedSize(len(c.nulls))
+	return n
+}
+
+func (c *columnarColumn) append(b []byte) []byte {
+	b = appendString(b, 1, c.name)
+	if len(c.floats) > 0 {
+		b = appendMessage(b, 2, 8*len(c.floats))
+		for _, v := range c.floats {
+			b = appendFixed64(b, math.Float64bits(v))
+		}
+	}
+	if len(c.integers) > 0 {
+		b = appendMessage(b, 3, varintsSize(c.integers))
+		for _, v := range c.integers {
+			b = appendVarint(b, uint64(v))
+		}
+	}
+	if len(c.unsigneds) > 0 {
+		var sz int
+		for _, v := range c.unsigneds {
+			sz += varintSize(v)
+		}
+		b = appendMessage(b, 4, sz)
+		for _, v := range c.unsigneds {
+			b = appendVarint(b, v)
+		}
+	}
+	for _, v := range c.strings {
+		b = appendMessage(b, 5, len(v))
+		b = append(b, v...)
+	}
+	b = appendBools(b, 6, c.booleans)
+	return appendBools(b, 7, c.nulls)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendFixed64(b []byte, v uint64) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24), byte(v>>32), byte(v>>40), byte(v>>48), byte(v>>56))
+}
+
+// appendMessage appends the key and length of a length-delimited field to b.
+// The caller appends the sz bytes of its value.
+func appendMessage(b []byte, num, sz int) []byte {
+	b = appendVarint(b, uint64(num)<<3|wireBytes)
+	return appendVarint(b, uint64(sz))
+}
+
+// appendString appends a string field to b, unless s is empty.
+func appendString(b []byte, num int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = appendMessage(b, num, len(s))
+	return append(b, s...)
+}
+
+// appendBools appends a packed repeated bool field to b, unless a is empty.
+func appendBools(b []byte, num int, a []bool) []byte {
+	if len(a) == 0 {
+		return b
+	}
+	b = appendMessage(b, num, len(a))
+	for _, v := range a {
+		if v {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+	}
+	return b
+}
+
+func varintSize(v uint64) int {
+	n := 1
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+	return n
+}
+
+func varintsSize(a []int64) int {
+	var n int
+	for _, v := range a {
+		n += varintSize(uint64(v))
+	}
+	return n
+}
+
+// messageSize returns the size of a length-delimited field with a value of
+// sz bytes. Field numbers are below 16, so their key takes a byte.
+func messageSize(sz int) int {
+	return 1 + varintSize(uint64(sz)) + sz
+}
+
+// stringSize returns the size of a string field, which is omitted if empty.
+func stringSize(s string) int {
+	if s == "" {
+		return 0
+	}
+	return messageSize(len(s))
+}
+
+// packedSize returns the size of a packed repeated field with a value of sz
+// bytes, which is omitted if empty.
+func packedSize(sz int) int {
+	if sz == 0 {
+		return 0
+	}
+	return messageSize(sz)
+}
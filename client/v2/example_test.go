@@ -1,6 +1,7 @@
 package client_test
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"os"
@@ -263,3 +264,33 @@ func ExampleClient_createDatabase() {
 		fmt.Println(response.Results)
 	}
 }
+
+// Stream the rows of a large query without buffering the whole response
+func ExampleClient_streamQuery() {
+	// Make client
+	c, err := client.NewHTTPClient(client.HTTPConfig{
+		Addr: "http://localhost:8086",
+	})
+	if err != nil {
+		fmt.Println("Error creating InfluxDB Client: ", err.Error())
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	q := client.NewQuery("SELECT count(value) FROM shapes GROUP BY time(1m)", "square_holes", "ns")
+	s, err := c.(client.ContextClient).StreamQuery(ctx, q)
+	if err != nil {
+		fmt.Println("Error: ", err.Error())
+		return
+	}
+	defer s.Close()
+
+	for s.Next() {
+		fmt.Println(s.Series().Name, s.Values())
+	}
+	if err := s.Err(); err != nil {
+		fmt.Println("Error: ", err.Error())
+	}
+}
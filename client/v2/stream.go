@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// Stream iterates over the series of a chunked query response as the chunks
+// are received, so large results are never held in memory all at once.
+//
+// A series spanning several chunks is returned once per chunk, with Partial
+// set on every part except the last. Stream is not safe for concurrent use.
+type Stream struct {
+	ctx  context.Context
+	body io.ReadCloser
+	cr   *ChunkedResponse
+
+	results []Result
+	series  []models.Row
+	cur     *models.Row
+	row     int
+
+	err       error
+	done      bool
+	closeOnce sync.Once
+	closing   chan struct{}
+}
+
+// newStream returns a Stream reading chunked responses from body. The body is
+// closed when ctx is done.
+func newStream(ctx context.Context, body io.ReadCloser) *Stream {
+	s := &Stream{
+		ctx:     ctx,
+		body:    body,
+		cr:      NewChunkedResponse(body),
+		closing: make(chan struct{}),
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			body.Close()
+		case <-s.closing:
+		}
+	}()
+	return s
+}
+
+// NextSeries advances to the next series, skipping the remaining rows of the
+// current one. It returns false when the stream is exhausted or an error
+// occurred.
+func (s *Stream) NextSeries() bool {
+	for len(s.series) == 0 {
+		if s.err != nil || s.done {
+			return false
+		}
+
+		if len(s.results) > 0 {
+			r := s.results[0]
+			s.results = s.results[1:]
+			if r.Err != "" {
+				s.err = errors.New(r.Err)
+				return false
+			}
+			s.series = r.Series
+			continue
+		}
+
+		resp, err := s.cr.NextResponse()
+		if err != nil {
+			if ctxErr := s.ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+			s.err = err
+			return false
+		} else if resp == nil {
+			s.done = true
+			return false
+		} else if resp.Err != "" {
+			s.err = errors.New(resp.Err)
+			return false
+		}
+		s.results = resp.Results
+	}
+
+	s.cur, s.series = &s.series[0], s.series[1:]
+	s.row = -1
+	return true
+}
+
+// Next advances to the next row, moving on to the next series when the rows
+// of the current one are exhausted. It returns false when the stream is
+// exhausted or an error occurred.
+func (s *Stream) Next() bool {
+	for {
+		if s.cur != nil && s.row+1 < len(s.cur.Values) {
+			s.row++
+			return true
+		}
+		if !s.NextSeries() {
+			return false
+		}
+	}
+}
+
+// Series returns the current series. Its Values only hold the rows of the
+// chunk being iterated over.
+func (s *Stream) Series() *models.Row {
+	return s.cur
+}
+
+// Values returns the values of the current row, in the order of the
+// columns of the current series.
+func (s *Stream) Values() []interface{} {
+	if s.cur == nil || s.row < 0 || s.row >= len(s.cur.Values) {
+		return nil
+	}
+	return s.cur.Values[s.row]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (s *Stream) Err() error {
+	return s.err
+}
+
+// Close closes the underlying response body. It is safe to call Close
+// multiple times.
+func (s *Stream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.closing)
+		err = s.body.Close()
+	})
+	return err
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_StreamQuery(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("chunked") != "true" {
+			t.Errorf("expected chunked query")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Influxdb-Version", "1.3.1")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[[1,1],[2,2]],"partial":true}]}]}` + "\n"))
+		w.Write([]byte(`{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[[3,3]]},{"name":"mem","columns":["time","value"],"values":[[1,10]]}]}]}` + "\n"))
+	}))
+	defer ts.Close()
+
+	c, _ := newContextClient(HTTPConfig{Addr: ts.URL})
+	defer c.Close()
+
+	s, err := c.StreamQuery(context.Background(), Query{Command: "SELECT * FROM cpu, mem"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer s.Close()
+
+	var names []string
+	var values []string
+	for s.Next() {
+		names = append(names, s.Series().Name)
+		values = append(values, string(s.Values()[1].(json.Number)))
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got, exp := len(values), 4; got != exp {
+		t.Fatalf("unexpected number of rows: got %d, exp %d", got, exp)
+	}
+	for i, exp := range []string{"1", "2", "3", "10"} {
+		if values[i] != exp {
+			t.Errorf("unexpected value at row %d: got %s, exp %s", i, values[i], exp)
+		}
+	}
+	if names[2] != "cpu" || names[3] != "mem" {
+		t.Errorf("unexpected series names: %v", names)
+	}
+}
+
+func TestClient_StreamQuery_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Influxdb-Version", "1.3.1")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[[1,1]]}]}]}` + "\n"))
+		w.Write([]byte(`{"results":[{"error":"max-select-point limit exceeded"}]}` + "\n"))
+	}))
+	defer ts.Close()
+
+	c, _ := newContextClient(HTTPConfig{Addr: ts.URL})
+	defer c.Close()
+
+	s, err := c.StreamQuery(context.Background(), Query{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer s.Close()
+
+	if !s.NextSeries() {
+		t.Fatalf("expected a series, got error: %v", s.Err())
+	}
+	if s.NextSeries() {
+		t.Fatal("unexpected series")
+	}
+	if err := s.Err(); err == nil || err.Error() != "max-select-point limit exceeded" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_StreamQuery_Cancel(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Influxdb-Version", "1.3.1")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[[1,1]]}]}]}` + "\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	c, _ := newContextClient(HTTPConfig{Addr: ts.URL})
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err := c.StreamQuery(ctx, Query{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer s.Close()
+
+	if !s.Next() {
+		t.Fatalf("expected a row, got error: %v", s.Err())
+	}
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if s.Next() {
+		t.Fatal("unexpected row")
+	}
+	if err := s.Err(); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_QueryContext_Cancel(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	c, _ := newContextClient(HTTPConfig{Addr: ts.URL})
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.QueryContext(ctx, Query{}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	bp, _ := NewBatchPoints(BatchPointsConfig{})
+	if err := c.WriteContext(ctx, bp); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// newContextClient returns a new HTTP client as a ContextClient.
+func newContextClient(conf HTTPConfig) (ContextClient, error) {
+	c, err := NewHTTPClient(conf)
+	if err != nil {
+		return nil, err
+	}
+	return c.(ContextClient), nil
+}
+
+func TestNewUDPClient_ContextClient(t *testing.T) {
+	c, err := NewUDPClient(UDPConfig{Addr: "localhost:8089"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer c.Close()
+
+	if _, ok := c.(ContextClient); !ok {
+		t.Fatal("expected the UDP client to implement ContextClient")
+	}
+}
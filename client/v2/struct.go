@@ -0,0 +1,440 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// structFieldInfo describes how a struct field maps to a column.
+type structFieldInfo struct {
+	name        string
+	index       []int
+	tag         bool
+	measurement bool
+	omitempty   bool
+	unsigned    bool
+}
+
+var (
+	structCacheMu sync.RWMutex
+	structCache   = make(map[reflect.Type][]structFieldInfo)
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structFields returns the mapped fields of the struct type t.
+func structFields(t reflect.Type) []structFieldInfo {
+	structCacheMu.RLock()
+	fields, ok := structCache[t]
+	structCacheMu.RUnlock()
+	if ok {
+		return fields
+	}
+
+	fields = appendStructFields(nil, t, nil)
+
+	structCacheMu.Lock()
+	structCache[t] = fields
+	structCacheMu.Unlock()
+	return fields
+}
+
+func appendStructFields(fields []structFieldInfo, t reflect.Type, index []int) []structFieldInfo {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("influx")
+		if tag == "-" {
+			continue
+		}
+
+		idx := make([]int, len(index)+1)
+		copy(idx, index)
+		idx[len(index)] = i
+
+		// Flatten embedded structs without a tag of their own.
+		if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct && sf.Type != timeType {
+			fields = appendStructFields(fields, sf.Type, idx)
+			continue
+		} else if sf.PkgPath != "" {
+			continue
+		}
+
+		info := structFieldInfo{name: sf.Name, index: idx}
+		if tag != "" {
+			opts := strings.Split(tag, ",")
+			if opts[0] != "" {
+				info.name = opts[0]
+			}
+			for _, opt := range opts[1:] {
+				switch opt {
+				case "tag":
+					info.tag = true
+				case "measurement":
+					info.measurement = true
+				case "omitempty":
+					info.omitempty = true
+				case "unsigned":
+					info.unsigned = true
+				}
+			}
+		}
+		fields = append(fields, info)
+	}
+	return fields
+}
+
+// Decode appends one element per row of the result's series to the slice
+// pointed to by v. See DecodeRows for details.
+func (r *Result) Decode(v interface{}, precision string) error {
+	if r.Err != "" {
+		return errors.New(r.Err)
+	}
+	return DecodeRows(r.Series, v, precision)
+}
+
+// DecodeRows decodes the values of rows into v, which must be a pointer to
+// a slice of structs or of struct pointers, or a pointer to a struct. One
+// element is appended to a slice for each row; a struct receives the first
+// row only.
+//
+// Struct fields are mapped to columns, tags and fields using the "influx"
+// struct tag. The tag holds the column name followed by comma-separated
+// options:
+//
+//	type CPU struct {
+//		Time  time.Time `influx:"time"`
+//		Host  string    `influx:"host,tag"`
+//		Name  string    `influx:",measurement"`
+//		Idle  float64   `influx:"usage_idle"`
+//		Count *int64    `influx:"count,omitempty"`
+//		Debug string    `influx:"-"`
+//	}
+//
+// Exported fields without a tag use the field name as column name. The "tag"
+// option maps the field to a tag, the "measurement" option maps it to the
+// measurement name and "omitempty" skips zero values when building points.
+// Unsigned integer fields are written as integers; the "unsigned" option
+// writes them as unsigned integers instead, which the server only accepts
+// when uint support is enabled.
+// The field named "time" holds the timestamp and must be a time.Time.
+//
+// Columns are assigned to the fields with the same name and the tags of each
+// series to the fields with the "tag" option. Timestamps are parsed from
+// RFC3339 strings or, if the query was made with an epoch precision, from
+// integers in that precision.
+func DecodeRows(rows []models.Row, v interface{}, precision string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode: expected non-nil pointer, got %T", v)
+	}
+	rv = rv.Elem()
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		for i := range rows {
+			if len(rows[i].Values) > 0 {
+				return decodeRow(rv, &rows[i], 0, precision)
+			}
+		}
+		return nil
+	case reflect.Slice:
+		elemType := rv.Type().Elem()
+		isPtr := elemType.Kind() == reflect.Ptr
+		if isPtr {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			return fmt.Errorf("decode: unsupported slice element type %s", rv.Type().Elem())
+		}
+
+		for i := range rows {
+			for j := range rows[i].Values {
+				elem := reflect.New(elemType)
+				if err := decodeRow(elem.Elem(), &rows[i], j, precision); err != nil {
+					return err
+				}
+				if isPtr {
+					rv.Set(reflect.Append(rv, elem))
+				} else {
+					rv.Set(reflect.Append(rv, elem.Elem()))
+				}
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("decode: unsupported type %T", v)
+	}
+}
+
+// decodeRow assigns the i-th values of row to the struct sv.
+func decodeRow(sv reflect.Value, row *models.Row, i int, precision string) error {
+	values := row.Values[i]
+	for _, f := range structFields(sv.Type()) {
+		fv := sv.FieldByIndex(f.index)
+
+		var value interface{}
+		if f.measurement {
+			value = row.Name
+		} else if col := columnIndex(row.Columns, f.name); col >= 0 && col < len(values) {
+			value = values[col]
+		} else if tv, ok := row.Tags[f.name]; ok && f.tag {
+			value = tv
+		} else {
+			continue
+		}
+
+		if err := setValue(fv, value, precision); err != nil {
+			return fmt.Errorf("decode: field %s: %s", f.name, err)
+		}
+	}
+	return nil
+}
+
+// columnIndex returns the index of the column name, falling back to a
+// case-insensitive match. It returns -1 if there is no such column.
+func columnIndex(columns []string, name string) int {
+	for i, col := range columns {
+		if col == name {
+			return i
+		}
+	}
+	for i, col := range columns {
+		if strings.EqualFold(col, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// setValue assigns the decoded JSON value v to fv, converting it as needed.
+func setValue(fv reflect.Value, v interface{}, precision string) error {
+	if v == nil {
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setValue(ptr.Elem(), v, precision); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+
+	if fv.Type() == timeType {
+		t, err := parseTime(v, precision)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Interface:
+		fv.Set(reflect.ValueOf(v))
+	case reflect.String:
+		switch v := v.(type) {
+		case string:
+			fv.SetString(v)
+		case json.Number:
+			fv.SetString(v.String())
+		default:
+			fv.SetString(fmt.Sprint(v))
+		}
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("cannot assign %T to bool", v)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := toInt64(v)
+		if err != nil {
+			return err
+		} else if fv.OverflowInt(n) {
+			return fmt.Errorf("value %d overflows %s", n, fv.Type())
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := toUint64(v)
+		if err != nil {
+			return err
+		} else if fv.OverflowUint(n) {
+			return fmt.Errorf("value %d overflows %s", n, fv.Type())
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(v)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
+
+// parseTime parses an RFC3339 timestamp or an epoch in the given precision.
+func parseTime(v interface{}, precision string) (time.Time, error) {
+	switch v := v.(type) {
+	case string:
+		return time.Parse(time.RFC3339Nano, v)
+	case json.Number, float64, int64:
+		n, err := toInt64(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(0, n*models.GetPrecisionMultiplier(precision)).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot assign %T to time.Time", v)
+}
+
+func toInt64(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		f, err := v.Float64()
+		if err != nil || f != float64(int64(f)) {
+			return 0, fmt.Errorf("cannot assign %s to integer", v)
+		}
+		return int64(f), nil
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("cannot assign %v to integer", v)
+		}
+		return int64(v), nil
+	case int64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("cannot assign %T to integer", v)
+}
+
+func toUint64(v interface{}) (uint64, error) {
+	if n, ok := v.(json.Number); ok {
+		if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			return u, nil
+		}
+	}
+	n, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	} else if n < 0 {
+		return 0, fmt.Errorf("cannot assign %d to unsigned integer", n)
+	}
+	return uint64(n), nil
+}
+
+func toFloat64(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.Float64()
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	}
+	return 0, fmt.Errorf("cannot assign %T to float", v)
+}
+
+// NewPointFromStruct returns a point built from the tagged fields of v, a
+// struct or a pointer to a struct. The measurement is name, or the value of
+// the field with the "measurement" option if name is empty. Fields with the
+// "tag" option become tags, the "time" field becomes the timestamp and all
+// other fields become fields. Nil pointers and empty tags are omitted.
+func NewPointFromStruct(name string, v interface{}) (*Point, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, errors.New("point: nil struct pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("point: expected struct, got %T", v)
+	}
+
+	var t time.Time
+	tags := make(map[string]string)
+	fields := make(map[string]interface{})
+	for _, f := range structFields(rv.Type()) {
+		fv := rv.FieldByIndex(f.index)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		switch {
+		case f.measurement:
+			if name == "" {
+				name = fmt.Sprint(fv.Interface())
+			}
+		case strings.EqualFold(f.name, "time"):
+			if fv.Type() != timeType {
+				return nil, fmt.Errorf("point: time field must be a time.Time, got %s", fv.Type())
+			}
+			t = fv.Interface().(time.Time)
+		case f.tag:
+			if s := fmt.Sprint(fv.Interface()); s != "" {
+				tags[f.name] = s
+			}
+		default:
+			if f.omitempty && isZero(fv) {
+				continue
+			}
+			value, err := fieldValue(fv, f.unsigned)
+			if err != nil {
+				return nil, fmt.Errorf("point: field %s: %s", f.name, err)
+			}
+			fields[f.name] = value
+		}
+	}
+
+	if name == "" {
+		return nil, errors.New("point: missing measurement name")
+	}
+	return NewPoint(name, tags, fields, t)
+}
+
+// fieldValue converts fv to one of the field types supported by models.
+// Unsigned integers are converted to integers, unless unsigned is set.
+func fieldValue(fv reflect.Value, unsigned bool) (interface{}, error) {
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v := fv.Uint()
+		if unsigned {
+			return v, nil
+		} else if v > math.MaxInt64 {
+			return nil, fmt.Errorf("value %d overflows int64, use the unsigned option", v)
+		}
+		return int64(v), nil
+	case reflect.Float32, reflect.Float64:
+		return fv.Float(), nil
+	case reflect.Bool:
+		return fv.Bool(), nil
+	case reflect.String:
+		return fv.String(), nil
+	}
+	return nil, fmt.Errorf("unsupported field type %s", fv.Type())
+}
+
+func isZero(fv reflect.Value) bool {
+	return reflect.DeepEqual(fv.Interface(), reflect.Zero(fv.Type()).Interface())
+}
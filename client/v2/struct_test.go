@@ -0,0 +1,133 @@
+package client
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+type testCPU struct {
+	Time        time.Time `influx:"time"`
+	Measurement string    `influx:",measurement"`
+	Host        string    `influx:"host,tag"`
+	Idle        float64   `influx:"usage_idle"`
+	Count       *int64    `influx:"count,omitempty"`
+	Active      bool
+	Ignored     string `influx:"-"`
+}
+
+func TestDecodeRows(t *testing.T) {
+	var rows []models.Row
+	if err := json.Unmarshal([]byte(`[
+		{"name":"cpu","tags":{"host":"server01"},"columns":["time","usage_idle","count","active"],
+		 "values":[["2017-01-01T00:00:00Z",91.5,3,true],["2017-01-01T00:00:10.5Z",90,null,false]]},
+		{"name":"cpu","tags":{"host":"server02"},"columns":["time","usage_idle","count","active"],
+		 "values":[["2017-01-01T00:00:00Z",12,7,true]]}
+	]`), &rows); err != nil {
+		t.Fatal(err)
+	}
+
+	var cpus []testCPU
+	if err := DecodeRows(rows, &cpus, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(cpus) != 3 {
+		t.Fatalf("unexpected number of rows: %d", len(cpus))
+	}
+	if got := cpus[0]; got.Host != "server01" || got.Measurement != "cpu" || got.Idle != 91.5 || got.Count == nil || *got.Count != 3 || !got.Active {
+		t.Errorf("unexpected first row: %+v", got)
+	}
+	if got, exp := cpus[1].Time, time.Date(2017, 1, 1, 0, 0, 10, 5e8, time.UTC); !got.Equal(exp) {
+		t.Errorf("unexpected time: got %s, exp %s", got, exp)
+	}
+	if cpus[1].Count != nil {
+		t.Errorf("expected nil count, got %d", *cpus[1].Count)
+	}
+	if got := cpus[2]; got.Host != "server02" || got.Idle != 12 {
+		t.Errorf("unexpected last row: %+v", got)
+	}
+}
+
+func TestResult_Decode_Epoch(t *testing.T) {
+	r := Result{Series: []models.Row{{
+		Name:    "cpu",
+		Columns: []string{"time", "usage_idle", "count"},
+		Values:  [][]interface{}{{json.Number("1500000000000"), json.Number("1.5"), json.Number("42")}},
+	}}}
+
+	var cpu testCPU
+	if err := r.Decode(&cpu, "ms"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, exp := cpu.Time, time.Unix(1500000000, 0); !got.Equal(exp) {
+		t.Errorf("unexpected time: got %s, exp %s", got, exp)
+	}
+	if cpu.Idle != 1.5 || cpu.Count == nil || *cpu.Count != 42 {
+		t.Errorf("unexpected value: %+v", cpu)
+	}
+
+	var bad []struct {
+		Count int8 `influx:"count"`
+	}
+	r.Series[0].Values[0][2] = json.Number("1000")
+	if err := r.Decode(&bad, "ms"); err == nil {
+		t.Error("expected overflow error")
+	}
+}
+
+func TestNewPointFromStruct(t *testing.T) {
+	count := int64(3)
+	cpu := testCPU{
+		Time:        time.Unix(10, 0),
+		Measurement: "cpu",
+		Host:        "server01",
+		Idle:        91.5,
+		Count:       &count,
+		Ignored:     "x",
+	}
+
+	pt, err := NewPointFromStruct("", &cpu)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, exp := pt.String(), "cpu,host=server01 Active=false,count=3i,usage_idle=91.5 10000000000"; got != exp {
+		t.Errorf("unexpected point: got %s, exp %s", got, exp)
+	}
+
+	cpu.Count = nil
+	pt, err = NewPointFromStruct("usage", cpu)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, exp := pt.String(), "usage,host=server01 Active=false,usage_idle=91.5 10000000000"; got != exp {
+		t.Errorf("unexpected point: got %s, exp %s", got, exp)
+	}
+
+	if _, err := NewPointFromStruct("", struct{ Value float64 }{1}); err == nil {
+		t.Error("expected missing measurement error")
+	}
+}
+
+func TestNewPointFromStruct_Unsigned(t *testing.T) {
+	type disk struct {
+		Free  uint32 `influx:"free"`
+		Used  uint64 `influx:"used"`
+		Total uint64 `influx:"total,unsigned"`
+	}
+
+	pt, err := NewPointFromStruct("disk", disk{Free: 1, Used: 2, Total: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, exp := pt.String(), "disk free=1i,total=3u,used=2i"; got != exp {
+		t.Errorf("unexpected point: got %s, exp %s", got, exp)
+	}
+
+	if _, err := NewPointFromStruct("disk", disk{Used: math.MaxUint64}); err == nil || err.Error() != "point: field used: value 18446744073709551615 overflows int64, use the unsigned option" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
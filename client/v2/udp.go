@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net"
@@ -59,6 +60,13 @@ type udpclient struct {
 	payloadSize int
 }
 
+func (uc *udpclient) WriteContext(ctx context.Context, bp BatchPoints) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return uc.Write(bp)
+}
+
 func (uc *udpclient) Write(bp BatchPoints) error {
 	var b = make([]byte, 0, uc.payloadSize) // initial buffer size, it will grow as needed
 	var d, _ = time.ParseDuration("1" + bp.Precision())
@@ -107,6 +115,14 @@ func (uc *udpclient) Query(q Query) (*Response, error) {
 	return nil, fmt.Errorf("Querying via UDP is not supported")
 }
 
+func (uc *udpclient) QueryContext(ctx context.Context, q Query) (*Response, error) {
+	return uc.Query(q)
+}
+
+func (uc *udpclient) StreamQuery(ctx context.Context, q Query) (*Stream, error) {
+	return nil, fmt.Errorf("Querying via UDP is not supported")
+}
+
 func (uc *udpclient) Ping(timeout time.Duration) (time.Duration, string, error) {
 	return 0, "", nil
 }
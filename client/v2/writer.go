@@ -0,0 +1,531 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+const (
+	// DefaultBatchSize is the default number of points buffered before
+	// a BufferedWriter flushes.
+	DefaultBatchSize = 5000
+
+	// DefaultFlushInterval is the default interval at which a BufferedWriter
+	// flushes any buffered points.
+	DefaultFlushInterval = time.Second
+
+	// DefaultMaxRetries is the default number of times a BufferedWriter
+	// retries a batch that failed with a temporary error.
+	DefaultMaxRetries = 5
+
+	// DefaultRetryInterval is the default delay before the first retry.
+	DefaultRetryInterval = 500 * time.Millisecond
+
+	// DefaultMaxRetryInterval is the default upper bound of the delay between retries.
+	DefaultMaxRetryInterval = 30 * time.Second
+
+	// DefaultErrorBufferSize is the default capacity of the channel returned
+	// by BufferedWriter.Errors.
+	DefaultErrorBufferSize = 64
+
+	// DefaultMaxBufferSize is the default maximum number of points buffered
+	// by a BufferedWriter.
+	DefaultMaxBufferSize = 20 * DefaultBatchSize
+)
+
+// spoolFileExt is the extension of batches spooled to disk.
+const spoolFileExt = ".lp"
+
+var (
+	// ErrWriterClosed is returned when writing to a closed BufferedWriter.
+	ErrWriterClosed = errors.New("buffered writer closed")
+
+	// ErrBufferFull is returned when writing to a BufferedWriter whose buffer
+	// cannot hold the points.
+	ErrBufferFull = errors.New("buffered writer buffer full")
+)
+
+// BufferedWriterConfig is the config data needed to create a BufferedWriter.
+type BufferedWriterConfig struct {
+	// BatchPointsConfig is applied to every batch written by the writer.
+	BatchPointsConfig
+
+	// BatchSize is the maximum number of points sent in a single write,
+	// defaults to DefaultBatchSize. Reaching it triggers a flush.
+	BatchSize int
+
+	// FlushInterval is the maximum time points are buffered before being
+	// written, defaults to DefaultFlushInterval.
+	FlushInterval time.Duration
+
+	// MaxRetries is the number of times a batch is retried after a temporary
+	// error (a 5xx response, a timeout or an unreachable server), defaults to
+	// DefaultMaxRetries. A negative value disables retries.
+	MaxRetries int
+
+	// RetryInterval is the delay before the first retry, defaults to
+	// DefaultRetryInterval. The delay doubles after every attempt.
+	RetryInterval time.Duration
+
+	// MaxRetryInterval caps the delay between retries, defaults to
+	// DefaultMaxRetryInterval.
+	MaxRetryInterval time.Duration
+
+	// SpoolDir is an optional directory where batches are stored when they
+	// still fail with a temporary error after all retries. Spooled batches are
+	// written again, oldest first, once the server accepts writes.
+	SpoolDir string
+
+	// ErrorBufferSize is the capacity of the error channel, defaults to
+	// DefaultErrorBufferSize. Errors are dropped when the channel is full.
+	ErrorBufferSize int
+
+	// MaxBufferSize is the maximum number of points waiting to be flushed,
+	// defaults to DefaultMaxBufferSize. Writes that would exceed it return
+	// ErrBufferFull, or wait for a flush with BlockWhenFull.
+	MaxBufferSize int
+
+	// BlockWhenFull makes writes wait until the buffer has room for their
+	// points instead of returning ErrBufferFull. A write larger than
+	// MaxBufferSize waits until the buffer is empty.
+	BlockWhenFull bool
+}
+
+// BufferedWriterStats holds the counters of a BufferedWriter.
+type BufferedWriterStats struct {
+	PointsReceived int64
+	PointsWritten  int64
+	PointsDropped  int64
+	PointsSpooled  int64
+	PointsReplayed int64
+	BatchesWritten int64
+	WriteErrors    int64
+	Retries        int64
+
+	// Buffered is the number of points waiting to be flushed.
+	Buffered int64
+}
+
+// BufferedWriter accepts points from multiple goroutines, groups them into
+// batches and writes them with a Client in the background. Batches are
+// flushed when they reach the configured size or when the flush interval
+// elapses, whichever comes first.
+//
+// Batches failing with a temporary error are retried with an exponential
+// backoff. Batches rejected by the server with a 4xx status, such as a
+// partial write, are not retried. Failed batches are reported on the
+// channel returned by Errors.
+type BufferedWriter struct {
+	client Client
+	config BufferedWriterConfig
+
+	mu     sync.Mutex
+	points []*Point
+	closed bool
+	space  *sync.Cond // signalled when the buffer is flushed or closed
+
+	// flushMu serializes writes to the client so batches are sent in order.
+	flushMu      sync.Mutex
+	spoolPending bool
+	spoolSeq     uint64
+
+	flushC  chan struct{}
+	errC    chan error
+	closing chan struct{}
+	wg      sync.WaitGroup
+
+	stats BufferedWriterStats
+}
+
+// NewBufferedWriter returns a new BufferedWriter writing to c. The returned
+// writer must be closed to flush the remaining points and release resources.
+// Closing the writer does not close c.
+func NewBufferedWriter(c Client, conf BufferedWriterConfig) (*BufferedWriter, error) {
+	if conf.Precision == "" {
+		conf.Precision = "ns"
+	}
+	if _, err := time.ParseDuration("1" + conf.Precision); err != nil {
+		return nil, err
+	}
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = DefaultBatchSize
+	}
+	if conf.FlushInterval <= 0 {
+		conf.FlushInterval = DefaultFlushInterval
+	}
+	if conf.MaxRetries == 0 {
+		conf.MaxRetries = DefaultMaxRetries
+	} else if conf.MaxRetries < 0 {
+		conf.MaxRetries = 0
+	}
+	if conf.RetryInterval <= 0 {
+		conf.RetryInterval = DefaultRetryInterval
+	}
+	if conf.MaxRetryInterval <= 0 {
+		conf.MaxRetryInterval = DefaultMaxRetryInterval
+	}
+	if conf.ErrorBufferSize <= 0 {
+		conf.ErrorBufferSize = DefaultErrorBufferSize
+	}
+	if conf.MaxBufferSize <= 0 {
+		conf.MaxBufferSize = DefaultMaxBufferSize
+	}
+
+	w := &BufferedWriter{
+		client:  c,
+		config:  conf,
+		flushC:  make(chan struct{}, 1),
+		errC:    make(chan error, conf.ErrorBufferSize),
+		closing: make(chan struct{}),
+	}
+	w.space = sync.NewCond(&w.mu)
+
+	if conf.SpoolDir != "" {
+		if err := os.MkdirAll(conf.SpoolDir, 0777); err != nil {
+			return nil, err
+		}
+		files, err := w.spoolFiles()
+		if err != nil {
+			return nil, err
+		}
+		w.spoolPending = len(files) > 0
+	}
+
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// Write adds p to the buffer. It does not block on the network, unless
+// BlockWhenFull is set and the buffer is full.
+func (w *BufferedWriter) Write(p *Point) error {
+	return w.WritePoints(p)
+}
+
+// WritePoints adds ps to the buffer. It does not block on the network, unless
+// BlockWhenFull is set and the buffer is full. Otherwise ErrBufferFull is
+// returned, and none of ps are added, if the buffer cannot hold them.
+func (w *BufferedWriter) WritePoints(ps ...*Point) error {
+	w.mu.Lock()
+	for !w.closed && len(w.points)+len(ps) > w.config.MaxBufferSize {
+		// Flush to make room for the points.
+		select {
+		case w.flushC <- struct{}{}:
+		default:
+		}
+
+		if !w.config.BlockWhenFull {
+			w.mu.Unlock()
+			return ErrBufferFull
+		} else if len(w.points) == 0 {
+			break
+		}
+		w.space.Wait()
+	}
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	w.points = append(w.points, ps...)
+	n := len(w.points)
+	w.mu.Unlock()
+
+	atomic.AddInt64(&w.stats.PointsReceived, int64(len(ps)))
+	atomic.StoreInt64(&w.stats.Buffered, int64(n))
+
+	if n >= w.config.BatchSize {
+		select {
+		case w.flushC <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Flush writes all buffered points and blocks until they have been written,
+// spooled or dropped. It returns the last error encountered.
+func (w *BufferedWriter) Flush() error {
+	return w.flush()
+}
+
+// Errors returns a channel receiving the errors of batches that could not be
+// written. The channel is closed when the writer is closed.
+func (w *BufferedWriter) Errors() <-chan error {
+	return w.errC
+}
+
+// Stats returns a snapshot of the writer's counters.
+func (w *BufferedWriter) Stats() BufferedWriterStats {
+	return BufferedWriterStats{
+		PointsReceived: atomic.LoadInt64(&w.stats.PointsReceived),
+		PointsWritten:  atomic.LoadInt64(&w.stats.PointsWritten),
+		PointsDropped:  atomic.LoadInt64(&w.stats.PointsDropped),
+		PointsSpooled:  atomic.LoadInt64(&w.stats.PointsSpooled),
+		PointsReplayed: atomic.LoadInt64(&w.stats.PointsReplayed),
+		BatchesWritten: atomic.LoadInt64(&w.stats.BatchesWritten),
+		WriteErrors:    atomic.LoadInt64(&w.stats.WriteErrors),
+		Retries:        atomic.LoadInt64(&w.stats.Retries),
+		Buffered:       atomic.LoadInt64(&w.stats.Buffered),
+	}
+}
+
+// Close stops the background flushing, writes the remaining points and closes
+// the error channel. Pending retries are abandoned when the writer is closed.
+func (w *BufferedWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.space.Broadcast()
+	w.mu.Unlock()
+
+	close(w.closing)
+	w.wg.Wait()
+
+	err := w.flush()
+	close(w.errC)
+	return err
+}
+
+// run flushes the buffer periodically or when signalled by WritePoints.
+func (w *BufferedWriter) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.closing:
+			return
+		case <-ticker.C:
+		case <-w.flushC:
+		}
+		w.flush()
+	}
+}
+
+// flush writes the buffered points in batches of at most BatchSize points.
+func (w *BufferedWriter) flush() error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	points := w.points
+	w.points = nil
+	w.space.Broadcast()
+	w.mu.Unlock()
+	atomic.StoreInt64(&w.stats.Buffered, 0)
+
+	var lastErr error
+	for len(points) > 0 {
+		n := w.config.BatchSize
+		if n > len(points) {
+			n = len(points)
+		}
+		if err := w.writeBatch(points[:n]); err != nil {
+			lastErr = err
+		}
+		points = points[n:]
+	}
+
+	if w.spoolPending && lastErr == nil {
+		if err := w.replaySpool(); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// writeBatch writes points with retries, spooling or dropping them on failure.
+func (w *BufferedWriter) writeBatch(points []*Point) error {
+	bp, err := NewBatchPoints(w.config.BatchPointsConfig)
+	if err != nil {
+		return err
+	}
+	bp.AddPoints(points)
+
+	err = w.writeWithRetry(bp)
+	if err == nil {
+		atomic.AddInt64(&w.stats.PointsWritten, int64(len(points)))
+		atomic.AddInt64(&w.stats.BatchesWritten, 1)
+		return nil
+	}
+	atomic.AddInt64(&w.stats.WriteErrors, 1)
+
+	if w.config.SpoolDir != "" && isTemporary(err) {
+		if serr := w.spool(points); serr != nil {
+			err = fmt.Errorf("%s (spooling failed: %s)", err, serr)
+		} else {
+			atomic.AddInt64(&w.stats.PointsSpooled, int64(len(points)))
+			w.sendError(err)
+			return err
+		}
+	}
+
+	atomic.AddInt64(&w.stats.PointsDropped, int64(len(points)))
+	w.sendError(err)
+	return err
+}
+
+// writeWithRetry writes bp, retrying temporary errors with an exponential backoff.
+func (w *BufferedWriter) writeWithRetry(bp BatchPoints) error {
+	interval := w.config.RetryInterval
+	for i := 0; ; i++ {
+		err := w.client.Write(bp)
+		if err == nil || !isTemporary(err) || i >= w.config.MaxRetries {
+			return err
+		}
+		atomic.AddInt64(&w.stats.Retries, 1)
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-w.closing:
+			timer.Stop()
+			return err
+		}
+
+		if interval *= 2; interval > w.config.MaxRetryInterval {
+			interval = w.config.MaxRetryInterval
+		}
+	}
+}
+
+// sendError reports err without blocking if nobody is reading the channel.
+func (w *BufferedWriter) sendError(err error) {
+	select {
+	case w.errC <- err:
+	default:
+	}
+}
+
+// spool stores points as line protocol in a new file in the spool directory.
+// Points without a timestamp are given the current time so they keep it when
+// they are replayed.
+func (w *BufferedWriter) spool(points []*Point) error {
+	w.spoolSeq++
+	name := fmt.Sprintf("%020d-%06d", time.Now().UnixNano(), w.spoolSeq)
+	tmp := filepath.Join(w.config.SpoolDir, name+".tmp")
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	now := strconv.FormatInt(time.Now().UnixNano()/models.GetPrecisionMultiplier(w.config.Precision), 10)
+	bw := bufio.NewWriter(f)
+	for _, p := range points {
+		bw.WriteString(p.pt.PrecisionString(w.config.Precision))
+		if p.pt.Time().IsZero() {
+			bw.WriteByte(' ')
+			bw.WriteString(now)
+		}
+		bw.WriteByte('\n')
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	} else if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, filepath.Join(w.config.SpoolDir, name+spoolFileExt)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	w.spoolPending = true
+	return nil
+}
+
+// spoolFiles returns the paths of the spooled batches, oldest first.
+func (w *BufferedWriter) spoolFiles() ([]string, error) {
+	fis, err := ioutil.ReadDir(w.config.SpoolDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), spoolFileExt) {
+			continue
+		}
+		files = append(files, filepath.Join(w.config.SpoolDir, fi.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// replaySpool writes the spooled batches, oldest first. It stops at the first
+// temporary error so the remaining batches are kept for a later attempt.
+func (w *BufferedWriter) replaySpool() error {
+	files, err := w.spoolFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		pts, err := models.ParsePointsWithPrecision(buf, time.Now().UTC(), w.config.Precision)
+		if err == nil {
+			bp, _ := NewBatchPoints(w.config.BatchPointsConfig)
+			for _, pt := range pts {
+				bp.AddPoint(NewPointFrom(pt))
+			}
+			err = w.client.Write(bp)
+			if err != nil && isTemporary(err) {
+				return err
+			}
+		}
+
+		if err != nil {
+			atomic.AddInt64(&w.stats.WriteErrors, 1)
+			atomic.AddInt64(&w.stats.PointsDropped, int64(len(pts)))
+			w.sendError(fmt.Errorf("replay %s: %s", filepath.Base(path), err))
+		} else {
+			atomic.AddInt64(&w.stats.PointsReplayed, int64(len(pts)))
+			atomic.AddInt64(&w.stats.BatchesWritten, 1)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	w.spoolPending = false
+	return nil
+}
+
+// isTemporary returns true if a write failing with err may succeed on retry.
+// Network errors, including timeouts and refused connections, and 5xx
+// responses are temporary. Other errors, such as 4xx responses, are not.
+func isTemporary(err error) bool {
+	switch err := err.(type) {
+	case *WriteError:
+		return err.Temporary()
+	case net.Error:
+		return true
+	}
+	return false
+}
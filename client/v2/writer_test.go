@@ -0,0 +1,252 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBufferedWriter_BatchSize(t *testing.T) {
+	var mu sync.Mutex
+	var batches []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		batches = append(batches, bytes.Count(in, []byte("\n")))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c, _ := NewHTTPClient(HTTPConfig{Addr: ts.URL})
+	defer c.Close()
+
+	w, err := NewBufferedWriter(c, BufferedWriterConfig{BatchSize: 2, FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		pt, _ := NewPoint("cpu", nil, map[string]interface{}{"value": i}, time.Unix(int64(i), 0))
+		if err := w.Write(pt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var total int
+	for _, n := range batches {
+		if n > 2 {
+			t.Errorf("batch exceeds batch size: %d", n)
+		}
+		total += n
+	}
+	if total != 5 {
+		t.Errorf("unexpected number of points written: %d", total)
+	}
+
+	if stats := w.Stats(); stats.PointsReceived != 5 || stats.PointsWritten != 5 || stats.Buffered != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if err := w.Write(&Point{}); err != ErrWriterClosed {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBufferedWriter_MaxBufferSize(t *testing.T) {
+	var written int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in, _ := ioutil.ReadAll(r.Body)
+		atomic.AddInt32(&written, int32(bytes.Count(in, []byte("\n"))))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c, _ := NewHTTPClient(HTTPConfig{Addr: ts.URL})
+	defer c.Close()
+
+	pt, _ := NewPoint("cpu", nil, map[string]interface{}{"value": 1.0})
+
+	t.Run("Error", func(t *testing.T) {
+		w, _ := NewBufferedWriter(c, BufferedWriterConfig{BatchSize: 10, FlushInterval: time.Hour, MaxBufferSize: 2})
+		defer w.Close()
+
+		if err := w.WritePoints(pt, pt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if err := w.Write(pt); err != ErrBufferFull {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if err := w.Write(pt); err != nil {
+			t.Fatalf("unexpected error after flush: %s", err)
+		}
+		if stats := w.Stats(); stats.PointsReceived != 3 || stats.Buffered != 1 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		atomic.StoreInt32(&written, 0)
+		w, _ := NewBufferedWriter(c, BufferedWriterConfig{BatchSize: 10, FlushInterval: time.Hour, MaxBufferSize: 2, BlockWhenFull: true})
+
+		// Writes beyond the limit wait for the buffer to be flushed.
+		done := make(chan error)
+		go func() {
+			for i := 0; i < 5; i++ {
+				if err := w.Write(pt); err != nil {
+					done <- err
+					return
+				}
+			}
+			done <- nil
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for writes")
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if n := atomic.LoadInt32(&written); n != 5 {
+			t.Errorf("unexpected number of points written: %d", n)
+		}
+	})
+}
+
+func TestBufferedWriter_Retry(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":"unavailable"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c, _ := NewHTTPClient(HTTPConfig{Addr: ts.URL})
+	defer c.Close()
+
+	w, _ := NewBufferedWriter(c, BufferedWriterConfig{FlushInterval: time.Hour, RetryInterval: time.Millisecond})
+	defer w.Close()
+
+	pt, _ := NewPoint("cpu", nil, map[string]interface{}{"value": 1.0})
+	w.Write(pt)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if stats := w.Stats(); stats.Retries != 2 || stats.PointsWritten != 1 || stats.WriteErrors != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestBufferedWriter_PartialWrite(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"partial write: field type conflict"}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewHTTPClient(HTTPConfig{Addr: ts.URL})
+	defer c.Close()
+
+	w, _ := NewBufferedWriter(c, BufferedWriterConfig{FlushInterval: time.Hour, RetryInterval: time.Millisecond})
+
+	pt, _ := NewPoint("cpu", nil, map[string]interface{}{"value": 1.0})
+	w.Write(pt)
+	w.Close()
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected a single request, got %d", n)
+	}
+
+	err, ok := <-w.Errors()
+	if !ok {
+		t.Fatal("expected an error")
+	} else if e, ok := err.(*WriteError); !ok || e.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if stats := w.Stats(); stats.PointsDropped != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestBufferedWriter_Spool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxdb-client-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var down int32 = 1
+	var mu sync.Mutex
+	var written bytes.Buffer
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		written.ReadFrom(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c, _ := NewHTTPClient(HTTPConfig{Addr: ts.URL})
+	defer c.Close()
+
+	w, err := NewBufferedWriter(c, BufferedWriterConfig{
+		BatchPointsConfig: BatchPointsConfig{Precision: "s"},
+		FlushInterval:     time.Hour,
+		MaxRetries:        -1,
+		SpoolDir:          dir,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer w.Close()
+
+	pt, _ := NewPoint("cpu", nil, map[string]interface{}{"value": 1.0}, time.Unix(10, 0))
+	w.Write(pt)
+	if err := w.Flush(); err == nil {
+		t.Fatal("expected error")
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Fatalf("expected 1 spooled batch, got %d", len(files))
+	}
+
+	atomic.StoreInt32(&down, 0)
+	pt, _ = NewPoint("cpu", nil, map[string]interface{}{"value": 2.0}, time.Unix(20, 0))
+	w.Write(pt)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Fatalf("expected spool to be empty, got %d files", len(files))
+	}
+	if got, exp := written.String(), "cpu value=2 20\ncpu value=1 10\n"; got != exp {
+		t.Errorf("unexpected writes: got %q, exp %q", got, exp)
+	}
+	if stats := w.Stats(); stats.PointsSpooled != 1 || stats.PointsReplayed != 1 || stats.PointsDropped != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
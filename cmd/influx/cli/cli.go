@@ -20,6 +20,8 @@ import (
 	"strings"
 	"syscall"
 	"text/tabwriter"
+	"time"
+	"unicode"
 
 	"golang.org/x/crypto/ssh/terminal"
 
@@ -46,6 +48,8 @@ type CommandLine struct {
 	Pretty          bool   // controls pretty print for json
 	Format          string // controls the output format.  Valid values are json, csv, or column
 	Execute         string
+	ScriptFile      string // path of a script file to run
+	OnError         string // error handling mode of scripts: stop or continue
 	ShowVersion     bool
 	Import          bool
 	Chunked         bool
@@ -55,6 +59,10 @@ type CommandLine struct {
 	ForceTTY        bool // Force the CLI to act as if it were connected to a TTY
 	osSignals       chan os.Signal
 	historyFilePath string
+	completer       *completer
+
+	// Parameters are bound to the $name placeholders of queries.
+	Parameters map[string]interface{}
 
 	Client         *client.Client
 	ClientConfig   client.Config // Client config options.
@@ -63,12 +71,14 @@ type CommandLine struct {
 
 // New returns an instance of CommandLine with the specified client version.
 func New(version string) *CommandLine {
-	return &CommandLine{
+	c := &CommandLine{
 		ClientVersion: version,
 		Quit:          make(chan struct{}, 1),
 		osSignals:     make(chan os.Signal, 1),
 		Chunked:       true,
 	}
+	c.completer = newCompleter(c.completionQuery, func() string { return c.Database })
+	return c
 }
 
 // Run executes the CLI.
@@ -136,16 +146,14 @@ func (c *CommandLine) Run() error {
 	// Modify precision.
 	c.SetPrecision(c.ClientConfig.Precision)
 
+	if c.ScriptFile != "" {
+		return c.RunScriptFile(c.ScriptFile)
+	}
+
 	if c.Execute != "" {
 		// Make the non-interactive mode send everything through the CLI's parser
 		// the same way the interactive mode works
-		lines := strings.Split(c.Execute, "\n")
-		for _, line := range lines {
-			if err := c.ParseCommand(line); err != nil {
-				return err
-			}
-		}
-		return nil
+		return c.RunScript(strings.NewReader(c.Execute), "", false)
 	}
 
 	if c.Import {
@@ -160,6 +168,8 @@ func (c *CommandLine) Run() error {
 		config := c.ImporterConfig
 		config.Config = c.ClientConfig
 		config.URL = u
+		config.Database = c.Database
+		config.RetentionPolicy = c.RetentionPolicy
 
 		i := v8.NewImporter(config)
 		if err := i.Import(); err != nil {
@@ -186,6 +196,9 @@ func (c *CommandLine) Run() error {
 	defer c.Line.Close()
 
 	c.Line.SetMultiLineMode(true)
+	if c.completer != nil {
+		c.Line.SetWordCompleter(c.completer.Complete)
+	}
 
 	fmt.Printf("Connected to %s version %s\n", c.Client.Addr(), c.ServerVersion)
 
@@ -207,6 +220,10 @@ func (c *CommandLine) Run() error {
 
 // mainLoop runs the main prompt loop for the CLI.
 func (c *CommandLine) mainLoop() error {
+	// Statements are read until they are terminated by a semicolon when they
+	// span several lines.
+	var b statementBuffer
+	var lines []string
 	for {
 		select {
 		case <-c.osSignals:
@@ -216,18 +233,34 @@ func (c *CommandLine) mainLoop() error {
 			c.exit()
 			return nil
 		default:
-			l, e := c.Line.Prompt("> ")
+			prompt := "> "
+			if b.Pending() {
+				prompt = "... "
+			}
+			l, e := c.Line.Prompt(prompt)
 			if e == io.EOF {
 				// Instead of die, register that someone exited the program gracefully
 				l = "exit"
+				b.Flush()
+				lines = nil
 			} else if e != nil {
 				c.exit()
 				return e
 			}
-			if err := c.ParseCommand(l); err != ErrBlankCommand && !strings.HasPrefix(strings.TrimSpace(l), "auth") {
-				l = influxql.Sanitize(l)
-				c.Line.AppendHistory(l)
-				c.saveHistory()
+
+			lines = append(lines, l)
+			var executed bool
+			for _, stmt := range b.Add(l) {
+				if err := c.ParseCommand(stmt); err != ErrBlankCommand && !strings.HasPrefix(strings.TrimSpace(stmt), "auth") {
+					executed = true
+				}
+			}
+			if !b.Pending() {
+				if executed {
+					c.Line.AppendHistory(influxql.Sanitize(strings.Join(lines, " ")))
+					c.saveHistory()
+				}
+				lines = nil
 			}
 		}
 	}
@@ -280,9 +313,25 @@ func (c *CommandLine) ParseCommand(cmd string) error {
 		case "use":
 			c.use(cmd)
 		case "insert":
+			c.resetCompletions()
 			return c.Insert(cmd)
 		case "clear":
 			c.clear(cmd)
+		case "set":
+			if len(tokens) > 1 && tokens[1] == "password" {
+				return c.ExecuteQuery(cmd)
+			}
+			c.SetParameter(cmd)
+		case "unset":
+			c.UnsetParameter(cmd)
+		case "watch":
+			return c.watch(cmd)
+		case "export":
+			return c.export(cmd)
+		case "create", "drop", "delete":
+			// Schema changes make the cached completions stale.
+			c.resetCompletions()
+			return c.ExecuteQuery(cmd)
 		default:
 			return c.ExecuteQuery(cmd)
 		}
@@ -319,6 +368,7 @@ func (c *CommandLine) Connect(cmd string) error {
 		return fmt.Errorf("Could not create client %s", err)
 	}
 	c.Client = client
+	c.resetCompletions()
 
 	_, v, err := c.Client.Ping()
 	if err != nil {
@@ -337,6 +387,30 @@ func (c *CommandLine) Connect(cmd string) error {
 	return nil
 }
 
+// completionQuery runs a metadata query for tab completion and returns the
+// resulting series.
+func (c *CommandLine) completionQuery(command string) ([]models.Row, error) {
+	response, err := c.Client.Query(client.Query{Command: command})
+	if err != nil {
+		return nil, err
+	} else if err := response.Error(); err != nil {
+		return nil, err
+	}
+
+	var rows []models.Row
+	for _, result := range response.Results {
+		rows = append(rows, result.Series...)
+	}
+	return rows, nil
+}
+
+// resetCompletions clears the metadata cached for tab completion.
+func (c *CommandLine) resetCompletions() {
+	if c.completer != nil {
+		c.completer.reset()
+	}
+}
+
 // SetAuth sets client authentication credentials.
 func (c *CommandLine) SetAuth(cmd string) {
 	// If they pass in the entire command, we should parse it
@@ -398,6 +472,94 @@ func (c *CommandLine) clear(cmd string) {
 	}
 }
 
+// parseParameter parses "set <name> = <value>". Quoted values are strings,
+// and unquoted values are parsed as booleans, integers or floats before
+// falling back to strings.
+func parseParameter(cmd string) (string, interface{}, error) {
+	_, args := nextArg(cmd)
+	args = strings.TrimSuffix(args, ";")
+	i := strings.Index(args, "=")
+	if i < 0 {
+		return "", nil, errors.New("usage: set <name> = <value>")
+	}
+
+	name := strings.TrimPrefix(strings.TrimSpace(args[:i]), "$")
+	if name == "" || strings.IndexFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) >= 0 {
+		return "", nil, fmt.Errorf("invalid parameter name %q", name)
+	}
+
+	value := strings.TrimSpace(args[i+1:])
+	if value == "" {
+		return "", nil, errors.New("usage: set <name> = <value>")
+	}
+
+	if n := len(value); n >= 2 && (value[0] == '\'' || value[0] == '"') && value[n-1] == value[0] {
+		quote := string(value[0])
+		return name, strings.Replace(value[1:n-1], `\`+quote, quote, -1), nil
+	}
+	if b, err := strconv.ParseBool(value); err == nil && strings.ContainsAny(value[:1], "tTfF") {
+		return name, b, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return name, n, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return name, f, nil
+	}
+	return name, value, nil
+}
+
+// SetParameter sets a parameter that is bound to the $name placeholders of
+// the queries sent to the server. Without arguments, the parameters are listed.
+func (c *CommandLine) SetParameter(cmd string) {
+	if _, args := nextArg(cmd); strings.TrimSuffix(args, ";") == "" {
+		c.parameters()
+		return
+	}
+
+	name, value, err := parseParameter(cmd)
+	if err != nil {
+		fmt.Printf("ERR: %s\n", err)
+		return
+	}
+	if c.Parameters == nil {
+		c.Parameters = make(map[string]interface{})
+	}
+	c.Parameters[name] = value
+}
+
+// UnsetParameter removes a parameter set with SetParameter.
+func (c *CommandLine) UnsetParameter(cmd string) {
+	_, name := nextArg(cmd)
+	name = strings.TrimPrefix(strings.TrimSuffix(name, ";"), "$")
+	if _, ok := c.Parameters[name]; !ok {
+		fmt.Printf("ERR: unknown parameter %q\n", name)
+		return
+	}
+	delete(c.Parameters, name)
+}
+
+// parameters prints the parameters set with SetParameter.
+func (c *CommandLine) parameters() {
+	names := make([]string, 0, len(c.Parameters))
+	for name := range c.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(w, "Parameter\tValue")
+	fmt.Fprintln(w, "--------\t--------")
+	for _, name := range names {
+		fmt.Fprintf(w, "$%s\t%#v\n", name, c.Parameters[name])
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
+
 func (c *CommandLine) use(cmd string) {
 	args := strings.Split(strings.TrimSuffix(strings.TrimSpace(cmd), ";"), " ")
 	if len(args) != 2 {
@@ -701,63 +863,97 @@ func (c *CommandLine) Insert(stmt string) error {
 // query creates a query struct to be used with the client.
 func (c *CommandLine) query(query string) client.Query {
 	return client.Query{
-		Command:   query,
-		Database:  c.Database,
-		Chunked:   c.Chunked,
-		ChunkSize: c.ChunkSize,
+		Command:    query,
+		Database:   c.Database,
+		Chunked:    c.Chunked,
+		ChunkSize:  c.ChunkSize,
+		Parameters: c.Parameters,
 	}
 }
 
 // ExecuteQuery runs any query statement.
 func (c *CommandLine) ExecuteQuery(query string) error {
-	// If we have a retention policy, we need to rewrite the statement sources
-	if c.RetentionPolicy != "" {
-		pq, err := influxql.NewParser(strings.NewReader(query)).ParseQuery()
-		if err != nil {
-			fmt.Printf("ERR: %s\n", err)
-			return err
-		}
-		for _, stmt := range pq.Statements {
-			if selectStatement, ok := stmt.(*influxql.SelectStatement); ok {
-				influxql.WalkFunc(selectStatement.Sources, func(n influxql.Node) {
-					if t, ok := n.(*influxql.Measurement); ok {
-						if t.Database == "" && c.Database != "" {
-							t.Database = c.Database
-						}
-						if t.RetentionPolicy == "" && c.RetentionPolicy != "" {
-							t.RetentionPolicy = c.RetentionPolicy
-						}
+	query, err := c.rewriteQuery(query)
+	if err != nil {
+		fmt.Printf("ERR: %s\n", err)
+		return err
+	}
+
+	ctx, cancel := c.signalContext()
+	defer cancel()
+	return c.executeQuery(ctx, query)
+}
+
+// rewriteQuery sets the database and retention policy of the sources of the
+// select statements in query if a retention policy is in use.
+func (c *CommandLine) rewriteQuery(query string) (string, error) {
+	if c.RetentionPolicy == "" {
+		return query, nil
+	}
+
+	p := influxql.NewParser(strings.NewReader(query))
+	p.SetParams(c.Parameters)
+	pq, err := p.ParseQuery()
+	if err != nil {
+		return "", err
+	}
+	for _, stmt := range pq.Statements {
+		if selectStatement, ok := stmt.(*influxql.SelectStatement); ok {
+			influxql.WalkFunc(selectStatement.Sources, func(n influxql.Node) {
+				if t, ok := n.(*influxql.Measurement); ok {
+					if t.Database == "" && c.Database != "" {
+						t.Database = c.Database
 					}
-				})
-			}
+					if t.RetentionPolicy == "" && c.RetentionPolicy != "" {
+						t.RetentionPolicy = c.RetentionPolicy
+					}
+				}
+			})
 		}
-		query = pq.String()
 	}
+	return pq.String(), nil
+}
 
+// signalContext returns a context that is cancelled when the user interrupts
+// the running command. The returned function must be called once the command
+// has completed.
+func (c *CommandLine) signalContext() (context.Context, func()) {
 	ctx := context.Background()
-	if !c.IgnoreSignals {
-		done := make(chan struct{})
-		defer close(done)
-
-		var cancel func()
-		ctx, cancel = context.WithCancel(ctx)
-		go func() {
-			select {
-			case <-done:
-			case <-c.osSignals:
-				cancel()
-			}
-		}()
+	if c.IgnoreSignals {
+		return ctx, func() {}
+	}
+
+	done := make(chan struct{})
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-done:
+		case <-c.osSignals:
+			cancel()
+		}
+	}()
+	return ctx, func() {
+		close(done)
+		cancel()
+	}
+}
+
+// queryError returns the error to report for a query that failed with err.
+func queryError(ctx context.Context, err error) error {
+	if err.Error() == "" || ctx.Err() != nil {
+		err = ctx.Err()
+		if err == context.Canceled {
+			err = errors.New("aborted by user")
+		}
 	}
+	return err
+}
 
+// executeQuery runs query and prints the response in the current format.
+func (c *CommandLine) executeQuery(ctx context.Context, query string) error {
 	response, err := c.Client.QueryContext(ctx, c.query(query))
 	if err != nil {
-		if err.Error() == "" {
-			err = ctx.Err()
-			if err == context.Canceled {
-				err = errors.New("aborted by user")
-			}
-		}
+		err = queryError(ctx, err)
 		fmt.Printf("ERR: %s\n", err)
 		return err
 	}
@@ -773,6 +969,217 @@ func (c *CommandLine) ExecuteQuery(query string) error {
 	return nil
 }
 
+// nextArg splits the first whitespace-separated argument from s.
+func nextArg(s string) (arg, remainder string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i:])
+	}
+	return s, ""
+}
+
+// parseWatchCommand parses "watch <interval> <query>".
+func parseWatchCommand(cmd string) (time.Duration, string, error) {
+	_, args := nextArg(cmd)
+	arg, query := nextArg(args)
+	if arg == "" || query == "" {
+		return 0, "", errors.New("usage: watch <interval> <query>")
+	}
+
+	interval, err := time.ParseDuration(arg)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid interval %q: %s", arg, err)
+	} else if interval <= 0 {
+		return 0, "", fmt.Errorf("invalid interval %q: must be positive", arg)
+	}
+	return interval, query, nil
+}
+
+// watch runs a query repeatedly, redrawing its results every interval until
+// interrupted.
+func (c *CommandLine) watch(cmd string) error {
+	interval, query, err := parseWatchCommand(cmd)
+	if err != nil {
+		fmt.Printf("ERR: %s\n", err)
+		return nil
+	}
+
+	query, err = c.rewriteQuery(query)
+	if err != nil {
+		fmt.Printf("ERR: %s\n", err)
+		return err
+	}
+
+	ctx, cancel := c.signalContext()
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Clear the screen and move the cursor to the top left corner.
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("Every %s: %s\t%s\n\n", interval, query, time.Now().Format(time.RFC3339))
+
+		// Errors are printed and the query is retried on the next tick.
+		c.executeQuery(ctx, query)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.Quit:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseExportCommand parses "export <file> <query>".
+func parseExportCommand(cmd string) (string, string, error) {
+	_, args := nextArg(cmd)
+	path, query := nextArg(args)
+	if path == "" || query == "" {
+		return "", "", errors.New("usage: export <file> <query>")
+	}
+	return path, query, nil
+}
+
+// export streams the results of a query to a file. The format is chosen from
+// the file extension: .csv, .json or .lp for line protocol.
+func (c *CommandLine) export(cmd string) error {
+	path, query, err := parseExportCommand(cmd)
+	if err == nil {
+		query, err = c.rewriteQuery(query)
+	}
+	var format string
+	if err == nil {
+		format, err = exportFormat(path)
+	}
+	if err != nil {
+		fmt.Printf("ERR: %s\n", err)
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Printf("ERR: %s\n", err)
+		return err
+	}
+	defer f.Close()
+
+	ctx, cancel := c.signalContext()
+	defer cancel()
+
+	var schema func(string) (*measurementSchema, error)
+	if format == "line" {
+		// Line protocol requires nanosecond timestamps and typed fields.
+		c.Client.SetPrecision("ns")
+		defer c.Client.SetPrecision(c.ClientConfig.Precision)
+		schema = c.measurementSchema()
+	}
+
+	q := c.query(query)
+	q.Chunked = true
+	cr, err := c.Client.QueryStream(ctx, q)
+	if err != nil {
+		err = queryError(ctx, err)
+		fmt.Printf("ERR: %s\n", err)
+		return err
+	}
+	defer cr.Close()
+
+	w := newExporter(format, f, schema)
+	var rows int
+	for {
+		resp, err := cr.NextResponse()
+		if err != nil {
+			err = queryError(ctx, err)
+			fmt.Printf("ERR: %s\n", err)
+			return err
+		} else if resp == nil {
+			break
+		} else if err := resp.Error(); err != nil {
+			fmt.Printf("ERR: %s\n", err)
+			return err
+		}
+
+		n, err := w.WriteResponse(resp)
+		rows += n
+		if err != nil {
+			fmt.Printf("ERR: %s\n", err)
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		fmt.Printf("ERR: %s\n", err)
+		return err
+	}
+	fmt.Printf("Exported %d rows to %s\n", rows, path)
+	return nil
+}
+
+// measurementSchema returns a function looking up the tag keys and field types
+// of a measurement in the current database. Results are cached for the
+// lifetime of the function.
+func (c *CommandLine) measurementSchema() func(string) (*measurementSchema, error) {
+	cache := make(map[string]*measurementSchema)
+	return func(measurement string) (*measurementSchema, error) {
+		if schema, ok := cache[measurement]; ok {
+			return schema, nil
+		}
+
+		schema := &measurementSchema{tags: make(map[string]bool), fields: make(map[string]string)}
+		values, err := c.showKeys("SHOW TAG KEYS FROM %s", measurement)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			key, _ := v[0].(string)
+			schema.tags[key] = true
+		}
+
+		if values, err = c.showKeys("SHOW FIELD KEYS FROM %s", measurement); err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			if len(v) < 2 {
+				continue
+			}
+			key, _ := v[0].(string)
+			schema.fields[key], _ = v[1].(string)
+		}
+		cache[measurement] = schema
+		return schema, nil
+	}
+}
+
+// showKeys runs a SHOW statement about a measurement in the current database
+// and returns the values of its rows.
+func (c *CommandLine) showKeys(stmt, measurement string) ([][]interface{}, error) {
+	response, err := c.Client.Query(client.Query{
+		Command:  fmt.Sprintf(stmt, influxql.QuoteIdent(measurement)),
+		Database: c.Database,
+	})
+	if err != nil {
+		return nil, err
+	} else if err := response.Error(); err != nil {
+		return nil, err
+	}
+
+	var values [][]interface{}
+	for _, result := range response.Results {
+		for _, row := range result.Series {
+			for _, v := range row.Values {
+				if len(v) > 0 {
+					values = append(values, v)
+				}
+			}
+		}
+	}
+	return values, nil
+}
+
 // FormatResponse formats output to the previously chosen format.
 func (c *CommandLine) FormatResponse(response *client.Response, w io.Writer) {
 	switch c.Format {
@@ -1005,8 +1412,16 @@ func (c *CommandLine) help() {
         history               displays command history
         settings              outputs the current settings for the shell
         clear                 clears settings such as database or retention policy.  run 'clear' for help
+        set <name> = <value>  sets a parameter bound to $name in queries. run 'set' to list the parameters
+        unset <name>          removes a parameter
+        watch <interval> <query>
+                              runs a query every interval and redraws its results until ctrl+c is pressed
+        export <file> <query> streams the results of a query to a file as csv, json or line protocol,
+                              depending on the file extension (.csv, .json or .lp)
         exit/quit/ctrl+d      quits the influx shell
 
+        Statements may span several lines when they are terminated by a semicolon.
+
         show databases        show database names
         show series           show series information
         show measurements     show measurement information
@@ -5,12 +5,16 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/influxdata/influxdb/client"
@@ -115,6 +119,75 @@ func TestRunCLI_ExecuteInsert_WithSignals(t *testing.T) {
 	}
 }
 
+func TestRunCLI_ScriptFile(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Influxdb-Version", SERVER_VERSION)
+		if r.URL.Path != "/query" {
+			return
+		}
+
+		q := r.FormValue("q")
+		if strings.HasPrefix(q, "SELECT") {
+			mu.Lock()
+			queries = append(queries, q+" "+r.FormValue("params"))
+			mu.Unlock()
+		}
+		if strings.Contains(q, "bad") {
+			io.WriteString(w, `{"results":[{"error":"measurement not found"}]}`)
+			return
+		}
+		io.WriteString(w, `{"results":[{}]}`)
+	}))
+	defer ts.Close()
+
+	f, err := ioutil.TempFile("", "influx-script")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("-- cpu usage\nset host = 'a'\nSELECT *\nFROM cpu\nWHERE host = $host;\nSELECT * FROM bad;\nSELECT * FROM mem;\n")
+	f.Close()
+
+	for _, tt := range []struct {
+		onError string
+		err     string
+		queries []string
+	}{
+		{
+			onError: cli.OnErrorStop,
+			err:     f.Name() + ":6: measurement not found",
+			queries: []string{"SELECT *\nFROM cpu\nWHERE host = $host {\"host\":\"a\"}", "SELECT * FROM bad {\"host\":\"a\"}"},
+		},
+		{
+			onError: cli.OnErrorContinue,
+			err:     "1 statement failed",
+			queries: []string{"SELECT *\nFROM cpu\nWHERE host = $host {\"host\":\"a\"}", "SELECT * FROM bad {\"host\":\"a\"}", "SELECT * FROM mem {\"host\":\"a\"}"},
+		},
+	} {
+		queries = nil
+
+		u, _ := url.Parse(ts.URL)
+		h, p, _ := net.SplitHostPort(u.Host)
+		c := cli.New(CLIENT_VERSION)
+		c.Host = h
+		c.Port, _ = strconv.Atoi(p)
+		c.ScriptFile = f.Name()
+		c.OnError = tt.onError
+		c.IgnoreSignals = true
+		c.ForceTTY = true
+		if err := c.Run(); err == nil || err.Error() != tt.err {
+			t.Errorf("%s: unexpected error: got %v, exp %s", tt.onError, err, tt.err)
+		}
+		if !reflect.DeepEqual(queries, tt.queries) {
+			t.Errorf("%s: unexpected queries:\ngot %q\nexp %q", tt.onError, queries, tt.queries)
+		}
+	}
+}
+
 func TestSetAuth(t *testing.T) {
 	t.Parallel()
 	c := cli.New(CLIENT_VERSION)
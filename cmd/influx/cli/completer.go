@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+)
+
+// cliCommands are the commands handled by the shell itself.
+var cliCommands = []string{
+	"auth", "chunk", "chunked", "clear", "connect", "consistency", "exit", "export", "format",
+	"help", "history", "insert", "precision", "pretty", "quit", "settings", "unset", "use", "watch",
+}
+
+// completionKeywords are the InfluxQL keywords offered for completion.
+var completionKeywords = []string{
+	"ALL", "ALTER", "AND", "ANALYZE", "ANY", "AS", "ASC", "BEGIN", "BY", "CARDINALITY",
+	"CONTINUOUS", "CREATE", "DATABASE", "DATABASES", "DEFAULT", "DELETE", "DESC",
+	"DESTINATIONS", "DIAGNOSTICS", "DISTINCT", "DROP", "DURATION", "END", "EVERY",
+	"EXACT", "EXPLAIN", "FIELD", "FILL", "FOR", "FROM", "GRANT", "GRANTS", "GROUP",
+	"GROUPS", "IN", "INF", "INSERT", "INTO", "KEY", "KEYS", "KILL", "LIMIT",
+	"MEASUREMENT", "MEASUREMENTS", "NAME", "OFFSET", "ON", "OR", "ORDER", "PASSWORD",
+	"POLICIES", "POLICY", "PRIVILEGES", "QUERIES", "QUERY", "READ", "REPLICATION",
+	"RESAMPLE", "RETENTION", "REVOKE", "SELECT", "SERIES", "SET", "SHARD", "SHARDS",
+	"SHOW", "SLIMIT", "SOFFSET", "STATS", "SUBSCRIPTION", "SUBSCRIPTIONS", "TAG", "TO",
+	"USER", "USERS", "VALUES", "WHERE", "WITH", "WRITE",
+}
+
+// completer provides context-aware tab completion of keywords, databases,
+// measurements, tag keys and field keys. Server metadata is fetched lazily
+// with SHOW queries and cached per database.
+type completer struct {
+	// query runs a statement and returns the resulting series.
+	query func(command string) ([]models.Row, error)
+
+	// database returns the current database of the shell.
+	database func() string
+
+	mu        sync.Mutex
+	databases []string
+	dbs       map[string]*databaseCompletions
+}
+
+// databaseCompletions holds the cached metadata of a single database.
+type databaseCompletions struct {
+	measurements []string
+	tagKeys      map[string][]string // keyed by measurement
+	fieldKeys    map[string][]string // keyed by measurement
+}
+
+func newCompleter(query func(string) ([]models.Row, error), database func() string) *completer {
+	return &completer{
+		query:    query,
+		database: database,
+		dbs:      make(map[string]*databaseCompletions),
+	}
+}
+
+// reset clears the cached metadata so it is fetched again on the next completion.
+func (c *completer) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.databases = nil
+	c.dbs = make(map[string]*databaseCompletions)
+}
+
+// Complete implements liner.WordCompleter. pos is the cursor position in runes.
+func (c *completer) Complete(line string, pos int) (head string, completions []string, tail string) {
+	runes := []rune(line)
+	if pos > len(runes) {
+		pos = len(runes)
+	}
+	before, tail := string(runes[:pos]), string(runes[pos:])
+
+	start := wordStart(before)
+	head, word := before[:start], before[start:]
+
+	tokens := completionTokens(head)
+	trimmed := strings.TrimSpace(head)
+	var candidates []string
+	quote := true
+
+	prev := ""
+	if len(tokens) > 0 {
+		prev = strings.ToUpper(tokens[len(tokens)-1])
+	}
+
+	switch {
+	case len(tokens) == 0:
+		candidates = append(append([]string{}, cliCommands...), "SELECT", "SHOW", "CREATE", "DROP", "DELETE", "ALTER", "GRANT", "REVOKE", "KILL", "EXPLAIN", "SET")
+		quote = false
+	case prev == "USE" && len(tokens) == 1, prev == "ON", prev == "DATABASE" && strings.EqualFold(tokens[0], "drop"):
+		candidates = c.databaseNames()
+	case prev == "FROM", prev == "MEASUREMENT":
+		candidates = c.measurementNames(c.database())
+	case prev == "BY":
+		candidates = c.keys(c.database(), fromMeasurement(line), true, false)
+	case prev == "SELECT", prev == "WHERE", prev == "AND", prev == "OR", strings.HasSuffix(trimmed, ","), strings.HasSuffix(trimmed, "("):
+		// Identifiers are expected in field lists and conditions.
+		candidates = c.keys(c.database(), fromMeasurement(line), true, true)
+	default:
+		candidates = completionKeywords
+		quote = false
+	}
+
+	return head, matchCompletions(word, candidates, quote), tail
+}
+
+// matchCompletions returns the candidates starting with word, ignoring case.
+// Keywords are returned in the case of word and identifiers are quoted when
+// needed.
+func matchCompletions(word string, candidates []string, quote bool) []string {
+	prefix := strings.ToLower(strings.TrimPrefix(word, `"`))
+	lower := word != "" && strings.ToLower(word) == word
+
+	seen := make(map[string]bool)
+	var matches []string
+	for _, cand := range candidates {
+		if !strings.HasPrefix(strings.ToLower(cand), prefix) {
+			continue
+		}
+
+		switch {
+		case quote && (strings.HasPrefix(word, `"`) || influxql.IdentNeedsQuotes(cand)):
+			cand = `"` + strings.Replace(cand, `"`, `\"`, -1) + `"`
+		case !quote && lower:
+			cand = strings.ToLower(cand)
+		}
+
+		if !seen[cand] {
+			seen[cand] = true
+			matches = append(matches, cand)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// wordStart returns the byte offset where the word ending s begins. A word
+// is either an unquoted identifier or an unterminated double-quoted one.
+func wordStart(s string) int {
+	var quoted bool
+	var quoteStart int
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			if !quoted {
+				quoteStart = i
+			}
+			quoted = !quoted
+		}
+	}
+	if quoted {
+		return quoteStart
+	}
+
+	i := len(s)
+	for i > 0 {
+		r := rune(s[i-1])
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r >= 0x80) {
+			break
+		}
+		i--
+	}
+	return i
+}
+
+// completionTokens splits s into words, ignoring punctuation.
+func completionTokens(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",()=<>;", r)
+	})
+}
+
+// fromMeasurement returns the first measurement named in the FROM clause of
+// line, without its database and retention policy, or an empty string.
+func fromMeasurement(line string) string {
+	tokens := completionTokens(line)
+	for i := 0; i < len(tokens)-1; i++ {
+		if !strings.EqualFold(tokens[i], "from") {
+			continue
+		}
+
+		name := tokens[i+1]
+		if strings.HasSuffix(name, `"`) {
+			if j := strings.LastIndex(name[:len(name)-1], `"`); j >= 0 {
+				return name[j+1 : len(name)-1]
+			}
+		}
+		if j := strings.LastIndex(name, "."); j >= 0 {
+			name = name[j+1:]
+		}
+		return strings.Trim(name, `"`)
+	}
+	return ""
+}
+
+// databaseNames returns the names of the databases, fetching them if needed.
+func (c *completer) databaseNames() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.databases == nil {
+		rows, err := c.query("SHOW DATABASES")
+		if err != nil {
+			return nil
+		}
+		c.databases = firstColumn(rows)
+	}
+	return c.databases
+}
+
+// measurementNames returns the measurements of db, fetching them if needed.
+func (c *completer) measurementNames(db string) []string {
+	if db == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := c.db(db)
+	if d.measurements == nil {
+		rows, err := c.query(fmt.Sprintf("SHOW MEASUREMENTS ON %s", influxql.QuoteIdent(db)))
+		if err != nil {
+			return nil
+		}
+		d.measurements = firstColumn(rows)
+	}
+	return d.measurements
+}
+
+// keys returns the tag keys and/or field keys of measurement in db, or of all
+// measurements in db if measurement is empty, fetching them if needed.
+func (c *completer) keys(db, measurement string, tags, fields bool) []string {
+	if db == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := c.db(db)
+	if tags && d.tagKeys == nil {
+		rows, err := c.query(fmt.Sprintf("SHOW TAG KEYS ON %s", influxql.QuoteIdent(db)))
+		if err != nil {
+			return nil
+		}
+		d.tagKeys = columnByName(rows)
+	}
+	if fields && d.fieldKeys == nil {
+		rows, err := c.query(fmt.Sprintf("SHOW FIELD KEYS ON %s", influxql.QuoteIdent(db)))
+		if err != nil {
+			return nil
+		}
+		d.fieldKeys = columnByName(rows)
+	}
+
+	var keys []string
+	if tags {
+		keys = appendKeys(keys, d.tagKeys, measurement)
+	}
+	if fields {
+		keys = appendKeys(keys, d.fieldKeys, measurement)
+	}
+	return keys
+}
+
+// appendKeys appends the keys of measurement, or of all measurements if
+// measurement is empty, to a.
+func appendKeys(a []string, m map[string][]string, measurement string) []string {
+	if measurement != "" {
+		return append(a, m[measurement]...)
+	}
+	for _, keys := range m {
+		a = append(a, keys...)
+	}
+	return a
+}
+
+// db returns the cached metadata of db. The caller must hold the lock.
+func (c *completer) db(name string) *databaseCompletions {
+	d, ok := c.dbs[name]
+	if !ok {
+		d = &databaseCompletions{}
+		c.dbs[name] = d
+	}
+	return d
+}
+
+// firstColumn returns the values of the first column of rows as strings.
+func firstColumn(rows []models.Row) []string {
+	a := []string{}
+	for _, row := range rows {
+		for _, values := range row.Values {
+			if len(values) > 0 {
+				if s, ok := values[0].(string); ok {
+					a = append(a, s)
+				}
+			}
+		}
+	}
+	return a
+}
+
+// columnByName returns the values of the first column of rows keyed by the
+// name of the series they belong to.
+func columnByName(rows []models.Row) map[string][]string {
+	m := make(map[string][]string)
+	for _, row := range rows {
+		m[row.Name] = append(m[row.Name], firstColumn([]models.Row{row})...)
+	}
+	return m
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+func newTestCompleter(queries *[]string) *completer {
+	return newCompleter(func(command string) ([]models.Row, error) {
+		*queries = append(*queries, command)
+		switch {
+		case command == "SHOW DATABASES":
+			return []models.Row{{Name: "databases", Columns: []string{"name"}, Values: [][]interface{}{{"_internal"}, {"telegraf"}}}}, nil
+		case strings.HasPrefix(command, "SHOW MEASUREMENTS"):
+			return []models.Row{{Name: "measurements", Columns: []string{"name"}, Values: [][]interface{}{{"cpu"}, {"cpu load"}, {"mem"}}}}, nil
+		case strings.HasPrefix(command, "SHOW TAG KEYS"):
+			return []models.Row{
+				{Name: "cpu", Columns: []string{"tagKey"}, Values: [][]interface{}{{"host"}, {"region"}}},
+				{Name: "mem", Columns: []string{"tagKey"}, Values: [][]interface{}{{"hostname"}}},
+			}, nil
+		case strings.HasPrefix(command, "SHOW FIELD KEYS"):
+			return []models.Row{
+				{Name: "cpu", Columns: []string{"fieldKey", "fieldType"}, Values: [][]interface{}{{"usage_idle", "float"}}},
+				{Name: "mem", Columns: []string{"fieldKey", "fieldType"}, Values: [][]interface{}{{"used", "integer"}}},
+			}, nil
+		}
+		return nil, nil
+	}, func() string { return "telegraf" })
+}
+
+func TestCompleter_Complete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		line        string
+		head        string
+		completions []string
+	}{
+		{line: "sel", head: "", completions: []string{"select"}},
+		{line: "SH", head: "", completions: []string{"SHOW"}},
+		{line: "use t", head: "use ", completions: []string{"telegraf"}},
+		{line: "SHOW MEASUREMENTS ON _", head: "SHOW MEASUREMENTS ON ", completions: []string{"_internal"}},
+		{line: "SELECT * FROM c", head: "SELECT * FROM ", completions: []string{`"cpu load"`, "cpu"}},
+		{line: `SELECT * FROM "cpu l`, head: "SELECT * FROM ", completions: []string{`"cpu load"`}},
+		{line: "SELECT * FROM cpu WHERE ho", head: "SELECT * FROM cpu WHERE ", completions: []string{"host"}},
+		{line: "SELECT mean(u", head: "SELECT mean(", completions: []string{"usage_idle", "used"}},
+		{line: "SELECT * FROM mem GROUP BY h", head: "SELECT * FROM mem GROUP BY ", completions: []string{"hostname"}},
+		{line: "SELECT * FROM cpu GRO", head: "SELECT * FROM cpu ", completions: []string{"GROUP", "GROUPS"}},
+	}
+
+	for _, tt := range tests {
+		var queries []string
+		c := newTestCompleter(&queries)
+
+		head, completions, tail := c.Complete(tt.line, len([]rune(tt.line)))
+		if head != tt.head {
+			t.Errorf("%q: unexpected head: got %q, exp %q", tt.line, head, tt.head)
+		}
+		if !reflect.DeepEqual(completions, tt.completions) {
+			t.Errorf("%q: unexpected completions: got %q, exp %q", tt.line, completions, tt.completions)
+		}
+		if tail != "" {
+			t.Errorf("%q: unexpected tail: %q", tt.line, tail)
+		}
+	}
+}
+
+func TestCompleter_Cache(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	c := newTestCompleter(&queries)
+
+	c.Complete("SELECT * FROM c", 15)
+	c.Complete("SELECT * FROM m", 15)
+	if len(queries) != 1 {
+		t.Fatalf("expected measurements to be fetched once, got queries %q", queries)
+	}
+
+	c.reset()
+	c.Complete("SELECT * FROM c", 15)
+	if len(queries) != 2 {
+		t.Fatalf("expected measurements to be fetched again after reset, got queries %q", queries)
+	}
+}
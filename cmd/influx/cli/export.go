@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/client"
+	"github.com/influxdata/influxdb/models"
+)
+
+// exporter writes query results to a file as they are received.
+type exporter interface {
+	// WriteResponse writes the series of one chunk of results and returns
+	// the number of rows written.
+	WriteResponse(resp *client.Response) (int, error)
+
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+}
+
+// exportFormat returns the export format matching the extension of path.
+func exportFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv", nil
+	case ".json":
+		return "json", nil
+	case ".lp", ".line", ".txt":
+		return "line", nil
+	}
+	return "", fmt.Errorf("unable to determine the export format of %q, use a .csv, .json or .lp file", path)
+}
+
+// newExporter returns an exporter writing format to w. schema returns the tag
+// keys and field types of a measurement and is only used by the line protocol
+// format to tell tags from fields and integers from floats.
+func newExporter(format string, w io.Writer, schema func(measurement string) (*measurementSchema, error)) exporter {
+	switch format {
+	case "csv":
+		return &csvExporter{w: csv.NewWriter(w)}
+	case "json":
+		return &jsonExporter{enc: json.NewEncoder(w)}
+	default:
+		return &lineExporter{w: w, schema: schema}
+	}
+}
+
+// measurementSchema holds the tag keys and the types of the fields of a
+// measurement.
+type measurementSchema struct {
+	tags   map[string]bool
+	fields map[string]string
+}
+
+// csvExporter writes a header followed by the rows of each series. The header
+// is only repeated when the name, tags or columns change.
+type csvExporter struct {
+	w      *csv.Writer
+	header []string
+}
+
+func (e *csvExporter) WriteResponse(resp *client.Response) (int, error) {
+	var n int
+	for _, result := range resp.Results {
+		for _, row := range result.Series {
+			tags := make([]string, 0, len(row.Tags))
+			for k, v := range row.Tags {
+				tags = append(tags, k+"="+v)
+			}
+			sort.Strings(tags)
+
+			header := append([]string{"name", "tags"}, row.Columns...)
+			if !columnsEqual(header, e.header) {
+				if err := e.w.Write(header); err != nil {
+					return n, err
+				}
+				e.header = header
+			}
+
+			record := make([]string, 0, len(header))
+			for _, values := range row.Values {
+				record = append(record[:0], row.Name, strings.Join(tags, ","))
+				for _, v := range values {
+					record = append(record, interfaceToString(v))
+				}
+				if err := e.w.Write(record); err != nil {
+					return n, err
+				}
+				n++
+			}
+		}
+	}
+	return n, e.w.Error()
+}
+
+func (e *csvExporter) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonExporter writes one JSON document per chunk of results.
+type jsonExporter struct {
+	enc *json.Encoder
+}
+
+func (e *jsonExporter) WriteResponse(resp *client.Response) (int, error) {
+	if err := e.enc.Encode(resp); err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, result := range resp.Results {
+		for _, row := range result.Series {
+			n += len(row.Values)
+		}
+	}
+	return n, nil
+}
+
+func (e *jsonExporter) Flush() error { return nil }
+
+// lineExporter writes rows as line protocol points. It expects timestamps as
+// nanosecond epochs. Columns that are tag keys of the measurement are written
+// as tags, and the other columns as fields.
+type lineExporter struct {
+	w      io.Writer
+	schema func(measurement string) (*measurementSchema, error)
+}
+
+func (e *lineExporter) WriteResponse(resp *client.Response) (int, error) {
+	var n int
+	for _, result := range resp.Results {
+		for _, row := range result.Series {
+			var schema *measurementSchema
+			if e.schema != nil {
+				var err error
+				if schema, err = e.schema(row.Name); err != nil {
+					return n, fmt.Errorf("unable to export %s: %s", row.Name, err)
+				}
+			}
+
+			for _, values := range row.Values {
+				pt, err := rowPoint(row, values, schema)
+				if err != nil {
+					return n, err
+				}
+				if _, err := io.WriteString(e.w, pt.String()+"\n"); err != nil {
+					return n, err
+				}
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+func (e *lineExporter) Flush() error { return nil }
+
+// rowPoint converts the values of a row to a point. Columns that are not tag
+// keys of the schema, such as aggregates and aliases, are fields, with a type
+// inferred from their value unless they are fields of the schema.
+func rowPoint(row models.Row, values []interface{}, schema *measurementSchema) (models.Point, error) {
+	tags := make(map[string]string, len(row.Tags))
+	for k, v := range row.Tags {
+		tags[k] = v
+	}
+	fields := make(map[string]interface{})
+	var t time.Time
+
+	for i, col := range row.Columns {
+		if i >= len(values) || values[i] == nil {
+			continue
+		}
+		v := values[i]
+
+		if col == "time" {
+			n, ok := v.(json.Number)
+			if !ok {
+				return nil, fmt.Errorf("unable to export time %v: expected an epoch", v)
+			}
+			ns, err := n.Int64()
+			if err != nil {
+				return nil, err
+			}
+			t = time.Unix(0, ns)
+			continue
+		}
+
+		var typ string
+		if schema != nil {
+			if schema.tags[col] {
+				tags[col] = interfaceToString(v)
+				continue
+			}
+			typ = schema.fields[col]
+		}
+
+		value, err := fieldValue(v, typ)
+		if err != nil {
+			return nil, fmt.Errorf("unable to export column %q: %s", col, err)
+		}
+		fields[col] = value
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("unable to export a row of %s at %d: no field values, use fill(none) to omit empty intervals", row.Name, t.UnixNano())
+	}
+	return models.NewPoint(row.Name, models.NewTags(tags), fields, t)
+}
+
+// fieldValue converts a JSON value to a field value of type typ. The type is
+// inferred from the value when typ is empty.
+func fieldValue(v interface{}, typ string) (interface{}, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v, nil
+	}
+
+	switch typ {
+	case "integer":
+		return n.Int64()
+	case "unsigned":
+		return strconv.ParseUint(n.String(), 10, 64)
+	case "float":
+		return n.Float64()
+	}
+	if i, err := n.Int64(); err == nil {
+		return i, nil
+	}
+	return n.Float64()
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client"
+	"github.com/influxdata/influxdb/models"
+)
+
+func TestParseWatchCommand(t *testing.T) {
+	t.Parallel()
+
+	interval, query, err := parseWatchCommand("watch 5s SELECT count(value)  FROM cpu")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if interval != 5*time.Second {
+		t.Errorf("unexpected interval: got %s, exp %s", interval, 5*time.Second)
+	}
+	if exp := "SELECT count(value)  FROM cpu"; query != exp {
+		t.Errorf("unexpected query: got %q, exp %q", query, exp)
+	}
+
+	for _, cmd := range []string{"watch", "watch 5s", "watch five SELECT 1", "watch -1s SELECT 1"} {
+		if _, _, err := parseWatchCommand(cmd); err == nil {
+			t.Errorf("expected error for %q", cmd)
+		}
+	}
+}
+
+func TestParseExportCommand(t *testing.T) {
+	t.Parallel()
+
+	path, query, err := parseExportCommand("export /tmp/cpu.csv SELECT * FROM cpu")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if path != "/tmp/cpu.csv" || query != "SELECT * FROM cpu" {
+		t.Errorf("unexpected command: got %q, %q", path, query)
+	}
+
+	if _, _, err := parseExportCommand("export /tmp/cpu.csv"); err == nil {
+		t.Error("expected error for missing query")
+	}
+}
+
+func TestExportFormat(t *testing.T) {
+	t.Parallel()
+
+	for path, exp := range map[string]string{"a.csv": "csv", "a.JSON": "json", "a.lp": "line", "a.txt": "line"} {
+		if got, err := exportFormat(path); err != nil {
+			t.Errorf("%s: unexpected error: %s", path, err)
+		} else if got != exp {
+			t.Errorf("%s: unexpected format: got %q, exp %q", path, got, exp)
+		}
+	}
+	if _, err := exportFormat("a.xml"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+}
+
+func exportResponse() *client.Response {
+	return &client.Response{Results: []client.Result{{Series: []models.Row{
+		{
+			Name:    "cpu",
+			Tags:    map[string]string{"region": "west"},
+			Columns: []string{"time", "host", "value", "count"},
+			Values: [][]interface{}{
+				{json.Number("10"), "a", json.Number("1"), json.Number("3")},
+				{json.Number("20"), "b", json.Number("1.5"), nil},
+			},
+		},
+	}}}}
+}
+
+func TestExporter_CSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := newExporter("csv", &buf, nil)
+	for i := 0; i < 2; i++ {
+		if n, err := w.WriteResponse(exportResponse()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if n != 2 {
+			t.Fatalf("unexpected number of rows: %d", n)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	exp := "name,tags,time,host,value,count\n" +
+		"cpu,region=west,10,a,1,3\n" +
+		"cpu,region=west,20,b,1.5,\n" +
+		"cpu,region=west,10,a,1,3\n" +
+		"cpu,region=west,20,b,1.5,\n"
+	if got := buf.String(); got != exp {
+		t.Errorf("unexpected output:\ngot:\n%s\nexp:\n%s", got, exp)
+	}
+}
+
+func TestExporter_Line(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := newExporter("line", &buf, func(measurement string) (*measurementSchema, error) {
+		return &measurementSchema{
+			tags:   map[string]bool{"host": true, "region": true},
+			fields: map[string]string{"value": "float", "count": "integer"},
+		}, nil
+	})
+	if n, err := w.WriteResponse(exportResponse()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if n != 2 {
+		t.Fatalf("unexpected number of rows: %d", n)
+	}
+
+	exp := "cpu,host=a,region=west count=3i,value=1 10\n" +
+		"cpu,host=b,region=west value=1.5 20\n"
+	if got := buf.String(); got != exp {
+		t.Errorf("unexpected output:\ngot:\n%s\nexp:\n%s", got, exp)
+	}
+
+	resp := exportResponse()
+	resp.Results[0].Series[0].Values[0][0] = "1970-01-01T00:00:00Z"
+	if _, err := w.WriteResponse(resp); err == nil {
+		t.Error("expected error for rfc3339 timestamps")
+	}
+}
+
+// Ensure columns that are not tag keys, such as aggregates and aliases, are
+// written as fields with a type inferred from their value.
+func TestExporter_Line_Aggregates(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := newExporter("line", &buf, func(measurement string) (*measurementSchema, error) {
+		return &measurementSchema{
+			tags:   map[string]bool{"host": true},
+			fields: map[string]string{"value": "float"},
+		}, nil
+	})
+	resp := &client.Response{Results: []client.Result{{Series: []models.Row{{
+		Name:    "cpu",
+		Tags:    map[string]string{"host": "a"},
+		Columns: []string{"time", "mean", "v", "state"},
+		Values: [][]interface{}{
+			{json.Number("10"), json.Number("1.5"), json.Number("2"), "on"},
+		},
+	}}}}}
+	if n, err := w.WriteResponse(resp); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if n != 1 {
+		t.Fatalf("unexpected number of rows: %d", n)
+	}
+	if got, exp := buf.String(), "cpu,host=a mean=1.5,state=\"on\",v=2i 10\n"; got != exp {
+		t.Errorf("unexpected output:\ngot:\n%s\nexp:\n%s", got, exp)
+	}
+
+	// A row without field values fails the export, and only the rows
+	// before it are counted.
+	buf.Reset()
+	resp.Results[0].Series[0].Values = [][]interface{}{
+		{json.Number("10"), json.Number("1.5"), nil, nil},
+		{json.Number("20"), nil, nil, nil},
+	}
+	if n, err := w.WriteResponse(resp); err == nil || err.Error() != "unable to export a row of cpu at 20: no field values, use fill(none) to omit empty intervals" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n != 1 {
+		t.Fatalf("unexpected number of rows: %d", n)
+	}
+	if got, exp := buf.String(), "cpu,host=a mean=1.5 10\n"; got != exp {
+		t.Errorf("unexpected output:\ngot:\n%s\nexp:\n%s", got, exp)
+	}
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/influxdata/influxdb/influxql"
+)
+
+// Error handling modes of scripts.
+const (
+	// OnErrorStop stops running a script at the first failing statement.
+	OnErrorStop = "stop"
+
+	// OnErrorContinue runs the remaining statements of a script when a
+	// statement fails.
+	OnErrorContinue = "continue"
+)
+
+// statementBuffer accumulates lines of input until they form complete
+// statements. Statements are terminated by a semicolon and may span several
+// lines. CLI commands, such as use or insert, always end at the end of the line.
+//
+// Unless strict is set, a line holding a complete statement also ends the
+// statement without a semicolon so single line queries run as they are typed.
+type statementBuffer struct {
+	strict bool
+	buf    string
+}
+
+// Add appends a line of input and returns the statements it completes.
+func (b *statementBuffer) Add(line string) []string {
+	if b.buf == "" {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			return nil
+		} else if isCLICommand(trimmed) {
+			return []string{line}
+		}
+	}
+
+	lenient := !b.strict && b.buf == ""
+	stmts, rest := splitStatements(b.buf + line + "\n")
+	if lenient && rest != "" && isCompleteStatement(rest) {
+		stmts, rest = append(stmts, strings.TrimSpace(rest)), ""
+	}
+	b.buf = rest
+	return stmts
+}
+
+// Flush returns the buffered unterminated statement, if any, and resets the
+// buffer.
+func (b *statementBuffer) Flush() string {
+	s := strings.TrimSpace(b.buf)
+	b.buf = ""
+	return s
+}
+
+// Pending returns true if an unterminated statement is buffered.
+func (b *statementBuffer) Pending() bool {
+	return b.buf != ""
+}
+
+// splitStatements splits s on the semicolons that are not quoted or part of
+// a comment. It returns the terminated statements and the remaining text, which
+// is empty if it only holds whitespace.
+func splitStatements(s string) (stmts []string, rest string) {
+	var quote rune
+	var comment bool
+	start := 0
+	for i := 0; i < len(s); i++ {
+		ch := rune(s[i])
+		switch {
+		case comment:
+			comment = ch != '\n'
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '-' && strings.HasPrefix(s[i:], "--"):
+			comment = true
+		case ch == ';':
+			if stmt := strings.TrimSpace(s[start:i]); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+			start = i + 1
+		}
+	}
+
+	rest = s[start:]
+	if strings.TrimSpace(rest) == "" {
+		rest = ""
+	}
+	return stmts, rest
+}
+
+// isCLICommand returns true if s starts with a command handled by the CLI
+// rather than the server.
+func isCLICommand(s string) bool {
+	tokens := strings.Fields(strings.ToLower(s))
+	if len(tokens) == 0 {
+		return false
+	}
+
+	switch tokens[0] {
+	case "gopher", "set":
+		return true
+	}
+	for _, cmd := range cliCommands {
+		if tokens[0] == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+// isCompleteStatement returns true unless s ends inside a quoted string or
+// the parser reached the end of s while expecting more of the statement.
+func isCompleteStatement(s string) bool {
+	if _, rest := splitStatements(s + "\n;"); rest != "" {
+		// The terminating semicolon is quoted.
+		return false
+	}
+
+	_, err := influxql.NewParser(strings.NewReader(s)).ParseQuery()
+	if e, ok := err.(*influxql.ParseError); ok && e.Found == "EOF" {
+		return false
+	}
+	return true
+}
+
+// RunScript runs the statements and commands read from r. name identifies
+// the script in error messages and may be empty. Statements must be terminated by a semicolon
+// unless strict is false, in which case a line holding a complete statement
+// is run as it is. The OnError mode of the CommandLine decides whether a
+// failing statement stops the script.
+func (c *CommandLine) RunScript(r io.Reader, name string, strict bool) error {
+	onError := c.OnError
+	switch onError {
+	case "":
+		onError = OnErrorStop
+	case OnErrorStop, OnErrorContinue:
+	default:
+		return fmt.Errorf("unknown error mode %q, expected %s or %s", onError, OnErrorStop, OnErrorContinue)
+	}
+
+	var failed int
+	run := func(stmt string, line int) error {
+		if err := c.ParseCommand(stmt); err != nil && err != ErrBlankCommand {
+			if onError == OnErrorStop && name != "" {
+				return fmt.Errorf("%s:%d: %s", name, line, err)
+			} else if onError == OnErrorStop {
+				return err
+			}
+			failed++
+		}
+		return nil
+	}
+
+	b := statementBuffer{strict: strict}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var lineN, start int
+	for scanner.Scan() {
+		lineN++
+		if !b.Pending() {
+			start = lineN
+		}
+
+		for _, stmt := range b.Add(scanner.Text()) {
+			if err := run(stmt, start); err != nil {
+				return err
+			} else if c.quitting() {
+				return nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if stmt := b.Flush(); stmt != "" {
+		if err := run(stmt, start); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d statement%s failed", failed, plural(failed))
+	}
+	return nil
+}
+
+// RunScriptFile runs the statements of the script file at path. Statements
+// must be terminated by a semicolon.
+func (c *CommandLine) RunScriptFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.RunScript(f, path, true)
+}
+
+// quitting returns true once the exit command has been run.
+func (c *CommandLine) quitting() bool {
+	select {
+	case <-c.Quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// plural returns "s" unless n is 1.
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
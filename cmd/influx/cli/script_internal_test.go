@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s     string
+		stmts []string
+		rest  string
+	}{
+		{s: "SELECT 1", rest: "SELECT 1"},
+		{s: "SELECT 1;\n", stmts: []string{"SELECT 1"}},
+		{s: "SELECT 1; ;SELECT 2; SELECT", stmts: []string{"SELECT 1", "SELECT 2"}, rest: " SELECT"},
+		{s: `SELECT * FROM "a;b" WHERE t = 'x;\'y';`, stmts: []string{`SELECT * FROM "a;b" WHERE t = 'x;\'y'`}},
+		{s: "SELECT * -- comment; more\nFROM cpu;", stmts: []string{"SELECT * -- comment; more\nFROM cpu"}},
+		{s: "SELECT 'unterminated;", rest: "SELECT 'unterminated;"},
+	}
+
+	for _, tt := range tests {
+		stmts, rest := splitStatements(tt.s)
+		if !reflect.DeepEqual(stmts, tt.stmts) {
+			t.Errorf("%q: unexpected statements: got %q, exp %q", tt.s, stmts, tt.stmts)
+		}
+		if rest != tt.rest {
+			t.Errorf("%q: unexpected rest: got %q, exp %q", tt.s, rest, tt.rest)
+		}
+	}
+}
+
+func TestStatementBuffer(t *testing.T) {
+	t.Parallel()
+
+	var b statementBuffer
+	for _, tt := range []struct {
+		line    string
+		stmts   []string
+		pending bool
+	}{
+		{line: "SELECT * FROM cpu", stmts: []string{"SELECT * FROM cpu"}},
+		{line: "use db0;", stmts: []string{"use db0;"}},
+		{line: "insert cpu value=1;2", stmts: []string{"insert cpu value=1;2"}},
+		{line: "-- comment"},
+		{line: "SELECT *", pending: true},
+		{line: "FROM cpu", pending: true},
+		{line: "WHERE host = 'a'; SELECT", stmts: []string{"SELECT *\nFROM cpu\nWHERE host = 'a'"}, pending: true},
+		{line: "1;", stmts: []string{"SELECT\n1"}},
+		{line: "SELECT 1 FROM a; SELECT 2 FROM b", stmts: []string{"SELECT 1 FROM a", "SELECT 2 FROM b"}},
+	} {
+		if stmts := b.Add(tt.line); !reflect.DeepEqual(stmts, tt.stmts) {
+			t.Errorf("%q: unexpected statements: got %q, exp %q", tt.line, stmts, tt.stmts)
+		}
+		if b.Pending() != tt.pending {
+			t.Errorf("%q: unexpected pending: got %v, exp %v", tt.line, b.Pending(), tt.pending)
+		}
+	}
+
+	b = statementBuffer{strict: true}
+	if stmts := b.Add("SELECT * FROM cpu"); len(stmts) != 0 {
+		t.Errorf("unexpected statements in strict mode: %q", stmts)
+	}
+	if got, exp := b.Flush(), "SELECT * FROM cpu"; got != exp {
+		t.Errorf("unexpected flushed statement: got %q, exp %q", got, exp)
+	}
+}
+
+func TestParseParameter(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		cmd   string
+		name  string
+		value interface{}
+	}{
+		{cmd: "set host = 'server01'", name: "host", value: "server01"},
+		{cmd: `SET $host="it\"s";`, name: "host", value: `it"s`},
+		{cmd: "set limit = 10", name: "limit", value: int64(10)},
+		{cmd: "set ratio = 0.5", name: "ratio", value: 0.5},
+		{cmd: "set enabled = true", name: "enabled", value: true},
+		{cmd: "set region = us-west", name: "region", value: "us-west"},
+	} {
+		name, value, err := parseParameter(tt.cmd)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", tt.cmd, err)
+			continue
+		}
+		if name != tt.name || !reflect.DeepEqual(value, tt.value) {
+			t.Errorf("%q: unexpected parameter: got %s=%#v, exp %s=%#v", tt.cmd, name, value, tt.name, tt.value)
+		}
+	}
+
+	for _, cmd := range []string{"set host", "set = 1", "set host =", "set ho-st = 1"} {
+		if _, _, err := parseParameter(cmd); err == nil {
+			t.Errorf("%q: expected error", cmd)
+		}
+	}
+}
@@ -51,11 +51,18 @@ func main() {
 	fs.StringVar(&c.ClientConfig.WriteConsistency, "consistency", "all", "Set write consistency level: any, one, quorum, or all.")
 	fs.BoolVar(&c.Pretty, "pretty", false, "Turns on pretty print for the json format.")
 	fs.StringVar(&c.Execute, "execute", c.Execute, "Execute command and quit.")
+	fs.StringVar(&c.ScriptFile, "file", "", "Execute the statements of a script file and quit.")
+	fs.StringVar(&c.OnError, "onerror", cli.OnErrorStop, "Set the error handling mode of scripts: stop or continue.")
 	fs.BoolVar(&c.ShowVersion, "version", false, "Displays the InfluxDB version.")
 	fs.BoolVar(&c.Import, "import", false, "Import a previous database.")
 	fs.IntVar(&c.ImporterConfig.PPS, "pps", defaultPPS, "How many points per second the import will allow.  By default it is zero and will not throttle importing.")
 	fs.StringVar(&c.ImporterConfig.Path, "path", "", "path to the file to import")
 	fs.BoolVar(&c.ImporterConfig.Compressed, "compressed", false, "set to true if the import file is compressed")
+	fs.BoolVar(&c.ImporterConfig.CSV.Enabled, "csv", false, "set to true if the import file is CSV")
+	fs.StringVar(&c.ImporterConfig.CSV.Spec, "csv-spec", "", "column mapping of the CSV import file")
+	fs.StringVar(&c.ImporterConfig.CSV.Measurement, "csv-measurement", "", "measurement name of the imported CSV rows")
+	fs.StringVar(&c.ImporterConfig.CSV.Delimiter, "csv-delimiter", ",", "field delimiter of the CSV import file")
+	fs.StringVar(&c.ImporterConfig.CSV.RejectsPath, "csv-rejects", "", "path to the file receiving rejected CSV rows")
 
 	// Define our own custom usage to print
 	fs.Usage = func() {
@@ -80,6 +87,10 @@ func main() {
         Set this when connecting to the cluster using https and not use SSL verification.
   -execute 'command'
        Execute command and quit.
+  -file 'path'
+       Execute the statements of a script file and quit. Statements are terminated by a semicolon.
+  -onerror 'stop|continue'
+       Set whether a script stops at the first failing statement or runs the remaining ones.
   -format 'json|csv|column'
        Format specifies the format of the server responses:  json, csv, or column.
   -precision 'rfc3339|h|m|s|ms|u|ns'
@@ -96,14 +107,32 @@ func main() {
        Path to file to import
   -compressed
        Set to true if the import file is compressed
+  -csv
+       Set to true if the import file is CSV. Points are written to the -database database.
+  -csv-spec 'column:role[:type],...'
+       Column mapping of the CSV import file. Roles are measurement, tag, ignore,
+       field[:float|integer|unsigned|boolean|string] and time[:rfc3339|s|ms|u|ns|<Go layout>].
+  -csv-measurement 'measurement name'
+       Measurement name of the imported rows when no column is mapped to the measurement.
+  -csv-delimiter ','
+       Field delimiter of the CSV import file.
+  -csv-rejects 'path'
+       Path to a CSV file receiving the rejected rows and the reason they were rejected.
 
 Examples:
 
     # Use influx in a non-interactive mode to query the database "metrics" and pretty print json:
     $ influx -database 'metrics' -execute 'select * from cpu' -format 'json' -pretty
 
+    # Run a script against the database "metrics", reporting the failing statements at the end:
+    $ influx -database 'metrics' -file 'queries.iql' -onerror continue
+
     # Connect to a specific database on startup and set database context:
     $ influx -database 'metrics' -host 'localhost' -port '8086'
+
+    # Import a CSV file into the "metrics" database:
+    $ influx -import -csv -path cpu.csv -database 'metrics' -csv-measurement cpu \
+        -csv-spec 'time:time:s,host:tag,usage_idle:field,count:field:integer'
 `)
 	}
 	fs.Parse(os.Args[1:])
@@ -0,0 +1,278 @@
+// Package dumpwal inspects and repairs tsm1 WAL segment files.
+package dumpwal
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+// Command represents the program execution for "influx_inspect dumpwal".
+type Command struct {
+	// Standard input/output, overridden for testing.
+	Stderr io.Writer
+	Stdout io.Writer
+
+	dumpKeys  bool
+	filterKey string
+	repair    bool
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("dumpwal", flag.ExitOnError)
+	fs.BoolVar(&cmd.dumpKeys, "keys", false, "Dump the keys of every entry")
+	fs.StringVar(&cmd.filterKey, "filter-key", "", "Only display keys matching this key substring")
+	fs.BoolVar(&cmd.repair, "repair", false, "Truncate corrupt segments at the last valid entry")
+
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = cmd.printUsage
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fmt.Fprintf(cmd.Stdout, "WAL segment not specified\n\n")
+		fs.Usage()
+		return nil
+	}
+	cmd.dumpKeys = cmd.dumpKeys || cmd.filterKey != ""
+
+	files, err := segmentFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	var corrupt int
+	for _, path := range files {
+		ok, err := cmd.dump(path)
+		if err != nil {
+			return err
+		} else if !ok {
+			corrupt++
+		}
+	}
+
+	if corrupt > 0 && !cmd.repair {
+		return fmt.Errorf("%d of %d segments corrupt, run with -repair to truncate them at the last valid entry", corrupt, len(files))
+	}
+	return nil
+}
+
+// segmentFiles returns the WAL segment files of paths. Directories are walked
+// for files with the WAL file extension.
+func segmentFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		} else if !fi.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		var segments []string
+		if err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && filepath.Ext(path) == "."+tsm1.WALFileExtension {
+				segments = append(segments, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		sort.Strings(segments)
+		files = append(files, segments...)
+	}
+	return files, nil
+}
+
+// dump prints the entries of the segment at path and returns false if the
+// segment is corrupt. Corrupt segments are truncated when repairing.
+func (cmd *Command) dump(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return false, err
+	}
+
+	fmt.Fprintf(cmd.Stdout, "File: %s\n", path)
+	fmt.Fprintf(cmd.Stdout, "  File Size: %d\n\n", stat.Size())
+
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "  "+strings.Join([]string{"Entry", "Ofs", "Len", "Type", "Keys", "Points", "Min Time", "Max Time"}, "\t"))
+
+	var entries, writes, deletes, deleteRanges, points int
+	var start int64
+	var readErr error
+	r := tsm1.NewWALSegmentReader(f)
+	for r.Next() {
+		entry, err := r.Read()
+		if err != nil {
+			readErr = err
+			break
+		}
+		entries++
+
+		var typ, keyCount, pointCount, minTime, maxTime string
+		var keys []string
+		var keyPoints map[string]int
+		switch e := entry.(type) {
+		case *tsm1.WriteWALEntry:
+			writes++
+			typ = "write"
+			keyPoints = make(map[string]int, len(e.Values))
+			var n int
+			for k, values := range e.Values {
+				keys = append(keys, k)
+				keyPoints[k] = len(values)
+				n += len(values)
+			}
+			points += n
+			keyCount, pointCount = strconv.Itoa(len(e.Values)), strconv.Itoa(n)
+		case *tsm1.DeleteWALEntry:
+			deletes++
+			typ = "delete"
+			for _, k := range e.Keys {
+				keys = append(keys, string(k))
+			}
+			keyCount = strconv.Itoa(len(e.Keys))
+		case *tsm1.DeleteRangeWALEntry:
+			deleteRanges++
+			typ = "delete-range"
+			for _, k := range e.Keys {
+				keys = append(keys, string(k))
+			}
+			keyCount = strconv.Itoa(len(e.Keys))
+			minTime = time.Unix(0, e.Min).UTC().Format(time.RFC3339Nano)
+			maxTime = time.Unix(0, e.Max).UTC().Format(time.RFC3339Nano)
+		}
+
+		fmt.Fprintln(tw, "  "+strings.Join([]string{
+			strconv.Itoa(entries),
+			strconv.FormatInt(start, 10),
+			strconv.FormatInt(r.Count()-start, 10),
+			typ, keyCount, pointCount, minTime, maxTime,
+		}, "\t"))
+		start = r.Count()
+
+		if cmd.dumpKeys {
+			sort.Strings(keys)
+			for _, k := range keys {
+				if cmd.filterKey != "" && !strings.Contains(k, cmd.filterKey) {
+					continue
+				}
+				if keyPoints != nil {
+					fmt.Fprintf(tw, "    %s (%d points)\n", k, keyPoints[k])
+				} else {
+					fmt.Fprintf(tw, "    %s\n", k)
+				}
+			}
+		}
+	}
+	valid := r.Count()
+	r.Close()
+	tw.Flush()
+
+	fmt.Fprintf(cmd.Stdout, "\n  Entries: %d (%d writes, %d deletes, %d delete ranges)\n", entries, writes, deletes, deleteRanges)
+	fmt.Fprintf(cmd.Stdout, "  Points: %d\n", points)
+
+	if readErr == nil {
+		fmt.Fprintf(cmd.Stdout, "  Status: ok\n\n")
+		return true, nil
+	}
+
+	dropped, err := describeTail(path, valid)
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(cmd.Stdout, "  Status: corrupt at position %d: %s\n", valid, readErr)
+	fmt.Fprintf(cmd.Stdout, "  Invalid: %d bytes after the last valid entry, %s\n", stat.Size()-valid, dropped)
+
+	if cmd.repair {
+		if err := os.Truncate(path, valid); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(cmd.Stdout, "  Repaired: truncated to %d bytes, dropped %d bytes\n", valid, stat.Size()-valid)
+	}
+	fmt.Fprintln(cmd.Stdout)
+	return false, nil
+}
+
+// describeTail describes the invalid data following the last valid entry at
+// offset off of the segment at path.
+func describeTail(path string, off int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var hdr [5]byte
+	n, err := f.ReadAt(hdr[:], off)
+	if err != nil && err != io.EOF {
+		return "", err
+	} else if n < len(hdr) {
+		return "a partial entry header", nil
+	}
+
+	var typ string
+	switch tsm1.WalEntryType(hdr[0]) {
+	case tsm1.WriteWALEntryType:
+		typ = "write"
+	case tsm1.DeleteWALEntryType:
+		typ = "delete"
+	case tsm1.DeleteRangeWALEntryType:
+		typ = "delete-range"
+	default:
+		return fmt.Sprintf("an entry of unknown type %d", hdr[0]), nil
+	}
+	return fmt.Sprintf("a %s entry of %d bytes", typ, binary.BigEndian.Uint32(hdr[1:])), nil
+}
+
+// printUsage prints the usage message to STDERR.
+func (cmd *Command) printUsage() {
+	usage := `Dumps the entries of tsm1 WAL segment files and repairs corrupt segments.
+
+Usage: influx_inspect dumpwal [flags] <path>...
+
+Paths may be WAL segment files or directories holding them.
+
+    -keys
+            Dump the keys of every entry, with the number of points of writes
+    -filter-key <name>
+            Only display keys matching this key substring
+    -repair
+            Truncate corrupt segments at the last valid entry.  Caution: the
+            data after the last valid entry is lost.  Stop influxd first.
+`
+
+	fmt.Fprint(cmd.Stdout, usage)
+}
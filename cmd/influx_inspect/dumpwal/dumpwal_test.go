@@ -0,0 +1,140 @@
+package dumpwal_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb/cmd/influx_inspect/dumpwal"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+func TestCommand_Dump(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "_00001.wal")
+	MustWriteSegment(path,
+		&tsm1.WriteWALEntry{Values: map[string][]tsm1.Value{
+			"cpu,host=a#!~#value": {tsm1.NewValue(1, 1.0), tsm1.NewValue(2, 2.0)},
+			"mem,host=a#!~#free":  {tsm1.NewValue(1, int64(1))},
+		}},
+		&tsm1.DeleteWALEntry{Keys: [][]byte{[]byte("cpu,host=b#!~#value")}},
+		&tsm1.DeleteRangeWALEntry{Keys: [][]byte{[]byte("cpu,host=a#!~#value")}, Min: 0, Max: 1},
+	)
+
+	var buf bytes.Buffer
+	cmd := dumpwal.NewCommand()
+	cmd.Stdout = &buf
+	if err := cmd.Run("-filter-key", "cpu", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{
+		"File: " + path,
+		"Entries: 3 (1 writes, 1 deletes, 1 delete ranges)",
+		"Points: 3",
+		"Status: ok",
+		"  1\t0\t",
+		"cpu,host=a#!~#value (2 points)",
+		"cpu,host=b#!~#value",
+	} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("expected %q in output:\n%s", s, out)
+		}
+	}
+	if strings.Contains(out, "mem,host=a") {
+		t.Fatalf("unexpected filtered key in output:\n%s", out)
+	}
+}
+
+func TestCommand_Repair(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "_00001.wal")
+	MustWriteSegment(path, &tsm1.WriteWALEntry{Values: map[string][]tsm1.Value{
+		"cpu,host=a#!~#value": {tsm1.NewValue(1, 1.0)},
+	}})
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := fi.Size()
+
+	// Append a write entry header declaring more data than follows it.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{byte(tsm1.WriteWALEntryType), 0, 0, 1, 0, 'x', 'y'}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	var buf bytes.Buffer
+	cmd := dumpwal.NewCommand()
+	cmd.Stdout = &buf
+	if err := cmd.Run(path); err == nil || !strings.Contains(err.Error(), "1 of 1 segments corrupt") {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !strings.Contains(buf.String(), "Invalid: 7 bytes after the last valid entry, a write entry of 256 bytes") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	cmd = dumpwal.NewCommand()
+	cmd.Stdout = &buf
+	if err := cmd.Run("-repair", path); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(buf.String(), "Repaired: truncated to") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+
+	if fi, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if fi.Size() != valid {
+		t.Fatalf("unexpected size after repair: got %d, exp %d", fi.Size(), valid)
+	}
+
+	buf.Reset()
+	if err := dumpwal.NewCommand().Run(path); err != nil {
+		t.Fatalf("unexpected error after repair: %s", err)
+	}
+}
+
+// MustTempDir returns a temporary directory. Panic on error.
+func MustTempDir() string {
+	dir, err := ioutil.TempDir("", "influx_inspect-dumpwal-")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+// MustWriteSegment writes entries to a WAL segment at path. Panic on error.
+func MustWriteSegment(path string, entries ...tsm1.WALEntry) {
+	f, err := os.Create(path)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	w := tsm1.NewWALSegmentWriter(f)
+	for _, e := range entries {
+		b, err := e.Encode(nil)
+		if err != nil {
+			panic(err)
+		}
+		if err := w.Write(e.Type(), snappy.Encode(nil, b)); err != nil {
+			panic(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		panic(err)
+	}
+}
@@ -1,4 +1,4 @@
-// Package export exports TSM files into InfluxDB line protocol format.
+// Package export exports TSM files into InfluxDB line protocol, CSV or Parquet format.
 package export
 
 import (
@@ -36,6 +36,7 @@ type Command struct {
 	startTime       int64
 	endTime         int64
 	compress        bool
+	format          string
 
 	manifest map[string]struct{}
 	tsmFiles map[string][]string
@@ -60,16 +61,17 @@ func (cmd *Command) Run(args ...string) error {
 	fs := flag.NewFlagSet("export", flag.ExitOnError)
 	fs.StringVar(&cmd.dataDir, "datadir", os.Getenv("HOME")+"/.influxdb/data", "Data storage path")
 	fs.StringVar(&cmd.walDir, "waldir", os.Getenv("HOME")+"/.influxdb/wal", "WAL storage path")
-	fs.StringVar(&cmd.out, "out", os.Getenv("HOME")+"/.influxdb/export", "Destination file to export to")
+	fs.StringVar(&cmd.out, "out", os.Getenv("HOME")+"/.influxdb/export", "Destination file, or directory for the csv and parquet formats, to export to")
 	fs.StringVar(&cmd.database, "database", "", "Optional: the database to export")
 	fs.StringVar(&cmd.retentionPolicy, "retention", "", "Optional: the retention policy to export (requires -database)")
 	fs.StringVar(&start, "start", "", "Optional: the start time to export (RFC3339 format)")
 	fs.StringVar(&end, "end", "", "Optional: the end time to export (RFC3339 format)")
 	fs.BoolVar(&cmd.compress, "compress", false, "Compress the output")
+	fs.StringVar(&cmd.format, "format", formatLine, "Output format: line, csv or parquet. The csv and parquet formats write a file per measurement")
 
 	fs.SetOutput(cmd.Stdout)
 	fs.Usage = func() {
-		fmt.Fprintf(cmd.Stdout, "Exports TSM files into InfluxDB line protocol format.\n\n")
+		fmt.Fprintf(cmd.Stdout, "Exports TSM files into InfluxDB line protocol, CSV or Parquet format.\n\n")
 		fmt.Fprintf(cmd.Stdout, "Usage: %s export [flags]\n\n", filepath.Base(os.Args[0]))
 		fs.PrintDefaults()
 	}
@@ -113,6 +115,11 @@ func (cmd *Command) validate() error {
 	if cmd.startTime != 0 && cmd.endTime != 0 && cmd.endTime < cmd.startTime {
 		return fmt.Errorf("end time before start time")
 	}
+	switch cmd.format {
+	case formatLine, formatCSV, formatParquet:
+	default:
+		return fmt.Errorf("unknown format %q", cmd.format)
+	}
 	return nil
 }
 
@@ -123,6 +130,9 @@ func (cmd *Command) export() error {
 	if err := cmd.walkWALFiles(); err != nil {
 		return err
 	}
+	if cmd.format != formatLine {
+		return cmd.writeTables()
+	}
 	return cmd.write()
 }
 
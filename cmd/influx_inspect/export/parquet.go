@@ -0,0 +1,486 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// This file implements the subset of the Apache Parquet format needed to
+// export measurements: flat schemas of required or optional columns, one
+// PLAIN encoded data page per column chunk, and optional GZIP compression.
+// See https://github.com/apache/parquet-format for the specification.
+
+const parquetMagic = "PAR1"
+
+// parquetRowGroupSize is the number of rows buffered before a row group is
+// written.
+const parquetRowGroupSize = 64 * 1024
+
+// Parquet physical types.
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+)
+
+// Parquet encodings, compression codecs and converted types.
+const (
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecUncompressed = 0
+	parquetCodecGzip         = 2
+
+	parquetConvertedUTF8   = 0
+	parquetConvertedUint64 = 14
+
+	parquetRequired = 0
+	parquetOptional = 1
+)
+
+// parquetColumn describes a column of a parquet file.
+type parquetColumn struct {
+	Name     string
+	Type     string // one of time, float, integer, unsigned, boolean or string
+	Optional bool
+}
+
+// physicalType returns the parquet type used to store the column values.
+func (c *parquetColumn) physicalType() int32 {
+	switch c.Type {
+	case "float":
+		return parquetDouble
+	case "boolean":
+		return parquetBoolean
+	case "string":
+		return parquetByteArray
+	default:
+		return parquetInt64
+	}
+}
+
+// parquetColumnChunk holds the values of a column for the current row group.
+type parquetColumnChunk struct {
+	values bytes.Buffer // PLAIN encoded non-null values
+	bools  []bool       // boolean values, which are bit-packed when written
+	levels []byte       // definition levels, 1 if the value is set
+}
+
+// parquetWriter writes rows to a parquet file.
+type parquetWriter struct {
+	w        *bufio.Writer
+	offset   int64
+	compress bool
+
+	columns   []parquetColumn
+	chunks    []parquetColumnChunk
+	rows      int
+	numRows   int64
+	rowGroups [][]parquetChunkMeta
+	groupRows []int64
+	groupSize []int64
+}
+
+// parquetChunkMeta is the metadata of a column chunk written to the file.
+type parquetChunkMeta struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+// newParquetWriter returns a writer of the given columns to w. Pages are gzip
+// compressed if compress is set.
+func newParquetWriter(w io.Writer, columns []parquetColumn, compress bool) (*parquetWriter, error) {
+	pw := &parquetWriter{
+		w:        bufio.NewWriterSize(w, 1024*1024),
+		compress: compress,
+		columns:  columns,
+		chunks:   make([]parquetColumnChunk, len(columns)),
+	}
+	if err := pw.write([]byte(parquetMagic)); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (pw *parquetWriter) write(b []byte) error {
+	n, err := pw.w.Write(b)
+	pw.offset += int64(n)
+	return err
+}
+
+// WriteRow appends a row. values are indexed by column and nil values are
+// null. The Go type of a value must match the type of its column: int64 for
+// time and integer columns, float64, uint64, bool or string.
+func (pw *parquetWriter) WriteRow(values []interface{}) error {
+	if len(values) != len(pw.columns) {
+		return fmt.Errorf("parquet: row has %d values, expected %d", len(values), len(pw.columns))
+	}
+
+	for i, v := range values {
+		col, chunk := &pw.columns[i], &pw.chunks[i]
+		if v == nil {
+			if !col.Optional {
+				return fmt.Errorf("parquet: missing value for required column %q", col.Name)
+			}
+			chunk.levels = append(chunk.levels, 0)
+			continue
+		}
+		if col.Optional {
+			chunk.levels = append(chunk.levels, 1)
+		}
+
+		var buf [8]byte
+		switch v := v.(type) {
+		case float64:
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+			chunk.values.Write(buf[:])
+		case int64:
+			binary.LittleEndian.PutUint64(buf[:], uint64(v))
+			chunk.values.Write(buf[:])
+		case uint64:
+			binary.LittleEndian.PutUint64(buf[:], v)
+			chunk.values.Write(buf[:])
+		case bool:
+			chunk.bools = append(chunk.bools, v)
+		case string:
+			binary.LittleEndian.PutUint32(buf[:4], uint32(len(v)))
+			chunk.values.Write(buf[:4])
+			chunk.values.WriteString(v)
+		default:
+			return fmt.Errorf("parquet: unsupported value type %T for column %q", v, col.Name)
+		}
+	}
+
+	pw.rows++
+	if pw.rows >= parquetRowGroupSize {
+		return pw.flushRowGroup()
+	}
+	return nil
+}
+
+// flushRowGroup writes the buffered rows as a row group.
+func (pw *parquetWriter) flushRowGroup() error {
+	if pw.rows == 0 {
+		return nil
+	}
+
+	start := pw.offset
+	metas := make([]parquetChunkMeta, len(pw.columns))
+	for i := range pw.columns {
+		meta, err := pw.writeColumnChunk(&pw.columns[i], &pw.chunks[i])
+		if err != nil {
+			return err
+		}
+		metas[i] = meta
+		pw.chunks[i] = parquetColumnChunk{}
+	}
+
+	pw.rowGroups = append(pw.rowGroups, metas)
+	pw.groupRows = append(pw.groupRows, int64(pw.rows))
+	pw.groupSize = append(pw.groupSize, pw.offset-start)
+	pw.numRows += int64(pw.rows)
+	pw.rows = 0
+	return nil
+}
+
+// writeColumnChunk writes a column chunk made of a single data page.
+func (pw *parquetWriter) writeColumnChunk(col *parquetColumn, chunk *parquetColumnChunk) (parquetChunkMeta, error) {
+	var page bytes.Buffer
+	if col.Optional {
+		levels := encodeParquetLevels(chunk.levels)
+		var n [4]byte
+		binary.LittleEndian.PutUint32(n[:], uint32(len(levels)))
+		page.Write(n[:])
+		page.Write(levels)
+	}
+	if col.Type == "boolean" {
+		page.Write(packParquetBools(chunk.bools))
+	} else {
+		page.Write(chunk.values.Bytes())
+	}
+
+	data := page.Bytes()
+	uncompressedSize := len(data)
+	if pw.compress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(data); err != nil {
+			return parquetChunkMeta{}, err
+		} else if err := gz.Close(); err != nil {
+			return parquetChunkMeta{}, err
+		}
+		data = buf.Bytes()
+	}
+
+	numValues := int64(pw.rows)
+	var e thriftEncoder
+	e.i32(1, 0) // type: DATA_PAGE
+	e.i32(2, int32(uncompressedSize))
+	e.i32(3, int32(len(data)))
+	e.structBegin(5) // data_page_header
+	e.i32(1, int32(numValues))
+	e.i32(2, parquetEncodingPlain)
+	e.i32(3, parquetEncodingRLE)
+	e.i32(4, parquetEncodingRLE)
+	e.structEnd()
+	e.stop()
+
+	meta := parquetChunkMeta{
+		offset:           pw.offset,
+		numValues:        numValues,
+		uncompressedSize: int64(len(e.buf) + uncompressedSize),
+		compressedSize:   int64(len(e.buf) + len(data)),
+	}
+	if err := pw.write(e.buf); err != nil {
+		return meta, err
+	}
+	return meta, pw.write(data)
+}
+
+// Close writes the remaining rows and the file footer. It does not close the
+// underlying writer.
+func (pw *parquetWriter) Close() error {
+	if err := pw.flushRowGroup(); err != nil {
+		return err
+	}
+
+	var e thriftEncoder
+	e.i32(1, 1) // version
+
+	e.listBegin(2, thriftStruct, len(pw.columns)+1)
+	e.elemBegin()
+	e.str(4, "schema")
+	e.i32(5, int32(len(pw.columns)))
+	e.elemEnd()
+	for _, col := range pw.columns {
+		e.elemBegin()
+		e.i32(1, col.physicalType())
+		if col.Optional {
+			e.i32(3, parquetOptional)
+		} else {
+			e.i32(3, parquetRequired)
+		}
+		e.str(4, col.Name)
+		switch col.Type {
+		case "string":
+			e.i32(6, parquetConvertedUTF8)
+			e.structBegin(10) // logicalType
+			e.structBegin(1)  // STRING
+			e.structEnd()
+			e.structEnd()
+		case "unsigned":
+			e.i32(6, parquetConvertedUint64)
+			e.structBegin(10) // logicalType
+			e.structBegin(10) // INTEGER
+			e.i8(1, 64)
+			e.boolean(2, false)
+			e.structEnd()
+			e.structEnd()
+		case "time":
+			e.structBegin(10) // logicalType
+			e.structBegin(8)  // TIMESTAMP
+			e.boolean(1, true)
+			e.structBegin(2) // unit
+			e.structBegin(3) // NANOS
+			e.structEnd()
+			e.structEnd()
+			e.structEnd()
+			e.structEnd()
+		}
+		e.elemEnd()
+	}
+
+	e.i64(3, pw.numRows)
+
+	e.listBegin(4, thriftStruct, len(pw.rowGroups))
+	for i, metas := range pw.rowGroups {
+		e.elemBegin()
+		e.listBegin(1, thriftStruct, len(metas))
+		for j, meta := range metas {
+			col := &pw.columns[j]
+			e.elemBegin()
+			e.i64(2, meta.offset)
+			e.structBegin(3) // meta_data
+			e.i32(1, col.physicalType())
+			if col.Optional {
+				e.listBegin(2, thriftI32, 2)
+				e.elemI32(parquetEncodingPlain)
+				e.elemI32(parquetEncodingRLE)
+			} else {
+				e.listBegin(2, thriftI32, 1)
+				e.elemI32(parquetEncodingPlain)
+			}
+			e.listBegin(3, thriftBinary, 1)
+			e.elemStr(col.Name)
+			if pw.compress {
+				e.i32(4, parquetCodecGzip)
+			} else {
+				e.i32(4, parquetCodecUncompressed)
+			}
+			e.i64(5, meta.numValues)
+			e.i64(6, meta.uncompressedSize)
+			e.i64(7, meta.compressedSize)
+			e.i64(9, meta.offset)
+			e.structEnd()
+			e.elemEnd()
+		}
+		e.i64(2, pw.groupSize[i])
+		e.i64(3, pw.groupRows[i])
+		e.elemEnd()
+	}
+	e.str(6, "influx_inspect export")
+	e.stop()
+
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(e.buf)))
+	for _, b := range [][]byte{e.buf, n[:], []byte(parquetMagic)} {
+		if err := pw.write(b); err != nil {
+			return err
+		}
+	}
+	return pw.w.Flush()
+}
+
+// encodeParquetLevels encodes definition levels with a bit width of 1 using
+// the run length encoding of the RLE/bit-packing hybrid encoding.
+func encodeParquetLevels(levels []byte) []byte {
+	var buf []byte
+	for i := 0; i < len(levels); {
+		j := i + 1
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		buf = appendUvarint(buf, uint64(j-i)<<1)
+		buf = append(buf, levels[i])
+		i = j
+	}
+	return buf
+}
+
+// packParquetBools bit-packs boolean values, least significant bit first.
+func packParquetBools(values []bool) []byte {
+	buf := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			buf[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return buf
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(buf, b[:n]...)
+}
+
+// Thrift compact protocol types.
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftEncoder encodes structs with the thrift compact protocol, which is
+// used by the parquet file metadata and page headers.
+type thriftEncoder struct {
+	buf  []byte
+	last []int16 // last field id of the enclosing structs
+	id   int16   // last field id of the current struct
+}
+
+func (e *thriftEncoder) fieldHeader(id int16, typ byte) {
+	if delta := id - e.id; delta > 0 && delta <= 15 {
+		e.buf = append(e.buf, byte(delta)<<4|typ)
+	} else {
+		e.buf = append(e.buf, typ)
+		e.buf = appendUvarint(e.buf, zigzag(int64(id)))
+	}
+	e.id = id
+}
+
+func (e *thriftEncoder) i8(id int16, v byte) {
+	e.fieldHeader(id, thriftByte)
+	e.buf = append(e.buf, v)
+}
+
+func (e *thriftEncoder) boolean(id int16, v bool) {
+	if v {
+		e.fieldHeader(id, thriftTrue)
+	} else {
+		e.fieldHeader(id, thriftFalse)
+	}
+}
+
+func (e *thriftEncoder) i32(id int16, v int32) {
+	e.fieldHeader(id, thriftI32)
+	e.buf = appendUvarint(e.buf, zigzag(int64(v)))
+}
+
+func (e *thriftEncoder) i64(id int16, v int64) {
+	e.fieldHeader(id, thriftI64)
+	e.buf = appendUvarint(e.buf, zigzag(v))
+}
+
+func (e *thriftEncoder) str(id int16, v string) {
+	e.fieldHeader(id, thriftBinary)
+	e.elemStr(v)
+}
+
+func (e *thriftEncoder) structBegin(id int16) {
+	e.fieldHeader(id, thriftStruct)
+	e.elemBegin()
+}
+
+func (e *thriftEncoder) structEnd() { e.elemEnd() }
+
+// stop terminates the top level struct.
+func (e *thriftEncoder) stop() { e.buf = append(e.buf, 0) }
+
+func (e *thriftEncoder) listBegin(id int16, elemType byte, size int) {
+	e.fieldHeader(id, thriftList)
+	if size < 15 {
+		e.buf = append(e.buf, byte(size)<<4|elemType)
+	} else {
+		e.buf = append(e.buf, 0xf0|elemType)
+		e.buf = appendUvarint(e.buf, uint64(size))
+	}
+}
+
+// elemBegin starts a struct element of a list.
+func (e *thriftEncoder) elemBegin() {
+	e.last = append(e.last, e.id)
+	e.id = 0
+}
+
+// elemEnd ends a struct element of a list.
+func (e *thriftEncoder) elemEnd() {
+	e.buf = append(e.buf, 0)
+	e.id, e.last = e.last[len(e.last)-1], e.last[:len(e.last)-1]
+}
+
+func (e *thriftEncoder) elemI32(v int32) {
+	e.buf = appendUvarint(e.buf, zigzag(int64(v)))
+}
+
+func (e *thriftEncoder) elemStr(v string) {
+	e.buf = appendUvarint(e.buf, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
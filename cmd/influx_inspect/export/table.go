@@ -0,0 +1,586 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/escape"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+// Export formats.
+const (
+	formatLine    = "line"
+	formatCSV     = "csv"
+	formatParquet = "parquet"
+)
+
+// measurementSchema holds the tag keys and field types of a measurement.
+type measurementSchema struct {
+	name   []byte // escaped, as stored in series keys
+	tags   map[string]struct{}
+	fields map[string]string
+}
+
+// addField adds a field of type typ to the schema. Fields with conflicting
+// types across shards are exported as strings.
+func (s *measurementSchema) addField(field, typ string) {
+	if t, ok := s.fields[field]; ok && t != typ {
+		typ = "string"
+	}
+	s.fields[field] = typ
+}
+
+// table maps the series of a measurement to the columns of an exported table:
+// the time, followed by the sorted tag keys and field names.
+type table struct {
+	columns []parquetColumn
+	tags    map[string]int // column index keyed by tag key
+	fields  map[string]int // column index keyed by field name
+}
+
+func newTable(s *measurementSchema) *table {
+	t := &table{
+		columns: []parquetColumn{{Name: "time", Type: "time"}},
+		tags:    make(map[string]int, len(s.tags)),
+		fields:  make(map[string]int, len(s.fields)),
+	}
+
+	names := make(map[string]bool)
+	for _, k := range sortedKeys(s.tags) {
+		t.tags[k] = len(t.columns)
+		t.columns = append(t.columns, parquetColumn{Name: k, Type: "string", Optional: true})
+		names[k] = true
+	}
+
+	fields := make([]string, 0, len(s.fields))
+	for k := range s.fields {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	for _, k := range fields {
+		// Fields sharing the name of a tag key are suffixed like in query results.
+		name := k
+		for i := 1; names[name]; i++ {
+			name = fmt.Sprintf("%s_%d", k, i)
+		}
+		names[name] = true
+
+		t.fields[k] = len(t.columns)
+		t.columns = append(t.columns, parquetColumn{Name: name, Type: s.fields[k], Optional: true})
+	}
+	return t
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	a := make([]string, 0, len(m))
+	for k := range m {
+		a = append(a, k)
+	}
+	sort.Strings(a)
+	return a
+}
+
+// tableWriter writes the rows of a table to a file.
+type tableWriter interface {
+	// WriteRow writes a row holding a value, or nil, for each column of the table.
+	WriteRow(values []interface{}) error
+
+	// Close flushes the rows and closes the file.
+	Close() error
+}
+
+// csvTableWriter writes rows as CSV records, preceded by a header.
+type csvTableWriter struct {
+	f   *os.File
+	bw  *bufio.Writer
+	gzw *gzip.Writer
+	w   *csv.Writer
+	rec []string
+}
+
+func newCSVTableWriter(f *os.File, columns []parquetColumn, compress bool) (*csvTableWriter, error) {
+	tw := &csvTableWriter{f: f, bw: bufio.NewWriterSize(f, 1024*1024)}
+
+	var w io.Writer = tw.bw
+	if compress {
+		tw.gzw = gzip.NewWriter(w)
+		w = tw.gzw
+	}
+	tw.w = csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Name
+	}
+	if err := tw.w.Write(header); err != nil {
+		return nil, err
+	}
+	tw.rec = make([]string, len(columns))
+	return tw, nil
+}
+
+func (tw *csvTableWriter) WriteRow(values []interface{}) error {
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+			tw.rec[i] = ""
+		case int64:
+			if i == 0 {
+				tw.rec[i] = time.Unix(0, v).UTC().Format(time.RFC3339Nano)
+			} else {
+				tw.rec[i] = strconv.FormatInt(v, 10)
+			}
+		case float64:
+			tw.rec[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		case uint64:
+			tw.rec[i] = strconv.FormatUint(v, 10)
+		case bool:
+			tw.rec[i] = strconv.FormatBool(v)
+		case string:
+			tw.rec[i] = v
+		default:
+			tw.rec[i] = fmt.Sprintf("%v", v)
+		}
+	}
+	return tw.w.Write(tw.rec)
+}
+
+func (tw *csvTableWriter) Close() error {
+	defer tw.f.Close()
+
+	tw.w.Flush()
+	if err := tw.w.Error(); err != nil {
+		return err
+	}
+	if tw.gzw != nil {
+		if err := tw.gzw.Close(); err != nil {
+			return err
+		}
+	}
+	if err := tw.bw.Flush(); err != nil {
+		return err
+	}
+	return tw.f.Close()
+}
+
+// parquetTableWriter writes rows to a parquet file.
+type parquetTableWriter struct {
+	f *os.File
+	*parquetWriter
+}
+
+func (tw *parquetTableWriter) Close() error {
+	defer tw.f.Close()
+
+	if err := tw.parquetWriter.Close(); err != nil {
+		return err
+	}
+	return tw.f.Close()
+}
+
+// seriesBuffer collects the fields of a series and writes them as rows, one
+// per timestamp, when all the fields of the series have been added. The table
+// file is created with the first row so measurements without values in the
+// exported time range are skipped.
+type seriesBuffer struct {
+	cmd    *Command
+	t      *table
+	path   string
+	w      tableWriter
+	key    []byte
+	fields map[string][]tsm1.Value
+}
+
+// add adds the values of a field. Fields must be added grouped by series.
+func (b *seriesBuffer) add(seriesKey, field []byte, values []tsm1.Value) error {
+	if !bytes.Equal(seriesKey, b.key) {
+		if err := b.flush(); err != nil {
+			return err
+		}
+		b.key = append(b.key[:0], seriesKey...)
+	}
+	b.fields[string(field)] = values
+	return nil
+}
+
+// flush writes the rows of the buffered series.
+func (b *seriesBuffer) flush() error {
+	if len(b.fields) == 0 {
+		return nil
+	}
+	defer func() { b.fields = make(map[string][]tsm1.Value) }()
+
+	base := make([]interface{}, len(b.t.columns))
+	_, tags := models.ParseKey(b.key)
+	for _, tag := range tags {
+		if i, ok := b.t.tags[string(tag.Key)]; ok {
+			base[i] = string(tag.Value)
+		}
+	}
+
+	rows := make(map[int64][]interface{})
+	for field, values := range b.fields {
+		i, ok := b.t.fields[field]
+		if !ok {
+			continue
+		}
+		for _, value := range values {
+			ts := value.UnixNano()
+			if ts < b.cmd.startTime || ts > b.cmd.endTime {
+				continue
+			}
+
+			row, ok := rows[ts]
+			if !ok {
+				row = make([]interface{}, len(base))
+				copy(row, base)
+				row[0] = ts
+				rows[ts] = row
+			}
+
+			v := value.Value()
+			if b.t.columns[i].Type == "string" {
+				if _, ok := v.(string); !ok {
+					v = fmt.Sprintf("%v", v)
+				}
+			}
+			row[i] = v
+		}
+	}
+
+	times := make([]int64, 0, len(rows))
+	for ts := range rows {
+		times = append(times, ts)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	if len(times) > 0 && b.w == nil {
+		w, err := b.cmd.createTableWriter(b.path, b.t.columns)
+		if err != nil {
+			return err
+		}
+		b.w = w
+	}
+
+	for _, ts := range times {
+		if err := b.w.WriteRow(rows[ts]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTables exports every measurement of each database and retention policy
+// to its own file in the output directory.
+func (cmd *Command) writeTables() error {
+	keys := make([]string, 0, len(cmd.manifest))
+	for key := range cmd.manifest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(cmd.Stdout, "writing out %s data for %s...", cmd.format, key)
+		if err := cmd.writeTablesFor(key); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.Stdout, "complete.")
+	}
+	return nil
+}
+
+// writeTablesFor exports the measurements of the TSM and WAL files of a
+// database and retention policy to <out>/<database>/<retention policy>.
+func (cmd *Command) writeTablesFor(key string) error {
+	dir := filepath.Join(cmd.out, key)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+
+	files := cmd.tsmFiles[key]
+	sort.Strings(files)
+
+	var readers []*tsm1.TSMReader
+	for _, path := range files {
+		r, err := cmd.openTSMFile(path)
+		if err != nil {
+			return err
+		} else if r == nil {
+			continue
+		}
+		defer r.Close()
+		readers = append(readers, r)
+	}
+
+	wal, err := cmd.readWALFiles(key)
+	if err != nil {
+		return err
+	}
+
+	// Collect the schema of every measurement so the columns are known
+	// before the first row is written.
+	schemas := make(map[string]*measurementSchema)
+	addKey := func(key []byte, typ string) {
+		seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+		name, tags := models.ParseKey(seriesKey)
+		s, ok := schemas[name]
+		if !ok {
+			s = &measurementSchema{
+				name:   []byte(name),
+				tags:   make(map[string]struct{}),
+				fields: make(map[string]string),
+			}
+			schemas[name] = s
+		}
+		for _, tag := range tags {
+			s.tags[string(tag.Key)] = struct{}{}
+		}
+		s.addField(string(field), typ)
+	}
+	for _, r := range readers {
+		for i := 0; i < r.KeyCount(); i++ {
+			key, typ := r.KeyAt(i)
+			addKey(key, blockTypeName(typ))
+		}
+	}
+	for key, values := range wal {
+		if len(values) > 0 {
+			addKey([]byte(key), valueTypeName(values[0].Value()))
+		}
+	}
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := cmd.writeTable(dir, schemas[name], readers, wal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTable exports the series of a single measurement.
+func (cmd *Command) writeTable(dir string, s *measurementSchema, readers []*tsm1.TSMReader, wal map[string][]tsm1.Value) error {
+	b := &seriesBuffer{
+		cmd:    cmd,
+		t:      newTable(s),
+		path:   filepath.Join(dir, url.PathEscape(string(escape.Unescape(s.name)))),
+		fields: make(map[string][]tsm1.Value),
+	}
+	err := cmd.writeTableRows(b, s, readers, wal)
+	if b.w != nil {
+		if cerr := b.w.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// writeTableRows writes the series of a measurement found in the TSM readers
+// and the WAL. The values of a field are merged across the TSM files, in the
+// order of the readers, and then the WAL, so the newest value of a timestamp
+// wins like in the storage engine.
+func (cmd *Command) writeTableRows(b *seriesBuffer, s *measurementSchema, readers []*tsm1.TSMReader, wal map[string][]tsm1.Value) error {
+	inMeasurement := func(key []byte) bool {
+		name, _ := models.ParseName(key)
+		return bytes.Equal(name, s.name)
+	}
+
+	keys := make(map[string]struct{})
+	for _, r := range readers {
+		// Keys are sorted so the keys of the measurement follow each other.
+		n := r.KeyCount()
+		i := sort.Search(n, func(i int) bool {
+			key, _ := r.KeyAt(i)
+			return bytes.Compare(key, s.name) >= 0
+		})
+		for ; i < n; i++ {
+			key, _ := r.KeyAt(i)
+			if !bytes.HasPrefix(key, s.name) {
+				break
+			} else if inMeasurement(key) {
+				keys[string(key)] = struct{}{}
+			}
+		}
+	}
+	for key := range wal {
+		if inMeasurement([]byte(key)) {
+			keys[key] = struct{}{}
+		}
+	}
+
+	// The fields of a series follow each other in the sorted keys.
+	for _, key := range sortedKeys(keys) {
+		var values tsm1.Values
+		for _, r := range readers {
+			if !r.Contains([]byte(key)) {
+				continue
+			}
+			v, err := r.ReadAll([]byte(key))
+			if err != nil {
+				fmt.Fprintf(cmd.Stderr, "unable to read key %q in %s, skipping: %s\n", key, r.Path(), err.Error())
+				continue
+			}
+			values = values.Merge(v)
+		}
+		values = values.Merge(wal[key])
+
+		seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey([]byte(key))
+		if err := b.add(seriesKey, field, values); err != nil {
+			return err
+		}
+	}
+	return b.flush()
+}
+
+// createTableWriter creates the file of a table named path, without an
+// extension, in the export format.
+func (cmd *Command) createTableWriter(path string, columns []parquetColumn) (tableWriter, error) {
+	switch cmd.format {
+	case formatCSV:
+		path += ".csv"
+		if cmd.compress {
+			path += ".gz"
+		}
+	case formatParquet:
+		path += ".parquet"
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var w tableWriter
+	if cmd.format == formatParquet {
+		var pw *parquetWriter
+		pw, err = newParquetWriter(f, columns, cmd.compress)
+		w = &parquetTableWriter{f: f, parquetWriter: pw}
+	} else {
+		w, err = newCSVTableWriter(f, columns, cmd.compress)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// openTSMFile opens a TSM file for reading. It returns nil if the file is
+// missing, unreadable or outside of the exported time range.
+func (cmd *Command) openTSMFile(path string) (*tsm1.TSMReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		fmt.Fprintf(cmd.Stderr, "unable to read %s, skipping: %s\n", path, err.Error())
+		return nil, nil
+	}
+
+	if min, max := r.TimeRange(); min > cmd.endTime || max < cmd.startTime {
+		r.Close()
+		return nil, nil
+	}
+	return r, nil
+}
+
+// readWALFiles returns the values written to the WAL files of a database and
+// retention policy, keyed by series and field.
+func (cmd *Command) readWALFiles(key string) (map[string][]tsm1.Value, error) {
+	files := cmd.walFiles[key]
+	sort.Strings(files)
+
+	var once sync.Once
+	values := make(map[string][]tsm1.Value)
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		r := tsm1.NewWALSegmentReader(f)
+		for r.Next() {
+			entry, err := r.Read()
+			if err != nil {
+				fmt.Fprintf(cmd.Stderr, "file %s corrupt at position %d\n", path, r.Count())
+				break
+			}
+
+			switch t := entry.(type) {
+			case *tsm1.DeleteWALEntry, *tsm1.DeleteRangeWALEntry:
+				once.Do(func() {
+					fmt.Fprintf(cmd.Stderr, "WARNING: detected deletes in wal file, some series for %q may be brought back by this export.\n", key)
+				})
+			case *tsm1.WriteWALEntry:
+				for k, v := range t.Values {
+					values[k] = append(values[k], v...)
+				}
+			}
+		}
+		r.Close()
+	}
+
+	for k, v := range values {
+		values[k] = tsm1.Values(v).Deduplicate()
+	}
+	return values, nil
+}
+
+// blockTypeName returns the field type of a TSM block type.
+func blockTypeName(typ byte) string {
+	switch typ {
+	case tsm1.BlockFloat64:
+		return "float"
+	case tsm1.BlockInteger:
+		return "integer"
+	case tsm1.BlockUnsigned:
+		return "unsigned"
+	case tsm1.BlockBoolean:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// valueTypeName returns the field type of a value.
+func valueTypeName(v interface{}) string {
+	switch v.(type) {
+	case float64:
+		return "float"
+	case int64:
+		return "integer"
+	case uint64:
+		return "unsigned"
+	case bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,232 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+var (
+	tableTSMCorpus = corpus{
+		tsm1.SeriesFieldKey("cpu,host=a", "f"): []tsm1.Value{
+			tsm1.NewValue(1, float64(1.5)),
+		},
+		tsm1.SeriesFieldKey("cpu,host=a", "i"): []tsm1.Value{
+			tsm1.NewValue(1, int64(2)),
+			tsm1.NewValue(2, int64(3)),
+		},
+		tsm1.SeriesFieldKey("cpu,host=b", "f"): []tsm1.Value{
+			tsm1.NewValue(2, float64(2)),
+		},
+		tsm1.SeriesFieldKey(`disk\ io,host=a`, "s"): []tsm1.Value{
+			tsm1.NewValue(5, `a "b", c`),
+		},
+	}
+
+	tableWALCorpus = corpus{
+		tsm1.SeriesFieldKey("cpu,host=c,region=west", "f"): []tsm1.Value{
+			tsm1.NewValue(3, float64(3)),
+		},
+	}
+)
+
+func Test_exportCSV(t *testing.T) {
+	cmd, dir := newTableCommand(t, formatCSV)
+	defer os.RemoveAll(dir)
+
+	if err := cmd.export(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, exp := range map[string]string{
+		"cpu.csv": `time,host,region,f,i
+1970-01-01T00:00:00.000000001Z,a,,1.5,2
+1970-01-01T00:00:00.000000002Z,a,,,3
+1970-01-01T00:00:00.000000002Z,b,,2,
+1970-01-01T00:00:00.000000003Z,c,west,3,
+`,
+		"disk%20io.csv": `time,host,s
+1970-01-01T00:00:00.000000005Z,a,"a ""b"", c"
+`,
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(cmd.out, "db", "rp", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b); got != exp {
+			t.Errorf("unexpected %s.  expected %q, actual %q", name, exp, got)
+		}
+	}
+}
+
+func Test_exportCSV_TimeRange(t *testing.T) {
+	cmd, dir := newTableCommand(t, formatCSV)
+	defer os.RemoveAll(dir)
+	cmd.startTime, cmd.endTime = 2, 2
+
+	if err := cmd.export(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(cmd.out, "db", "rp", "cpu.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `time,host,region,f,i
+1970-01-01T00:00:00.000000002Z,a,,,3
+1970-01-01T00:00:00.000000002Z,b,,2,
+`
+	if got := string(b); got != exp {
+		t.Errorf("unexpected cpu.csv.  expected %q, actual %q", exp, got)
+	}
+
+	// The TSM file of disk io is outside of the time range.
+	if _, err := os.Stat(filepath.Join(cmd.out, "db", "rp", "disk%20io.csv")); !os.IsNotExist(err) {
+		t.Errorf("unexpected disk io export: %v", err)
+	}
+}
+
+// Ensure the values of a series split over TSM files and the WAL are merged,
+// the newest value of a timestamp winning.
+func Test_exportCSV_MergeFiles(t *testing.T) {
+	cmd, dir := newTableCommand(t, formatCSV)
+	defer os.RemoveAll(dir)
+
+	moveFile(t, writeCorpusToTSMFile(corpus{
+		tsm1.SeriesFieldKey("cpu,host=a", "f"): []tsm1.Value{
+			tsm1.NewValue(1, float64(9.5)),
+			tsm1.NewValue(4, float64(4)),
+		},
+		tsm1.SeriesFieldKey("cpu,host=a", "i"): []tsm1.Value{
+			tsm1.NewValue(3, int64(5)),
+		},
+	}), filepath.Join(cmd.dataDir, "db", "rp", "1", "000000002-000000001."+tsm1.TSMFileExtension))
+	moveFile(t, writeCorpusToWALFile(corpus{
+		tsm1.SeriesFieldKey("cpu,host=a", "f"): []tsm1.Value{
+			tsm1.NewValue(4, float64(8)),
+		},
+	}), filepath.Join(cmd.walDir, "db", "rp", "1", "_00002."+tsm1.WALFileExtension))
+
+	if err := cmd.export(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(cmd.out, "db", "rp", "cpu.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `time,host,region,f,i
+1970-01-01T00:00:00.000000001Z,a,,9.5,2
+1970-01-01T00:00:00.000000002Z,a,,,3
+1970-01-01T00:00:00.000000003Z,a,,,5
+1970-01-01T00:00:00.000000004Z,a,,8,
+1970-01-01T00:00:00.000000002Z,b,,2,
+1970-01-01T00:00:00.000000003Z,c,west,3,
+`
+	if got := string(b); got != exp {
+		t.Errorf("unexpected cpu.csv.  expected %q, actual %q", exp, got)
+	}
+}
+
+func Test_exportParquet(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		cmd, dir := newTableCommand(t, formatParquet)
+		cmd.compress = compress
+
+		if err := cmd.export(); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(cmd.out, "db", "rp", "cpu.parquet"))
+		os.RemoveAll(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(b) < 12 || !bytes.HasPrefix(b, []byte(parquetMagic)) || !bytes.HasSuffix(b, []byte(parquetMagic)) {
+			t.Fatalf("missing parquet magic: %q", b)
+		}
+		n := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+		if n <= 0 || n > len(b)-12 {
+			t.Fatalf("invalid footer length: %d", n)
+		}
+		footer := b[len(b)-8-n : len(b)-8]
+		for _, s := range []string{"time", "host", "region", "f", "i", "influx_inspect export"} {
+			if !bytes.Contains(footer, []byte(s)) {
+				t.Errorf("footer is missing %q", s)
+			}
+		}
+	}
+}
+
+func Test_validateFormat(t *testing.T) {
+	cmd := newCommand()
+	cmd.format = "xml"
+	if err := cmd.validate(); err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewTable(t *testing.T) {
+	s := &measurementSchema{
+		name:   []byte("m"),
+		tags:   map[string]struct{}{"b": {}, "a": {}},
+		fields: make(map[string]string),
+	}
+	s.addField("x", "float")
+	s.addField("x", "integer")
+	s.addField("a", "boolean")
+
+	tbl := newTable(s)
+	var names, types []string
+	for _, col := range tbl.columns {
+		names = append(names, col.Name)
+		types = append(types, col.Type)
+	}
+	if got, exp := strings.Join(names, ","), "time,a,b,a_1,x"; got != exp {
+		t.Errorf("unexpected columns.  expected %s, actual %s", exp, got)
+	}
+	if got, exp := strings.Join(types, ","), "time,string,string,boolean,string"; got != exp {
+		t.Errorf("unexpected column types.  expected %s, actual %s", exp, got)
+	}
+}
+
+// newTableCommand returns a command exporting a data directory holding
+// tableTSMCorpus and a WAL directory holding tableWALCorpus, both in db/rp.
+// It is the caller's responsibility to remove the returned directory.
+func newTableCommand(t *testing.T, format string) (*Command, string) {
+	dir, err := ioutil.TempDir("", "export_test_table")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewCommand()
+	cmd.Stdout, cmd.Stderr = ioutil.Discard, ioutil.Discard
+	cmd.startTime, cmd.endTime = newCommand().startTime, newCommand().endTime
+	cmd.dataDir = filepath.Join(dir, "data")
+	cmd.walDir = filepath.Join(dir, "wal")
+	cmd.out = filepath.Join(dir, "out")
+	cmd.format = format
+
+	moveFile(t, writeCorpusToTSMFile(tableTSMCorpus), filepath.Join(cmd.dataDir, "db", "rp", "1", "000000001-000000001."+tsm1.TSMFileExtension))
+	moveFile(t, writeCorpusToWALFile(tableWALCorpus), filepath.Join(cmd.walDir, "db", "rp", "1", "_00001."+tsm1.WALFileExtension))
+	return cmd, dir
+}
+
+// moveFile closes f and moves it to path.
+func moveFile(t *testing.T, f *os.File, path string) {
+	f.Close()
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		t.Fatal(err)
+	}
+}
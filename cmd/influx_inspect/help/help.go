@@ -33,6 +33,7 @@ The commands are:
 
     dumptsi              dumps low-level details about tsi1 files.
     dumptsm              dumps low-level details about tsm1 files.
+    dumpwal              dumps and repairs tsm1 WAL segment files.
     export               exports raw data from a shard to line protocol
     inmem2tsi            generates a tsi1 index from an in-memory index shard
     help                 display this help message
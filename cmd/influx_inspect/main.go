@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/influxdb/cmd"
 	"github.com/influxdata/influxdb/cmd/influx_inspect/dumptsi"
 	"github.com/influxdata/influxdb/cmd/influx_inspect/dumptsm"
+	"github.com/influxdata/influxdb/cmd/influx_inspect/dumpwal"
 	"github.com/influxdata/influxdb/cmd/influx_inspect/export"
 	"github.com/influxdata/influxdb/cmd/influx_inspect/help"
 	"github.com/influxdata/influxdb/cmd/influx_inspect/inmem2tsi"
@@ -68,6 +69,11 @@ func (m *Main) Run(args ...string) error {
 		if err := name.Run(args...); err != nil {
 			return fmt.Errorf("dumptsm: %s", err)
 		}
+	case "dumpwal":
+		name := dumpwal.NewCommand()
+		if err := name.Run(args...); err != nil {
+			return fmt.Errorf("dumpwal: %s", err)
+		}
 	case "export":
 		name := export.NewCommand()
 		if err := name.Run(args...); err != nil {
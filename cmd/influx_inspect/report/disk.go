@@ -0,0 +1,364 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+// Sort orders of the disk usage report.
+const (
+	sortByBytes  = "bytes"
+	sortByPoints = "points"
+	sortBySeries = "series"
+	sortByName   = "name"
+)
+
+// Output formats of the disk usage report.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// diskReport is the disk usage of the measurements found in TSM files.
+type diskReport struct {
+	Files        int                  `json:"files"`
+	Bytes        int64                `json:"bytes"`
+	Points       int64                `json:"points"`
+	Measurements []*measurementReport `json:"measurements"`
+}
+
+// measurementReport is the disk usage of a measurement, summed across the
+// shards and retention policies of its database.
+type measurementReport struct {
+	Database    string         `json:"database"`
+	Measurement string         `json:"measurement"`
+	Shards      int            `json:"shards"`
+	Series      uint64         `json:"series"`
+	Bytes       int64          `json:"bytes"`
+	Points      int64          `json:"points"`
+	Fields      []*fieldReport `json:"fields"`
+	Tags        []*tagReport   `json:"tags"`
+
+	shards map[string]struct{}
+	series counter
+	fields map[string]*fieldReport
+	tags   map[string]counter
+}
+
+// fieldReport is the disk usage of a field of a measurement.
+type fieldReport struct {
+	database, measurement string
+
+	Field  string `json:"field"`
+	Type   string `json:"type"`
+	Bytes  int64  `json:"bytes"`
+	Points int64  `json:"points"`
+}
+
+// tagReport is the cardinality of a tag key of a measurement.
+type tagReport struct {
+	database, measurement string
+
+	Key    string `json:"key"`
+	Values uint64 `json:"values"`
+}
+
+// runDiskReport reports the compressed bytes and the number of points of
+// every measurement and field, and the cardinality of their tag keys.
+func (cmd *Command) runDiskReport(newCounterFn func() counter) error {
+	switch cmd.sortBy {
+	case sortByBytes, sortByPoints, sortBySeries, sortByName:
+	default:
+		return fmt.Errorf("unknown sort order %q", cmd.sortBy)
+	}
+	switch cmd.format {
+	case formatTable, formatJSON:
+	default:
+		return fmt.Errorf("unknown format %q", cmd.format)
+	}
+
+	report := &diskReport{}
+	measurements := make(map[string]*measurementReport)
+	if err := cmd.WalkShardDirs(cmd.dir, func(db, rp, id, path string) error {
+		if cmd.pattern != "" && !strings.Contains(path, cmd.pattern) {
+			return nil
+		}
+
+		file, err := os.OpenFile(path, os.O_RDONLY, 0600)
+		if err != nil {
+			fmt.Fprintf(cmd.Stderr, "error: %s: %v. Skipping.\n", path, err)
+			return nil
+		}
+
+		reader, err := tsm1.NewTSMReader(file)
+		if err != nil {
+			fmt.Fprintf(cmd.Stderr, "error: %s: %v. Skipping.\n", file.Name(), err)
+			return nil
+		}
+		defer reader.Close()
+		report.Files++
+
+		var m *measurementReport
+		var prevSeries []byte
+		itr := reader.BlockIterator()
+		for itr.Next() {
+			key, _, _, typ, _, buf, err := itr.Read()
+			if err != nil {
+				fmt.Fprintf(cmd.Stderr, "error: %s: %v. Skipping.\n", path, err)
+				return nil
+			}
+
+			n, err := blockCount(buf)
+			if err != nil {
+				fmt.Fprintf(cmd.Stderr, "error: %s: key %q: %v. Skipping block.\n", path, key, err)
+				continue
+			}
+
+			seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+			if !bytes.Equal(seriesKey, prevSeries) {
+				prevSeries = append(prevSeries[:0], seriesKey...)
+
+				name, tags := models.ParseKey(seriesKey)
+				m = measurements[db+"\x00"+name]
+				if m == nil {
+					m = &measurementReport{
+						Database:    db,
+						Measurement: name,
+						shards:      make(map[string]struct{}),
+						series:      newCounterFn(),
+						fields:      make(map[string]*fieldReport),
+						tags:        make(map[string]counter),
+					}
+					measurements[db+"\x00"+name] = m
+				}
+				m.shards[rp+"/"+id] = struct{}{}
+				m.series.Add(seriesKey)
+
+				for _, t := range tags {
+					c := m.tags[string(t.Key)]
+					if c == nil {
+						c = newCounterFn()
+						m.tags[string(t.Key)] = c
+					}
+					c.Add(t.Value)
+				}
+			}
+
+			f := m.fields[string(field)]
+			if f == nil {
+				f = &fieldReport{database: db, measurement: m.Measurement, Field: string(field), Type: blockTypeName(typ)}
+				m.fields[string(field)] = f
+			} else if f.Type != blockTypeName(typ) {
+				f.Type = "mixed"
+			}
+
+			// The size on disk includes the checksum preceding the block.
+			size := int64(len(buf) + 4)
+			f.Bytes += size
+			f.Points += int64(n)
+			m.Bytes += size
+			m.Points += int64(n)
+			report.Bytes += size
+			report.Points += int64(n)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, m := range measurements {
+		m.Shards = len(m.shards)
+		m.Series = m.series.Count()
+		for _, f := range m.fields {
+			m.Fields = append(m.Fields, f)
+		}
+		for k, c := range m.tags {
+			m.Tags = append(m.Tags, &tagReport{database: m.Database, measurement: m.Measurement, Key: k, Values: c.Count()})
+		}
+		cmd.sortFields(m.Fields)
+		cmd.sortTags(m.Tags)
+		report.Measurements = append(report.Measurements, m)
+	}
+	cmd.sortMeasurements(report.Measurements)
+
+	if cmd.format == formatJSON {
+		if cmd.top > 0 && len(report.Measurements) > cmd.top {
+			report.Measurements = report.Measurements[:cmd.top]
+		}
+		enc := json.NewEncoder(cmd.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return cmd.printDiskReport(report)
+}
+
+// printDiskReport prints the measurements, fields and tag keys of the report
+// as tables.
+func (cmd *Command) printDiskReport(report *diskReport) error {
+	var fields []*fieldReport
+	var tags []*tagReport
+	for _, m := range report.Measurements {
+		fields = append(fields, m.Fields...)
+		tags = append(tags, m.Tags...)
+	}
+	cmd.sortFields(fields)
+	cmd.sortTags(tags)
+
+	percent := func(n int64) string {
+		if report.Bytes == 0 {
+			return "0.0"
+		}
+		return strconv.FormatFloat(float64(n)/float64(report.Bytes)*100, 'f', 1, 64)
+	}
+	perPoint := func(n, points int64) string {
+		if points == 0 {
+			return "0.00"
+		}
+		return strconv.FormatFloat(float64(n)/float64(points), 'f', 2, 64)
+	}
+
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 2, 1, ' ', 0)
+	fmt.Fprintln(tw, "Measurements:")
+	fmt.Fprintln(tw, strings.Join([]string{"DB", "Measurement", "Shards", "Series", "Fields", "Bytes", "%", "Points", "Bytes/Point"}, "\t"))
+	for i, m := range report.Measurements {
+		if cmd.top > 0 && i >= cmd.top {
+			break
+		}
+		fmt.Fprintln(tw, strings.Join([]string{
+			m.Database, m.Measurement,
+			strconv.Itoa(m.Shards),
+			strconv.FormatUint(m.Series, 10),
+			strconv.Itoa(len(m.Fields)),
+			strconv.FormatInt(m.Bytes, 10),
+			percent(m.Bytes),
+			strconv.FormatInt(m.Points, 10),
+			perPoint(m.Bytes, m.Points),
+		}, "\t"))
+	}
+
+	fmt.Fprintln(tw, "\nFields:")
+	fmt.Fprintln(tw, strings.Join([]string{"DB", "Measurement", "Field", "Type", "Bytes", "%", "Points", "Bytes/Point"}, "\t"))
+	for i, f := range fields {
+		if cmd.top > 0 && i >= cmd.top {
+			break
+		}
+		fmt.Fprintln(tw, strings.Join([]string{
+			f.database, f.measurement, f.Field, f.Type,
+			strconv.FormatInt(f.Bytes, 10),
+			percent(f.Bytes),
+			strconv.FormatInt(f.Points, 10),
+			perPoint(f.Bytes, f.Points),
+		}, "\t"))
+	}
+
+	fmt.Fprintln(tw, "\nTag Keys:")
+	fmt.Fprintln(tw, strings.Join([]string{"DB", "Measurement", "Tag Key", "Values"}, "\t"))
+	for i, t := range tags {
+		if cmd.top > 0 && i >= cmd.top {
+			break
+		}
+		fmt.Fprintln(tw, strings.Join([]string{t.database, t.measurement, t.Key, strconv.FormatUint(t.Values, 10)}, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.Stdout, "\nFiles: %d, Bytes: %d, Points: %d\n", report.Files, report.Bytes, report.Points)
+	return nil
+}
+
+// sortMeasurements sorts measurements by the sort order of the command.
+// Numbers are sorted in descending order and ties are sorted by name.
+func (cmd *Command) sortMeasurements(a []*measurementReport) {
+	sort.Slice(a, func(i, j int) bool {
+		x, y := a[i], a[j]
+		switch {
+		case cmd.sortBy == sortByBytes && x.Bytes != y.Bytes:
+			return x.Bytes > y.Bytes
+		case cmd.sortBy == sortByPoints && x.Points != y.Points:
+			return x.Points > y.Points
+		case cmd.sortBy == sortBySeries && x.Series != y.Series:
+			return x.Series > y.Series
+		case x.Database != y.Database:
+			return x.Database < y.Database
+		default:
+			return x.Measurement < y.Measurement
+		}
+	})
+}
+
+// sortFields sorts fields by the sort order of the command. Fields are sorted
+// by bytes when sorting by series.
+func (cmd *Command) sortFields(a []*fieldReport) {
+	sort.Slice(a, func(i, j int) bool {
+		x, y := a[i], a[j]
+		switch {
+		case (cmd.sortBy == sortByBytes || cmd.sortBy == sortBySeries) && x.Bytes != y.Bytes:
+			return x.Bytes > y.Bytes
+		case cmd.sortBy == sortByPoints && x.Points != y.Points:
+			return x.Points > y.Points
+		case x.database != y.database:
+			return x.database < y.database
+		case x.measurement != y.measurement:
+			return x.measurement < y.measurement
+		default:
+			return x.Field < y.Field
+		}
+	})
+}
+
+// sortTags sorts tag keys by descending cardinality, or by name when sorting
+// by name.
+func (cmd *Command) sortTags(a []*tagReport) {
+	sort.Slice(a, func(i, j int) bool {
+		x, y := a[i], a[j]
+		switch {
+		case cmd.sortBy != sortByName && x.Values != y.Values:
+			return x.Values > y.Values
+		case x.database != y.database:
+			return x.database < y.database
+		case x.measurement != y.measurement:
+			return x.measurement < y.measurement
+		default:
+			return x.Key < y.Key
+		}
+	})
+}
+
+// blockCount returns the number of points in a block, or an error if the
+// block is corrupt.
+func blockCount(buf []byte) (n int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return tsm1.BlockCount(buf), nil
+}
+
+// blockTypeName returns the field type of a TSM block type.
+func blockTypeName(typ byte) string {
+	switch typ {
+	case tsm1.BlockFloat64:
+		return "float"
+	case tsm1.BlockInteger:
+		return "integer"
+	case tsm1.BlockUnsigned:
+		return "unsigned"
+	case tsm1.BlockBoolean:
+		return "boolean"
+	case tsm1.BlockString:
+		return "string"
+	default:
+		return "unknown"
+	}
+}
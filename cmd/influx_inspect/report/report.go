@@ -27,6 +27,11 @@ type Command struct {
 	dir             string
 	pattern         string
 	detailed, exact bool
+
+	disk   bool
+	sortBy string
+	format string
+	top    int
 }
 
 // NewCommand returns a new instance of Command.
@@ -43,6 +48,10 @@ func (cmd *Command) Run(args ...string) error {
 	fs.StringVar(&cmd.pattern, "pattern", "", "Include only files matching a pattern")
 	fs.BoolVar(&cmd.detailed, "detailed", false, "Report detailed cardinality estimates")
 	fs.BoolVar(&cmd.exact, "exact", false, "Report exact counts")
+	fs.BoolVar(&cmd.disk, "disk", false, "Report disk usage by measurement, field and tag key")
+	fs.StringVar(&cmd.sortBy, "sort", sortByBytes, "Sort the disk usage report by bytes, points, series or name")
+	fs.StringVar(&cmd.format, "format", formatTable, "Output format of the disk usage report: table or json")
+	fs.IntVar(&cmd.top, "top", 0, "Limit the disk usage report to the top n rows")
 
 	fs.SetOutput(cmd.Stdout)
 	fs.Usage = cmd.printUsage
@@ -60,6 +69,10 @@ func (cmd *Command) Run(args ...string) error {
 
 	cmd.dir = fs.Arg(0)
 
+	if cmd.disk {
+		return cmd.runDiskReport(newCounterFn)
+	}
+
 	err := cmd.isShardDir(cmd.dir)
 	if cmd.detailed && err != nil {
 		return fmt.Errorf("-detailed only supported for shard dirs.")
@@ -80,7 +93,7 @@ func (cmd *Command) Run(args ...string) error {
 	minTime, maxTime := int64(math.MaxInt64), int64(math.MinInt64)
 	var fileCount int
 	if err := cmd.WalkShardDirs(cmd.dir, func(db, rp, id, path string) error {
-		if cmd.pattern != "" && strings.Contains(path, cmd.pattern) {
+		if cmd.pattern != "" && !strings.Contains(path, cmd.pattern) {
 			return nil
 		}
 
@@ -280,7 +293,7 @@ func (cmd *Command) WalkShardDirs(root string, fn func(db, rp, id, path string)
 func (cmd *Command) printUsage() {
 	usage := `Displays shard level report.
 
-Usage: influx_inspect report [flags]
+Usage: influx_inspect report [flags] <path>
 
     -pattern <pattern>
             Include only files matching a pattern.
@@ -290,6 +303,20 @@ Usage: influx_inspect report [flags]
     -detailed
             Report detailed cardinality estimates.
             Defaults to "false".
+    -disk
+            Report the compressed bytes and points of every measurement and
+            field, and the cardinality of their tag keys, across shards and
+            retention policies.
+            Defaults to "false".
+    -sort <bytes|points|series|name>
+            Sort order of the disk usage report.
+            Defaults to "bytes".
+    -format <table|json>
+            Output format of the disk usage report.
+            Defaults to "table".
+    -top <n>
+            Limit each table of the disk usage report, or its measurements
+            in JSON, to the first n rows.  Defaults to 0, no limit.
 `
 
 	fmt.Fprintf(cmd.Stdout, usage)
@@ -1,3 +1,200 @@
 package report_test
 
-// TODO: write some tests
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/cmd/influx_inspect/report"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+// Ensure the report includes only the files matching the pattern.
+func TestCommand_Pattern(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	MustWriteTSM(filepath.Join(dir, "db0", "autogen", "1", "000000001-000000001.tsm"), map[string][]tsm1.Value{
+		"cpu,host=a#!~#value": {tsm1.NewValue(0, 1.0)},
+	})
+	MustWriteTSM(filepath.Join(dir, "db1", "autogen", "2", "000000001-000000001.tsm"), map[string][]tsm1.Value{
+		"mem,host=a#!~#free": {tsm1.NewValue(0, int64(1))},
+	})
+
+	for _, args := range [][]string{{"-pattern", "db1"}, {"-disk", "-pattern", "db1"}} {
+		var buf bytes.Buffer
+		cmd := report.NewCommand()
+		cmd.Stdout, cmd.Stderr = &buf, ioutil.Discard
+		if err := cmd.Run(append(args, dir)...); err != nil {
+			t.Fatal(err)
+		}
+
+		out := buf.String()
+		if !strings.Contains(out, "db1") || strings.Contains(out, "db0") {
+			t.Fatalf("unexpected report with %v:\n%s", args, out)
+		}
+	}
+}
+
+func TestCommand_Disk_JSON(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	MustWriteTSM(filepath.Join(dir, "db0", "autogen", "1", "000000001-000000001.tsm"), map[string][]tsm1.Value{
+		"cpu,host=a#!~#value": {tsm1.NewValue(0, 1.0), tsm1.NewValue(1, 2.0)},
+		"cpu,host=b#!~#value": {tsm1.NewValue(0, 3.0)},
+		"mem,host=a#!~#free":  {tsm1.NewValue(0, int64(1))},
+	})
+	MustWriteTSM(filepath.Join(dir, "db0", "rp1", "2", "000000001-000000001.tsm"), map[string][]tsm1.Value{
+		"cpu,host=c,region=west#!~#value": {tsm1.NewValue(0, 4.0)},
+		"cpu,host=c,region=west#!~#idle":  {tsm1.NewValue(0, true)},
+	})
+
+	var buf bytes.Buffer
+	cmd := report.NewCommand()
+	cmd.Stdout, cmd.Stderr = &buf, ioutil.Discard
+	if err := cmd.Run("-disk", "-exact", "-format", "json", "-sort", "points", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Files        int
+		Bytes        int64
+		Points       int64
+		Measurements []struct {
+			Database    string
+			Measurement string
+			Shards      int
+			Series      uint64
+			Bytes       int64
+			Points      int64
+			Fields      []struct {
+				Field  string
+				Type   string
+				Bytes  int64
+				Points int64
+			}
+			Tags []struct {
+				Key    string
+				Values uint64
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+		t.Fatalf("unexpected error: %s: %s", err, buf.String())
+	}
+
+	if r.Files != 2 || r.Points != 6 {
+		t.Fatalf("unexpected totals: files=%d points=%d", r.Files, r.Points)
+	} else if len(r.Measurements) != 2 {
+		t.Fatalf("unexpected measurements: %d", len(r.Measurements))
+	}
+
+	cpu, mem := r.Measurements[0], r.Measurements[1]
+	if cpu.Measurement != "cpu" || cpu.Shards != 2 || cpu.Series != 3 || cpu.Points != 5 {
+		t.Fatalf("unexpected cpu report: %+v", cpu)
+	} else if mem.Measurement != "mem" || mem.Series != 1 || mem.Points != 1 {
+		t.Fatalf("unexpected mem report: %+v", mem)
+	} else if cpu.Bytes+mem.Bytes != r.Bytes {
+		t.Fatalf("unexpected bytes: %d + %d != %d", cpu.Bytes, mem.Bytes, r.Bytes)
+	}
+
+	if len(cpu.Fields) != 2 {
+		t.Fatalf("unexpected cpu fields: %+v", cpu.Fields)
+	} else if f := cpu.Fields[0]; f.Field != "value" || f.Type != "float" || f.Points != 4 {
+		t.Fatalf("unexpected value field: %+v", f)
+	} else if f := cpu.Fields[1]; f.Field != "idle" || f.Type != "boolean" || f.Points != 1 {
+		t.Fatalf("unexpected idle field: %+v", f)
+	}
+
+	if len(cpu.Tags) != 2 {
+		t.Fatalf("unexpected cpu tags: %+v", cpu.Tags)
+	} else if tag := cpu.Tags[0]; tag.Key != "host" || tag.Values != 3 {
+		t.Fatalf("unexpected host tag: %+v", tag)
+	} else if tag := cpu.Tags[1]; tag.Key != "region" || tag.Values != 1 {
+		t.Fatalf("unexpected region tag: %+v", tag)
+	}
+}
+
+func TestCommand_Disk_Table(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	MustWriteTSM(filepath.Join(dir, "db0", "autogen", "1", "000000001-000000001.tsm"), map[string][]tsm1.Value{
+		"cpu,host=a#!~#value": {tsm1.NewValue(0, 1.0), tsm1.NewValue(1, 2.0)},
+		"mem,host=a#!~#free":  {tsm1.NewValue(0, int64(1))},
+	})
+
+	var buf bytes.Buffer
+	cmd := report.NewCommand()
+	cmd.Stdout, cmd.Stderr = &buf, ioutil.Discard
+	if err := cmd.Run("-disk", "-sort", "name", "-top", "1", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{"Measurements:", "Fields:", "Tag Keys:", "Files: 1,"} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("expected %q in output:\n%s", s, out)
+		}
+	}
+	if !strings.Contains(out, "cpu") || strings.Contains(out, "mem") {
+		t.Fatalf("unexpected top rows:\n%s", out)
+	}
+}
+
+func TestCommand_Disk_InvalidSort(t *testing.T) {
+	cmd := report.NewCommand()
+	cmd.Stdout, cmd.Stderr = ioutil.Discard, ioutil.Discard
+	if err := cmd.Run("-disk", "-sort", "size", "."); err == nil || err.Error() != `unknown sort order "size"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// MustTempDir returns a temporary directory. Panic on error.
+func MustTempDir() string {
+	dir, err := ioutil.TempDir("", "influx_inspect-report-")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+// MustWriteTSM writes values to a TSM file at path. Panic on error.
+func MustWriteTSM(path string, values map[string][]tsm1.Value) {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		panic(err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		panic(err)
+	}
+
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		panic(err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := w.Write([]byte(k), values[k]); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		panic(err)
+	} else if err := w.Close(); err != nil {
+		panic(err)
+	}
+}
@@ -4,11 +4,16 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"runtime/pprof"
+	"strings"
+	"time"
 
 	"github.com/influxdata/influxdb/stress"
+	"github.com/influxdata/influxdb/stress/replay"
+	"github.com/influxdata/influxdb/stress/report"
 	v2 "github.com/influxdata/influxdb/stress/v2"
 )
 
@@ -17,6 +22,20 @@ var (
 	config     = flag.String("config", "", "The stress test file")
 	cpuprofile = flag.String("cpuprofile", "", "Write the cpu profile to `filename`")
 	db         = flag.String("db", "", "target database within test system for write and query load")
+
+	replayWrites      = flag.String("replay-writes", "", "Comma separated list of line protocol files to replay")
+	replayQueries     = flag.String("replay-queries", "", "HTTP access log of the queries to replay")
+	replayHost        = flag.String("replay-host", "http://localhost:8086", "URL of the server to replay traffic against")
+	replaySpeed       = flag.Float64("replay-speed", 1, "Speed of the replay relative to the recording, as fast as possible if 0")
+	replayPrecision   = flag.String("replay-precision", "ns", "Precision of the timestamps of the replayed line protocol")
+	replayBatchSize   = flag.Int("replay-batch-size", replay.DefaultBatchSize, "Maximum number of points of a replayed write")
+	replayConcurrency = flag.Int("replay-concurrency", 0, "Maximum number of replayed requests in flight, unlimited if 0")
+
+	reportFile      = flag.String("report", "", "Write the report of the test to `filename`, or to stdout if -")
+	reportFormat    = flag.String("report-format", report.JSONFormat, "Format of the report: json, csv or text")
+	reportTimeline  = flag.String("report-timeline", "", "Write the throughput timeline of the test as CSV to `filename`")
+	reportBaseline  = flag.String("report-baseline", "", "Compare the report with the JSON report of a previous test in `filename`")
+	reportThreshold = flag.Float64("report-threshold", 0.1, "Fraction by which a latency or throughput must be worse than the baseline to fail")
 )
 
 func main() {
@@ -33,11 +52,21 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
-	if *useV2 {
+	var rep *report.Report
+	if *replayWrites != "" || *replayQueries != "" {
+		r, err := runReplay()
+		if r != nil {
+			r.WriteText(os.Stdout)
+			rep = r.Report
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else if *useV2 {
 		if *config != "" {
-			v2.RunStress(*config)
+			rep = v2.RunStress(*config)
 		} else {
-			v2.RunStress("stress/v2/iql/file.iql")
+			rep = v2.RunStress("stress/v2/iql/file.iql")
 		}
 	} else {
 
@@ -57,15 +86,115 @@ func main() {
 		r := stress.NewQuerier(&c.Read.QueryGenerators.Basic, &c.Read.QueryClients.Basic)
 		s := stress.NewStressTest(&c.Provision.Basic, w, r)
 
+		rep = report.New(time.Now())
+
 		bw := stress.NewBroadcastChannel()
 		bw.Register(c.Write.InfluxClients.Basic.BasicWriteHandler)
 		bw.Register(o.HTTPHandler("write"))
+		bw.Register(stress.ReportHandler(rep, "write", "write", c.Write.InfluxClients.Basic.BatchSize))
 
 		br := stress.NewBroadcastChannel()
 		br.Register(c.Read.QueryClients.Basic.BasicReadHandler)
 		br.Register(o.HTTPHandler("read"))
+		br.Register(stress.ReportHandler(rep, "query", "query", 0))
 
 		s.Start(bw.Handle, br.Handle)
+		rep.Duration = time.Since(rep.Start)
+
+	}
+
+	if err := writeReport(rep); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeReport writes the report and its timeline to the files of the flags,
+// and compares it with the baseline report.
+func writeReport(rep *report.Report) error {
+	if *reportFile != "" {
+		if err := writeFile(*reportFile, func(w io.Writer) error { return rep.Write(w, *reportFormat) }); err != nil {
+			return err
+		}
+	}
+	if *reportTimeline != "" {
+		if err := writeFile(*reportTimeline, rep.WriteTimelineCSV); err != nil {
+			return err
+		}
+	}
+
+	if *reportBaseline != "" {
+		f, err := os.Open(*reportBaseline)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		base, err := report.ReadJSON(f)
+		if err != nil {
+			return fmt.Errorf("reading baseline report: %s", err)
+		}
+
+		if regressions := rep.Compare(base, *reportThreshold); len(regressions) > 0 {
+			for _, r := range regressions {
+				fmt.Fprintln(os.Stderr, r)
+			}
+			return fmt.Errorf("%d regressions from %s", len(regressions), *reportBaseline)
+		}
+	}
+	return nil
+}
+
+// writeFile writes to the file at path with fn, or to stdout if path is -.
+func writeFile(path string, fn func(w io.Writer) error) error {
+	if path == "-" {
+		return fn(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runReplay replays the recorded writes and queries.
+func runReplay() (*replay.Report, error) {
+	var writes []io.Reader
+	if *replayWrites != "" {
+		if *db == "" {
+			return nil, fmt.Errorf("-db is required to replay writes")
+		}
+		for _, path := range strings.Split(*replayWrites, ",") {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			writes = append(writes, f)
+		}
+	}
 
+	var queries io.Reader
+	if *replayQueries != "" {
+		f, err := os.Open(*replayQueries)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		queries = f
 	}
+
+	r := replay.NewReplayer(*replayHost)
+	r.Username = os.Getenv("INFLUX_USERNAME")
+	r.Password = os.Getenv("INFLUX_PASSWORD")
+	r.Database = *db
+	r.Precision = *replayPrecision
+	r.BatchSize = *replayBatchSize
+	r.Speed = *replaySpeed
+	r.Concurrency = *replayConcurrency
+
+	return r.Replay(writes, queries)
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/influxdata/influxdb/cmd"
 	"github.com/influxdata/influxdb/cmd/influxd/backup"
 	"github.com/influxdata/influxdb/cmd/influxd/help"
+	"github.com/influxdata/influxdb/cmd/influxd/meta"
 	"github.com/influxdata/influxdb/cmd/influxd/restore"
 	"github.com/influxdata/influxdb/cmd/influxd/run"
 	"github.com/uber-go/zap"
@@ -92,10 +93,25 @@ func (m *Main) Run(args ...string) error {
 
 		signalCh := make(chan os.Signal, 1)
 		signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+		reloadCh := make(chan os.Signal, 1)
+		signal.Notify(reloadCh, syscall.SIGHUP)
 		m.Logger.Info("Listening for signals")
 
-		// Block until one of the signals above is received
-		<-signalCh
+		// Block until one of the signals above is received, reloading the
+		// configuration on SIGHUP.
+	WAIT:
+		for {
+			select {
+			case <-reloadCh:
+				m.Logger.Info("SIGHUP received, reloading configuration")
+				if err := cmd.Reload(); err != nil {
+					m.Logger.Info(fmt.Sprintf("failed to reload configuration: %s", err))
+				}
+			case <-signalCh:
+				break WAIT
+			}
+		}
+		signal.Stop(reloadCh)
 		m.Logger.Info("Signal received, initializing clean shutdown...")
 		go cmd.Close()
 
@@ -123,6 +139,11 @@ func (m *Main) Run(args ...string) error {
 		if err := name.Run(args...); err != nil {
 			return fmt.Errorf("restore: %s", err)
 		}
+	case "meta":
+		name := meta.NewCommand()
+		if err := name.Run(args...); err != nil {
+			return fmt.Errorf("meta: %s", err)
+		}
 	case "config":
 		if err := run.NewPrintConfigCommand().Run(args...); err != nil {
 			return fmt.Errorf("config: %s", err)
@@ -0,0 +1,205 @@
+// Package meta is the meta subcommand of the influxd command,
+// for exporting and importing the metastore as JSON.
+package meta
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/influxdb/services/meta"
+)
+
+// Command represents the program execution for "influxd meta".
+type Command struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	metadir    string
+	noHashes   bool
+	out        string
+	replace    bool
+	importPath string
+}
+
+// NewCommand returns a new instance of Command with default settings.
+func NewCommand() *Command {
+	return &Command{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// Run executes the program.
+func (cmd *Command) Run(args ...string) error {
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	switch name {
+	case "export":
+		if err := cmd.parseExportFlags(args); err != nil {
+			return err
+		}
+		return cmd.export()
+	case "import":
+		if err := cmd.parseImportFlags(args); err != nil {
+			return err
+		}
+		return cmd.importData()
+	case "", "help":
+		cmd.printUsage()
+		return nil
+	default:
+		return fmt.Errorf(`unknown command "%s"`+"\n"+`Run 'influxd meta -help' for usage`, name)
+	}
+}
+
+// parseExportFlags parses and validates the arguments of the export command.
+func (cmd *Command) parseExportFlags(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.StringVar(&cmd.metadir, "metadir", "", "")
+	fs.BoolVar(&cmd.noHashes, "no-hashes", false, "")
+	fs.StringVar(&cmd.out, "out", "", "")
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = cmd.printUsage
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cmd.metadir == "" {
+		return errors.New("-metadir is required to export the metastore")
+	}
+	return nil
+}
+
+// parseImportFlags parses and validates the arguments of the import command.
+func (cmd *Command) parseImportFlags(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.StringVar(&cmd.metadir, "metadir", "", "")
+	fs.BoolVar(&cmd.replace, "replace", false, "")
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = cmd.printUsage
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cmd.metadir == "" {
+		return errors.New("-metadir is required to import the metastore")
+	}
+	cmd.importPath = fs.Arg(0)
+	return nil
+}
+
+// export writes the metastore in the metadir as JSON. The metastore file is
+// read directly, so it can be exported while the server is running.
+func (cmd *Command) export() error {
+	b, err := ioutil.ReadFile(filepath.Join(cmd.metadir, "meta.db"))
+	if err != nil {
+		return err
+	}
+
+	var data meta.Data
+	if err := data.UnmarshalBinary(b); err != nil {
+		return fmt.Errorf("unmarshal: %s", err)
+	}
+	if cmd.noHashes {
+		data.RedactHashes()
+	}
+
+	if b, err = json.MarshalIndent(&data, "", "    "); err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	if cmd.out == "" || cmd.out == "-" {
+		_, err = cmd.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(cmd.out, b, 0600)
+}
+
+// importData imports the JSON metastore of the import path into the metadir.
+func (cmd *Command) importData() error {
+	r := cmd.Stdin
+	if cmd.importPath != "" && cmd.importPath != "-" {
+		f, err := os.Open(cmd.importPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var data meta.Data
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return fmt.Errorf("decode: %s", err)
+	}
+
+	c := meta.NewConfig()
+	c.Dir = cmd.metadir
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return err
+	}
+
+	client := meta.NewClient(c)
+	if err := client.Open(); err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.ImportData(&data, cmd.replace); err != nil {
+		return fmt.Errorf("import: %s", err)
+	}
+
+	mode := "Merged"
+	if cmd.replace {
+		mode = "Replaced"
+	}
+	fmt.Fprintf(cmd.Stdout, "%s %d databases and %d users into %s\n", mode, len(data.Databases), len(data.Users), cmd.metadir)
+	return nil
+}
+
+// printUsage prints the usage message to STDERR.
+func (cmd *Command) printUsage() {
+	fmt.Fprintf(cmd.Stderr, `Exports the metastore as JSON, or imports it from JSON.
+
+Usage: influxd meta export [flags]
+       influxd meta import [flags] [PATH]
+
+export writes the databases, retention policies, shard groups, continuous
+queries, subscriptions and users of the metastore to STDOUT. It can be run
+while the InfluxDB process is running.
+
+    -metadir <path>
+            Required. The directory of the metastore.
+    -no-hashes
+            Optional. Omit the password hashes of the users.
+    -out <path>
+            Optional. Write the JSON to the given file instead of STDOUT.
+
+import reads the JSON from PATH, or STDIN if not given, and merges it into
+the metastore: databases, retention policies, shard groups, continuous
+queries, subscriptions and users that do not exist are added, and existing
+ones are left unchanged. Users without a password hash keep the hash of the
+existing user. The InfluxDB process must not be running during an import.
+
+    -metadir <path>
+            Required. The directory of the metastore.
+    -replace
+            Optional. Replace the databases and users of the metastore
+            instead of merging them. The import fails if a shard of the
+            metastore is not in the JSON: its data would be left on disk.
+            Drop such shards first.
+
+`)
+}
@@ -35,9 +35,10 @@ type Command struct {
 	Commit    string
 	BuildTime string
 
-	closing chan struct{}
-	pidfile string
-	Closed  chan struct{}
+	closing    chan struct{}
+	pidfile    string
+	configPath string
+	Closed     chan struct{}
 
 	Stdin  io.Reader
 	Stdout io.Writer
@@ -85,7 +86,8 @@ func (cmd *Command) Run(args ...string) error {
 	cmd.pidfile = options.PIDFile
 
 	// Parse config
-	config, err := cmd.ParseConfig(options.GetConfigPath())
+	cmd.configPath = options.GetConfigPath()
+	config, err := cmd.ParseConfig(cmd.configPath)
 	if err != nil {
 		return fmt.Errorf("parse config: %s", err)
 	}
@@ -131,6 +133,34 @@ func (cmd *Command) Run(args ...string) error {
 	return nil
 }
 
+// Reload parses the config again and applies the settings that can change
+// while the server is running. Changed settings that require a restart are
+// logged and left unchanged.
+func (cmd *Command) Reload() error {
+	config, err := cmd.ParseConfig(cmd.configPath)
+	if err != nil {
+		return fmt.Errorf("parse config: %s", err)
+	}
+
+	if err := config.ApplyEnvOverrides(cmd.Getenv); err != nil {
+		return fmt.Errorf("apply env config: %v", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	restart, err := cmd.Server.Reload(config)
+	if err != nil {
+		return err
+	}
+	for _, name := range restart {
+		cmd.Logger.Info(fmt.Sprintf("Configuration setting %s changed, restart to apply it", name))
+	}
+	cmd.Logger.Info("Configuration reloaded")
+	return nil
+}
+
 // Close shuts down the server.
 func (cmd *Command) Close() error {
 	defer close(cmd.Closed)
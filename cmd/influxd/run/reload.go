@@ -0,0 +1,292 @@
+package run
+
+import (
+	"crypto/tls"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/coordinator"
+	"github.com/influxdata/influxdb/services/collectd"
+	"github.com/influxdata/influxdb/services/graphite"
+	"github.com/influxdata/influxdb/services/httpd"
+	"github.com/influxdata/influxdb/services/opentsdb"
+	"github.com/influxdata/influxdb/services/provision"
+	"github.com/influxdata/influxdb/services/udp"
+)
+
+// reloadableSettings holds, by section, the settings applied by a reload
+// without restarting the server. Changes of any other setting require a restart.
+var reloadableSettings = map[string][]string{
+	"coordinator": {
+		"max-concurrent-queries", "query-timeout", "log-queries-after",
+		"max-select-point", "max-select-series", "max-select-buckets",
+		"slow-query-trace-dir", "slow-query-trace-threshold",
+		"slow-query-trace-sample-rate", "slow-query-trace-format",
+	},
+	"http": {
+		"auth-enabled", "write-tracing", "pprof-enabled",
+		"https-certificate", "https-private-key",
+		"max-row-limit", "shared-secret", "realm", "max-body-size",
+	},
+	"subscriber": {
+		"http-timeout", "insecure-skip-verify", "ca-certs",
+		"write-concurrency", "write-buffer-size",
+	},
+	"monitor":   {"store-enabled", "store-database", "store-interval"},
+	"provision": {"file", "dry-run"},
+	"graphite":  {"batch-size", "batch-timeout"},
+	"collectd":  {"batch-size", "batch-timeout"},
+	"opentsdb":  {"batch-size", "batch-timeout"},
+	"udp":       {"batch-size", "batch-timeout"},
+}
+
+// isReloadable returns true if the setting name, as returned by
+// changedSettings, can be applied without a restart.
+func isReloadable(name string) bool {
+	i := strings.Index(name, ".")
+	if i == -1 {
+		return false
+	}
+	section, key := name[:i], name[i+1:]
+	if j := strings.Index(section, "["); j != -1 {
+		section = section[:j]
+	}
+
+	for _, k := range reloadableSettings[section] {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// changedSettings returns the names of the settings that differ between a and
+// b. Names are the TOML keys of the settings joined by dots, with the index of
+// the input sections in brackets, such as "http.auth-enabled" or
+// "graphite[0].batch-size". A section whose number of inputs changed is
+// returned by its name only.
+func changedSettings(a, b *Config) []string {
+	return changedFields("", reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem())
+}
+
+func changedFields(prefix string, a, b reflect.Value) []string {
+	var changed []string
+	for i := 0; i < a.NumField(); i++ {
+		f := a.Type().Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		name := strings.Split(f.Tag.Get("toml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		fa, fb := a.Field(i), b.Field(i)
+		if fa.Kind() == reflect.Ptr && !fa.IsNil() && !fb.IsNil() {
+			fa, fb = fa.Elem(), fb.Elem()
+		}
+
+		switch {
+		case fa.Kind() == reflect.Struct:
+			changed = append(changed, changedFields(name, fa, fb)...)
+		case fa.Kind() == reflect.Slice && fa.Type().Elem().Kind() == reflect.Struct:
+			if fa.Len() != fb.Len() {
+				changed = append(changed, name)
+				continue
+			}
+			for j := 0; j < fa.Len(); j++ {
+				changed = append(changed, changedFields(fmt.Sprintf("%s[%d]", name, j), fa.Index(j), fb.Index(j))...)
+			}
+		case !reflect.DeepEqual(fa.Interface(), fb.Interface()):
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// reloadedConfig returns the configuration applied by reloading c over last:
+// the reloadable settings of c and the other settings of last. Neither last
+// nor c is modified.
+func reloadedConfig(last, c *Config) *Config {
+	applied := *last
+	copyReloadable("", reflect.ValueOf(&applied).Elem(), reflect.ValueOf(c).Elem())
+	return &applied
+}
+
+// copyReloadable sets the reloadable settings of dst to those of src. The
+// structs and slices of dst are copied before they are changed, so that dst
+// does not share them with the configuration it was copied from.
+func copyReloadable(prefix string, dst, src reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		f := dst.Type().Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		name := strings.Split(f.Tag.Get("toml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		fd, fs := dst.Field(i), src.Field(i)
+		if fd.Kind() == reflect.Ptr && !fd.IsNil() && !fs.IsNil() {
+			p := reflect.New(fd.Type().Elem())
+			p.Elem().Set(fd.Elem())
+			fd.Set(p)
+			fd, fs = fd.Elem(), fs.Elem()
+		}
+
+		switch {
+		case fd.Kind() == reflect.Struct:
+			copyReloadable(name, fd, fs)
+		case fd.Kind() == reflect.Slice && fd.Type().Elem().Kind() == reflect.Struct:
+			// Inputs can only be reloaded if their number did not change.
+			if fd.Len() != fs.Len() {
+				continue
+			}
+			a := reflect.MakeSlice(fd.Type(), fd.Len(), fd.Len())
+			reflect.Copy(a, fd)
+			fd.Set(a)
+			for j := 0; j < fd.Len(); j++ {
+				copyReloadable(fmt.Sprintf("%s[%d]", name, j), fd.Index(j), fs.Index(j))
+			}
+		case isReloadable(name):
+			fd.Set(fs)
+		}
+	}
+}
+
+// Reload applies the settings of c that can change while the server is
+// running: the query limits of the coordinator, the TLS certificate and
+// settings of the HTTP service, the subscriber and monitor settings and the
+// batching of the input services. The provisioning file is applied again.
+//
+// It returns the names of the changed settings that require a restart, which
+// are left unchanged. Settings are compared with those applied by the last
+// reload, or with the startup configuration. The TLS certificate and the
+// provisioning file are loaded before any setting is changed, so that a
+// reload failing on them changes nothing.
+func (s *Server) Reload(c *Config) ([]string, error) {
+	last := s.config
+	if s.reloaded != nil {
+		last = s.reloaded
+	}
+
+	var restart []string
+	for _, name := range changedSettings(last, c) {
+		if !isReloadable(name) {
+			restart = append(restart, name)
+		}
+	}
+	c = reloadedConfig(last, c)
+
+	if c.HTTPD.Enabled && c.HTTPD.HTTPSEnabled {
+		key := c.HTTPD.HTTPSPrivateKey
+		if key == "" {
+			key = c.HTTPD.HTTPSCertificate
+		}
+		if _, err := tls.LoadX509KeyPair(c.HTTPD.HTTPSCertificate, key); err != nil {
+			return nil, fmt.Errorf("reload http: %s", err)
+		}
+	}
+	if c.Provision.File != "" {
+		if _, err := provision.ParseFile(c.Provision.File); err != nil {
+			return nil, fmt.Errorf("reload provision: %s: %s", c.Provision.File, err)
+		}
+	}
+
+	s.QueryExecutor.TaskManager.SetLimits(
+		time.Duration(c.Coordinator.QueryTimeout),
+		time.Duration(c.Coordinator.LogQueriesAfter),
+		c.Coordinator.MaxConcurrentQueries,
+	)
+	if e, ok := s.QueryExecutor.StatementExecutor.(*coordinator.StatementExecutor); ok {
+		e.Reload(c.Coordinator)
+	}
+
+	if !reflect.DeepEqual(last.Subscriber, c.Subscriber) {
+		if err := s.Subscriber.Reload(c.Subscriber); err != nil {
+			return nil, fmt.Errorf("reload subscriber: %s", err)
+		}
+	}
+	if !reflect.DeepEqual(last.Monitor, c.Monitor) {
+		if err := s.Monitor.Reload(monitorConfig(c)); err != nil {
+			return nil, fmt.Errorf("reload monitor: %s", err)
+		}
+	}
+
+	// The input services are created in the order of their enabled sections,
+	// which a reload does not change.
+	var graphiteInputs []graphite.Config
+	for _, i := range c.GraphiteInputs {
+		if i.Enabled {
+			graphiteInputs = append(graphiteInputs, i)
+		}
+	}
+	var collectdInputs []collectd.Config
+	for _, i := range c.CollectdInputs {
+		if i.Enabled {
+			collectdInputs = append(collectdInputs, i)
+		}
+	}
+	var openTSDBInputs []opentsdb.Config
+	for _, i := range c.OpenTSDBInputs {
+		if i.Enabled {
+			openTSDBInputs = append(openTSDBInputs, i)
+		}
+	}
+	var udpInputs []udp.Config
+	for _, i := range c.UDPInputs {
+		if i.Enabled {
+			udpInputs = append(udpInputs, i)
+		}
+	}
+
+	for _, svc := range s.Services {
+		switch svc := svc.(type) {
+		case *httpd.Service:
+			if err := svc.Reload(c.HTTPD); err != nil {
+				return nil, fmt.Errorf("reload http: %s", err)
+			}
+		case *graphite.Service:
+			if len(graphiteInputs) > 0 {
+				svc.Reload(graphiteInputs[0])
+				graphiteInputs = graphiteInputs[1:]
+			}
+		case *collectd.Service:
+			if len(collectdInputs) > 0 {
+				svc.Reload(collectdInputs[0])
+				collectdInputs = collectdInputs[1:]
+			}
+		case *opentsdb.Service:
+			if len(openTSDBInputs) > 0 {
+				svc.Reload(openTSDBInputs[0])
+				openTSDBInputs = openTSDBInputs[1:]
+			}
+		case *udp.Service:
+			if len(udpInputs) > 0 {
+				svc.Reload(udpInputs[0])
+				udpInputs = udpInputs[1:]
+			}
+		}
+	}
+
+	// The provisioning file is applied again by every reload, so a failure to
+	// execute its statements does not prevent recording the applied settings.
+	s.reloaded = c
+	for _, svc := range s.Services {
+		if svc, ok := svc.(*provision.Service); ok {
+			if err := svc.Reload(c.Provision); err != nil {
+				return nil, fmt.Errorf("reload provision: %s", err)
+			}
+		}
+	}
+	return restart, nil
+}
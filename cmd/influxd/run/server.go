@@ -23,6 +23,8 @@ import (
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/services/opentsdb"
 	"github.com/influxdata/influxdb/services/precreator"
+	"github.com/influxdata/influxdb/services/provision"
+	"github.com/influxdata/influxdb/services/replication"
 	"github.com/influxdata/influxdb/services/retention"
 	"github.com/influxdata/influxdb/services/snapshotter"
 	"github.com/influxdata/influxdb/services/subscriber"
@@ -73,10 +75,14 @@ type Server struct {
 	PointsWriter  *coordinator.PointsWriter
 	Subscriber    *subscriber.Service
 
+	// inputWriter writes the points of the HTTP and input services.
+	inputWriter inputPointsWriter
+
 	Services []Service
 
 	// These references are required for the tcp muxer.
 	SnapshotterService *snapshotter.Service
+	ReplicationService *replication.Service
 
 	Monitor *monitor.Monitor
 
@@ -97,6 +103,10 @@ type Server struct {
 	tcpAddr string
 
 	config *Config
+
+	// reloaded is the configuration applied by the last successful Reload:
+	// the reloadable settings of its config and the startup value of others.
+	reloaded *Config
 }
 
 // NewServer returns a new instance of Server built from a config.
@@ -157,7 +167,7 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 
 		config: c,
 	}
-	s.Monitor = monitor.New(s, c.Monitor)
+	s.Monitor = monitor.New(s, monitorConfig(c))
 	s.config.registerDiagnostics(s.Monitor)
 
 	if err := s.MetaClient.Open(); err != nil {
@@ -177,8 +187,16 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 	// Initialize points writer.
 	s.PointsWriter = coordinator.NewPointsWriter()
 	s.PointsWriter.WriteTimeout = time.Duration(c.Coordinator.WriteTimeout)
+	s.PointsWriter.QuotaCheckInterval = time.Duration(c.Coordinator.QuotaCheckInterval)
 	s.PointsWriter.TSDBStore = s.TSDBStore
 
+	// A read replica only applies the writes of its leader.
+	s.inputWriter = s.PointsWriter
+	if c.Replication.Leader != "" {
+		s.inputWriter = readReplicaPointsWriter{}
+		s.PointsWriter.ReadReplica = true
+	}
+
 	// Initialize query executor.
 	s.QueryExecutor = query.NewQueryExecutor()
 	s.QueryExecutor.StatementExecutor = &coordinator.StatementExecutor{
@@ -194,6 +212,8 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 		MaxSelectPointN:   c.Coordinator.MaxSelectPointN,
 		MaxSelectSeriesN:  c.Coordinator.MaxSelectSeriesN,
 		MaxSelectBucketsN: c.Coordinator.MaxSelectBucketsN,
+		SlowQueryTracer:   c.Coordinator.SlowQueryTracer(),
+		ReadReplica:       c.Replication.Leader != "",
 	}
 	s.QueryExecutor.TaskManager.QueryTimeout = time.Duration(c.Coordinator.QueryTimeout)
 	s.QueryExecutor.TaskManager.LogQueriesAfter = time.Duration(c.Coordinator.LogQueriesAfter)
@@ -223,6 +243,31 @@ func (s *Server) Statistics(tags map[string]string) []models.Statistic {
 	return statistics
 }
 
+func (s *Server) appendReplicationService(c replication.Config) {
+	if !c.Enabled {
+		return
+	}
+	srv := replication.NewService(c)
+	srv.MetaClient = s.MetaClient
+	s.PointsWriter.WriteLog = srv.Log
+	if e, ok := s.QueryExecutor.StatementExecutor.(*coordinator.StatementExecutor); ok {
+		e.StoreLog = srv.Log
+	}
+	s.Services = append(s.Services, srv)
+	s.ReplicationService = srv
+}
+
+func (s *Server) appendReplicationFollower(c replication.Config) {
+	if c.Leader == "" {
+		return
+	}
+	srv := replication.NewFollower(c)
+	srv.MetaClient = s.MetaClient
+	srv.PointsWriter = s.PointsWriter
+	srv.TSDBStore = s.TSDBStore
+	s.Services = append(s.Services, srv)
+}
+
 func (s *Server) appendSnapshotterService() {
 	srv := snapshotter.NewService()
 	srv.TSDBStore = s.TSDBStore
@@ -241,6 +286,13 @@ func (s *Server) appendMonitorService() {
 	s.Services = append(s.Services, s.Monitor)
 }
 
+func (s *Server) appendProvisionService(c provision.Config) {
+	srv := provision.NewService(c)
+	srv.MetaClient = s.MetaClient
+	srv.QueryExecutor = s.QueryExecutor
+	s.Services = append(s.Services, srv)
+}
+
 func (s *Server) appendRetentionPolicyService(c retention.Config) {
 	if !c.Enabled {
 		return
@@ -261,7 +313,7 @@ func (s *Server) appendHTTPDService(c httpd.Config) {
 	srv.Handler.WriteAuthorizer = meta.NewWriteAuthorizer(s.MetaClient)
 	srv.Handler.QueryExecutor = s.QueryExecutor
 	srv.Handler.Monitor = s.Monitor
-	srv.Handler.PointsWriter = s.PointsWriter
+	srv.Handler.PointsWriter = s.inputWriter
 	srv.Handler.Version = s.buildInfo.Version
 	srv.Handler.BuildType = "OSS"
 
@@ -285,7 +337,7 @@ func (s *Server) appendCollectdService(c collectd.Config) {
 	}
 	srv := collectd.NewService(c)
 	srv.MetaClient = s.MetaClient
-	srv.PointsWriter = s.PointsWriter
+	srv.PointsWriter = s.inputWriter
 	s.Services = append(s.Services, srv)
 }
 
@@ -297,7 +349,7 @@ func (s *Server) appendOpenTSDBService(c opentsdb.Config) error {
 	if err != nil {
 		return err
 	}
-	srv.PointsWriter = s.PointsWriter
+	srv.PointsWriter = s.inputWriter
 	srv.MetaClient = s.MetaClient
 	s.Services = append(s.Services, srv)
 	return nil
@@ -312,7 +364,7 @@ func (s *Server) appendGraphiteService(c graphite.Config) error {
 		return err
 	}
 
-	srv.PointsWriter = s.PointsWriter
+	srv.PointsWriter = s.inputWriter
 	srv.MetaClient = s.MetaClient
 	srv.Monitor = s.Monitor
 	s.Services = append(s.Services, srv)
@@ -338,7 +390,7 @@ func (s *Server) appendUDPService(c udp.Config) {
 		return
 	}
 	srv := udp.NewService(c)
-	srv.PointsWriter = s.PointsWriter
+	srv.PointsWriter = s.inputWriter
 	srv.MetaClient = s.MetaClient
 	s.Services = append(s.Services, srv)
 }
@@ -373,11 +425,19 @@ func (s *Server) Open() error {
 	mux := tcp.NewMux()
 	go mux.Serve(ln)
 
-	// Append services.
+	// Append services. A read replica does not run the services changing
+	// the meta store or writing data, which are replicated from its leader.
+	follower := s.config.Replication.Leader != ""
 	s.appendMonitorService()
-	s.appendPrecreatorService(s.config.Precreator)
+	if !follower {
+		s.appendProvisionService(s.config.Provision)
+		s.appendPrecreatorService(s.config.Precreator)
+	}
 	s.appendSnapshotterService()
-	s.appendContinuousQueryService(s.config.ContinuousQuery)
+	s.appendReplicationService(s.config.Replication)
+	if !follower {
+		s.appendContinuousQueryService(s.config.ContinuousQuery)
+	}
 	s.appendHTTPDService(s.config.HTTPD)
 	s.appendStorageService(s.config.Storage)
 	s.appendRetentionPolicyService(s.config.Retention)
@@ -397,12 +457,16 @@ func (s *Server) Open() error {
 	for _, i := range s.config.UDPInputs {
 		s.appendUDPService(i)
 	}
+	s.appendReplicationFollower(s.config.Replication)
 
 	s.Subscriber.MetaClient = s.MetaClient
 	s.PointsWriter.MetaClient = s.MetaClient
 	s.Monitor.MetaClient = s.MetaClient
 
 	s.SnapshotterService.Listener = mux.Listen(snapshotter.MuxHeader)
+	if s.ReplicationService != nil {
+		s.ReplicationService.Listener = mux.Listen(replication.MuxHeader)
+	}
 
 	// Configure logging for all services and clients.
 	if s.config.Meta.LoggingEnabled {
@@ -435,7 +499,10 @@ func (s *Server) Open() error {
 		return fmt.Errorf("open points writer: %s", err)
 	}
 
-	s.PointsWriter.AddWriteSubscriber(s.Subscriber.Points())
+	// The subscriptions of a read replica are served by its leader.
+	if !follower {
+		s.PointsWriter.AddWriteSubscriber(s.Subscriber.Points())
+	}
 
 	for _, service := range s.Services {
 		if err := service.Open(); err != nil {
@@ -620,6 +687,34 @@ func (pw *monitorPointsWriter) WritePoints(database, retentionPolicy string, poi
 	return (*coordinator.PointsWriter)(pw).WritePointsPrivileged(database, retentionPolicy, models.ConsistencyLevelAny, points)
 }
 
+// inputPointsWriter is the interface of the points writer of the HTTP and
+// input services.
+type inputPointsWriter interface {
+	WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error
+	WritePointsPrivileged(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error
+}
+
+// readReplicaPointsWriter rejects the writes to a read replica.
+type readReplicaPointsWriter struct{}
+
+func (readReplicaPointsWriter) WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error {
+	return influxdb.ErrReadReplica
+}
+
+func (readReplicaPointsWriter) WritePointsPrivileged(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error {
+	return influxdb.ErrReadReplica
+}
+
+// monitorConfig returns the monitor settings of c. A read replica does not
+// store its statistics, since it only applies the writes of its leader.
+func monitorConfig(c *Config) monitor.Config {
+	mc := c.Monitor
+	if c.Replication.Leader != "" {
+		mc.StoreEnabled = false
+	}
+	return mc
+}
+
 func raftDBExists(dir string) error {
 	// Check to see if there is a raft db, if so, error out with a message
 	// to downgrade, export, and then import the meta data
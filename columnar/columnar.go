@@ -0,0 +1,12 @@
+// Package columnar implements the columnar write format.
+//
+// The format is the protocol buffer encoding of the WriteRequest message of
+// columnar.proto. It groups points by series, so the measurement and tags of a
+// series are sent once, and stores the values of each field in a column of
+// typed values. Parsing it is cheaper than parsing line protocol.
+package columnar
+
+//go:generate protoc --gogofaster_out=. columnar.proto
+
+// ContentType is the content type of writes in the columnar format.
+const ContentType = "application/x-protobuf"
@@ -0,0 +1,337 @@
+package columnar_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/columnar"
+	"github.com/influxdata/influxdb/models"
+)
+
+func TestWriteRequest_RoundTrip(t *testing.T) {
+	r := &columnar.WriteRequest{Series: []*columnar.Series{
+		{
+			Measurement: "cpu",
+			Tags:        []*columnar.Tag{{Key: "region", Value: "west"}, {Key: "host", Value: "a"}},
+			Timestamps:  []int64{10, 20, 30},
+			Columns: []*columnar.Column{
+				{Name: "value", FloatValues: []float64{1, 4}, Nulls: []bool{false, false, true}},
+				{Name: "count", IntegerValues: []int64{2, 5}, Nulls: []bool{false, true, false}},
+				{Name: "up", BooleanValues: []bool{true}, Nulls: []bool{true, true, false}},
+			},
+		},
+		{
+			Measurement: "cpu",
+			Tags:        []*columnar.Tag{{Key: "host", Value: "b"}},
+			Timestamps:  []int64{10},
+			Columns: []*columnar.Column{
+				{Name: "value", FloatValues: []float64{3}},
+				{Name: "state", StringValues: []string{`on"off`}},
+			},
+		},
+	}}
+
+	b, err := r.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other columnar.WriteRequest
+	if err := other.Unmarshal(b); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(&other, r) {
+		t.Fatalf("unexpected write request:\ngot=%#v\nexp=%#v", other, *r)
+	}
+
+	points, err := other.Points("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range points {
+		got = append(got, p.String())
+	}
+	if exp := []string{
+		`cpu,host=a,region=west value=1,count=2i 10`,
+		`cpu,host=a,region=west value=4 20`,
+		`cpu,host=a,region=west count=5i,up=true 30`,
+		`cpu,host=b value=3,state="on\"off" 10`,
+	}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected points:\ngot=%q\nexp=%q", got, exp)
+	}
+}
+
+// Ensure the fields of points are read from the columns, and the points are
+// still converted when they are modified.
+func TestWriteRequest_Points_Fields(t *testing.T) {
+	r := columnar.WriteRequest{Series: []*columnar.Series{{
+		Measurement: "cpu",
+		Tags:        []*columnar.Tag{{Key: "host", Value: "a"}},
+		Timestamps:  []int64{10, 20},
+		Columns: []*columnar.Column{
+			{Name: "value", FloatValues: []float64{1.5}, Nulls: []bool{true, false}},
+			{Name: "count", IntegerValues: []int64{2, 3}},
+			{Name: "state", StringValues: []string{"on", "off"}},
+		},
+	}}}
+
+	points, err := r.Points("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := points[1]
+	if string(p.Name()) != "cpu" || string(p.Key()) != "cpu,host=a" || !p.HasTag([]byte("host")) || p.UnixNano() != 20 {
+		t.Fatalf("unexpected point: %s", p)
+	}
+
+	var fields []string
+	iter := p.FieldIterator()
+	for iter.Next() {
+		switch iter.Type() {
+		case models.Float:
+			v, _ := iter.FloatValue()
+			fields = append(fields, fmt.Sprintf("%s=%v", iter.FieldKey(), v))
+		case models.Integer:
+			v, _ := iter.IntegerValue()
+			fields = append(fields, fmt.Sprintf("%s=%di", iter.FieldKey(), v))
+		case models.String:
+			fields = append(fields, fmt.Sprintf("%s=%q", iter.FieldKey(), iter.StringValue()))
+		}
+	}
+	if exp := []string{"value=1.5", "count=3i", `state="off"`}; !reflect.DeepEqual(fields, exp) {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+
+	if f, err := points[0].Fields(); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(f, models.Fields{"count": int64(2), "state": "on"}) {
+		t.Fatalf("unexpected fields: %v", f)
+	}
+
+	p.AddTag("region", "west")
+	if exp := `cpu,host=a,region=west value=1.5,count=3i,state="off" 20`; p.String() != exp {
+		t.Fatalf("unexpected point: %s", p)
+	} else if !p.HasTag([]byte("region")) {
+		t.Fatal("expected tag region")
+	}
+	if exp := `cpu,host=a count=2i,state="on" 10`; points[0].String() != exp {
+		t.Fatalf("unexpected point: %s", points[0])
+	}
+}
+
+func TestWriteRequest_Points_Precision(t *testing.T) {
+	r := columnar.WriteRequest{Series: []*columnar.Series{{
+		Measurement: "cpu",
+		Timestamps:  []int64{2},
+		Columns:     []*columnar.Column{{Name: "value", FloatValues: []float64{1}}},
+	}}}
+
+	points, err := r.Points("s")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(points) != 1 || !points[0].Time().Equal(time.Unix(2, 0)) {
+		t.Fatalf("unexpected points: %v", points)
+	}
+}
+
+func TestWriteRequest_Points_Invalid(t *testing.T) {
+	r := columnar.WriteRequest{Series: []*columnar.Series{
+		{
+			Measurement: "cpu",
+			Timestamps:  []int64{1, 2},
+			Columns:     []*columnar.Column{{Name: "value", FloatValues: []float64{1}}},
+		},
+		{
+			Timestamps: []int64{1},
+			Columns:    []*columnar.Column{{Name: "value", FloatValues: []float64{1}}},
+		},
+		{
+			Measurement: "mem",
+			Timestamps:  []int64{1, 2},
+			Columns:     []*columnar.Column{{Name: "value", FloatValues: []float64{1}, Nulls: []bool{true, false}}},
+		},
+		{
+			Measurement: "disk",
+			Timestamps:  []int64{1, 2},
+			Columns: []*columnar.Column{
+				{Name: "value", FloatValues: []float64{math.NaN(), 1}},
+				{Name: "free", IntegerValues: []int64{3, 4}},
+			},
+		},
+		{
+			Measurement: "net",
+			Tags:        []*columnar.Tag{{Key: "", Value: "eth0"}},
+			Timestamps:  []int64{1},
+			Columns:     []*columnar.Column{{Name: "bytes", IntegerValues: []int64{1}}},
+		},
+		{
+			Measurement: "net",
+			Timestamps:  []int64{1},
+			Columns:     []*columnar.Column{{Name: "", IntegerValues: []int64{1}}},
+		},
+		{
+			Measurement: "net",
+			Timestamps:  []int64{1},
+			Columns:     []*columnar.Column{{Name: "bytes", UnsignedValues: []uint64{1}}},
+		},
+	}}
+
+	points, err := r.Points("")
+	if exp := "series cpu: column \"value\" has 1 values, expected 2\nmissing measurement\nseries mem at 1: point without fields is unsupported\nseries disk at 1: NaN is an unsupported value for field value\nseries net: missing tag key\nseries net: missing field key\nseries net: column \"bytes\" has unsupported unsigned values"; err == nil || err.Error() != exp {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(points) != 2 || points[0].String() != "mem value=1 2" || points[1].String() != "disk value=1,free=4i 2" {
+		t.Fatalf("unexpected points: %v", points)
+	}
+}
+
+// Ensure series with tags rejected by line protocol are rejected.
+func TestWriteRequest_Points_InvalidTags(t *testing.T) {
+	for _, tt := range []struct {
+		tags []*columnar.Tag
+		err  string
+	}{
+		{
+			tags: []*columnar.Tag{{Key: "host", Value: "a"}, {Key: "region", Value: "west"}, {Key: "host", Value: "b"}},
+			err:  "series cpu: duplicate tags",
+		},
+		{
+			tags: []*columnar.Tag{{Key: "host", Value: ""}},
+			err:  "series cpu: missing tag value",
+		},
+	} {
+		r := columnar.WriteRequest{Series: []*columnar.Series{{
+			Measurement: "cpu",
+			Tags:        tt.tags,
+			Timestamps:  []int64{1},
+			Columns:     []*columnar.Column{{Name: "value", FloatValues: []float64{1}}},
+		}}}
+
+		points, err := r.Points("")
+		if err == nil || err.Error() != tt.err {
+			t.Fatalf("unexpected error: %v", err)
+		} else if len(points) != 0 {
+			t.Fatalf("unexpected points: %v", points)
+		}
+
+		// Line protocol rejects the same tags.
+		line := "cpu"
+		for _, tag := range tt.tags {
+			line += "," + tag.Key + "=" + tag.Value
+		}
+		if _, err := models.ParsePointsString(line + " value=1 1"); err == nil || !strings.Contains(err.Error(), strings.TrimPrefix(tt.err, "series cpu: ")) {
+			t.Fatalf("unexpected line protocol error: %v", err)
+		}
+	}
+}
+
+func TestWriteRequest_Unmarshal_Invalid(t *testing.T) {
+	for _, b := range [][]byte{
+		{0x0a},             // missing length of series
+		{0x0a, 0x05, 0x0a}, // truncated series
+		{0x08, 0x01},       // series as varint
+	} {
+		var r columnar.WriteRequest
+		if err := r.Unmarshal(b); err == nil {
+			t.Fatalf("expected error for %x", b)
+		}
+	}
+}
+
+// benchmarkLines returns line protocol of n series of m points with two float
+// fields and an integer field.
+func benchmarkLines(n, m int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		for j := 0; j < m; j++ {
+			fmt.Fprintf(&buf, "cpu,host=server%d,region=us-west,dc=dc%d usage_user=%d.5,usage_system=%d.25,procs=%di %d\n", i, i%4, j, j, j, 1500000000000000000+int64(j)*10000000000)
+		}
+	}
+	return buf.Bytes()
+}
+
+// benchmarkWriteRequest returns the write request of the points of
+// benchmarkLines.
+func benchmarkWriteRequest(n, m int) *columnar.WriteRequest {
+	r := &columnar.WriteRequest{}
+	for i := 0; i < n; i++ {
+		s := &columnar.Series{
+			Measurement: "cpu",
+			Tags: []*columnar.Tag{
+				{Key: "dc", Value: fmt.Sprintf("dc%d", i%4)},
+				{Key: "host", Value: fmt.Sprintf("server%d", i)},
+				{Key: "region", Value: "us-west"},
+			},
+			Columns: []*columnar.Column{{Name: "usage_user"}, {Name: "usage_system"}, {Name: "procs"}},
+		}
+		for j := 0; j < m; j++ {
+			s.Timestamps = append(s.Timestamps, 1500000000000000000+int64(j)*10000000000)
+			s.Columns[0].FloatValues = append(s.Columns[0].FloatValues, float64(j)+0.5)
+			s.Columns[1].FloatValues = append(s.Columns[1].FloatValues, float64(j)+0.25)
+			s.Columns[2].IntegerValues = append(s.Columns[2].IntegerValues, int64(j))
+		}
+		r.Series = append(r.Series, s)
+	}
+	return r
+}
+
+// readFields reads the fields of points, as the engine does when it writes
+// them.
+func readFields(b *testing.B, points []models.Point) {
+	for _, p := range points {
+		iter := p.FieldIterator()
+		for iter.Next() {
+			var err error
+			switch iter.Type() {
+			case models.Float:
+				_, err = iter.FloatValue()
+			case models.Integer:
+				_, err = iter.IntegerValue()
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkParsePoints_LineProtocol(b *testing.B) {
+	lines := benchmarkLines(100, 100)
+	b.SetBytes(int64(len(lines)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		points, err := models.ParsePoints(lines)
+		if err != nil {
+			b.Fatal(err)
+		}
+		readFields(b, points)
+	}
+}
+
+func BenchmarkParsePoints_Columnar(b *testing.B) {
+	buf, err := benchmarkWriteRequest(100, 100).Marshal()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(buf)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var r columnar.WriteRequest
+		if err := r.Unmarshal(buf); err != nil {
+			b.Fatal(err)
+		}
+		points, err := r.Points("")
+		if err != nil {
+			b.Fatal(err)
+		}
+		readFields(b, points)
+	}
+}
@@ -0,0 +1,372 @@
+package columnar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/escape"
+)
+
+// Points returns the points of the write request, with timestamps in the
+// precision. Like models.ParsePoints, invalid points are skipped and returned
+// in an error with the valid points.
+func (r *WriteRequest) Points(precision string) ([]models.Point, error) {
+	mul := models.GetPrecisionMultiplier(precision)
+
+	var n int
+	for _, s := range r.Series {
+		n += len(s.Timestamps)
+	}
+	points := make([]models.Point, 0, n)
+
+	var failed []string
+	for _, s := range r.Series {
+		var err error
+		if points, err = s.appendPoints(points, mul); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		return points, errors.New(strings.Join(failed, "\n"))
+	}
+	return points, nil
+}
+
+// appendPoints appends the points of s to points. The points of a series with
+// invalid columns are all skipped.
+func (s *Series) appendPoints(points []models.Point, mul int64) ([]models.Point, error) {
+	if s.Measurement == "" {
+		return points, errors.New("missing measurement")
+	}
+
+	tags := make(models.Tags, len(s.Tags))
+	for i, t := range s.Tags {
+		if t.Key == "" {
+			return points, fmt.Errorf("series %s: missing tag key", s.Measurement)
+		} else if t.Value == "" {
+			return points, fmt.Errorf("series %s: missing tag value", s.Measurement)
+		}
+		tags[i] = models.NewTag([]byte(t.Key), []byte(t.Value))
+	}
+	sort.Sort(tags)
+	for i := 1; i < len(tags); i++ {
+		if bytes.Equal(tags[i-1].Key, tags[i].Key) {
+			return points, fmt.Errorf("series %s: duplicate tags", s.Measurement)
+		}
+	}
+	key := models.MakeKey([]byte(s.Measurement), tags)
+
+	ps := &pointSeries{
+		s:     s,
+		key:   key[:len(key):len(key)],
+		name:  []byte(s.Measurement),
+		tags:  tags,
+		names: make([][]byte, len(s.Columns)),
+		types: make([]models.FieldType, len(s.Columns)),
+	}
+	h := models.NewInlineFNV64a()
+	h.Write(key)
+	ps.hash = h.Sum64()
+
+	for i, c := range s.Columns {
+		if c.Name == "" {
+			return points, fmt.Errorf("series %s: missing field key", key)
+		} else if len(c.UnsignedValues) > 0 && !models.UintSupportEnabled() {
+			// Line protocol rejects unsigned values unless uint support is enabled.
+			return points, fmt.Errorf("series %s: column %q has unsupported unsigned values", key, c.Name)
+		} else if err := c.check(len(s.Timestamps)); err != nil {
+			return points, fmt.Errorf("series %s: %s", key, err)
+		}
+		// 4 is the length of the separator of series and field keys of tsm1.
+		if sz := len(key) + 4 + len(c.Name); sz > models.MaxKeyLength {
+			return points, fmt.Errorf("series %s: max key length exceeded: %v > %v", key, sz, models.MaxKeyLength)
+		}
+		ps.names[i] = []byte(c.Name)
+		ps.types[i] = c.fieldType()
+	}
+
+	// The index of the value of each column for each row, shared by the points.
+	values := make([]int, len(s.Timestamps)*len(s.Columns))
+
+	// The next value of each column.
+	next := make([]int, len(s.Columns))
+
+	var failed []string
+	for row, ts := range s.Timestamps {
+		indexes := values[row*len(s.Columns) : (row+1)*len(s.Columns) : (row+1)*len(s.Columns)]
+
+		var err error
+		var fields int
+		for i, c := range s.Columns {
+			if len(c.Nulls) > 0 && c.Nulls[row] {
+				indexes[i] = -1
+				continue
+			}
+
+			j := next[i]
+			next[i]++
+			indexes[i] = j
+			fields++
+			if err == nil && len(c.FloatValues) > 0 && (math.IsNaN(c.FloatValues[j]) || math.IsInf(c.FloatValues[j], 0)) {
+				err = fmt.Errorf("%v is an unsupported value for field %s", c.FloatValues[j], c.Name)
+			}
+		}
+
+		t := time.Unix(0, ts*mul)
+		if err == nil && fields == 0 {
+			err = models.ErrPointMustHaveAField
+		} else if err == nil {
+			err = models.CheckTime(t)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("series %s at %d: %s", key, ts, err))
+			continue
+		}
+		points = append(points, &point{s: ps, time: t, values: indexes})
+	}
+
+	if len(failed) > 0 {
+		return points, errors.New(strings.Join(failed, "\n"))
+	}
+	return points, nil
+}
+
+// check returns an error if c does not have a value of a single type for
+// each of the n rows without a null.
+func (c *Column) check(n int) error {
+	var types int
+	for _, l := range []int{len(c.FloatValues), len(c.IntegerValues), len(c.UnsignedValues), len(c.StringValues), len(c.BooleanValues)} {
+		if l > 0 {
+			types++
+		}
+	}
+	if types > 1 {
+		return fmt.Errorf("column %q has values of more than one type", c.Name)
+	}
+
+	if len(c.Nulls) > 0 {
+		if len(c.Nulls) != n {
+			return fmt.Errorf("column %q has %d nulls, expected %d", c.Name, len(c.Nulls), n)
+		}
+		for _, null := range c.Nulls {
+			if null {
+				n--
+			}
+		}
+	}
+	if l := len(c.FloatValues) + len(c.IntegerValues) + len(c.UnsignedValues) + len(c.StringValues) + len(c.BooleanValues); l != n {
+		return fmt.Errorf("column %q has %d values, expected %d", c.Name, l, n)
+	}
+	return nil
+}
+
+// fieldType returns the type of the values of c, which has values of a
+// single type.
+func (c *Column) fieldType() models.FieldType {
+	switch {
+	case len(c.IntegerValues) > 0:
+		return models.Integer
+	case len(c.UnsignedValues) > 0:
+		return models.Unsigned
+	case len(c.StringValues) > 0:
+		return models.String
+	case len(c.BooleanValues) > 0:
+		return models.Boolean
+	default:
+		return models.Float
+	}
+}
+
+// pointSeries holds what the points of a series of a write request share.
+type pointSeries struct {
+	s     *Series
+	key   []byte
+	name  []byte
+	tags  models.Tags
+	hash  uint64
+	names [][]byte
+	types []models.FieldType
+}
+
+// point is a point of a write request. Its fields are read from the columns
+// of its series, without formatting or parsing text. Methods that modify or
+// encode the point convert it to a point of the models package, which holds
+// its fields as line protocol, and delegate to it from then on.
+type point struct {
+	s      *pointSeries
+	time   time.Time
+	values []int // index of the value of each column, -1 for a null
+	col    int   // column of the field iterator
+
+	pt models.Point
+}
+
+var (
+	_ models.Point         = (*point)(nil)
+	_ models.FieldIterator = (*point)(nil)
+)
+
+// models returns the point converted to a point of the models package.
+func (p *point) models() models.Point {
+	if p.pt != nil {
+		return p.pt
+	}
+
+	var buf []byte
+	for i, j := range p.values {
+		if j < 0 {
+			continue
+		} else if len(buf) > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, escape.String(string(p.s.names[i]))...)
+		buf = append(buf, '=')
+
+		c := p.s.s.Columns[i]
+		switch p.s.types[i] {
+		case models.Float:
+			buf = strconv.AppendFloat(buf, c.FloatValues[j], 'f', -1, 64)
+		case models.Integer:
+			buf = append(strconv.AppendInt(buf, c.IntegerValues[j], 10), 'i')
+		case models.Unsigned:
+			buf = append(strconv.AppendUint(buf, c.UnsignedValues[j], 10), 'u')
+		case models.String:
+			buf = append(buf, '"')
+			buf = append(buf, models.EscapeStringField(c.StringValues[j])...)
+			buf = append(buf, '"')
+		case models.Boolean:
+			buf = strconv.AppendBool(buf, c.BooleanValues[j])
+		}
+	}
+
+	// The fields and time of the point were checked by appendPoints.
+	p.pt, _ = models.NewPointWithKey(p.s.key, buf, p.time)
+	return p.pt
+}
+
+func (p *point) Name() []byte {
+	if p.pt != nil {
+		return p.pt.Name()
+	}
+	return p.s.name
+}
+
+func (p *point) SetName(name string) { p.models().SetName(name) }
+
+func (p *point) Tags() models.Tags {
+	if p.pt != nil {
+		return p.pt.Tags()
+	}
+	return p.s.tags
+}
+
+func (p *point) AddTag(key, value string) { p.models().AddTag(key, value) }
+
+func (p *point) SetTags(tags models.Tags) { p.models().SetTags(tags) }
+
+func (p *point) HasTag(tag []byte) bool {
+	if p.pt != nil {
+		return p.pt.HasTag(tag)
+	}
+	return p.s.tags.Get(tag) != nil
+}
+
+func (p *point) Fields() (models.Fields, error) { return p.models().Fields() }
+
+func (p *point) Time() time.Time {
+	if p.pt != nil {
+		return p.pt.Time()
+	}
+	return p.time
+}
+
+func (p *point) SetTime(t time.Time) { p.models().SetTime(t) }
+
+func (p *point) UnixNano() int64 { return p.Time().UnixNano() }
+
+func (p *point) HashID() uint64 {
+	if p.pt != nil {
+		return p.pt.HashID()
+	}
+	return p.s.hash
+}
+
+func (p *point) Key() []byte {
+	if p.pt != nil {
+		return p.pt.Key()
+	}
+	return p.s.key
+}
+
+func (p *point) String() string { return p.models().String() }
+
+func (p *point) MarshalBinary() ([]byte, error) { return p.models().MarshalBinary() }
+
+func (p *point) PrecisionString(precision string) string {
+	return p.models().PrecisionString(precision)
+}
+
+func (p *point) RoundedString(d time.Duration) string { return p.models().RoundedString(d) }
+
+func (p *point) Split(size int) []models.Point { return p.models().Split(size) }
+
+func (p *point) Round(d time.Duration) { p.models().Round(d) }
+
+func (p *point) StringSize() int { return p.models().StringSize() }
+
+func (p *point) AppendString(buf []byte) []byte { return p.models().AppendString(buf) }
+
+// FieldIterator returns an iterator over the fields of the point, which reads
+// the values from the columns.
+func (p *point) FieldIterator() models.FieldIterator {
+	if p.pt != nil {
+		return p.pt.FieldIterator()
+	}
+	p.Reset()
+	return p
+}
+
+// Next moves to the next field of the point, skipping the nulls.
+func (p *point) Next() bool {
+	for p.col++; p.col < len(p.values); p.col++ {
+		if p.values[p.col] >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *point) FieldKey() []byte { return p.s.names[p.col] }
+
+func (p *point) Type() models.FieldType { return p.s.types[p.col] }
+
+func (p *point) StringValue() string {
+	return p.s.s.Columns[p.col].StringValues[p.values[p.col]]
+}
+
+func (p *point) IntegerValue() (int64, error) {
+	return p.s.s.Columns[p.col].IntegerValues[p.values[p.col]], nil
+}
+
+func (p *point) UnsignedValue() (uint64, error) {
+	return p.s.s.Columns[p.col].UnsignedValues[p.values[p.col]], nil
+}
+
+func (p *point) BooleanValue() (bool, error) {
+	return p.s.s.Columns[p.col].BooleanValues[p.values[p.col]], nil
+}
+
+func (p *point) FloatValue() (float64, error) {
+	return p.s.s.Columns[p.col].FloatValues[p.values[p.col]], nil
+}
+
+// Reset moves the field iterator before the first field.
+func (p *point) Reset() { p.col = -1 }
@@ -3,9 +3,12 @@
 package coordinator
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/influxdata/influxdb/monitor/diagnostics"
+	"github.com/influxdata/influxdb/pkg/tracing"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/toml"
 )
@@ -25,6 +28,18 @@ const (
 	// DefaultMaxSelectSeriesN is the maximum number of series a SELECT can run.
 	// A value of zero will make the maximum series count unlimited.
 	DefaultMaxSelectSeriesN = 0
+
+	// DefaultQuotaCheckInterval is the default interval at which the usage of
+	// a database with quotas is measured.
+	DefaultQuotaCheckInterval = 10 * time.Second
+
+	// DefaultSlowQueryTraceThreshold is the minimum duration of a SELECT for
+	// its trace to be written to the slow query trace directory.
+	DefaultSlowQueryTraceThreshold = 10 * time.Second
+
+	// DefaultSlowQueryTraceSampleRate is the fraction of SELECT statements
+	// traced when a slow query trace directory is set.
+	DefaultSlowQueryTraceSampleRate = 1.0
 )
 
 // Config represents the configuration for the coordinator service.
@@ -36,6 +51,12 @@ type Config struct {
 	MaxSelectPointN      int           `toml:"max-select-point"`
 	MaxSelectSeriesN     int           `toml:"max-select-series"`
 	MaxSelectBucketsN    int           `toml:"max-select-buckets"`
+	QuotaCheckInterval   toml.Duration `toml:"quota-check-interval"`
+
+	SlowQueryTraceDir        string        `toml:"slow-query-trace-dir"`
+	SlowQueryTraceThreshold  toml.Duration `toml:"slow-query-trace-threshold"`
+	SlowQueryTraceSampleRate float64       `toml:"slow-query-trace-sample-rate"`
+	SlowQueryTraceFormat     string        `toml:"slow-query-trace-format"`
 }
 
 // NewConfig returns an instance of Config with defaults.
@@ -46,7 +67,40 @@ func NewConfig() Config {
 		MaxConcurrentQueries: DefaultMaxConcurrentQueries,
 		MaxSelectPointN:      DefaultMaxSelectPointN,
 		MaxSelectSeriesN:     DefaultMaxSelectSeriesN,
+		QuotaCheckInterval:   toml.Duration(DefaultQuotaCheckInterval),
+
+		SlowQueryTraceThreshold:  toml.Duration(DefaultSlowQueryTraceThreshold),
+		SlowQueryTraceSampleRate: DefaultSlowQueryTraceSampleRate,
+		SlowQueryTraceFormat:     tracing.ChromeFormat,
+	}
+}
+
+// Validate returns an error if the config is invalid.
+func (c Config) Validate() error {
+	if c.SlowQueryTraceDir == "" {
+		return nil
+	}
+	if !tracing.ValidFormat(c.SlowQueryTraceFormat) {
+		return fmt.Errorf("unknown slow-query-trace-format %q", c.SlowQueryTraceFormat)
+	} else if c.SlowQueryTraceSampleRate < 0 || c.SlowQueryTraceSampleRate > 1 {
+		return errors.New("slow-query-trace-sample-rate must be between 0 and 1")
+	} else if c.SlowQueryTraceThreshold < 0 {
+		return errors.New("slow-query-trace-threshold must be positive")
+	}
+	return nil
+}
+
+// SlowQueryTracer returns the tracer of slow queries configured by c, or nil
+// if slow query tracing is disabled.
+func (c Config) SlowQueryTracer() *SlowQueryTracer {
+	if c.SlowQueryTraceDir == "" {
+		return nil
 	}
+	t := NewSlowQueryTracer(c.SlowQueryTraceDir)
+	t.Threshold = time.Duration(c.SlowQueryTraceThreshold)
+	t.SampleRate = c.SlowQueryTraceSampleRate
+	t.Format = c.SlowQueryTraceFormat
+	return t
 }
 
 // Diagnostics returns a diagnostics representation of a subset of the Config.
@@ -59,5 +113,7 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"max-select-point":       c.MaxSelectPointN,
 		"max-select-series":      c.MaxSelectSeriesN,
 		"max-select-buckets":     c.MaxSelectBucketsN,
+		"quota-check-interval":   c.QuotaCheckInterval,
+		"slow-query-trace-dir":   c.SlowQueryTraceDir,
 	}), nil
 }
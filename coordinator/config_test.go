@@ -22,3 +22,22 @@ write-timeout = "20s"
 		t.Fatalf("unexpected write timeout s: %s", c.WriteTimeout)
 	}
 }
+
+func TestConfig_Validate(t *testing.T) {
+	c := coordinator.NewConfig()
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	c.SlowQueryTraceDir = "/tmp/traces"
+	c.SlowQueryTraceFormat = "zipkin"
+	if err := c.Validate(); err == nil || err.Error() != `unknown slow-query-trace-format "zipkin"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.SlowQueryTraceFormat = "jaeger"
+	c.SlowQueryTraceSampleRate = 1.5
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for sample rate")
+	}
+}
@@ -9,24 +9,33 @@ import (
 
 // MetaClient is an interface for accessing meta data.
 type MetaClient interface {
+	CopyShardGroup(id uint64, database, policy string) (*meta.ShardGroupInfo, error)
 	CreateContinuousQuery(database, name, query string) error
 	CreateDatabase(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithRetentionPolicy(name string, spec *meta.RetentionPolicySpec) (*meta.DatabaseInfo, error)
+	CreateMeasurementSchema(database string, msi meta.MeasurementSchemaInfo) error
 	CreateRetentionPolicy(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error)
 	CreateSubscription(database, rp, name, mode string, destinations []string) error
 	CreateUser(name, password string, admin bool) (meta.User, error)
+	Data() meta.Data
 	Database(name string) *meta.DatabaseInfo
 	Databases() []meta.DatabaseInfo
 	DropShard(id uint64) error
 	DropContinuousQuery(database, name string) error
 	DropDatabase(name string) error
+	DropMeasurementSchema(database, name string) error
 	DropRetentionPolicy(database, name string) error
 	DropSubscription(database, rp, name string) error
 	DropUser(name string) error
+	ImportData(data *meta.Data, replace bool) error
+	MoveShardGroup(id uint64, database, policy string) error
 	RetentionPolicy(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
 	SetAdminPrivilege(username string, admin bool) error
+	SetDatabaseReadOnly(name string, readOnly bool) error
 	SetPrivilege(username, database string, p influxql.Privilege) error
+	SetShardReadOnly(id uint64, readOnly bool) error
 	ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
+	UpdateDatabaseQuota(database string, qu *meta.DatabaseQuotaUpdate) error
 	UpdateRetentionPolicy(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error
 	UpdateUser(name, password string) error
 	UserPrivilege(username, database string) (*influxql.Privilege, error)
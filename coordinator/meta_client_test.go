@@ -9,29 +9,38 @@ import (
 
 // MetaClient is a mockable implementation of cluster.MetaClient.
 type MetaClient struct {
+	CopyShardGroupFn                    func(id uint64, database, policy string) (*meta.ShardGroupInfo, error)
 	CreateContinuousQueryFn             func(database, name, query string) error
 	CreateDatabaseFn                    func(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithRetentionPolicyFn func(name string, spec *meta.RetentionPolicySpec) (*meta.DatabaseInfo, error)
+	CreateMeasurementSchemaFn           func(database string, msi meta.MeasurementSchemaInfo) error
 	CreateRetentionPolicyFn             func(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error)
 	CreateSubscriptionFn                func(database, rp, name, mode string, destinations []string) error
 	CreateUserFn                        func(name, password string, admin bool) (meta.User, error)
 	DatabaseFn                          func(name string) *meta.DatabaseInfo
 	DatabasesFn                         func() []meta.DatabaseInfo
+	DataFn                              func() meta.Data
 	DataNodeFn                          func(id uint64) (*meta.NodeInfo, error)
 	DataNodesFn                         func() ([]meta.NodeInfo, error)
 	DeleteDataNodeFn                    func(id uint64) error
 	DeleteMetaNodeFn                    func(id uint64) error
 	DropContinuousQueryFn               func(database, name string) error
 	DropDatabaseFn                      func(name string) error
+	DropMeasurementSchemaFn             func(database, name string) error
 	DropRetentionPolicyFn               func(database, name string) error
 	DropSubscriptionFn                  func(database, rp, name string) error
 	DropShardFn                         func(id uint64) error
 	DropUserFn                          func(name string) error
+	ImportDataFn                        func(data *meta.Data, replace bool) error
 	MetaNodesFn                         func() ([]meta.NodeInfo, error)
+	MoveShardGroupFn                    func(id uint64, database, policy string) error
 	RetentionPolicyFn                   func(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
 	SetAdminPrivilegeFn                 func(username string, admin bool) error
+	SetDatabaseReadOnlyFn               func(name string, readOnly bool) error
 	SetPrivilegeFn                      func(username, database string, p influxql.Privilege) error
+	SetShardReadOnlyFn                  func(id uint64, readOnly bool) error
 	ShardGroupsByTimeRangeFn            func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
+	UpdateDatabaseQuotaFn               func(database string, qu *meta.DatabaseQuotaUpdate) error
 	UpdateRetentionPolicyFn             func(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error
 	UpdateUserFn                        func(name, password string) error
 	UserPrivilegeFn                     func(username, database string) (*influxql.Privilege, error)
@@ -39,6 +48,10 @@ type MetaClient struct {
 	UsersFn                             func() []meta.UserInfo
 }
 
+func (c *MetaClient) CopyShardGroup(id uint64, database, policy string) (*meta.ShardGroupInfo, error) {
+	return c.CopyShardGroupFn(id, database, policy)
+}
+
 func (c *MetaClient) CreateContinuousQuery(database, name, query string) error {
 	return c.CreateContinuousQueryFn(database, name, query)
 }
@@ -51,6 +64,10 @@ func (c *MetaClient) CreateDatabaseWithRetentionPolicy(name string, spec *meta.R
 	return c.CreateDatabaseWithRetentionPolicyFn(name, spec)
 }
 
+func (c *MetaClient) CreateMeasurementSchema(database string, msi meta.MeasurementSchemaInfo) error {
+	return c.CreateMeasurementSchemaFn(database, msi)
+}
+
 func (c *MetaClient) CreateRetentionPolicy(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error) {
 	return c.CreateRetentionPolicyFn(database, spec, makeDefault)
 }
@@ -75,6 +92,10 @@ func (c *MetaClient) Databases() []meta.DatabaseInfo {
 	return c.DatabasesFn()
 }
 
+func (c *MetaClient) Data() meta.Data {
+	return c.DataFn()
+}
+
 func (c *MetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
 	return c.DataNodeFn(id)
 }
@@ -99,6 +120,10 @@ func (c *MetaClient) DropDatabase(name string) error {
 	return c.DropDatabaseFn(name)
 }
 
+func (c *MetaClient) DropMeasurementSchema(database, name string) error {
+	return c.DropMeasurementSchemaFn(database, name)
+}
+
 func (c *MetaClient) DropRetentionPolicy(database, name string) error {
 	return c.DropRetentionPolicyFn(database, name)
 }
@@ -111,10 +136,18 @@ func (c *MetaClient) DropUser(name string) error {
 	return c.DropUserFn(name)
 }
 
+func (c *MetaClient) ImportData(data *meta.Data, replace bool) error {
+	return c.ImportDataFn(data, replace)
+}
+
 func (c *MetaClient) MetaNodes() ([]meta.NodeInfo, error) {
 	return c.MetaNodesFn()
 }
 
+func (c *MetaClient) MoveShardGroup(id uint64, database, policy string) error {
+	return c.MoveShardGroupFn(id, database, policy)
+}
+
 func (c *MetaClient) RetentionPolicy(database, name string) (rpi *meta.RetentionPolicyInfo, err error) {
 	return c.RetentionPolicyFn(database, name)
 }
@@ -123,14 +156,26 @@ func (c *MetaClient) SetAdminPrivilege(username string, admin bool) error {
 	return c.SetAdminPrivilegeFn(username, admin)
 }
 
+func (c *MetaClient) SetDatabaseReadOnly(name string, readOnly bool) error {
+	return c.SetDatabaseReadOnlyFn(name, readOnly)
+}
+
 func (c *MetaClient) SetPrivilege(username, database string, p influxql.Privilege) error {
 	return c.SetPrivilegeFn(username, database, p)
 }
 
+func (c *MetaClient) SetShardReadOnly(id uint64, readOnly bool) error {
+	return c.SetShardReadOnlyFn(id, readOnly)
+}
+
 func (c *MetaClient) ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
 	return c.ShardGroupsByTimeRangeFn(database, policy, min, max)
 }
 
+func (c *MetaClient) UpdateDatabaseQuota(database string, qu *meta.DatabaseQuotaUpdate) error {
+	return c.UpdateDatabaseQuotaFn(database, qu)
+}
+
 func (c *MetaClient) UpdateRetentionPolicy(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error {
 	return c.UpdateRetentionPolicyFn(database, name, rpu, makeDefault)
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/tsdb"
@@ -26,6 +27,7 @@ const (
 	statWriteErr           = "writeError"
 	statSubWriteOK         = "subWriteOk"
 	statSubWriteDrop       = "subWriteDrop"
+	statSchemaViolation    = "schemaViolation"
 )
 
 var (
@@ -58,8 +60,27 @@ type PointsWriter struct {
 	TSDBStore interface {
 		CreateShard(database, retentionPolicy string, shardID uint64, enabled bool) error
 		WriteToShard(shardID uint64, points []models.Point) error
+		DatabaseDiskSize(database string) (int64, error)
+		SeriesCardinality(database string) (int64, error)
 	}
 
+	// QuotaCheckInterval is how long the usage of a database with quotas is
+	// cached before it is measured again.
+	QuotaCheckInterval time.Duration
+
+	quotaMu sync.Mutex
+	quotas  map[string]*databaseQuota
+
+	// WriteLog, if set, records the points of every write once they are
+	// written to the shards, so that followers can replicate them.
+	WriteLog interface {
+		AppendWrite(database, retentionPolicy string, points []models.Point) error
+	}
+
+	// ReadReplica writes points to read-only databases, retention policies
+	// and shards. The writes of a read replica were accepted by its leader.
+	ReadReplica bool
+
 	subPoints []chan<- *WritePointsRequest
 
 	stats *WriteStatistics
@@ -86,10 +107,12 @@ func (w *WritePointsRequest) AddPoint(name string, value interface{}, timestamp
 // NewPointsWriter returns a new instance of PointsWriter for a node.
 func NewPointsWriter() *PointsWriter {
 	return &PointsWriter{
-		closing:      make(chan struct{}),
-		WriteTimeout: DefaultWriteTimeout,
-		Logger:       zap.New(zap.NullEncoder()),
-		stats:        &WriteStatistics{},
+		closing:            make(chan struct{}),
+		WriteTimeout:       DefaultWriteTimeout,
+		QuotaCheckInterval: DefaultQuotaCheckInterval,
+		Logger:             zap.New(zap.NullEncoder()),
+		quotas:             make(map[string]*databaseQuota),
+		stats:              &WriteStatistics{},
 	}
 }
 
@@ -163,6 +186,7 @@ type WriteStatistics struct {
 	WriteErr           int64
 	SubWriteOK         int64
 	SubWriteDrop       int64
+	SchemaViolation    int64
 }
 
 // Statistics returns statistics for periodic monitoring.
@@ -180,6 +204,7 @@ func (w *PointsWriter) Statistics(tags map[string]string) []models.Statistic {
 			statWriteErr:           atomic.LoadInt64(&w.stats.WriteErr),
 			statSubWriteOK:         atomic.LoadInt64(&w.stats.SubWriteOK),
 			statSubWriteDrop:       atomic.LoadInt64(&w.stats.SubWriteDrop),
+			statSchemaViolation:    atomic.LoadInt64(&w.stats.SchemaViolation),
 		},
 	}}
 }
@@ -292,19 +317,49 @@ func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, c
 	atomic.AddInt64(&w.stats.WriteReq, 1)
 	atomic.AddInt64(&w.stats.PointWriteReq, int64(len(points)))
 
+	db := w.MetaClient.Database(database)
 	if retentionPolicy == "" {
-		db := w.MetaClient.Database(database)
 		if db == nil {
 			return influxdb.ErrDatabaseNotFound(database)
 		}
 		retentionPolicy = db.DefaultRetentionPolicy
 	}
 
+	if db != nil && !w.ReadReplica {
+		if err := checkReadOnly(db, retentionPolicy); err != nil {
+			atomic.AddInt64(&w.stats.WriteErr, 1)
+			return err
+		}
+	}
+
+	if db != nil {
+		if err := w.checkQuotas(db); err != nil {
+			atomic.AddInt64(&w.stats.WriteErr, 1)
+			return err
+		}
+	}
+
+	// Drop the points that violate a strict measurement schema.
+	var schemaErr *tsdb.PartialWriteError
+	if db != nil && len(db.MeasurementSchemas) > 0 {
+		points, schemaErr = w.applySchemas(db, points)
+	}
+
 	shardMappings, err := w.MapShards(&WritePointsRequest{Database: database, RetentionPolicy: retentionPolicy, Points: points})
 	if err != nil {
 		return err
 	}
 
+	// Reject the whole write if one of its shards is read-only.
+	if !w.ReadReplica {
+		for _, si := range shardMappings.Shards {
+			if si.ReadOnly {
+				atomic.AddInt64(&w.stats.WriteErr, 1)
+				return influxdb.ReadOnlyError{Database: database, RetentionPolicy: retentionPolicy, ShardID: si.ID}
+			}
+		}
+	}
+
 	// Write each shard in it's own goroutine and return as soon as one fails.
 	ch := make(chan error, len(shardMappings.Points))
 	for shardID, points := range shardMappings.Points {
@@ -336,7 +391,10 @@ func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, c
 		atomic.AddInt64(&w.stats.SubWriteDrop, dropped)
 	}
 
-	if err == nil && len(shardMappings.Dropped) > 0 {
+	if err == nil && schemaErr != nil {
+		schemaErr.Dropped += len(shardMappings.Dropped)
+		err = *schemaErr
+	} else if err == nil && len(shardMappings.Dropped) > 0 {
 		err = tsdb.PartialWriteError{Reason: "points beyond retention policy", Dropped: len(shardMappings.Dropped)}
 
 	}
@@ -350,12 +408,24 @@ func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, c
 			atomic.AddInt64(&w.stats.WriteTimeout, 1)
 			// return timeout error to caller
 			return ErrTimeout
-		case err := <-ch:
-			if err != nil {
-				return err
+		case shardErr := <-ch:
+			if _, ok := shardErr.(tsdb.PartialWriteError); ok {
+				// The other points of the shard were written.
+				err = shardErr
+			} else if shardErr != nil {
+				return shardErr
 			}
 		}
 	}
+
+	// Record the write once it is in the shards, so that a write that failed
+	// is not replicated.
+	if w.WriteLog != nil && len(points) > 0 {
+		if logErr := w.WriteLog.AppendWrite(database, retentionPolicy, points); logErr != nil {
+			atomic.AddInt64(&w.stats.WriteErr, 1)
+			return fmt.Errorf("replication log: %s", logErr)
+		}
+	}
 	return err
 }
 
@@ -396,3 +466,186 @@ func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPo
 	atomic.AddInt64(&w.stats.WriteOK, 1)
 	return nil
 }
+
+// applySchemas checks points against the measurement schemas of db. Points
+// violating a strict schema are removed and reported by the returned error;
+// points violating a schema in warn mode are kept and logged.
+func (w *PointsWriter) applySchemas(db *meta.DatabaseInfo, points []models.Point) ([]models.Point, *tsdb.PartialWriteError) {
+	var (
+		kept             = make([]models.Point, 0, len(points))
+		dropped, warned  int
+		reason, warnings string
+	)
+	for _, p := range points {
+		msi := db.MeasurementSchema(string(p.Name()))
+		if msi == nil {
+			kept = append(kept, p)
+			continue
+		}
+
+		err := validatePointSchema(msi, p)
+		if err == nil {
+			kept = append(kept, p)
+			continue
+		}
+
+		atomic.AddInt64(&w.stats.SchemaViolation, 1)
+		if msi.Mode == meta.SchemaModeWarn {
+			kept = append(kept, p)
+			if warned == 0 {
+				warnings = err.Error()
+			}
+			warned++
+			continue
+		}
+		if dropped == 0 {
+			reason = err.Error()
+		}
+		dropped++
+	}
+
+	if warned > 0 {
+		w.Logger.Info(fmt.Sprintf("schema violation on database %s: %s (%d points written)", db.Name, warnings, warned))
+	}
+	if dropped == 0 {
+		return kept, nil
+	}
+	return kept, &tsdb.PartialWriteError{Reason: reason, Dropped: dropped}
+}
+
+// validatePointSchema returns an error if p does not match the tags and
+// fields declared by msi.
+func validatePointSchema(msi *meta.MeasurementSchemaInfo, p models.Point) error {
+	tags := p.Tags()
+	for _, key := range msi.RequiredTags {
+		if tags.Get([]byte(key)) == nil {
+			return fmt.Errorf("schema violation: measurement %q requires tag %q", msi.Name, key)
+		}
+	}
+
+	if len(msi.TagKeys) > 0 || len(msi.RequiredTags) > 0 {
+		for _, t := range tags {
+			if !containsString(msi.TagKeys, string(t.Key)) && !containsString(msi.RequiredTags, string(t.Key)) {
+				return fmt.Errorf("schema violation: tag %q is not in the schema of measurement %q", t.Key, msi.Name)
+			}
+		}
+	}
+
+	iter := p.FieldIterator()
+	for iter.Next() {
+		var typ influxql.DataType
+		switch iter.Type() {
+		case models.Float:
+			typ = influxql.Float
+		case models.Integer:
+			typ = influxql.Integer
+		case models.Unsigned:
+			typ = influxql.Unsigned
+		case models.Boolean:
+			typ = influxql.Boolean
+		case models.String:
+			typ = influxql.String
+		default:
+			continue
+		}
+
+		f := msi.Field(string(iter.FieldKey()))
+		if f == nil {
+			return fmt.Errorf("schema violation: field %q is not in the schema of measurement %q", iter.FieldKey(), msi.Name)
+		} else if f.Type != typ {
+			return fmt.Errorf("schema violation: field %q on measurement %q is type %s, schema requires %s", iter.FieldKey(), msi.Name, typ, f.Type)
+		}
+	}
+	return nil
+}
+
+// containsString returns true if a contains s.
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// databaseUsage is the measured disk size and series cardinality of a database.
+type databaseUsage struct {
+	diskBytes  int64
+	seriesN    int64
+	measuredAt time.Time
+}
+
+// databaseQuota holds the last usage measured for a database. Its lock is held
+// while the usage is measured, so that writes to other databases are not
+// blocked and concurrent writes to the database measure it once.
+type databaseQuota struct {
+	mu    sync.Mutex
+	usage databaseUsage
+}
+
+// checkReadOnly returns an error if the database or the retention policy is read-only.
+func checkReadOnly(di *meta.DatabaseInfo, retentionPolicy string) error {
+	if di.ReadOnly {
+		return influxdb.ReadOnlyError{Database: di.Name}
+	}
+	if rpi := di.RetentionPolicy(retentionPolicy); rpi != nil && rpi.ReadOnly {
+		return influxdb.ReadOnlyError{Database: di.Name, RetentionPolicy: rpi.Name}
+	}
+	return nil
+}
+
+// checkQuotas returns an error if the database exceeded one of its quotas.
+// Quotas are soft limits: the usage of the database is measured at most once
+// per QuotaCheckInterval and the points of a write are not counted, so a
+// database can exceed a quota by the writes of one interval.
+func (w *PointsWriter) checkQuotas(di *meta.DatabaseInfo) error {
+	if di.MaxDiskBytes <= 0 && di.MaxSeriesN <= 0 {
+		return nil
+	}
+
+	u, err := w.databaseUsage(di.Name)
+	if err != nil {
+		return err
+	}
+
+	if di.MaxDiskBytes > 0 && u.diskBytes > di.MaxDiskBytes {
+		return influxdb.QuotaExceededError{Database: di.Name, Quota: "disk", Usage: u.diskBytes, Limit: di.MaxDiskBytes}
+	} else if di.MaxSeriesN > 0 && u.seriesN > di.MaxSeriesN {
+		return influxdb.QuotaExceededError{Database: di.Name, Quota: "series", Usage: u.seriesN, Limit: di.MaxSeriesN}
+	}
+	return nil
+}
+
+// databaseUsage returns the usage of a database, measuring it again if the
+// last measurement is older than QuotaCheckInterval.
+func (w *PointsWriter) databaseUsage(database string) (databaseUsage, error) {
+	w.quotaMu.Lock()
+	q := w.quotas[database]
+	if q == nil {
+		q = &databaseQuota{}
+		w.quotas[database] = q
+	}
+	w.quotaMu.Unlock()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	if u := q.usage; !u.measuredAt.IsZero() && now.Sub(u.measuredAt) < w.QuotaCheckInterval {
+		return u, nil
+	}
+
+	diskBytes, err := w.TSDBStore.DatabaseDiskSize(database)
+	if err != nil {
+		return databaseUsage{}, err
+	}
+	seriesN, err := w.TSDBStore.SeriesCardinality(database)
+	if err != nil {
+		return databaseUsage{}, err
+	}
+
+	u := databaseUsage{diskBytes: diskBytes, seriesN: seriesN, measuredAt: now}
+	q.usage = u
+	return u, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/coordinator"
+	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/tsdb"
@@ -436,6 +437,330 @@ func (f *fakePointsWriter) WritePointsInto(req *coordinator.IntoWriteRequest) er
 	return f.WritePointsIntoFn(req)
 }
 
+func TestPointsWriter_WritePoints_QuotaExceeded(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		di        meta.DatabaseInfo
+		diskBytes int64
+		seriesN   int64
+		exp       error
+	}{
+		{name: "no quota", di: meta.DatabaseInfo{Name: "mydb"}, diskBytes: 1000, seriesN: 1000},
+		{name: "within quotas", di: meta.DatabaseInfo{Name: "mydb", MaxDiskBytes: 1000, MaxSeriesN: 10}, diskBytes: 1000, seriesN: 10},
+		{
+			name:      "disk",
+			di:        meta.DatabaseInfo{Name: "mydb", MaxDiskBytes: 1000},
+			diskBytes: 1001,
+			exp:       influxdb.QuotaExceededError{Database: "mydb", Quota: "disk", Usage: 1001, Limit: 1000},
+		},
+		{
+			name:    "series",
+			di:      meta.DatabaseInfo{Name: "mydb", MaxSeriesN: 10},
+			seriesN: 11,
+			exp:     influxdb.QuotaExceededError{Database: "mydb", Quota: "series", Usage: 11, Limit: 10},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := NewPointsWriterMetaClient()
+			ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+				return &tt.di
+			}
+
+			var written, measured int
+			store := &fakeStore{
+				WriteFn: func(shardID uint64, points []models.Point) error {
+					written++
+					return nil
+				},
+				DatabaseDiskSizeFn: func(database string) (int64, error) {
+					measured++
+					return tt.diskBytes, nil
+				},
+				SeriesCardinalityFn: func(database string) (int64, error) {
+					return tt.seriesN, nil
+				},
+			}
+
+			c := coordinator.NewPointsWriter()
+			c.MetaClient = ms
+			c.TSDBStore = store
+			c.Open()
+			defer c.Close()
+
+			pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+			pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+			for i := 0; i < 2; i++ {
+				if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); !reflect.DeepEqual(err, tt.exp) {
+					t.Fatalf("unexpected error: got %v, exp %v", err, tt.exp)
+				}
+			}
+
+			if tt.exp != nil && written != 0 {
+				t.Fatalf("unexpected writes: %d", written)
+			} else if tt.exp == nil && written != 2 {
+				t.Fatalf("unexpected writes: %d", written)
+			}
+
+			// The usage is only measured for databases with quotas, once per
+			// quota check interval.
+			exp := 1
+			if tt.di.MaxDiskBytes == 0 && tt.di.MaxSeriesN == 0 {
+				exp = 0
+			}
+			if measured != exp {
+				t.Fatalf("unexpected measurements: got %d, exp %d", measured, exp)
+			}
+		})
+	}
+}
+
+// Ensure measuring the usage of a database does not block writes to others.
+func TestPointsWriter_WritePoints_QuotaMeasuredPerDatabase(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{Name: database, MaxDiskBytes: 1000}
+	}
+
+	measuring, release := make(chan struct{}), make(chan struct{})
+	store := &fakeStore{
+		WriteFn: func(shardID uint64, points []models.Point) error { return nil },
+		DatabaseDiskSizeFn: func(database string) (int64, error) {
+			if database == "slowdb" {
+				close(measuring)
+				<-release
+			}
+			return 0, nil
+		},
+		SeriesCardinalityFn: func(database string) (int64, error) { return 0, nil },
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.TSDBStore = store
+	c.Open()
+	defer c.Close()
+
+	pr := &coordinator.WritePointsRequest{RetentionPolicy: "myrp"}
+	pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+	slow := make(chan error)
+	go func() {
+		slow <- c.WritePointsPrivileged("slowdb", pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+	}()
+	<-measuring
+
+	done := make(chan error)
+	go func() {
+		done <- c.WritePointsPrivileged("mydb", pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("write blocked by the measurement of another database")
+	}
+
+	close(release)
+	if err := <-slow; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPointsWriter_WritePoints_ReadOnly(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		dbReadOnly  bool
+		rpReadOnly  bool
+		shReadOnly  bool
+		readReplica bool
+		exp         error
+	}{
+		{name: "writable"},
+		{name: "database", dbReadOnly: true, exp: influxdb.ReadOnlyError{Database: "mydb"}},
+		{name: "retention policy", rpReadOnly: true, exp: influxdb.ReadOnlyError{Database: "mydb", RetentionPolicy: "myrp"}},
+		{name: "shard", shReadOnly: true, exp: influxdb.ReadOnlyError{Database: "mydb", RetentionPolicy: "myrp"}},
+		{name: "read replica", dbReadOnly: true, readReplica: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := NewPointsWriterMetaClient()
+			ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+				return &meta.DatabaseInfo{
+					Name:              "mydb",
+					ReadOnly:          tt.dbReadOnly,
+					RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "myrp", ReadOnly: tt.rpReadOnly}},
+				}
+			}
+			createShardGroup := ms.CreateShardGroupIfNotExistsFn
+			var shardID uint64
+			ms.CreateShardGroupIfNotExistsFn = func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+				sgi, err := createShardGroup(database, policy, timestamp)
+				sgi.Shards[0].ReadOnly = tt.shReadOnly
+				shardID = sgi.Shards[0].ID
+				return sgi, err
+			}
+
+			var written int
+			store := &fakeStore{
+				WriteFn: func(shardID uint64, points []models.Point) error {
+					written += len(points)
+					return nil
+				},
+			}
+
+			c := coordinator.NewPointsWriter()
+			c.MetaClient = ms
+			c.TSDBStore = store
+			c.ReadReplica = tt.readReplica
+			c.Open()
+			defer c.Close()
+
+			pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+			pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+			err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+
+			exp, expWritten := tt.exp, 1
+			if e, ok := exp.(influxdb.ReadOnlyError); ok {
+				if tt.shReadOnly {
+					e.ShardID = shardID
+				}
+				exp, expWritten = e, 0
+			}
+			if !reflect.DeepEqual(err, exp) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, exp)
+			} else if written != expWritten {
+				t.Fatalf("unexpected points written: got %d, exp %d", written, expWritten)
+			}
+		})
+	}
+}
+
+// Ensure a write is recorded in the write log only once it is in the shards.
+func TestPointsWriter_WritePoints_WriteLog(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		err    error
+		logged bool
+	}{
+		{name: "ok", logged: true},
+		{name: "partial", err: tsdb.PartialWriteError{Reason: "field type conflict", Dropped: 1}, logged: true},
+		{name: "failed", err: fmt.Errorf("write failed")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{
+				WriteFn: func(shardID uint64, points []models.Point) error {
+					return tt.err
+				},
+			}
+			wl := &fakeWriteLog{}
+
+			ms := NewPointsWriterMetaClient()
+			ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+				return &meta.DatabaseInfo{Name: database}
+			}
+
+			c := coordinator.NewPointsWriter()
+			c.MetaClient = ms
+			c.TSDBStore = store
+			c.WriteLog = wl
+			c.Open()
+			defer c.Close()
+
+			pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+			pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+			if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); !reflect.DeepEqual(err, tt.err) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tt.err)
+			} else if got, exp := len(wl.points) > 0, tt.logged; got != exp {
+				t.Fatalf("unexpected write logged: got %v, exp %v", got, exp)
+			}
+		})
+	}
+}
+
+func TestPointsWriter_WritePoints_MeasurementSchema(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		mode    string
+		tags    map[string]string
+		value   interface{}
+		written int
+		exp     error
+	}{
+		{name: "valid", mode: meta.SchemaModeStrict, tags: map[string]string{"host": "a"}, value: 1.0, written: 2},
+		{
+			name:  "missing tag",
+			mode:  meta.SchemaModeStrict,
+			value: 1.0,
+			exp:   tsdb.PartialWriteError{Reason: `schema violation: measurement "cpu" requires tag "host"`, Dropped: 1},
+		},
+		{
+			name:  "unknown tag",
+			mode:  meta.SchemaModeStrict,
+			tags:  map[string]string{"host": "a", "region": "west"},
+			value: 1.0,
+			exp:   tsdb.PartialWriteError{Reason: `schema violation: tag "region" is not in the schema of measurement "cpu"`, Dropped: 1},
+		},
+		{
+			name:  "field type",
+			mode:  meta.SchemaModeStrict,
+			tags:  map[string]string{"host": "a"},
+			value: int64(1),
+			exp:   tsdb.PartialWriteError{Reason: `schema violation: field "value" on measurement "cpu" is type integer, schema requires float`, Dropped: 1},
+		},
+		{name: "warn", mode: meta.SchemaModeWarn, value: int64(1), written: 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := NewPointsWriterMetaClient()
+			ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+				return &meta.DatabaseInfo{
+					Name: "mydb",
+					MeasurementSchemas: []meta.MeasurementSchemaInfo{{
+						Name:         "cpu",
+						Mode:         tt.mode,
+						RequiredTags: []string{"host"},
+						Fields:       []meta.FieldSchemaInfo{{Name: "value", Type: influxql.Float}},
+					}},
+				}
+			}
+
+			var written int
+			store := &fakeStore{
+				WriteFn: func(shardID uint64, points []models.Point) error {
+					written += len(points)
+					return nil
+				},
+			}
+
+			c := coordinator.NewPointsWriter()
+			c.MetaClient = ms
+			c.TSDBStore = store
+			c.Open()
+			defer c.Close()
+
+			// Measurements without a schema are not checked.
+			pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+			pr.AddPoint("cpu", tt.value, time.Now(), tt.tags)
+			pr.AddPoint("mem", "anything", time.Now(), map[string]string{"region": "west"})
+
+			if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); !reflect.DeepEqual(err, tt.exp) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tt.exp)
+			}
+
+			exp := tt.written
+			if tt.exp != nil {
+				exp = 1
+			}
+			if written != exp {
+				t.Fatalf("unexpected points written: got %d, exp %d", written, exp)
+			}
+		})
+	}
+}
+
 func TestBufferedPointsWriter(t *testing.T) {
 	db := "db0"
 	rp := "rp0"
@@ -548,9 +873,20 @@ func TestBufferedPointsWriter(t *testing.T) {
 
 var shardID uint64
 
+type fakeWriteLog struct {
+	points []models.Point
+}
+
+func (f *fakeWriteLog) AppendWrite(database, retentionPolicy string, points []models.Point) error {
+	f.points = append(f.points, points...)
+	return nil
+}
+
 type fakeStore struct {
-	WriteFn       func(shardID uint64, points []models.Point) error
-	CreateShardfn func(database, retentionPolicy string, shardID uint64, enabled bool) error
+	WriteFn             func(shardID uint64, points []models.Point) error
+	CreateShardfn       func(database, retentionPolicy string, shardID uint64, enabled bool) error
+	DatabaseDiskSizeFn  func(database string) (int64, error)
+	SeriesCardinalityFn func(database string) (int64, error)
 }
 
 func (f *fakeStore) WriteToShard(shardID uint64, points []models.Point) error {
@@ -561,6 +897,14 @@ func (f *fakeStore) CreateShard(database, retentionPolicy string, shardID uint64
 	return f.CreateShardfn(database, retentionPolicy, shardID, enabled)
 }
 
+func (f *fakeStore) DatabaseDiskSize(database string) (int64, error) {
+	return f.DatabaseDiskSizeFn(database)
+}
+
+func (f *fakeStore) SeriesCardinality(database string) (int64, error) {
+	return f.SeriesCardinalityFn(database)
+}
+
 func NewPointsWriterMetaClient() *PointsWriterMetaClient {
 	ms := &PointsWriterMetaClient{}
 	rp := NewRetentionPolicy("myp", time.Hour, 3)
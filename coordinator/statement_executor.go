@@ -3,12 +3,14 @@ package coordinator
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/influxdata/influxdb"
@@ -53,24 +55,56 @@ type StatementExecutor struct {
 	MaxSelectPointN   int
 	MaxSelectSeriesN  int
 	MaxSelectBucketsN int
+
+	// SlowQueryTracer, if set, writes the traces of slow SELECT statements.
+	SlowQueryTracer *SlowQueryTracer
+
+	// ReadReplica rejects the statements that write data or change the meta
+	// store, which are only applied by the leader of a read replica.
+	ReadReplica bool
+
+	// StoreLog, if set, records the deletes, moves and copies applied to the
+	// local store, so that followers can replicate them.
+	StoreLog interface {
+		AppendDeleteSeries(database string, sources influxql.Sources, condition influxql.Expr) error
+		AppendDeleteMeasurement(database, name string) error
+		AppendMoveShard(id uint64, database, retentionPolicy string) error
+		AppendCopyShard(id uint64, database, retentionPolicy string, newID uint64) error
+	}
+
+	mu sync.RWMutex
 }
 
 // ExecuteStatement executes the given statement with the given execution context.
 func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx query.ExecutionContext) error {
+	if e.ReadReplica && !isReadStatement(stmt) {
+		return influxdb.ErrReadReplica
+	}
+
 	// Select statements are handled separately so that they can be streamed.
 	if stmt, ok := stmt.(*influxql.SelectStatement); ok {
-		return e.executeSelectStatement(context.Background(), stmt, &ctx)
+		return e.executeTracedSelectStatement(stmt, &ctx)
 	}
 
 	var rows models.Rows
 	var messages []*query.Message
 	var err error
 	switch stmt := stmt.(type) {
+	case *influxql.AlterDatabaseStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterDatabaseStatement(stmt)
 	case *influxql.AlterRetentionPolicyStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeAlterRetentionPolicyStatement(stmt)
+	case *influxql.AlterShardStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterShardStatement(stmt)
 	case *influxql.CreateContinuousQueryStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -86,6 +120,11 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx query.
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeCreateRetentionPolicyStatement(stmt)
+	case *influxql.CreateSchemaStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeCreateSchemaStatement(stmt)
 	case *influxql.CreateSubscriptionStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -123,6 +162,11 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx query.
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeDropRetentionPolicyStatement(stmt)
+	case *influxql.DropSchemaStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropSchemaStatement(stmt)
 	case *influxql.DropShardStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -144,6 +188,13 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx query.
 		} else {
 			rows, err = e.executeExplainStatement(stmt, &ctx)
 		}
+	case *influxql.ExportMetaStatement:
+		rows, err = e.executeExportMetaStatement(stmt)
+	case *influxql.ImportMetaStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeImportMetaStatement(stmt)
 	case *influxql.GrantStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -154,6 +205,11 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx query.
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeGrantAdminStatement(stmt)
+	case *influxql.MoveShardStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeMoveShardStatement(stmt)
 	case *influxql.RevokeStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -176,8 +232,12 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx query.
 		return e.executeShowMeasurementsStatement(stmt, &ctx)
 	case *influxql.ShowMeasurementCardinalityStatement:
 		rows, err = e.executeShowMeasurementCardinalityStatement(stmt)
+	case *influxql.ShowQuotasStatement:
+		rows, err = e.executeShowQuotasStatement(stmt, &ctx)
 	case *influxql.ShowRetentionPoliciesStatement:
 		rows, err = e.executeShowRetentionPoliciesStatement(stmt)
+	case *influxql.ShowSchemasStatement:
+		rows, err = e.executeShowSchemasStatement(stmt)
 	case *influxql.ShowSeriesCardinalityStatement:
 		rows, err = e.executeShowSeriesCardinalityStatement(stmt)
 	case *influxql.ShowShardsStatement:
@@ -215,11 +275,29 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx query.
 	})
 }
 
+func (e *StatementExecutor) executeAlterDatabaseStatement(stmt *influxql.AlterDatabaseStatement) error {
+	if stmt.MaxDiskBytes != nil || stmt.MaxSeriesN != nil {
+		qu := &meta.DatabaseQuotaUpdate{
+			MaxDiskBytes: stmt.MaxDiskBytes,
+			MaxSeriesN:   stmt.MaxSeriesN,
+		}
+		if err := e.MetaClient.UpdateDatabaseQuota(stmt.Name, qu); err != nil {
+			return err
+		}
+	}
+
+	if stmt.ReadOnly != nil {
+		return e.MetaClient.SetDatabaseReadOnly(stmt.Name, *stmt.ReadOnly)
+	}
+	return nil
+}
+
 func (e *StatementExecutor) executeAlterRetentionPolicyStatement(stmt *influxql.AlterRetentionPolicyStatement) error {
 	rpu := &meta.RetentionPolicyUpdate{
 		Duration:           stmt.Duration,
 		ReplicaN:           stmt.Replication,
 		ShardGroupDuration: stmt.ShardGroupDuration,
+		ReadOnly:           stmt.ReadOnly,
 	}
 
 	// Update the retention policy.
@@ -229,6 +307,10 @@ func (e *StatementExecutor) executeAlterRetentionPolicyStatement(stmt *influxql.
 	return nil
 }
 
+func (e *StatementExecutor) executeAlterShardStatement(stmt *influxql.AlterShardStatement) error {
+	return e.MetaClient.SetShardReadOnly(stmt.ID, stmt.ReadOnly)
+}
+
 func (e *StatementExecutor) executeCreateContinuousQueryStatement(q *influxql.CreateContinuousQueryStatement) error {
 	// Verify that retention policies exist.
 	var err error
@@ -311,6 +393,26 @@ func (e *StatementExecutor) executeCreateRetentionPolicyStatement(stmt *influxql
 	return nil
 }
 
+func (e *StatementExecutor) executeCreateSchemaStatement(stmt *influxql.CreateSchemaStatement) error {
+	if stmt.Database == "" {
+		return ErrDatabaseNameRequired
+	}
+
+	msi := meta.MeasurementSchemaInfo{
+		Name:         stmt.Name,
+		Mode:         meta.SchemaModeStrict,
+		TagKeys:      stmt.TagKeys,
+		RequiredTags: stmt.RequiredTags,
+	}
+	if stmt.Warn {
+		msi.Mode = meta.SchemaModeWarn
+	}
+	for _, f := range stmt.Fields {
+		msi.Fields = append(msi.Fields, meta.FieldSchemaInfo{Name: f.Name, Type: f.Type})
+	}
+	return e.MetaClient.CreateMeasurementSchema(stmt.Database, msi)
+}
+
 func (e *StatementExecutor) executeCreateSubscriptionStatement(q *influxql.CreateSubscriptionStatement) error {
 	return e.MetaClient.CreateSubscription(q.Database, q.RetentionPolicy, q.Name, q.Mode, q.Destinations)
 }
@@ -323,13 +425,18 @@ func (e *StatementExecutor) executeCreateUserStatement(q *influxql.CreateUserSta
 func (e *StatementExecutor) executeDeleteSeriesStatement(stmt *influxql.DeleteSeriesStatement, database string) error {
 	if dbi := e.MetaClient.Database(database); dbi == nil {
 		return query.ErrDatabaseNotFound(database)
+	} else if err := readOnlyError(dbi); err != nil {
+		return err
 	}
 
 	// Convert "now()" to current time.
 	stmt.Condition = influxql.Reduce(stmt.Condition, &influxql.NowValuer{Now: time.Now().UTC()})
 
 	// Locally delete the series.
-	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
+	if err := e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition); err != nil {
+		return err
+	}
+	return e.logDeleteSeries(database, stmt.Sources, stmt.Condition)
 }
 
 func (e *StatementExecutor) executeDropContinuousQueryStatement(q *influxql.DropContinuousQueryStatement) error {
@@ -356,15 +463,27 @@ func (e *StatementExecutor) executeDropDatabaseStatement(stmt *influxql.DropData
 func (e *StatementExecutor) executeDropMeasurementStatement(stmt *influxql.DropMeasurementStatement, database string) error {
 	if dbi := e.MetaClient.Database(database); dbi == nil {
 		return query.ErrDatabaseNotFound(database)
+	} else if err := readOnlyError(dbi); err != nil {
+		return err
 	}
 
 	// Locally drop the measurement
-	return e.TSDBStore.DeleteMeasurement(database, stmt.Name)
+	if err := e.TSDBStore.DeleteMeasurement(database, stmt.Name); err != nil {
+		return err
+	}
+	if e.StoreLog != nil {
+		if err := e.StoreLog.AppendDeleteMeasurement(database, stmt.Name); err != nil {
+			return fmt.Errorf("replication log: %s", err)
+		}
+	}
+	return nil
 }
 
 func (e *StatementExecutor) executeDropSeriesStatement(stmt *influxql.DropSeriesStatement, database string) error {
 	if dbi := e.MetaClient.Database(database); dbi == nil {
 		return query.ErrDatabaseNotFound(database)
+	} else if err := readOnlyError(dbi); err != nil {
+		return err
 	}
 
 	// Check for time in WHERE clause (not supported).
@@ -373,7 +492,27 @@ func (e *StatementExecutor) executeDropSeriesStatement(stmt *influxql.DropSeries
 	}
 
 	// Locally drop the series.
-	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
+	if err := e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition); err != nil {
+		return err
+	}
+	return e.logDeleteSeries(database, stmt.Sources, stmt.Condition)
+}
+
+// logDeleteSeries records a delete of series in the StoreLog, if set.
+func (e *StatementExecutor) logDeleteSeries(database string, sources influxql.Sources, condition influxql.Expr) error {
+	if e.StoreLog == nil {
+		return nil
+	} else if err := e.StoreLog.AppendDeleteSeries(database, sources, condition); err != nil {
+		return fmt.Errorf("replication log: %s", err)
+	}
+	return nil
+}
+
+func (e *StatementExecutor) executeDropSchemaStatement(stmt *influxql.DropSchemaStatement) error {
+	if stmt.Database == "" {
+		return ErrDatabaseNameRequired
+	}
+	return e.MetaClient.DropMeasurementSchema(stmt.Database, stmt.Name)
 }
 
 func (e *StatementExecutor) executeDropShardStatement(stmt *influxql.DropShardStatement) error {
@@ -413,13 +552,7 @@ func (e *StatementExecutor) executeDropUserStatement(q *influxql.DropUserStateme
 }
 
 func (e *StatementExecutor) executeExplainStatement(q *influxql.ExplainStatement, ectx *query.ExecutionContext) (models.Rows, error) {
-	opt := query.SelectOptions{
-		InterruptCh: ectx.InterruptCh,
-		NodeID:      ectx.ExecutionOptions.NodeID,
-		MaxSeriesN:  e.MaxSelectSeriesN,
-		MaxBucketsN: e.MaxSelectBucketsN,
-		Authorizer:  ectx.Authorizer,
-	}
+	opt, _ := e.selectOptions(ectx)
 
 	// Prepare the query for execution, but do not actually execute it.
 	// This should perform any needed substitutions.
@@ -525,6 +658,93 @@ func (e *StatementExecutor) executeGrantAdminStatement(stmt *influxql.GrantAdmin
 	return e.MetaClient.SetAdminPrivilege(stmt.User, true)
 }
 
+func (e *StatementExecutor) executeMoveShardStatement(stmt *influxql.MoveShardStatement) error {
+	database, policy, sgi := e.shardGroupOf(stmt.ID, stmt.Group)
+	if sgi == nil {
+		if stmt.Group {
+			return meta.ErrShardGroupNotFound
+		}
+		return meta.ErrShardNotFound
+	} else if !stmt.Group && len(sgi.Shards) > 1 {
+		return fmt.Errorf("shard %d shares shard group %d with other shards, move the shard group", stmt.ID, sgi.ID)
+	}
+
+	if stmt.Copy {
+		// The meta store allocates the IDs of the copies.
+		copied, err := e.MetaClient.CopyShardGroup(sgi.ID, stmt.Database, stmt.RetentionPolicy)
+		if err != nil {
+			return err
+		}
+		for i, si := range sgi.Shards {
+			if err := e.TSDBStore.CopyShard(si.ID, stmt.Database, stmt.RetentionPolicy, copied.Shards[i].ID); err != nil {
+				// Drop the incomplete copy.
+				for _, si := range copied.Shards {
+					e.TSDBStore.DeleteShard(si.ID)
+					e.MetaClient.DropShard(si.ID)
+				}
+				return err
+			}
+		}
+		if e.StoreLog != nil {
+			for i, si := range sgi.Shards {
+				if err := e.StoreLog.AppendCopyShard(si.ID, stmt.Database, stmt.RetentionPolicy, copied.Shards[i].ID); err != nil {
+					return fmt.Errorf("replication log: %s", err)
+				}
+			}
+		}
+		return nil
+	}
+
+	// Move the shard group in the meta store first so the target is validated
+	// before any data is moved.
+	if err := e.MetaClient.MoveShardGroup(sgi.ID, stmt.Database, stmt.RetentionPolicy); err != nil {
+		return err
+	}
+	for i, si := range sgi.Shards {
+		if err := e.TSDBStore.MoveShard(si.ID, stmt.Database, stmt.RetentionPolicy); err != nil {
+			// Move the shard group back.
+			for _, si := range sgi.Shards[:i] {
+				e.TSDBStore.MoveShard(si.ID, database, policy)
+			}
+			e.MetaClient.MoveShardGroup(sgi.ID, database, policy)
+			return err
+		}
+	}
+	if e.StoreLog != nil {
+		for _, si := range sgi.Shards {
+			if err := e.StoreLog.AppendMoveShard(si.ID, stmt.Database, stmt.RetentionPolicy); err != nil {
+				return fmt.Errorf("replication log: %s", err)
+			}
+		}
+	}
+	return nil
+}
+
+// shardGroupOf returns the database, retention policy and shard group of the
+// shard with the id, or of the shard group with the id if group is true.
+func (e *StatementExecutor) shardGroupOf(id uint64, group bool) (string, string, *meta.ShardGroupInfo) {
+	for _, dbi := range e.MetaClient.Databases() {
+		for _, rpi := range dbi.RetentionPolicies {
+			for i := range rpi.ShardGroups {
+				sgi := &rpi.ShardGroups[i]
+				if sgi.Deleted() {
+					continue
+				} else if group && sgi.ID == id {
+					return dbi.Name, rpi.Name, sgi
+				} else if group {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					if si.ID == id {
+						return dbi.Name, rpi.Name, sgi
+					}
+				}
+			}
+		}
+	}
+	return "", "", nil
+}
+
 func (e *StatementExecutor) executeRevokeStatement(stmt *influxql.RevokeStatement) error {
 	priv := influxql.NoPrivileges
 
@@ -645,13 +865,7 @@ func (e *StatementExecutor) executeSelectStatement(ctx context.Context, stmt *in
 }
 
 func (e *StatementExecutor) createIterators(ctx context.Context, stmt *influxql.SelectStatement, ectx *query.ExecutionContext) ([]query.Iterator, []string, error) {
-	opt := query.SelectOptions{
-		InterruptCh: ectx.InterruptCh,
-		NodeID:      ectx.ExecutionOptions.NodeID,
-		MaxSeriesN:  e.MaxSelectSeriesN,
-		MaxBucketsN: e.MaxSelectBucketsN,
-		Authorizer:  ectx.Authorizer,
-	}
+	opt, maxPointN := e.selectOptions(ectx)
 
 	// Create a set of iterators from a selection.
 	itrs, columns, err := query.Select(ctx, stmt, e.ShardMapper, opt)
@@ -659,13 +873,38 @@ func (e *StatementExecutor) createIterators(ctx context.Context, stmt *influxql.
 		return nil, nil, err
 	}
 
-	if e.MaxSelectPointN > 0 {
-		monitor := query.PointLimitMonitor(itrs, query.DefaultStatsInterval, e.MaxSelectPointN)
+	if maxPointN > 0 {
+		monitor := query.PointLimitMonitor(itrs, query.DefaultStatsInterval, maxPointN)
 		ectx.Query.Monitor(monitor)
 	}
 	return itrs, columns, nil
 }
 
+// selectOptions returns the options of a SELECT statement executed with ectx
+// and the maximum number of points the statement can process.
+func (e *StatementExecutor) selectOptions(ectx *query.ExecutionContext) (query.SelectOptions, int) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return query.SelectOptions{
+		InterruptCh: ectx.InterruptCh,
+		NodeID:      ectx.ExecutionOptions.NodeID,
+		MaxSeriesN:  e.MaxSelectSeriesN,
+		MaxBucketsN: e.MaxSelectBucketsN,
+		Authorizer:  ectx.Authorizer,
+	}, e.MaxSelectPointN
+}
+
+// Reload applies the select statement limits and the slow query tracing
+// settings of c. Running statements keep the settings they started with.
+func (e *StatementExecutor) Reload(c Config) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.MaxSelectPointN = c.MaxSelectPointN
+	e.MaxSelectSeriesN = c.MaxSelectSeriesN
+	e.MaxSelectBucketsN = c.MaxSelectBucketsN
+	e.SlowQueryTracer = c.SlowQueryTracer()
+}
+
 func (e *StatementExecutor) executeShowContinuousQueriesStatement(stmt *influxql.ShowContinuousQueriesStatement) (models.Rows, error) {
 	dis := e.MetaClient.Databases()
 
@@ -694,6 +933,31 @@ func (e *StatementExecutor) executeShowDatabasesStatement(q *influxql.ShowDataba
 	return []*models.Row{row}, nil
 }
 
+func (e *StatementExecutor) executeExportMetaStatement(stmt *influxql.ExportMetaStatement) (models.Rows, error) {
+	data := e.MetaClient.Data()
+	if stmt.WithoutHashes {
+		data.RedactHashes()
+	}
+
+	b, err := json.Marshal(&data)
+	if err != nil {
+		return nil, err
+	}
+	return []*models.Row{{
+		Name:    "meta",
+		Columns: []string{"json"},
+		Values:  [][]interface{}{{string(b)}},
+	}}, nil
+}
+
+func (e *StatementExecutor) executeImportMetaStatement(stmt *influxql.ImportMetaStatement) error {
+	var data meta.Data
+	if err := json.Unmarshal([]byte(stmt.Data), &data); err != nil {
+		return fmt.Errorf("invalid meta data: %s", err)
+	}
+	return e.MetaClient.ImportData(&data, stmt.Replace)
+}
+
 func (e *StatementExecutor) executeShowDiagnosticsStatement(stmt *influxql.ShowDiagnosticsStatement) (models.Rows, error) {
 	diags, err := e.Monitor.Diagnostics()
 	if err != nil {
@@ -795,6 +1059,39 @@ func (e *StatementExecutor) executeShowMeasurementCardinalityStatement(stmt *inf
 	}}, nil
 }
 
+func (e *StatementExecutor) executeShowQuotasStatement(q *influxql.ShowQuotasStatement, ctx *query.ExecutionContext) (models.Rows, error) {
+	var dis []meta.DatabaseInfo
+	if q.Database != "" {
+		di := e.MetaClient.Database(q.Database)
+		if di == nil {
+			return nil, influxdb.ErrDatabaseNotFound(q.Database)
+		}
+		dis = append(dis, *di)
+	} else {
+		dis = e.MetaClient.Databases()
+	}
+	a := ctx.ExecutionOptions.Authorizer
+
+	row := &models.Row{Name: "quotas", Columns: []string{"name", "disk_bytes", "disk_quota", "series", "series_quota"}}
+	for _, di := range dis {
+		// Only include databases that the user is authorized to read or write.
+		if !a.AuthorizeDatabase(influxql.ReadPrivilege, di.Name) && !a.AuthorizeDatabase(influxql.WritePrivilege, di.Name) {
+			continue
+		}
+
+		diskBytes, err := e.TSDBStore.DatabaseDiskSize(di.Name)
+		if err != nil {
+			return nil, err
+		}
+		seriesN, err := e.TSDBStore.SeriesCardinality(di.Name)
+		if err != nil {
+			return nil, err
+		}
+		row.Values = append(row.Values, []interface{}{di.Name, diskBytes, di.MaxDiskBytes, seriesN, di.MaxSeriesN})
+	}
+	return []*models.Row{row}, nil
+}
+
 func (e *StatementExecutor) executeShowRetentionPoliciesStatement(q *influxql.ShowRetentionPoliciesStatement) (models.Rows, error) {
 	if q.Database == "" {
 		return nil, ErrDatabaseNameRequired
@@ -812,6 +1109,33 @@ func (e *StatementExecutor) executeShowRetentionPoliciesStatement(q *influxql.Sh
 	return []*models.Row{row}, nil
 }
 
+func (e *StatementExecutor) executeShowSchemasStatement(q *influxql.ShowSchemasStatement) (models.Rows, error) {
+	if q.Database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+
+	di := e.MetaClient.Database(q.Database)
+	if di == nil {
+		return nil, influxdb.ErrDatabaseNotFound(q.Database)
+	}
+
+	row := &models.Row{Columns: []string{"measurement", "mode", "tag_keys", "required_tags", "fields"}}
+	for _, msi := range di.MeasurementSchemas {
+		fields := make([]string, len(msi.Fields))
+		for i, f := range msi.Fields {
+			fields[i] = f.Name + " " + f.Type.String()
+		}
+		row.Values = append(row.Values, []interface{}{
+			msi.Name,
+			msi.Mode,
+			strings.Join(msi.TagKeys, ", "),
+			strings.Join(msi.RequiredTags, ", "),
+			strings.Join(fields, ", "),
+		})
+	}
+	return []*models.Row{row}, nil
+}
+
 func (e *StatementExecutor) executeShowShardsStatement(stmt *influxql.ShowShardsStatement) (models.Rows, error) {
 	dis := e.MetaClient.Databases()
 
@@ -1155,6 +1479,68 @@ func convertRowToPoints(measurementName string, row *models.Row) ([]models.Point
 	return points, nil
 }
 
+// readOnlyError returns an error if the database, or one of its retention
+// policies or shards, is read-only. Deleting series changes every shard of
+// a database.
+func readOnlyError(dbi *meta.DatabaseInfo) error {
+	if dbi.ReadOnly {
+		return influxdb.ReadOnlyError{Database: dbi.Name}
+	}
+	for _, rpi := range dbi.RetentionPolicies {
+		if rpi.ReadOnly {
+			return influxdb.ReadOnlyError{Database: dbi.Name, RetentionPolicy: rpi.Name}
+		}
+		for _, sgi := range rpi.ShardGroups {
+			if sgi.Deleted() {
+				continue
+			}
+			for _, si := range sgi.Shards {
+				if si.ReadOnly {
+					return influxdb.ReadOnlyError{Database: dbi.Name, RetentionPolicy: rpi.Name, ShardID: si.ID}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isReadStatement returns true if stmt neither writes data nor changes the meta store.
+func isReadStatement(stmt influxql.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *influxql.SelectStatement:
+		return stmt.Target == nil
+	case *influxql.ExplainStatement:
+		return stmt.Statement.Target == nil
+	case *influxql.ExportMetaStatement,
+		*influxql.KillQueryStatement,
+		*influxql.ShowContinuousQueriesStatement,
+		*influxql.ShowDatabasesStatement,
+		*influxql.ShowDiagnosticsStatement,
+		*influxql.ShowFieldKeyCardinalityStatement,
+		*influxql.ShowFieldKeysStatement,
+		*influxql.ShowGrantsForUserStatement,
+		*influxql.ShowMeasurementCardinalityStatement,
+		*influxql.ShowMeasurementsStatement,
+		*influxql.ShowQueriesStatement,
+		*influxql.ShowQuotasStatement,
+		*influxql.ShowRetentionPoliciesStatement,
+		*influxql.ShowSchemasStatement,
+		*influxql.ShowSeriesCardinalityStatement,
+		*influxql.ShowSeriesStatement,
+		*influxql.ShowShardGroupsStatement,
+		*influxql.ShowShardsStatement,
+		*influxql.ShowStatsStatement,
+		*influxql.ShowSubscriptionsStatement,
+		*influxql.ShowTagKeyCardinalityStatement,
+		*influxql.ShowTagKeysStatement,
+		*influxql.ShowTagValuesCardinalityStatement,
+		*influxql.ShowTagValuesStatement,
+		*influxql.ShowUsersStatement:
+		return true
+	}
+	return false
+}
+
 // NormalizeStatement adds a default database and policy to the measurements in statement.
 func (e *StatementExecutor) NormalizeStatement(stmt influxql.Statement, defaultDatabase string) (err error) {
 	influxql.WalkFunc(stmt, func(node influxql.Node) {
@@ -1166,6 +1552,18 @@ func (e *StatementExecutor) NormalizeStatement(stmt influxql.Statement, defaultD
 			if node.Database == "" {
 				node.Database = defaultDatabase
 			}
+		case *influxql.CreateSchemaStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
+		case *influxql.DropSchemaStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
+		case *influxql.ShowSchemasStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
 		case *influxql.ShowMeasurementsStatement:
 			if node.Database == "" {
 				node.Database = defaultDatabase
@@ -1250,11 +1648,15 @@ type TSDBStore interface {
 	DeleteSeries(database string, sources []influxql.Source, condition influxql.Expr) error
 	DeleteShard(id uint64) error
 
+	MoveShard(id uint64, database, policy string) error
+	CopyShard(id uint64, database, policy string, newID uint64) error
+
 	MeasurementNames(database string, cond influxql.Expr) ([][]byte, error)
 	TagValues(auth query.Authorizer, database string, cond influxql.Expr) ([]tsdb.TagValues, error)
 
 	SeriesCardinality(database string) (int64, error)
 	MeasurementsCardinality(database string) (int64, error)
+	DatabaseDiskSize(database string) (int64, error)
 }
 
 var _ TSDBStore = LocalTSDBStore{}
@@ -3,9 +3,13 @@ package coordinator_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"testing"
@@ -16,6 +20,7 @@ import (
 	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/internal"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/tracing"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/tsdb"
@@ -84,6 +89,76 @@ func TestQueryExecutor_ExecuteQuery_SelectStatement(t *testing.T) {
 	}
 }
 
+// Ensure query executor returns the trace of a SELECT statement when requested.
+func TestQueryExecutor_ExecuteQuery_Trace(t *testing.T) {
+	e := DefaultQueryExecutor()
+	e.MockSelect()
+
+	results := ReadAllResults(e.QueryExecutor.ExecuteQuery(MustParseQuery(`SELECT * FROM cpu`), query.ExecutionOptions{
+		Database:    "db0",
+		TraceFormat: tracing.ChromeFormat,
+	}, make(chan struct{})))
+	if len(results) != 2 {
+		t.Fatalf("unexpected results: %s", spew.Sdump(results))
+	} else if len(results[0].Series) != 1 || results[0].Trace != nil {
+		t.Fatalf("unexpected first result: %s", spew.Sdump(results[0]))
+	} else if results[1].StatementID != 0 || results[1].Trace == nil {
+		t.Fatalf("unexpected trace result: %s", spew.Sdump(results[1]))
+	}
+
+	var trace struct {
+		TraceEvents []struct {
+			Name string
+			Args map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(results[1].Trace, &trace); err != nil {
+		t.Fatal(err)
+	} else if len(trace.TraceEvents) == 0 || trace.TraceEvents[0].Name != "select" {
+		t.Fatalf("unexpected trace: %s", results[1].Trace)
+	} else if trace.TraceEvents[0].Args["statement"] != `SELECT * FROM db0.rp0.cpu` {
+		t.Fatalf("unexpected root span args: %v", trace.TraceEvents[0].Args)
+	}
+}
+
+// Ensure query executor writes the traces of slow SELECT statements.
+func TestQueryExecutor_ExecuteQuery_SlowQueryTrace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "coordinator-trace-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	e := DefaultQueryExecutor()
+	e.MockSelect()
+	e.StatementExecutor.SlowQueryTracer = coordinator.NewSlowQueryTracer(dir)
+	e.StatementExecutor.SlowQueryTracer.Format = tracing.JaegerFormat
+
+	// The trace is only written, not returned.
+	if results := ReadAllResults(e.ExecuteQuery(`SELECT * FROM cpu`, "db0", 0)); len(results) != 1 || results[0].Trace != nil {
+		t.Fatalf("unexpected results: %s", spew.Sdump(results))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) != 1 {
+		t.Fatalf("unexpected trace files: %v", files)
+	}
+	if b, err := ioutil.ReadFile(files[0]); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Contains(b, []byte(`"operationName":"select"`)) {
+		t.Fatalf("unexpected trace: %s", b)
+	}
+
+	// Statements faster than the threshold are not written.
+	e.StatementExecutor.SlowQueryTracer.Threshold = time.Hour
+	ReadAllResults(e.ExecuteQuery(`SELECT * FROM cpu`, "db0", 0))
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(files) != 1 {
+		t.Fatalf("unexpected trace files: %v", files)
+	}
+}
+
 // Ensure query executor can enforce a maximum bucket selection count.
 func TestQueryExecutor_ExecuteQuery_MaxSelectBucketsN(t *testing.T) {
 	e := DefaultQueryExecutor()
@@ -258,6 +333,52 @@ func TestQueryExecutor_ExecuteQuery_ShowDatabases(t *testing.T) {
 	}
 }
 
+// Ensure the deletes and moves applied to the store are recorded in the
+// StoreLog.
+func TestQueryExecutor_ExecuteQuery_StoreLog(t *testing.T) {
+	e := NewQueryExecutor()
+	e.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{Name: name}
+	}
+	e.MetaClient.DatabasesFn = func() []meta.DatabaseInfo {
+		return []meta.DatabaseInfo{{
+			Name: "db0",
+			RetentionPolicies: []meta.RetentionPolicyInfo{{
+				Name:        "rp0",
+				ShardGroups: []meta.ShardGroupInfo{{ID: 1, Shards: []meta.ShardInfo{{ID: 2}}}},
+			}},
+		}}
+	}
+	e.MetaClient.MoveShardGroupFn = func(id uint64, database, policy string) error { return nil }
+	e.TSDBStore.DeleteSeriesFn = func(database string, sources []influxql.Source, condition influxql.Expr) error { return nil }
+	e.TSDBStore.DeleteMeasurementFn = func(database, name string) error { return nil }
+	e.TSDBStore.MoveShardFn = func(id uint64, database, policy string) error { return nil }
+
+	var log StoreLog
+	e.StatementExecutor.StoreLog = &log
+
+	for _, q := range []string{
+		`DELETE FROM cpu WHERE time < '2017-01-01T00:00:00Z'`,
+		`DROP SERIES FROM cpu WHERE host = 'a'`,
+		`DROP MEASUREMENT mem`,
+		`MOVE SHARD 2 TO db0.rp1`,
+	} {
+		if a := ReadAllResults(e.ExecuteQuery(q, "db0", 0)); a[0].Err != nil {
+			t.Fatalf("%s: %s", q, a[0].Err)
+		}
+	}
+
+	exp := []string{
+		`DeleteSeries db0 cpu time < '2017-01-01T00:00:00Z'`,
+		`DeleteSeries db0 cpu host = 'a'`,
+		`DeleteMeasurement db0 mem`,
+		`MoveShard 2 db0 rp1`,
+	}
+	if !reflect.DeepEqual([]string(log), exp) {
+		t.Fatalf("unexpected log:\ngot %q\nexp %q", log, exp)
+	}
+}
+
 // QueryExecutor is a test wrapper for coordinator.QueryExecutor.
 type QueryExecutor struct {
 	*query.QueryExecutor
@@ -296,6 +417,30 @@ func NewQueryExecutor() *QueryExecutor {
 	return e
 }
 
+// MockSelect mocks a single local shard returning two points of cpu.
+func (e *QueryExecutor) MockSelect() {
+	e.MetaClient.ShardGroupsByTimeRangeFn = func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
+		return []meta.ShardGroupInfo{
+			{ID: 1, Shards: []meta.ShardInfo{
+				{ID: 100, Owners: []meta.ShardOwner{{NodeID: 0}}},
+			}},
+		}, nil
+	}
+	e.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		var sh MockShard
+		sh.CreateIteratorFn = func(ctx context.Context, m string, opt query.IteratorOptions) (query.Iterator, error) {
+			return &FloatIterator{Points: []query.FloatPoint{
+				{Name: "cpu", Time: int64(0 * time.Second), Aux: []interface{}{float64(100)}},
+				{Name: "cpu", Time: int64(1 * time.Second), Aux: []interface{}{float64(200)}},
+			}}, nil
+		}
+		sh.FieldDimensionsFn = func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
+			return map[string]influxql.DataType{"value": influxql.Float}, nil, nil
+		}
+		return &sh
+	}
+}
+
 // DefaultQueryExecutor returns a QueryExecutor with a database (db0) and retention policy (rp0).
 func DefaultQueryExecutor() *QueryExecutor {
 	e := NewQueryExecutor()
@@ -324,9 +469,12 @@ type TSDBStore struct {
 	DeleteRetentionPolicyFn   func(database, name string) error
 	DeleteShardFn             func(id uint64) error
 	DeleteSeriesFn            func(database string, sources []influxql.Source, condition influxql.Expr) error
+	MoveShardFn               func(id uint64, database, policy string) error
+	CopyShardFn               func(id uint64, database, policy string, newID uint64) error
 	ShardGroupFn              func(ids []uint64) tsdb.ShardGroup
 	MeasurementsCardinalityFn func(database string) (int64, error)
 	SeriesCardinalityFn       func(database string) (int64, error)
+	DatabaseDiskSizeFn        func(database string) (int64, error)
 }
 
 func (s *TSDBStore) CreateShard(database, policy string, shardID uint64, enabled bool) error {
@@ -368,6 +516,14 @@ func (s *TSDBStore) DeleteSeries(database string, sources []influxql.Source, con
 	return s.DeleteSeriesFn(database, sources, condition)
 }
 
+func (s *TSDBStore) MoveShard(id uint64, database, policy string) error {
+	return s.MoveShardFn(id, database, policy)
+}
+
+func (s *TSDBStore) CopyShard(id uint64, database, policy string, newID uint64) error {
+	return s.CopyShardFn(id, database, policy, newID)
+}
+
 func (s *TSDBStore) ShardGroup(ids []uint64) tsdb.ShardGroup {
 	return s.ShardGroupFn(ids)
 }
@@ -392,6 +548,10 @@ func (s *TSDBStore) SeriesCardinality(database string) (int64, error) {
 	return s.SeriesCardinalityFn(database)
 }
 
+func (s *TSDBStore) DatabaseDiskSize(database string) (int64, error) {
+	return s.DatabaseDiskSizeFn(database)
+}
+
 type MockShard struct {
 	Measurements      []string
 	FieldDimensionsFn func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error)
@@ -477,3 +637,26 @@ func (itr *FloatIterator) Next() (*query.FloatPoint, error) {
 	itr.Points = itr.Points[1:]
 	return v, nil
 }
+
+// StoreLog records the entries appended to it.
+type StoreLog []string
+
+func (l *StoreLog) AppendDeleteSeries(database string, sources influxql.Sources, condition influxql.Expr) error {
+	*l = append(*l, fmt.Sprintf("DeleteSeries %s %s %s", database, sources, condition))
+	return nil
+}
+
+func (l *StoreLog) AppendDeleteMeasurement(database, name string) error {
+	*l = append(*l, fmt.Sprintf("DeleteMeasurement %s %s", database, name))
+	return nil
+}
+
+func (l *StoreLog) AppendMoveShard(id uint64, database, retentionPolicy string) error {
+	*l = append(*l, fmt.Sprintf("MoveShard %d %s %s", id, database, retentionPolicy))
+	return nil
+}
+
+func (l *StoreLog) AppendCopyShard(id uint64, database, retentionPolicy string, newID uint64) error {
+	*l = append(*l, fmt.Sprintf("CopyShard %d %s %s %d", id, database, retentionPolicy, newID))
+	return nil
+}
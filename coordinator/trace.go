@@ -0,0 +1,126 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/pkg/tracing"
+	"github.com/influxdata/influxdb/query"
+)
+
+// SlowQueryTracer traces a sample of SELECT statements and writes the traces
+// of those running longer than a threshold to a directory.
+type SlowQueryTracer struct {
+	// Dir is the directory the traces are written to.
+	Dir string
+
+	// Threshold is the minimum duration of a statement for its trace to be written.
+	Threshold time.Duration
+
+	// SampleRate is the fraction of statements traced, between 0 and 1.
+	SampleRate float64
+
+	// Format is the format the traces are exported to.
+	Format string
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewSlowQueryTracer returns a new instance of SlowQueryTracer writing traces to dir.
+func NewSlowQueryTracer(dir string) *SlowQueryTracer {
+	return &SlowQueryTracer{
+		Dir:        dir,
+		SampleRate: DefaultSlowQueryTraceSampleRate,
+		Format:     tracing.ChromeFormat,
+		rnd:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// sample returns true if the next statement should be traced.
+func (t *SlowQueryTracer) sample() bool {
+	if t.SampleRate >= 1 {
+		return true
+	} else if t.SampleRate <= 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rnd.Float64() < t.SampleRate
+}
+
+// write writes tr to a file of the trace directory and returns its path.
+func (t *SlowQueryTracer) write(tr *tracing.Trace, ectx *query.ExecutionContext) (string, error) {
+	b, err := tr.Export(t.Format)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(t.Dir, 0777); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s-%d-%d.json", time.Now().UTC().Format("20060102T150405.000000000"), ectx.QueryID, ectx.StatementID)
+	path := filepath.Join(t.Dir, name)
+	if err := ioutil.WriteFile(path, b, 0666); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// executeTracedSelectStatement executes stmt, capturing its trace when the
+// trace was requested or the statement is sampled by the SlowQueryTracer.
+// A requested trace is sent as a separate result of the statement.
+func (e *StatementExecutor) executeTracedSelectStatement(stmt *influxql.SelectStatement, ectx *query.ExecutionContext) error {
+	e.mu.RLock()
+	tracer := e.SlowQueryTracer
+	e.mu.RUnlock()
+
+	sampled := tracer != nil && tracer.sample()
+	if ectx.TraceFormat == "" && !sampled {
+		return e.executeSelectStatement(context.Background(), stmt, ectx)
+	}
+
+	t, span := tracing.NewTrace("select")
+	span.SetLabels("statement", stmt.String())
+	ctx := tracing.NewContextWithTrace(context.Background(), t)
+	ctx = tracing.NewContextWithSpan(ctx, span)
+	var aux query.Iterators
+	ctx = query.NewContextWithIterators(ctx, &aux)
+	start := time.Now()
+
+	err := e.executeSelectStatement(ctx, stmt, ectx)
+
+	// close auxiliary iterators deterministically to finalize any captured measurements
+	aux.Close()
+	span.Finish()
+
+	if sampled && time.Since(start) >= tracer.Threshold {
+		if path, err := tracer.write(t, ectx); err != nil {
+			ectx.Log.Info(fmt.Sprintf("failed to write slow query trace: %s", err))
+		} else {
+			ectx.Log.Info(fmt.Sprintf("slow query trace written to %s", path))
+		}
+	}
+
+	if err != nil || ectx.TraceFormat == "" {
+		return err
+	}
+
+	b, err := t.Export(ectx.TraceFormat)
+	if err != nil {
+		return err
+	}
+	return ectx.Send(&query.Result{
+		StatementID: ectx.StatementID,
+		Trace:       b,
+	})
+}
@@ -10,6 +10,10 @@ import (
 // different type.
 var ErrFieldTypeConflict = errors.New("field type conflict")
 
+// ErrReadReplica is returned when writing to a read replica, or changing its
+// meta store. A read replica only applies the changes of its leader.
+var ErrReadReplica = errors.New("not permitted on a read replica, use the leader")
+
 // ErrDatabaseNotFound indicates that a database operation failed on the
 // specified database because the specified database does not exist.
 func ErrDatabaseNotFound(name string) error { return fmt.Errorf("database not found: %s", name) }
@@ -20,6 +24,57 @@ func ErrRetentionPolicyNotFound(name string) error {
 	return fmt.Errorf("retention policy not found: %s", name)
 }
 
+// QuotaExceededError is returned when a write is rejected because the
+// database exceeded one of its quotas.
+type QuotaExceededError struct {
+	Database string
+	Quota    string // "disk" or "series"
+	Usage    int64
+	Limit    int64
+}
+
+// Error returns a string representation of the error.
+func (e QuotaExceededError) Error() string {
+	unit := "bytes"
+	if e.Quota == "series" {
+		unit = "series"
+	}
+	return fmt.Sprintf("database %q exceeded its %s quota: %d of %d %s", e.Database, e.Quota, e.Usage, e.Limit, unit)
+}
+
+// IsQuotaExceededError indicates whether an error is due to an exceeded database quota.
+func IsQuotaExceededError(err error) bool {
+	_, ok := err.(QuotaExceededError)
+	return ok
+}
+
+// ReadOnlyError is returned when a write is rejected because the database,
+// retention policy or shard it writes to is read-only.
+type ReadOnlyError struct {
+	Database        string
+	RetentionPolicy string
+	ShardID         uint64
+}
+
+// Error returns a string representation of the error.
+func (e ReadOnlyError) Error() string {
+	switch {
+	case e.ShardID != 0:
+		return fmt.Sprintf("shard %d of database %q is read-only", e.ShardID, e.Database)
+	case e.RetentionPolicy != "":
+		return fmt.Sprintf("retention policy %q on database %q is read-only", e.RetentionPolicy, e.Database)
+	default:
+		return fmt.Sprintf("database %q is read-only", e.Database)
+	}
+}
+
+// IsReadOnlyError indicates whether an error is due to a read-only database,
+// retention policy or shard.
+func IsReadOnlyError(err error) bool {
+	_, ok := err.(ReadOnlyError)
+	return ok
+}
+
 // IsAuthorizationError indicates whether an error is due to an authorization failure
 func IsAuthorizationError(err error) bool {
 	e, ok := err.(interface {
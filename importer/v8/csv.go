@@ -0,0 +1,312 @@
+package v8
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// CSVConfig holds the options used to import CSV files.
+//
+// Spec maps the columns of the header row to the parts of a point. It is a
+// comma-separated list of "column:role[:type]" entries where role is one of:
+//
+//	measurement          the column holds the measurement name
+//	tag                  the column holds a tag value
+//	field[:type]         the column holds a field value of type float
+//	                     (default), integer, unsigned, boolean or string
+//	time[:format]        the column holds the timestamp in rfc3339 (default),
+//	                     s, ms, u, ns or a Go time layout
+//	ignore               the column is skipped
+//
+// Columns missing from the spec are ignored. For example:
+//
+//	time:time:s,host:tag,region:tag,usage_idle:field,count:field:integer
+type CSVConfig struct {
+	Enabled     bool   // Import the file as CSV instead of the export format.
+	Spec        string // Column mapping.
+	Measurement string // Measurement name when no column holds it.
+	Delimiter   string // Field delimiter, defaults to a comma.
+	RejectsPath string // Optional file receiving the rejected rows.
+}
+
+// csvColumn describes how a CSV column maps to a point.
+type csvColumn struct {
+	name   string
+	role   string
+	typ    string
+	format string
+}
+
+// csvMapping is a parsed CSV spec bound to the columns of a header row.
+type csvMapping struct {
+	measurement string
+	columns     []*csvColumn // indexed by the column position in a row
+}
+
+// parseCSVSpec parses spec into columns keyed by column name.
+func parseCSVSpec(spec string) (map[string]*csvColumn, error) {
+	columns := make(map[string]*csvColumn)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid csv spec entry %q: expected column:role", entry)
+		}
+
+		col := &csvColumn{name: parts[0], role: parts[1]}
+		if len(parts) == 3 {
+			col.typ = parts[2]
+		}
+
+		switch col.role {
+		case "measurement", "tag", "ignore":
+			if col.typ != "" {
+				return nil, fmt.Errorf("invalid csv spec entry %q: %s columns have no type", entry, col.role)
+			}
+		case "field":
+			switch col.typ {
+			case "":
+				col.typ = "float"
+			case "float", "integer", "unsigned", "boolean", "string":
+			default:
+				return nil, fmt.Errorf("invalid csv spec entry %q: unknown field type %q", entry, col.typ)
+			}
+		case "time":
+			col.format, col.typ = col.typ, ""
+			if col.format == "" {
+				col.format = "rfc3339"
+			}
+		default:
+			return nil, fmt.Errorf("invalid csv spec entry %q: unknown role %q", entry, col.role)
+		}
+
+		if _, ok := columns[col.name]; ok {
+			return nil, fmt.Errorf("invalid csv spec: column %q mapped more than once", col.name)
+		}
+		columns[col.name] = col
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.New("csv spec required")
+	}
+	return columns, nil
+}
+
+// newCSVMapping binds the spec columns to the positions of header.
+func newCSVMapping(spec map[string]*csvColumn, header []string, measurement string) (*csvMapping, error) {
+	m := &csvMapping{
+		measurement: measurement,
+		columns:     make([]*csvColumn, len(header)),
+	}
+
+	var hasField, hasMeasurement bool
+	found := make(map[string]bool, len(spec))
+	for i, name := range header {
+		col, ok := spec[strings.TrimSpace(name)]
+		if !ok {
+			continue
+		}
+		m.columns[i] = col
+		found[col.name] = true
+		hasField = hasField || col.role == "field"
+		hasMeasurement = hasMeasurement || col.role == "measurement"
+	}
+
+	for name := range spec {
+		if !found[name] {
+			return nil, fmt.Errorf("csv spec column %q not found in header", name)
+		}
+	}
+	if !hasField {
+		return nil, errors.New("csv spec must map at least one field column")
+	} else if !hasMeasurement && measurement == "" {
+		return nil, errors.New("a measurement name or a measurement column is required")
+	}
+	return m, nil
+}
+
+// point converts a CSV record to a point.
+func (m *csvMapping) point(record []string) (models.Point, error) {
+	name := m.measurement
+	tags := make(map[string]string)
+	fields := make(map[string]interface{})
+	var t time.Time
+
+	for i, value := range record {
+		if i >= len(m.columns) || m.columns[i] == nil || value == "" {
+			continue
+		}
+
+		col := m.columns[i]
+		switch col.role {
+		case "measurement":
+			name = value
+		case "tag":
+			tags[col.name] = value
+		case "time":
+			ts, err := parseCSVTime(value, col.format)
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %s", col.name, err)
+			}
+			t = ts
+		case "field":
+			v, err := parseCSVField(value, col.typ)
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %s", col.name, err)
+			}
+			fields[col.name] = v
+		}
+	}
+
+	if name == "" {
+		return nil, errors.New("missing measurement name")
+	} else if len(fields) == 0 {
+		return nil, errors.New("no field values")
+	}
+	return models.NewPoint(name, models.NewTags(tags), fields, t)
+}
+
+// parseCSVField parses value as a field of type typ.
+func parseCSVField(value, typ string) (interface{}, error) {
+	switch typ {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "unsigned":
+		return strconv.ParseUint(value, 10, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	case "string":
+		return value, nil
+	default:
+		return strconv.ParseFloat(value, 64)
+	}
+}
+
+// parseCSVTime parses value as a timestamp in the given format.
+func parseCSVTime(value, format string) (time.Time, error) {
+	var d time.Duration
+	switch format {
+	case "rfc3339":
+		return time.Parse(time.RFC3339Nano, value)
+	case "s":
+		d = time.Second
+	case "ms":
+		d = time.Millisecond
+	case "u", "us":
+		d = time.Microsecond
+	case "ns":
+		d = time.Nanosecond
+	default:
+		return time.Parse(format, value)
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, n*int64(d)).UTC(), nil
+}
+
+// importCSV reads CSV rows from r, converts them to points and writes them
+// in batches. Rows that cannot be converted are reported and skipped. Unlike
+// the export format, CSV has no context comments, so a database is required.
+func (i *Importer) importCSV(r io.Reader) error {
+	if i.database == "" {
+		return errors.New("csv import requires a database, set with -database")
+	}
+
+	spec, err := parseCSVSpec(i.config.CSV.Spec)
+	if err != nil {
+		return err
+	}
+
+	cr := csv.NewReader(r)
+	if d := i.config.CSV.Delimiter; d != "" {
+		if d == `\t` {
+			d = "\t"
+		}
+		if utf8.RuneCountInString(d) != 1 {
+			return fmt.Errorf("invalid csv delimiter %q", d)
+		}
+		cr.Comma, _ = utf8.DecodeRuneInString(d)
+	}
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return errors.New("csv file is empty")
+	} else if err != nil {
+		return fmt.Errorf("reading csv header: %s", err)
+	}
+	cr.FieldsPerRecord = len(header)
+
+	m, err := newCSVMapping(spec, header, i.config.CSV.Measurement)
+	if err != nil {
+		return err
+	}
+
+	var rejects *csv.Writer
+	if path := i.config.CSV.RejectsPath; path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		rejects = csv.NewWriter(f)
+		defer rejects.Flush()
+		rejects.Write(append([]string{"row", "error"}, header...))
+	}
+
+	// Set up our throttle channel.
+	i.startThrottle()
+	defer i.throttle.Stop()
+
+	start := time.Now()
+	var rows int
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		rows++
+
+		var pt models.Point
+		if err == nil {
+			pt, err = m.point(record)
+		} else if _, ok := err.(*csv.ParseError); !ok {
+			return fmt.Errorf("reading csv: %s", err)
+		}
+
+		if err != nil {
+			i.rejectedRows++
+			i.stderrLogger.Printf("rejected row %d: %s\n", rows, err)
+			if rejects != nil {
+				rejects.Write(append([]string{strconv.Itoa(rows), err.Error()}, record...))
+			}
+			continue
+		}
+		i.batchAccumulator(pt.PrecisionString(i.config.Precision), start)
+	}
+	// Call batchWrite one last time to flush anything out in the batch
+	i.batchWrite()
+
+	i.stdoutLogger.Printf("Processed %d rows\n", rows)
+	i.stdoutLogger.Printf("Rejected %d rows\n", i.rejectedRows)
+	if rejects != nil && i.rejectedRows > 0 {
+		i.stdoutLogger.Printf("Rejected rows written to %s\n", i.config.CSV.RejectsPath)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package v8
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCSVSpec(t *testing.T) {
+	spec, err := parseCSVSpec("time:time:2006-01-02 15:04:05, host:tag,value:field,count:field:integer,notes:ignore")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, exp := spec["time"].format, "2006-01-02 15:04:05"; got != exp {
+		t.Errorf("unexpected time format: got %q, exp %q", got, exp)
+	}
+	if got, exp := spec["value"].typ, "float"; got != exp {
+		t.Errorf("unexpected default field type: got %q, exp %q", got, exp)
+	}
+
+	for _, s := range []string{
+		"",
+		"value",
+		"value:field:decimal",
+		"host:tag:string",
+		"host:label",
+		"value:field,value:tag",
+	} {
+		if _, err := parseCSVSpec(s); err == nil {
+			t.Errorf("expected error for spec %q", s)
+		}
+	}
+}
+
+func TestCSVMapping_Point(t *testing.T) {
+	spec, _ := parseCSVSpec("ts:time:s,host:tag,value:field,count:field:integer,up:field:boolean")
+	m, err := newCSVMapping(spec, []string{"ts", "host", "value", "count", "up", "extra"}, "cpu")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	pt, err := m.point([]string{"10", "server01", "1.5", "3", "true", "ignored"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, exp := pt.String(), "cpu,host=server01 count=3i,up=true,value=1.5 10000000000"; got != exp {
+		t.Errorf("unexpected point: got %s, exp %s", got, exp)
+	}
+
+	if _, err := m.point([]string{"10", "server01", "abc", "3", "true", ""}); err == nil {
+		t.Error("expected error for invalid float")
+	}
+	if _, err := m.point([]string{"10", "server01", "", "", "", ""}); err == nil {
+		t.Error("expected error for row without fields")
+	}
+
+	if _, err := newCSVMapping(spec, []string{"ts", "host", "value"}, "cpu"); err == nil {
+		t.Error("expected error for missing column")
+	}
+	if _, err := newCSVMapping(spec, []string{"ts", "host", "value", "count", "up"}, ""); err == nil {
+		t.Error("expected error for missing measurement")
+	}
+}
+
+func TestImporter_ImportCSV(t *testing.T) {
+	var written []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/write" {
+			if got, exp := r.URL.Query().Get("db"), "db0"; got != exp {
+				t.Errorf("unexpected database: got %q, exp %q", got, exp)
+			}
+			b, _ := ioutil.ReadAll(r.Body)
+			written = append(written, string(b))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "influx-csv-import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "data.csv")
+	data := "name,time,host,value\ncpu,2017-01-01T00:00:00Z,a,1\ncpu,2017-01-01T00:00:10Z,b,oops\nmem,2017-01-01T00:00:20Z,c,3\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := url.Parse(ts.URL)
+	config := NewConfig()
+	config.URL = *u
+	config.Path = path
+	config.Database = "db0"
+	config.CSV = CSVConfig{
+		Enabled:     true,
+		Spec:        "name:measurement,time:time,host:tag,value:field",
+		RejectsPath: filepath.Join(dir, "rejects.csv"),
+	}
+
+	i := NewImporter(config)
+	i.stdoutLogger.SetOutput(ioutil.Discard)
+	i.stderrLogger.SetOutput(ioutil.Discard)
+	if err := i.Import(); err == nil || err.Error() != "1 row was rejected" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, exp := strings.Join(written, ""), "cpu,host=a value=1 1483228800000000000\nmem,host=c value=3 1483228820000000000"; got != exp {
+		t.Errorf("unexpected write: got %q, exp %q", got, exp)
+	}
+
+	rejects, _ := ioutil.ReadFile(config.CSV.RejectsPath)
+	if !strings.Contains(string(rejects), "2,") || !strings.Contains(string(rejects), "oops") {
+		t.Errorf("unexpected rejects file: %s", rejects)
+	}
+}
+
+// Ensure a CSV import without a database fails before writing anything.
+func TestImporter_ImportCSV_NoDatabase(t *testing.T) {
+	var writes int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/write" {
+			writes++
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	f, err := ioutil.TempFile("", "influx-csv-import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("name,time,value\ncpu,2017-01-01T00:00:00Z,1\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	u, _ := url.Parse(ts.URL)
+	config := NewConfig()
+	config.URL = *u
+	config.Path = f.Name()
+	config.CSV = CSVConfig{Enabled: true, Spec: "name:measurement,time:time,value:field"}
+
+	i := NewImporter(config)
+	i.stdoutLogger.SetOutput(ioutil.Discard)
+	i.stderrLogger.SetOutput(ioutil.Discard)
+	if err := i.Import(); err == nil || err.Error() != "csv import requires a database, set with -database" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if writes != 0 {
+		t.Fatalf("unexpected writes: %d", writes)
+	}
+}
@@ -23,6 +23,13 @@ type Config struct {
 	Compressed bool // Whether import data is gzipped.
 	PPS        int  // points per second importer imports with.
 
+	// Database and RetentionPolicy are the default write destination. The
+	// export format overrides them with its context comments.
+	Database        string
+	RetentionPolicy string
+
+	CSV CSVConfig // CSV import options.
+
 	client.Config
 }
 
@@ -41,6 +48,7 @@ type Importer struct {
 	totalInserts          int
 	failedInserts         int
 	totalCommands         int
+	rejectedRows          int
 	throttlePointsWritten int
 	lastWrite             time.Time
 	throttle              *time.Ticker
@@ -108,20 +116,52 @@ func (i *Importer) Import() error {
 		r = f
 	}
 
+	i.database = i.config.Database
+	i.retentionPolicy = i.config.RetentionPolicy
+
+	if i.config.CSV.Enabled {
+		if err := i.importCSV(r); err != nil {
+			return err
+		}
+	} else if err := i.importExport(r); err != nil {
+		return err
+	}
+
+	// If there were any failed inserts then return an error so that a non-zero
+	// exit code can be returned.
+	if i.failedInserts > 0 {
+		plural := " was"
+		if i.failedInserts > 1 {
+			plural = "s were"
+		}
+
+		return fmt.Errorf("%d point%s not inserted", i.failedInserts, plural)
+	}
+
+	if i.rejectedRows > 0 {
+		plural := " was"
+		if i.rejectedRows > 1 {
+			plural = "s were"
+		}
+
+		return fmt.Errorf("%d row%s rejected", i.rejectedRows, plural)
+	}
+
+	return nil
+}
+
+// importExport imports data in the format written by influx_inspect export.
+func (i *Importer) importExport(r io.Reader) error {
 	// Get our reader
 	scanner := bufio.NewScanner(r)
 
 	// Process the DDL
 	i.processDDL(scanner)
 
-	// Set up our throttle channel.  Since there is effectively no other activity at this point
-	// the smaller resolution gets us much closer to the requested PPS
-	i.throttle = time.NewTicker(time.Microsecond)
+	// Set up our throttle channel.
+	i.startThrottle()
 	defer i.throttle.Stop()
 
-	// Prime the last write
-	i.lastWrite = time.Now()
-
 	// Process the DML
 	i.processDML(scanner)
 
@@ -129,19 +169,17 @@ func (i *Importer) Import() error {
 	if err := scanner.Err(); err != nil {
 		return fmt.Errorf("reading standard input: %s", err)
 	}
+	return nil
+}
 
-	// If there were any failed inserts then return an error so that a non-zero
-	// exit code can be returned.
-	if i.failedInserts > 0 {
-		plural := " was"
-		if i.failedInserts > 1 {
-			plural = "s were"
-		}
-
-		return fmt.Errorf("%d point%s not inserted", i.failedInserts, plural)
-	}
+// startThrottle sets up the throttle ticker and primes the last write.
+func (i *Importer) startThrottle() {
+	// Since there is effectively no other activity at this point
+	// the smaller resolution gets us much closer to the requested PPS
+	i.throttle = time.NewTicker(time.Microsecond)
 
-	return nil
+	// Prime the last write
+	i.lastWrite = time.Now()
 }
 
 func (i *Importer) processDDL(scanner *bufio.Scanner) {
@@ -136,10 +136,13 @@ type Node interface {
 func (*Query) node()     {}
 func (Statements) node() {}
 
+func (*AlterDatabaseStatement) node()              {}
 func (*AlterRetentionPolicyStatement) node()       {}
+func (*AlterShardStatement) node()                 {}
 func (*CreateContinuousQueryStatement) node()      {}
 func (*CreateDatabaseStatement) node()             {}
 func (*CreateRetentionPolicyStatement) node()      {}
+func (*CreateSchemaStatement) node()               {}
 func (*CreateSubscriptionStatement) node()         {}
 func (*CreateUserStatement) node()                 {}
 func (*Distinct) node()                            {}
@@ -149,14 +152,18 @@ func (*DropContinuousQueryStatement) node()        {}
 func (*DropDatabaseStatement) node()               {}
 func (*DropMeasurementStatement) node()            {}
 func (*DropRetentionPolicyStatement) node()        {}
+func (*DropSchemaStatement) node()                 {}
 func (*DropSeriesStatement) node()                 {}
 func (*DropShardStatement) node()                  {}
 func (*DropSubscriptionStatement) node()           {}
 func (*DropUserStatement) node()                   {}
 func (*ExplainStatement) node()                    {}
+func (*ExportMetaStatement) node()                 {}
 func (*GrantStatement) node()                      {}
 func (*GrantAdminStatement) node()                 {}
+func (*ImportMetaStatement) node()                 {}
 func (*KillQueryStatement) node()                  {}
+func (*MoveShardStatement) node()                  {}
 func (*RevokeStatement) node()                     {}
 func (*RevokeAdminStatement) node()                {}
 func (*SelectStatement) node()                     {}
@@ -167,9 +174,11 @@ func (*ShowDatabasesStatement) node()              {}
 func (*ShowFieldKeyCardinalityStatement) node()    {}
 func (*ShowFieldKeysStatement) node()              {}
 func (*ShowRetentionPoliciesStatement) node()      {}
+func (*ShowSchemasStatement) node()                {}
 func (*ShowMeasurementCardinalityStatement) node() {}
 func (*ShowMeasurementsStatement) node()           {}
 func (*ShowQueriesStatement) node()                {}
+func (*ShowQuotasStatement) node()                 {}
 func (*ShowSeriesStatement) node()                 {}
 func (*ShowSeriesCardinalityStatement) node()      {}
 func (*ShowShardGroupsStatement) node()            {}
@@ -264,10 +273,13 @@ type ExecutionPrivilege struct {
 // ExecutionPrivileges is a list of privileges required to execute a statement.
 type ExecutionPrivileges []ExecutionPrivilege
 
+func (*AlterDatabaseStatement) stmt()              {}
 func (*AlterRetentionPolicyStatement) stmt()       {}
+func (*AlterShardStatement) stmt()                 {}
 func (*CreateContinuousQueryStatement) stmt()      {}
 func (*CreateDatabaseStatement) stmt()             {}
 func (*CreateRetentionPolicyStatement) stmt()      {}
+func (*CreateSchemaStatement) stmt()               {}
 func (*CreateSubscriptionStatement) stmt()         {}
 func (*CreateUserStatement) stmt()                 {}
 func (*DeleteSeriesStatement) stmt()               {}
@@ -276,13 +288,17 @@ func (*DropContinuousQueryStatement) stmt()        {}
 func (*DropDatabaseStatement) stmt()               {}
 func (*DropMeasurementStatement) stmt()            {}
 func (*DropRetentionPolicyStatement) stmt()        {}
+func (*DropSchemaStatement) stmt()                 {}
 func (*DropSeriesStatement) stmt()                 {}
 func (*DropSubscriptionStatement) stmt()           {}
 func (*DropUserStatement) stmt()                   {}
 func (*ExplainStatement) stmt()                    {}
+func (*ExportMetaStatement) stmt()                 {}
 func (*GrantStatement) stmt()                      {}
 func (*GrantAdminStatement) stmt()                 {}
+func (*ImportMetaStatement) stmt()                 {}
 func (*KillQueryStatement) stmt()                  {}
+func (*MoveShardStatement) stmt()                  {}
 func (*ShowContinuousQueriesStatement) stmt()      {}
 func (*ShowGrantsForUserStatement) stmt()          {}
 func (*ShowDatabasesStatement) stmt()              {}
@@ -291,7 +307,9 @@ func (*ShowFieldKeysStatement) stmt()              {}
 func (*ShowMeasurementCardinalityStatement) stmt() {}
 func (*ShowMeasurementsStatement) stmt()           {}
 func (*ShowQueriesStatement) stmt()                {}
+func (*ShowQuotasStatement) stmt()                 {}
 func (*ShowRetentionPoliciesStatement) stmt()      {}
+func (*ShowSchemasStatement) stmt()                {}
 func (*ShowSeriesStatement) stmt()                 {}
 func (*ShowSeriesCardinalityStatement) stmt()      {}
 func (*ShowShardGroupsStatement) stmt()            {}
@@ -888,6 +906,55 @@ func (s *CreateRetentionPolicyStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// AlterDatabaseStatement represents a command to alter the quotas or the
+// read-only mode of an existing database.
+type AlterDatabaseStatement struct {
+	// Name of the database to alter.
+	Name string
+
+	// Maximum size on disk of the database in bytes.
+	MaxDiskBytes *int64
+
+	// Maximum number of series of the database.
+	MaxSeriesN *int64
+
+	// Should writes to the database be rejected?
+	ReadOnly *bool
+}
+
+// String returns a string representation of the alter database statement.
+func (s *AlterDatabaseStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("ALTER DATABASE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+
+	if s.MaxDiskBytes != nil {
+		_, _ = buf.WriteString(" DISK QUOTA ")
+		_, _ = buf.WriteString(strconv.FormatInt(*s.MaxDiskBytes, 10))
+	}
+
+	if s.MaxSeriesN != nil {
+		_, _ = buf.WriteString(" SERIES QUOTA ")
+		_, _ = buf.WriteString(strconv.FormatInt(*s.MaxSeriesN, 10))
+	}
+
+	if s.ReadOnly != nil {
+		_, _ = buf.WriteString(readOnlyString(*s.ReadOnly))
+	}
+
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an AlterDatabaseStatement.
+func (s *AlterDatabaseStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *AlterDatabaseStatement) DefaultDatabase() string {
+	return s.Name
+}
+
 // AlterRetentionPolicyStatement represents a command to alter an existing retention policy.
 type AlterRetentionPolicyStatement struct {
 	// Name of policy to alter.
@@ -907,6 +974,9 @@ type AlterRetentionPolicyStatement struct {
 
 	// Duration of the Shard.
 	ShardGroupDuration *time.Duration
+
+	// Should writes to this policy be rejected?
+	ReadOnly *bool
 }
 
 // String returns a string representation of the alter retention policy statement.
@@ -936,6 +1006,10 @@ func (s *AlterRetentionPolicyStatement) String() string {
 		_, _ = buf.WriteString(" DEFAULT")
 	}
 
+	if s.ReadOnly != nil {
+		_, _ = buf.WriteString(readOnlyString(*s.ReadOnly))
+	}
+
 	return buf.String()
 }
 
@@ -949,6 +1023,37 @@ func (s *AlterRetentionPolicyStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// AlterShardStatement represents a command to alter the read-only mode of a shard.
+type AlterShardStatement struct {
+	// ID of the shard to alter.
+	ID uint64
+
+	// Should writes to the shard be rejected?
+	ReadOnly bool
+}
+
+// String returns a string representation of the alter shard statement.
+func (s *AlterShardStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("ALTER SHARD ")
+	_, _ = buf.WriteString(strconv.FormatUint(s.ID, 10))
+	_, _ = buf.WriteString(readOnlyString(s.ReadOnly))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an AlterShardStatement.
+func (s *AlterShardStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// readOnlyString returns the READ ONLY or READ WRITE option of an ALTER statement.
+func readOnlyString(readOnly bool) string {
+	if readOnly {
+		return " READ ONLY"
+	}
+	return " READ WRITE"
+}
+
 // FillOption represents different options for filling aggregate windows.
 type FillOption int
 
@@ -2096,6 +2201,45 @@ func (s *DropShardStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// MoveShardStatement represents a command for moving or copying a shard, or
+// a shard group, to another database and retention policy.
+type MoveShardStatement struct {
+	// ID of the shard, or of the shard group, to move.
+	ID uint64
+
+	// Is ID the ID of a shard group?
+	Group bool
+
+	// Should the shard be copied instead of moved?
+	Copy bool
+
+	// Database and retention policy to move the shard to.
+	Database        string
+	RetentionPolicy string
+}
+
+// String returns a string representation of the move shard statement.
+func (s *MoveShardStatement) String() string {
+	var buf bytes.Buffer
+	if s.Copy {
+		_, _ = buf.WriteString("COPY SHARD ")
+	} else {
+		_, _ = buf.WriteString("MOVE SHARD ")
+	}
+	if s.Group {
+		_, _ = buf.WriteString("GROUP ")
+	}
+	_, _ = buf.WriteString(strconv.FormatUint(s.ID, 10))
+	_, _ = buf.WriteString(" TO ")
+	_, _ = buf.WriteString(QuoteIdent(s.Database, s.RetentionPolicy))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a MoveShardStatement.
+func (s *MoveShardStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // ShowSeriesCardinalityStatement represents a command for listing series cardinality.
 type ShowSeriesCardinalityStatement struct {
 	// Database to query. If blank, use the default database.
@@ -2500,6 +2644,171 @@ func (s *ShowRetentionPoliciesStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// ShowQuotasStatement represents a command for listing the quotas and usage of databases.
+type ShowQuotasStatement struct {
+	// Name of the database to list the quotas of. All databases if empty.
+	Database string
+}
+
+// String returns a string representation of a ShowQuotasStatement.
+func (s *ShowQuotasStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("SHOW QUOTAS")
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowQuotasStatement.
+func (s *ShowQuotasStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	// Like SHOW DATABASES, the returned results depend on the user's
+	// individual database permissions.
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: NoPrivileges}}, nil
+}
+
+// CreateSchemaStatement represents a command for declaring the schema of a measurement.
+type CreateSchemaStatement struct {
+	// Name of the measurement.
+	Name string
+
+	// Name of the database the measurement belongs to.
+	Database string
+
+	// Tag keys allowed in addition to the required tags.
+	TagKeys []string
+
+	// Tag keys every point must have.
+	RequiredTags []string
+
+	// Fields of the measurement and their types.
+	Fields []*SchemaField
+
+	// Whether points not matching the schema are written with a warning
+	// instead of dropped.
+	Warn bool
+}
+
+// SchemaField represents a field declared by a CreateSchemaStatement.
+type SchemaField struct {
+	Name string
+	Type DataType
+}
+
+// String returns a string representation of the create schema statement.
+func (s *CreateSchemaStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("CREATE SCHEMA ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+
+	if len(s.TagKeys) > 0 {
+		_, _ = buf.WriteString(" TAGS ")
+		_, _ = buf.WriteString(quoteIdentList(s.TagKeys))
+	}
+
+	if len(s.RequiredTags) > 0 {
+		_, _ = buf.WriteString(" REQUIRED TAGS ")
+		_, _ = buf.WriteString(quoteIdentList(s.RequiredTags))
+	}
+
+	_, _ = buf.WriteString(" FIELDS ")
+	for i, f := range s.Fields {
+		if i > 0 {
+			_, _ = buf.WriteString(", ")
+		}
+		_, _ = buf.WriteString(QuoteIdent(f.Name))
+		_, _ = buf.WriteString(" ")
+		_, _ = buf.WriteString(f.Type.String())
+	}
+
+	if s.Warn {
+		_, _ = buf.WriteString(" WARN")
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a CreateSchemaStatement.
+func (s *CreateSchemaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *CreateSchemaStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// quoteIdentList returns a comma separated list of quoted identifiers.
+func quoteIdentList(idents []string) string {
+	quoted := make([]string, len(idents))
+	for i, ident := range idents {
+		quoted[i] = QuoteIdent(ident)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// DropSchemaStatement represents a command for removing the schema of a measurement.
+type DropSchemaStatement struct {
+	// Name of the measurement.
+	Name string
+
+	// Name of the database the measurement belongs to.
+	Database string
+}
+
+// String returns a string representation of the drop schema statement.
+func (s *DropSchemaStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("DROP SCHEMA ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a DropSchemaStatement.
+func (s *DropSchemaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *DropSchemaStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// ShowSchemasStatement represents a command for listing the measurement schemas of a database.
+type ShowSchemasStatement struct {
+	// Name of the database to list the schemas of.
+	Database string
+}
+
+// String returns a string representation of a ShowSchemasStatement.
+func (s *ShowSchemasStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("SHOW SCHEMAS")
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowSchemasStatement.
+func (s *ShowSchemasStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *ShowSchemasStatement) DefaultDatabase() string {
+	return s.Database
+}
+
 // ShowStatsStatement displays statistics for a given module.
 type ShowStatsStatement struct {
 	Module string
@@ -2565,6 +2874,49 @@ func (s *ShowDiagnosticsStatement) RequiredPrivileges() (ExecutionPrivileges, er
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// ExportMetaStatement represents a command for exporting the meta store as JSON.
+type ExportMetaStatement struct {
+	// WithoutHashes omits the password hashes of the users.
+	WithoutHashes bool
+}
+
+// String returns a string representation of the ExportMetaStatement.
+func (s *ExportMetaStatement) String() string {
+	if s.WithoutHashes {
+		return "EXPORT META WITHOUT HASHES"
+	}
+	return "EXPORT META"
+}
+
+// RequiredPrivileges returns the privilege required to execute an ExportMetaStatement.
+func (s *ExportMetaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ImportMetaStatement represents a command for importing the meta store from JSON.
+type ImportMetaStatement struct {
+	// Data is the meta store, in the JSON format of ExportMetaStatement.
+	Data string
+
+	// Replace replaces the databases and users instead of merging them. It
+	// fails if a shard of the meta store is not in Data.
+	Replace bool
+}
+
+// String returns a string representation of the ImportMetaStatement.
+// The imported data, which may contain password hashes, is redacted.
+func (s *ImportMetaStatement) String() string {
+	if s.Replace {
+		return "IMPORT META [REDACTED] REPLACE"
+	}
+	return "IMPORT META [REDACTED]"
+}
+
+// RequiredPrivileges returns the privilege required to execute an ImportMetaStatement.
+func (s *ImportMetaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // CreateSubscriptionStatement represents a command to add a subscription to the incoming data stream.
 type CreateSubscriptionStatement struct {
 	Name            string
@@ -4297,7 +4649,7 @@ func EvalType(expr Expr, sources Sources, typmap TypeMapper) DataType {
 		return typ
 	case *Call:
 		switch expr.Name {
-		case "mean", "median", "integral":
+		case "mean", "median", "integral", "corr", "covar", "linreg_slope", "linreg_intercept", "linreg_r2":
 			return Float
 		case "count":
 			return Integer
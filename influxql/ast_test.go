@@ -1102,6 +1102,27 @@ func TestEvalType(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: `corr() with integers`,
+			in:   `corr(value, total)`,
+			typ:  influxql.Float,
+			data: EvalFixture{
+				"cpu": map[string]influxql.DataType{
+					"value": influxql.Integer,
+					"total": influxql.Integer,
+				},
+			},
+		},
+		{
+			name: `linreg_slope() with an integer`,
+			in:   `linreg_slope(value, 1h)`,
+			typ:  influxql.Float,
+			data: EvalFixture{
+				"cpu": map[string]influxql.DataType{
+					"value": influxql.Integer,
+				},
+			},
+		},
 		{
 			name: `count() with a float`,
 			in:   `count(value)`,
@@ -1571,6 +1592,7 @@ func Test_EnforceHasDefaultDatabase(t *testing.T) {
 
 	// this is a list of statements that do not have a database context
 	exemptStatements := []string{
+		"AlterShardStatement",
 		"CreateDatabaseStatement",
 		"CreateUserStatement",
 		"DeleteSeriesStatement",
@@ -1582,6 +1604,7 @@ func Test_EnforceHasDefaultDatabase(t *testing.T) {
 		"ExplainStatement",
 		"GrantAdminStatement",
 		"KillQueryStatement",
+		"MoveShardStatement",
 		"RevokeAdminStatement",
 		"SelectStatement",
 		"SetPasswordUserStatement",
@@ -139,9 +139,15 @@ func init() {
 		show.Handle(QUERIES, func(p *Parser) (Statement, error) {
 			return p.parseShowQueriesStatement()
 		})
+		show.Handle(QUOTAS, func(p *Parser) (Statement, error) {
+			return p.parseShowQuotasStatement()
+		})
 		show.Group(RETENTION).Handle(POLICIES, func(p *Parser) (Statement, error) {
 			return p.parseShowRetentionPoliciesStatement()
 		})
+		show.Handle(SCHEMAS, func(p *Parser) (Statement, error) {
+			return p.parseShowSchemasStatement()
+		})
 		show.Handle(SERIES, func(p *Parser) (Statement, error) {
 			return p.parseShowSeriesStatement()
 		})
@@ -185,6 +191,9 @@ func init() {
 		create.Group(RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
 			return p.parseCreateRetentionPolicyStatement()
 		})
+		create.Handle(SCHEMA, func(p *Parser) (Statement, error) {
+			return p.parseCreateSchemaStatement()
+		})
 		create.Handle(SUBSCRIPTION, func(p *Parser) (Statement, error) {
 			return p.parseCreateSubscriptionStatement()
 		})
@@ -202,6 +211,9 @@ func init() {
 		drop.Group(RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
 			return p.parseDropRetentionPolicyStatement()
 		})
+		drop.Handle(SCHEMA, func(p *Parser) (Statement, error) {
+			return p.parseDropSchemaStatement()
+		})
 		drop.Handle(SERIES, func(p *Parser) (Statement, error) {
 			return p.parseDropSeriesStatement()
 		})
@@ -218,14 +230,34 @@ func init() {
 	Language.Handle(EXPLAIN, func(p *Parser) (Statement, error) {
 		return p.parseExplainStatement()
 	})
+	Language.Handle(EXPORT, func(p *Parser) (Statement, error) {
+		return p.parseExportMetaStatement()
+	})
+	Language.Handle(IMPORT, func(p *Parser) (Statement, error) {
+		return p.parseImportMetaStatement()
+	})
 	Language.Handle(GRANT, func(p *Parser) (Statement, error) {
 		return p.parseGrantStatement()
 	})
 	Language.Handle(REVOKE, func(p *Parser) (Statement, error) {
 		return p.parseRevokeStatement()
 	})
-	Language.Group(ALTER, RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
-		return p.parseAlterRetentionPolicyStatement()
+	Language.Group(ALTER).With(func(alter *ParseTree) {
+		alter.Handle(DATABASE, func(p *Parser) (Statement, error) {
+			return p.parseAlterDatabaseStatement()
+		})
+		alter.Group(RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
+			return p.parseAlterRetentionPolicyStatement()
+		})
+		alter.Handle(SHARD, func(p *Parser) (Statement, error) {
+			return p.parseAlterShardStatement()
+		})
+	})
+	Language.Group(MOVE).Handle(SHARD, func(p *Parser) (Statement, error) {
+		return p.parseMoveShardStatement(false)
+	})
+	Language.Group(COPY).Handle(SHARD, func(p *Parser) (Statement, error) {
+		return p.parseMoveShardStatement(true)
 	})
 	Language.Group(SET, PASSWORD).Handle(FOR, func(p *Parser) (Statement, error) {
 		return p.parseSetPasswordUserStatement()
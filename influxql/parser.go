@@ -197,6 +197,150 @@ func (p *Parser) parseCreateSubscriptionStatement() (*CreateSubscriptionStatemen
 	return stmt, nil
 }
 
+// parseCreateSchemaStatement parses a string and returns a CreateSchemaStatement.
+// This function assumes the "CREATE SCHEMA" tokens have already been consumed.
+func (p *Parser) parseCreateSchemaStatement() (*CreateSchemaStatement, error) {
+	stmt := &CreateSchemaStatement{}
+
+	// Parse the measurement name and the optional database.
+	name, db, err := p.parseSchemaName()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name, stmt.Database = name, db
+
+	// Loop through the options (TAGS, REQUIRED TAGS, FIELDS, STRICT, WARN).
+	found := make(map[string]struct{})
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+
+		var option string
+		if tok == IDENT {
+			option = strings.ToUpper(lit)
+		}
+		if option == "STRICT" || option == "WARN" {
+			option = "mode"
+		}
+
+		if _, ok := found[option]; ok && option != "" {
+			return nil, &ParseError{
+				Message: fmt.Sprintf("found duplicate %s option", strings.ToUpper(lit)),
+				Pos:     pos,
+			}
+		}
+
+		switch option {
+		case "TAGS":
+			if stmt.TagKeys, err = p.ParseIdentList(); err != nil {
+				return nil, err
+			}
+		case "REQUIRED":
+			if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || !strings.EqualFold(lit, "TAGS") {
+				return nil, newParseError(tokstr(tok, lit), []string{"TAGS"}, pos)
+			}
+			if stmt.RequiredTags, err = p.ParseIdentList(); err != nil {
+				return nil, err
+			}
+		case "FIELDS":
+			if stmt.Fields, err = p.parseSchemaFields(); err != nil {
+				return nil, err
+			}
+		case "mode":
+			stmt.Warn = strings.EqualFold(lit, "WARN")
+		default:
+			if len(stmt.Fields) == 0 {
+				return nil, newParseError(tokstr(tok, lit), []string{"TAGS", "REQUIRED", "FIELDS", "STRICT", "WARN"}, pos)
+			}
+			p.Unscan()
+			return stmt, nil
+		}
+		found[option] = struct{}{}
+	}
+}
+
+// parseSchemaFields parses a comma delimited list of field names and types.
+func (p *Parser) parseSchemaFields() ([]*SchemaField, error) {
+	var fields []*SchemaField
+	for {
+		name, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+
+		f := &SchemaField{Name: name}
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok == IDENT {
+			switch strings.ToLower(lit) {
+			case "float":
+				f.Type = Float
+			case "integer":
+				f.Type = Integer
+			case "unsigned":
+				f.Type = Unsigned
+			case "string":
+				f.Type = String
+			case "boolean":
+				f.Type = Boolean
+			}
+		}
+		if f.Type == Unknown {
+			return nil, newParseError(tokstr(tok, lit), []string{"float", "integer", "unsigned", "string", "boolean"}, pos)
+		}
+		fields = append(fields, f)
+
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != COMMA {
+			p.Unscan()
+			return fields, nil
+		}
+	}
+}
+
+// parseDropSchemaStatement parses a string and returns a DropSchemaStatement.
+// This function assumes the "DROP SCHEMA" tokens have already been consumed.
+func (p *Parser) parseDropSchemaStatement() (*DropSchemaStatement, error) {
+	name, db, err := p.parseSchemaName()
+	if err != nil {
+		return nil, err
+	}
+	return &DropSchemaStatement{Name: name, Database: db}, nil
+}
+
+// parseSchemaName parses a measurement name followed by an optional
+// "ON <database>" clause.
+func (p *Parser) parseSchemaName() (name, database string, err error) {
+	if name, err = p.ParseIdent(); err != nil {
+		return "", "", err
+	}
+
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		if database, err = p.ParseIdent(); err != nil {
+			return "", "", err
+		}
+	} else {
+		p.Unscan()
+	}
+	return name, database, nil
+}
+
+// parseShowSchemasStatement parses a string and returns a ShowSchemasStatement.
+// This function assumes the "SHOW SCHEMAS" tokens have already been consumed.
+func (p *Parser) parseShowSchemasStatement() (*ShowSchemasStatement, error) {
+	stmt := &ShowSchemasStatement{}
+
+	// Parse the optional database.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		ident, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Database = ident
+	} else {
+		p.Unscan()
+	}
+
+	return stmt, nil
+}
+
 // parseCreateRetentionPolicyStatement parses a string and returns a create retention policy statement.
 // This function assumes the CREATE RETENTION POLICY tokens have already been consumed.
 func (p *Parser) parseCreateRetentionPolicyStatement() (*CreateRetentionPolicyStatement, error) {
@@ -355,9 +499,15 @@ Loop:
 			}
 		case DEFAULT:
 			stmt.Default = true
+		case READ:
+			readOnly, err := p.parseReadOnly()
+			if err != nil {
+				return nil, err
+			}
+			stmt.ReadOnly = &readOnly
 		default:
 			if len(found) == 0 {
-				return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "DEFAULT"}, pos)
+				return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "DEFAULT", "READ"}, pos)
 			}
 			p.Unscan()
 			break Loop
@@ -368,6 +518,103 @@ Loop:
 	return stmt, nil
 }
 
+// parseAlterShardStatement parses a string and returns an AlterShardStatement.
+// This function assumes the "ALTER SHARD" tokens have already been consumed.
+func (p *Parser) parseAlterShardStatement() (*AlterShardStatement, error) {
+	var err error
+	stmt := &AlterShardStatement{}
+
+	// Parse the ID of the shard to alter.
+	if stmt.ID, err = p.ParseUInt64(); err != nil {
+		return nil, err
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != READ {
+		return nil, newParseError(tokstr(tok, lit), []string{"READ"}, pos)
+	}
+	if stmt.ReadOnly, err = p.parseReadOnly(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseReadOnly parses the ONLY or WRITE token of a READ ONLY or READ WRITE
+// option and returns true for READ ONLY.
+// This function assumes the "READ" token has already been consumed.
+func (p *Parser) parseReadOnly() (bool, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch {
+	case tok == IDENT && strings.EqualFold(lit, "ONLY"):
+		return true, nil
+	case tok == WRITE:
+		return false, nil
+	default:
+		return false, newParseError(tokstr(tok, lit), []string{"ONLY", "WRITE"}, pos)
+	}
+}
+
+// parseAlterDatabaseStatement parses a string and returns an AlterDatabaseStatement.
+// This function assumes the "ALTER DATABASE" tokens have already been consumed.
+func (p *Parser) parseAlterDatabaseStatement() (*AlterDatabaseStatement, error) {
+	stmt := &AlterDatabaseStatement{}
+
+	// Parse the database name.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Loop through the options (DISK QUOTA, SERIES QUOTA, READ ONLY, READ WRITE).
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+
+		var quota **int64
+		switch {
+		case tok == IDENT && strings.EqualFold(lit, "DISK"):
+			quota = &stmt.MaxDiskBytes
+		case tok == SERIES:
+			quota = &stmt.MaxSeriesN
+		case tok == READ:
+			if stmt.ReadOnly != nil {
+				return nil, &ParseError{Message: "found duplicate READ option", Pos: pos}
+			}
+			readOnly, err := p.parseReadOnly()
+			if err != nil {
+				return nil, err
+			}
+			stmt.ReadOnly = &readOnly
+			continue
+		default:
+			if stmt.MaxDiskBytes == nil && stmt.MaxSeriesN == nil && stmt.ReadOnly == nil {
+				return nil, newParseError(tokstr(tok, lit), []string{"DISK", "SERIES", "READ"}, pos)
+			}
+			p.Unscan()
+			return stmt, nil
+		}
+
+		if *quota != nil {
+			return nil, &ParseError{
+				Message: fmt.Sprintf("found duplicate %s QUOTA option", strings.ToUpper(lit)),
+				Pos:     pos,
+			}
+		}
+
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != QUOTA {
+			return nil, newParseError(tokstr(tok, lit), []string{"QUOTA"}, pos)
+		}
+
+		n, err := p.ParseUInt64()
+		if err != nil {
+			return nil, err
+		} else if n > math.MaxInt64 {
+			return nil, &ParseError{Message: fmt.Sprintf("quota %d is too large", n), Pos: pos}
+		}
+		v := int64(n)
+		*quota = &v
+	}
+}
+
 // ParseInt parses a string representing a base 10 integer and returns the number.
 // It returns an error if the parsed number is outside the range [min, max].
 func (p *Parser) ParseInt(min, max int) (int, error) {
@@ -1142,6 +1389,25 @@ func (p *Parser) parseShowRetentionPoliciesStatement() (*ShowRetentionPoliciesSt
 	return stmt, nil
 }
 
+// parseShowQuotasStatement parses a string and returns a ShowQuotasStatement.
+// This function assumes the "SHOW QUOTAS" tokens have already been consumed.
+func (p *Parser) parseShowQuotasStatement() (*ShowQuotasStatement, error) {
+	stmt := &ShowQuotasStatement{}
+
+	// Parse the optional database.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		ident, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Database = ident
+	} else {
+		p.Unscan()
+	}
+
+	return stmt, nil
+}
+
 // This function assumes the "SHOW TAG KEY" tokens have already been consumed.
 func (p *Parser) parseShowTagKeyCardinalityStatement() (Statement, error) {
 	var err error
@@ -1614,6 +1880,42 @@ func (p *Parser) parseDropShardStatement() (*DropShardStatement, error) {
 	return stmt, nil
 }
 
+// parseMoveShardStatement parses a string and returns a MoveShardStatement
+// copying the shard if copy is true.
+// This function assumes the "MOVE SHARD" or "COPY SHARD" tokens have already been consumed.
+func (p *Parser) parseMoveShardStatement(copy bool) (*MoveShardStatement, error) {
+	var err error
+	stmt := &MoveShardStatement{Copy: copy}
+
+	// Parse the optional GROUP token.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == GROUP {
+		stmt.Group = true
+	} else {
+		p.Unscan()
+	}
+
+	// Parse the ID of the shard or shard group.
+	if stmt.ID, err = p.ParseUInt64(); err != nil {
+		return nil, err
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+
+	// Parse the database and retention policy to move the shard to.
+	if stmt.Database, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	if tok, pos, lit := p.Scan(); tok != DOT {
+		return nil, newParseError(tokstr(tok, lit), []string{"."}, pos)
+	}
+	if stmt.RetentionPolicy, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
 // parseShowContinuousQueriesStatement parses a string and returns a ShowContinuousQueriesStatement.
 // This function assumes the "SHOW CONTINUOUS" tokens have already been consumed.
 func (p *Parser) parseShowContinuousQueriesStatement() (*ShowContinuousQueriesStatement, error) {
@@ -1984,6 +2286,55 @@ func (p *Parser) parseShowDiagnosticsStatement() (*ShowDiagnosticsStatement, err
 	return stmt, err
 }
 
+// parseExportMetaStatement parses a string and returns an ExportMetaStatement.
+// This function assumes the EXPORT token has already been consumed.
+func (p *Parser) parseExportMetaStatement() (*ExportMetaStatement, error) {
+	if err := p.parseMetaIdent(); err != nil {
+		return nil, err
+	}
+	stmt := &ExportMetaStatement{}
+
+	tok, _, lit := p.ScanIgnoreWhitespace()
+	if tok != IDENT || strings.ToLower(lit) != "without" {
+		p.Unscan()
+		return stmt, nil
+	}
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToLower(lit) != "hashes" {
+		return nil, newParseError(tokstr(tok, lit), []string{"HASHES"}, pos)
+	}
+	stmt.WithoutHashes = true
+	return stmt, nil
+}
+
+// parseImportMetaStatement parses a string and returns an ImportMetaStatement.
+// This function assumes the IMPORT token has already been consumed.
+func (p *Parser) parseImportMetaStatement() (*ImportMetaStatement, error) {
+	if err := p.parseMetaIdent(); err != nil {
+		return nil, err
+	}
+	stmt := &ImportMetaStatement{}
+
+	var err error
+	if stmt.Data, err = p.parseString(); err != nil {
+		return nil, err
+	}
+
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToLower(lit) == "replace" {
+		stmt.Replace = true
+	} else {
+		p.Unscan()
+	}
+	return stmt, nil
+}
+
+// parseMetaIdent consumes the META identifier of the EXPORT and IMPORT statements.
+func (p *Parser) parseMetaIdent() error {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToLower(lit) != "meta" {
+		return newParseError(tokstr(tok, lit), []string{"META"}, pos)
+	}
+	return nil
+}
+
 // parseDropContinuousQueriesStatement parses a string and returns a DropContinuousQueryStatement.
 // This function assumes the "DROP CONTINUOUS" tokens have already been consumed.
 func (p *Parser) parseDropContinuousQueryStatement() (*DropContinuousQueryStatement, error) {
@@ -1802,6 +1802,24 @@ func TestParser_ParseStatement(t *testing.T) {
 				Database: "db0",
 			},
 		},
+		// SHOW QUOTAS
+		{
+			s:    `SHOW QUOTAS`,
+			stmt: &influxql.ShowQuotasStatement{},
+		},
+
+		// SHOW QUOTAS ON db0
+		{
+			s:    `SHOW QUOTAS ON db0`,
+			stmt: &influxql.ShowQuotasStatement{Database: "db0"},
+		},
+
+		// SHOW SCHEMAS
+		{
+			s:    `SHOW SCHEMAS ON db0`,
+			stmt: &influxql.ShowSchemasStatement{Database: "db0"},
+		},
+
 		// SHOW TAG KEY CARDINALITY statement
 		{
 			s:    `SHOW TAG KEY CARDINALITY`,
@@ -3029,6 +3047,66 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// CREATE SCHEMA
+		{
+			s: `CREATE SCHEMA cpu ON db0 TAGS region, dc REQUIRED TAGS host FIELDS value float, n integer, ok boolean WARN`,
+			stmt: &influxql.CreateSchemaStatement{
+				Name:         "cpu",
+				Database:     "db0",
+				TagKeys:      []string{"region", "dc"},
+				RequiredTags: []string{"host"},
+				Fields: []*influxql.SchemaField{
+					{Name: "value", Type: influxql.Float},
+					{Name: "n", Type: influxql.Integer},
+					{Name: "ok", Type: influxql.Boolean},
+				},
+				Warn: true,
+			},
+		},
+
+		// CREATE SCHEMA with options in another order
+		{
+			s: `CREATE SCHEMA "cpu load" STRICT FIELDS value FLOAT, msg String REQUIRED TAGS host`,
+			stmt: &influxql.CreateSchemaStatement{
+				Name:         "cpu load",
+				RequiredTags: []string{"host"},
+				Fields: []*influxql.SchemaField{
+					{Name: "value", Type: influxql.Float},
+					{Name: "msg", Type: influxql.String},
+				},
+			},
+		},
+
+		// DROP SCHEMA
+		{
+			s:    `DROP SCHEMA cpu ON db0`,
+			stmt: &influxql.DropSchemaStatement{Name: "cpu", Database: "db0"},
+		},
+
+		// ALTER DATABASE
+		{
+			s:    `ALTER DATABASE testdb DISK QUOTA 10737418240 SERIES QUOTA 100000`,
+			stmt: &influxql.AlterDatabaseStatement{Name: "testdb", MaxDiskBytes: int64ptr(10737418240), MaxSeriesN: int64ptr(100000)},
+		},
+
+		// ALTER DATABASE with a single quota
+		{
+			s:    `ALTER DATABASE testdb series quota 0`,
+			stmt: &influxql.AlterDatabaseStatement{Name: "testdb", MaxSeriesN: int64ptr(0)},
+		},
+
+		// ALTER DATABASE READ ONLY
+		{
+			s:    `ALTER DATABASE testdb READ ONLY`,
+			stmt: &influxql.AlterDatabaseStatement{Name: "testdb", ReadOnly: boolptr(true)},
+		},
+
+		// ALTER DATABASE READ WRITE with a quota
+		{
+			s:    `ALTER DATABASE testdb DISK QUOTA 0 read write`,
+			stmt: &influxql.AlterDatabaseStatement{Name: "testdb", MaxDiskBytes: int64ptr(0), ReadOnly: boolptr(false)},
+		},
+
 		// ALTER RETENTION POLICY
 		{
 			s:    `ALTER RETENTION POLICY policy1 ON testdb DURATION 1m REPLICATION 4 DEFAULT`,
@@ -3085,6 +3163,36 @@ func TestParser_ParseStatement(t *testing.T) {
 			stmt: newAlterRetentionPolicyStatement("default", "testdb", time.Duration(0), 0, 1, false),
 		},
 
+		// ALTER RETENTION POLICY READ ONLY
+		{
+			s:    `ALTER RETENTION POLICY policy1 ON testdb READ ONLY`,
+			stmt: &influxql.AlterRetentionPolicyStatement{Name: "policy1", Database: "testdb", ReadOnly: boolptr(true)},
+		},
+
+		// MOVE SHARD
+		{
+			s:    `MOVE SHARD 12 TO db1.rp1`,
+			stmt: &influxql.MoveShardStatement{ID: 12, Database: "db1", RetentionPolicy: "rp1"},
+		},
+
+		// COPY SHARD GROUP
+		{
+			s:    `COPY SHARD GROUP 3 TO "db 1"."rp 1"`,
+			stmt: &influxql.MoveShardStatement{ID: 3, Group: true, Copy: true, Database: "db 1", RetentionPolicy: "rp 1"},
+		},
+
+		// ALTER SHARD READ ONLY
+		{
+			s:    `ALTER SHARD 12 READ ONLY`,
+			stmt: &influxql.AlterShardStatement{ID: 12, ReadOnly: true},
+		},
+
+		// ALTER SHARD READ WRITE
+		{
+			s:    `ALTER SHARD 12 READ WRITE`,
+			stmt: &influxql.AlterShardStatement{ID: 12},
+		},
+
 		// SHOW STATS
 		{
 			s: `SHOW STATS`,
@@ -3123,6 +3231,26 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// EXPORT META
+		{
+			s:    `EXPORT META`,
+			stmt: &influxql.ExportMetaStatement{},
+		},
+		{
+			s:    `EXPORT META WITHOUT HASHES`,
+			stmt: &influxql.ExportMetaStatement{WithoutHashes: true},
+		},
+
+		// IMPORT META
+		{
+			s:    `IMPORT META '{"databases":[]}'`,
+			stmt: &influxql.ImportMetaStatement{Data: `{"databases":[]}`},
+		},
+		{
+			s:    `IMPORT META '{"databases":[]}' REPLACE`,
+			stmt: &influxql.ImportMetaStatement{Data: `{"databases":[]}`, Replace: true},
+		},
+
 		// CREATE SUBSCRIPTION
 		{
 			s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ANY 'udp://host1:9093', 'udp://host2:9093'`,
@@ -3152,9 +3280,9 @@ func TestParser_ParseStatement(t *testing.T) {
 		},
 
 		// Errors
-		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, EXPORT, IMPORT, GRANT, REVOKE, ALTER, MOVE, COPY, SET, KILL at line 1, char 1`},
 		{s: `SELECT`, err: `found EOF, expected identifier, string, number, bool at line 1, char 8`},
-		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, EXPORT, IMPORT, GRANT, REVOKE, ALTER, MOVE, COPY, SET, KILL at line 1, char 1`},
 		{s: `SELECT field1 X`, err: `found X, expected FROM at line 1, char 15`},
 		{s: `SELECT field1 FROM "series" WHERE X +;`, err: `found ;, expected identifier, string, number, bool at line 1, char 38`},
 		{s: `SELECT field1 FROM myseries GROUP`, err: `found EOF, expected BY at line 1, char 35`},
@@ -3200,9 +3328,13 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `SHOW RETENTION ON`, err: `found ON, expected POLICIES at line 1, char 16`},
 		{s: `SHOW RETENTION POLICIES ON`, err: `found EOF, expected identifier at line 1, char 28`},
 		{s: `SHOW SHARD`, err: `found EOF, expected GROUPS at line 1, char 12`},
-		{s: `SHOW FOO`, err: `found FOO, expected CONTINUOUS, DATABASES, DIAGNOSTICS, FIELD, GRANTS, MEASUREMENT, MEASUREMENTS, QUERIES, RETENTION, SERIES, SHARD, SHARDS, STATS, SUBSCRIPTIONS, TAG, USERS at line 1, char 6`},
+		{s: `SHOW FOO`, err: `found FOO, expected CONTINUOUS, DATABASES, DIAGNOSTICS, FIELD, GRANTS, MEASUREMENT, MEASUREMENTS, QUERIES, QUOTAS, RETENTION, SCHEMAS, SERIES, SHARD, SHARDS, STATS, SUBSCRIPTIONS, TAG, USERS at line 1, char 6`},
+		{s: `SHOW QUOTAS ON`, err: `found EOF, expected identifier at line 1, char 16`},
 		{s: `SHOW STATS FOR`, err: `found EOF, expected string at line 1, char 16`},
 		{s: `SHOW DIAGNOSTICS FOR`, err: `found EOF, expected string at line 1, char 22`},
+		{s: `EXPORT`, err: `found EOF, expected META at line 1, char 8`},
+		{s: `EXPORT META WITHOUT`, err: `found EOF, expected HASHES at line 1, char 21`},
+		{s: `IMPORT META`, err: `found EOF, expected string at line 1, char 13`},
 		{s: `SHOW GRANTS`, err: `found EOF, expected FOR at line 1, char 13`},
 		{s: `SHOW GRANTS FOR`, err: `found EOF, expected identifier at line 1, char 17`},
 		{s: `DROP CONTINUOUS`, err: `found EOF, expected QUERY at line 1, char 17`},
@@ -3213,8 +3345,16 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `CREATE CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 25`},
 		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
 		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE EVERY 10s FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(5s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
-		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, MEASUREMENT, RETENTION, SERIES, SHARD, SUBSCRIPTION, USER at line 1, char 6`},
-		{s: `CREATE FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, USER, RETENTION, SUBSCRIPTION at line 1, char 8`},
+		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, MEASUREMENT, RETENTION, SCHEMA, SERIES, SHARD, SUBSCRIPTION, USER at line 1, char 6`},
+		{s: `DROP SCHEMA`, err: `found EOF, expected identifier at line 1, char 13`},
+		{s: `CREATE FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, USER, RETENTION, SCHEMA, SUBSCRIPTION at line 1, char 8`},
+		{s: `CREATE SCHEMA cpu`, err: `found EOF, expected TAGS, REQUIRED, FIELDS, STRICT, WARN at line 1, char 19`},
+		{s: `CREATE SCHEMA cpu TAGS host`, err: `found EOF, expected TAGS, REQUIRED, FIELDS, STRICT, WARN at line 1, char 29`},
+		{s: `CREATE SCHEMA cpu REQUIRED host`, err: `found host, expected TAGS at line 1, char 28`},
+		{s: `CREATE SCHEMA cpu FIELDS value`, err: `found EOF, expected float, integer, unsigned, string, boolean at line 1, char 32`},
+		{s: `CREATE SCHEMA cpu FIELDS value time`, err: `found time, expected float, integer, unsigned, string, boolean at line 1, char 32`},
+		{s: `CREATE SCHEMA cpu FIELDS value float FIELDS n integer`, err: `found duplicate FIELDS option at line 1, char 38`},
+		{s: `CREATE SCHEMA cpu WARN FIELDS value float STRICT`, err: `found duplicate STRICT option at line 1, char 43`},
 		{s: `CREATE DATABASE`, err: `found EOF, expected identifier at line 1, char 17`},
 		{s: `CREATE DATABASE "testdb" WITH`, err: `found EOF, expected DURATION, NAME, REPLICATION, SHARD at line 1, char 31`},
 		{s: `CREATE DATABASE "testdb" WITH DURATION`, err: `found EOF, expected duration at line 1, char 40`},
@@ -3325,11 +3465,27 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 0`, err: `invalid value 0: must be 1 <= n <= 2147483647 at line 1, char 67`},
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION bad`, err: `found bad, expected integer at line 1, char 67`},
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 SHARD DURATION INF`, err: `invalid duration INF for shard duration at line 1, char 84`},
-		{s: `ALTER`, err: `found EOF, expected RETENTION at line 1, char 7`},
+		{s: `ALTER`, err: `found EOF, expected DATABASE, RETENTION, SHARD at line 1, char 7`},
+		{s: `ALTER DATABASE`, err: `found EOF, expected identifier at line 1, char 16`},
+		{s: `ALTER DATABASE db0`, err: `found EOF, expected DISK, SERIES, READ at line 1, char 20`},
+		{s: `ALTER DATABASE db0 READ`, err: `found EOF, expected ONLY, WRITE at line 1, char 25`},
+		{s: `ALTER DATABASE db0 READ ONLY READ WRITE`, err: `found duplicate READ option at line 1, char 30`},
+		{s: `ALTER DATABASE db0 DISK`, err: `found EOF, expected QUOTA at line 1, char 25`},
+		{s: `ALTER DATABASE db0 DISK QUOTA -1`, err: `found -, expected integer at line 1, char 31`},
+		{s: `ALTER DATABASE db0 DISK QUOTA 1 DISK QUOTA 2`, err: `found duplicate DISK QUOTA option at line 1, char 33`},
 		{s: `ALTER RETENTION`, err: `found EOF, expected POLICY at line 1, char 17`},
 		{s: `ALTER RETENTION POLICY`, err: `found EOF, expected identifier at line 1, char 24`},
 		{s: `ALTER RETENTION POLICY policy1`, err: `found EOF, expected ON at line 1, char 32`}, {s: `ALTER RETENTION POLICY policy1 ON`, err: `found EOF, expected identifier at line 1, char 35`},
-		{s: `ALTER RETENTION POLICY policy1 ON testdb`, err: `found EOF, expected DURATION, REPLICATION, SHARD, DEFAULT at line 1, char 42`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb`, err: `found EOF, expected DURATION, REPLICATION, SHARD, DEFAULT, READ at line 1, char 42`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb READ ONLY READ WRITE`, err: `found duplicate READ option at line 1, char 52`},
+		{s: `ALTER SHARD`, err: `found EOF, expected integer at line 1, char 13`},
+		{s: `MOVE`, err: `found EOF, expected SHARD at line 1, char 6`},
+		{s: `MOVE SHARD GROUP`, err: `found EOF, expected integer at line 1, char 18`},
+		{s: `MOVE SHARD 1`, err: `found EOF, expected TO at line 1, char 13`},
+		{s: `MOVE SHARD 1 TO db1`, err: `found EOF, expected . at line 1, char 21`},
+		{s: `COPY SHARD 1 TO db1.`, err: `found EOF, expected identifier at line 1, char 21`},
+		{s: `ALTER SHARD 1`, err: `found EOF, expected READ at line 1, char 14`},
+		{s: `ALTER SHARD 1 READ`, err: `found EOF, expected ONLY, WRITE at line 1, char 20`},
 		{s: `ALTER RETENTION POLICY policy1 ON testdb REPLICATION 1 REPLICATION 2`, err: `found duplicate REPLICATION option at line 1, char 56`},
 		{s: `ALTER RETENTION POLICY policy1 ON testdb DURATION 15251w`, err: `overflowed duration 15251w: choose a smaller duration or INF at line 1, char 51`},
 		{s: `ALTER RETENTION POLICY policy1 ON testdb DURATION INF SHARD DURATION INF`, err: `invalid duration INF for shard duration at line 1, char 70`},
@@ -3340,7 +3496,7 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `SET PASSWORD FOR dejan`, err: `found EOF, expected = at line 1, char 24`},
 		{s: `SET PASSWORD FOR dejan =`, err: `found EOF, expected string at line 1, char 25`},
 		{s: `SET PASSWORD FOR dejan = bla`, err: `found bla, expected string at line 1, char 26`},
-		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, EXPORT, IMPORT, GRANT, REVOKE, ALTER, MOVE, COPY, SET, KILL at line 1, char 1`},
 		{s: `SELECT * FROM cpu WHERE "tagkey" = $$`, err: `empty bound parameter`},
 	}
 
@@ -3370,9 +3526,9 @@ func TestParser_ParseStatement(t *testing.T) {
 				t.Errorf("%d. %q\n\nstmt mismatch:\n\nexp=%#v\n\ngot=%#v\n\n", i, tt.s, tt.stmt, stmt)
 			} else {
 				// Attempt to reparse the statement as a string and confirm it parses the same.
-				// Skip this if we have some kind of statement with a password since those will never be reparsed.
+				// Skip this if we have some kind of statement with a password or hashes since those will never be reparsed.
 				switch stmt.(type) {
-				case *influxql.CreateUserStatement, *influxql.SetPasswordUserStatement:
+				case *influxql.CreateUserStatement, *influxql.SetPasswordUserStatement, *influxql.ImportMetaStatement:
 					continue
 				}
 
@@ -4042,3 +4198,11 @@ func duration(v time.Duration) *time.Duration {
 func intptr(v int) *int {
 	return &v
 }
+
+func int64ptr(v int64) *int64 {
+	return &v
+}
+
+func boolptr(v bool) *bool {
+	return &v
+}
@@ -20,6 +20,10 @@ func TestSanitize(t *testing.T) {
 			s:    `set password for "admin" = 'admin'`,
 			stmt: `set password for "admin" = [REDACTED]`,
 		},
+		{
+			s:    `import meta '{"users":[{"name":"admin","hash":"$2a$10$abc"}]}'`,
+			stmt: `import meta '{"users":[{"name":"admin","hash":[REDACTED]}]}'`,
+		},
 
 		// Common invalid statements that should still be redacted.
 		{
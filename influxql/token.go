@@ -76,6 +76,7 @@ const (
 	CARDINALITY
 	CREATE
 	CONTINUOUS
+	COPY
 	DATABASE
 	DATABASES
 	DEFAULT
@@ -90,6 +91,7 @@ const (
 	EVERY
 	EXACT
 	EXPLAIN
+	EXPORT
 	FIELD
 	FOR
 	FROM
@@ -97,6 +99,7 @@ const (
 	GRANTS
 	GROUP
 	GROUPS
+	IMPORT
 	IN
 	INF
 	INSERT
@@ -107,6 +110,7 @@ const (
 	LIMIT
 	MEASUREMENT
 	MEASUREMENTS
+	MOVE
 	NAME
 	OFFSET
 	ON
@@ -117,11 +121,15 @@ const (
 	PRIVILEGES
 	QUERIES
 	QUERY
+	QUOTA
+	QUOTAS
 	READ
 	REPLICATION
 	RESAMPLE
 	RETENTION
 	REVOKE
+	SCHEMA
+	SCHEMAS
 	SELECT
 	SERIES
 	SET
@@ -199,6 +207,7 @@ var tokens = [...]string{
 	CARDINALITY:   "CARDINALITY",
 	CREATE:        "CREATE",
 	CONTINUOUS:    "CONTINUOUS",
+	COPY:          "COPY",
 	DATABASE:      "DATABASE",
 	DATABASES:     "DATABASES",
 	DEFAULT:       "DEFAULT",
@@ -213,6 +222,7 @@ var tokens = [...]string{
 	EVERY:         "EVERY",
 	EXACT:         "EXACT",
 	EXPLAIN:       "EXPLAIN",
+	EXPORT:        "EXPORT",
 	FIELD:         "FIELD",
 	FOR:           "FOR",
 	FROM:          "FROM",
@@ -220,6 +230,7 @@ var tokens = [...]string{
 	GRANTS:        "GRANTS",
 	GROUP:         "GROUP",
 	GROUPS:        "GROUPS",
+	IMPORT:        "IMPORT",
 	IN:            "IN",
 	INF:           "INF",
 	INSERT:        "INSERT",
@@ -230,6 +241,7 @@ var tokens = [...]string{
 	LIMIT:         "LIMIT",
 	MEASUREMENT:   "MEASUREMENT",
 	MEASUREMENTS:  "MEASUREMENTS",
+	MOVE:          "MOVE",
 	NAME:          "NAME",
 	OFFSET:        "OFFSET",
 	ON:            "ON",
@@ -240,11 +252,15 @@ var tokens = [...]string{
 	PRIVILEGES:    "PRIVILEGES",
 	QUERIES:       "QUERIES",
 	QUERY:         "QUERY",
+	QUOTA:         "QUOTA",
+	QUOTAS:        "QUOTAS",
 	READ:          "READ",
 	REPLICATION:   "REPLICATION",
 	RESAMPLE:      "RESAMPLE",
 	RETENTION:     "RETENTION",
 	REVOKE:        "REVOKE",
+	SCHEMA:        "SCHEMA",
+	SCHEMAS:       "SCHEMAS",
 	SELECT:        "SELECT",
 	SERIES:        "SERIES",
 	SET:           "SET",
@@ -10,9 +10,11 @@ import (
 // MetaClientMock is a mockable implementation of meta.MetaClient.
 type MetaClientMock struct {
 	CloseFn                             func() error
+	CopyShardGroupFn                    func(id uint64, database, policy string) (*meta.ShardGroupInfo, error)
 	CreateContinuousQueryFn             func(database, name, query string) error
 	CreateDatabaseFn                    func(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithRetentionPolicyFn func(name string, spec *meta.RetentionPolicySpec) (*meta.DatabaseInfo, error)
+	CreateMeasurementSchemaFn           func(database string, msi meta.MeasurementSchemaInfo) error
 	CreateRetentionPolicyFn             func(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error)
 	CreateShardGroupFn                  func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error)
 	CreateSubscriptionFn                func(database, rp, name, mode string, destinations []string) error
@@ -21,14 +23,19 @@ type MetaClientMock struct {
 	DatabaseFn  func(name string) *meta.DatabaseInfo
 	DatabasesFn func() []meta.DatabaseInfo
 
-	DataFn                func() meta.Data
-	DeleteShardGroupFn    func(database string, policy string, id uint64) error
-	DropContinuousQueryFn func(database, name string) error
-	DropDatabaseFn        func(name string) error
-	DropRetentionPolicyFn func(database, name string) error
-	DropSubscriptionFn    func(database, rp, name string) error
-	DropShardFn           func(id uint64) error
-	DropUserFn            func(name string) error
+	DataFn                  func() meta.Data
+	DeleteShardGroupFn      func(database string, policy string, id uint64) error
+	DropContinuousQueryFn   func(database, name string) error
+	DropDatabaseFn          func(name string) error
+	DropMeasurementSchemaFn func(database, name string) error
+	DropRetentionPolicyFn   func(database, name string) error
+	DropSubscriptionFn      func(database, rp, name string) error
+	DropShardFn             func(id uint64) error
+	DropUserFn              func(name string) error
+
+	ImportDataFn func(data *meta.Data, replace bool) error
+
+	MoveShardGroupFn func(id uint64, database, policy string) error
 
 	OpenFn func() error
 
@@ -40,9 +47,12 @@ type MetaClientMock struct {
 	AdminUserExistsFn        func() bool
 	SetAdminPrivilegeFn      func(username string, admin bool) error
 	SetDataFn                func(*meta.Data) error
+	SetDatabaseReadOnlyFn    func(name string, readOnly bool) error
 	SetPrivilegeFn           func(username, database string, p influxql.Privilege) error
+	SetShardReadOnlyFn       func(id uint64, readOnly bool) error
 	ShardGroupsByTimeRangeFn func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
 	ShardOwnerFn             func(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo)
+	UpdateDatabaseQuotaFn    func(database string, qu *meta.DatabaseQuotaUpdate) error
 	UpdateRetentionPolicyFn  func(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error
 	UpdateUserFn             func(name, password string) error
 	UserPrivilegeFn          func(username, database string) (*influxql.Privilege, error)
@@ -55,6 +65,10 @@ func (c *MetaClientMock) Close() error {
 	return c.CloseFn()
 }
 
+func (c *MetaClientMock) CopyShardGroup(id uint64, database, policy string) (*meta.ShardGroupInfo, error) {
+	return c.CopyShardGroupFn(id, database, policy)
+}
+
 func (c *MetaClientMock) CreateContinuousQuery(database, name, query string) error {
 	return c.CreateContinuousQueryFn(database, name, query)
 }
@@ -67,6 +81,10 @@ func (c *MetaClientMock) CreateDatabaseWithRetentionPolicy(name string, spec *me
 	return c.CreateDatabaseWithRetentionPolicyFn(name, spec)
 }
 
+func (c *MetaClientMock) CreateMeasurementSchema(database string, msi meta.MeasurementSchemaInfo) error {
+	return c.CreateMeasurementSchemaFn(database, msi)
+}
+
 func (c *MetaClientMock) CreateRetentionPolicy(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error) {
 	return c.CreateRetentionPolicyFn(database, spec, makeDefault)
 }
@@ -103,6 +121,10 @@ func (c *MetaClientMock) DropDatabase(name string) error {
 	return c.DropDatabaseFn(name)
 }
 
+func (c *MetaClientMock) DropMeasurementSchema(database, name string) error {
+	return c.DropMeasurementSchemaFn(database, name)
+}
+
 func (c *MetaClientMock) DropRetentionPolicy(database, name string) error {
 	return c.DropRetentionPolicyFn(database, name)
 }
@@ -127,10 +149,18 @@ func (c *MetaClientMock) SetAdminPrivilege(username string, admin bool) error {
 	return c.SetAdminPrivilegeFn(username, admin)
 }
 
+func (c *MetaClientMock) SetDatabaseReadOnly(name string, readOnly bool) error {
+	return c.SetDatabaseReadOnlyFn(name, readOnly)
+}
+
 func (c *MetaClientMock) SetPrivilege(username, database string, p influxql.Privilege) error {
 	return c.SetPrivilegeFn(username, database, p)
 }
 
+func (c *MetaClientMock) SetShardReadOnly(id uint64, readOnly bool) error {
+	return c.SetShardReadOnlyFn(id, readOnly)
+}
+
 func (c *MetaClientMock) ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
 	return c.ShardGroupsByTimeRangeFn(database, policy, min, max)
 }
@@ -139,6 +169,10 @@ func (c *MetaClientMock) ShardOwner(shardID uint64) (database, policy string, sg
 	return c.ShardOwnerFn(shardID)
 }
 
+func (c *MetaClientMock) UpdateDatabaseQuota(database string, qu *meta.DatabaseQuotaUpdate) error {
+	return c.UpdateDatabaseQuotaFn(database, qu)
+}
+
 func (c *MetaClientMock) UpdateRetentionPolicy(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error {
 	return c.UpdateRetentionPolicyFn(database, name, rpu, makeDefault)
 }
@@ -167,4 +201,12 @@ func (c *MetaClientMock) Open() error                { return c.OpenFn() }
 func (c *MetaClientMock) Data() meta.Data            { return c.DataFn() }
 func (c *MetaClientMock) SetData(d *meta.Data) error { return c.SetDataFn(d) }
 
+func (c *MetaClientMock) ImportData(d *meta.Data, replace bool) error {
+	return c.ImportDataFn(d, replace)
+}
+
+func (c *MetaClientMock) MoveShardGroup(id uint64, database, policy string) error {
+	return c.MoveShardGroupFn(id, database, policy)
+}
+
 func (c *MetaClientMock) PruneShardGroups() error { return c.PruneShardGroupsFn() }
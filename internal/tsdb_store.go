@@ -15,6 +15,7 @@ import (
 type TSDBStoreMock struct {
 	BackupShardFn             func(id uint64, since time.Time, w io.Writer) error
 	CloseFn                   func() error
+	CopyShardFn               func(id uint64, database, policy string, newID uint64) error
 	CreateShardFn             func(database, policy string, shardID uint64, enabled bool) error
 	CreateShardSnapshotFn     func(id uint64) (string, error)
 	DatabasesFn               func() []string
@@ -23,12 +24,14 @@ type TSDBStoreMock struct {
 	DeleteRetentionPolicyFn   func(database, name string) error
 	DeleteSeriesFn            func(database string, sources []influxql.Source, condition influxql.Expr) error
 	DeleteShardFn             func(id uint64) error
+	DatabaseDiskSizeFn        func(database string) (int64, error)
 	DiskSizeFn                func() (int64, error)
 	ExpandSourcesFn           func(sources influxql.Sources) (influxql.Sources, error)
 	ImportShardFn             func(id uint64, r io.Reader) error
 	MeasurementSeriesCountsFn func(database string) (measuments int, series int)
 	MeasurementsCardinalityFn func(database string) (int64, error)
 	MeasurementNamesFn        func(database string, cond influxql.Expr) ([][]byte, error)
+	MoveShardFn               func(id uint64, database, policy string) error
 	OpenFn                    func() error
 	PathFn                    func() string
 	RestoreShardFn            func(id uint64, r io.Reader) error
@@ -50,6 +53,9 @@ func (s *TSDBStoreMock) BackupShard(id uint64, since time.Time, w io.Writer) err
 	return s.BackupShardFn(id, since, w)
 }
 func (s *TSDBStoreMock) Close() error { return s.CloseFn() }
+func (s *TSDBStoreMock) CopyShard(id uint64, database, policy string, newID uint64) error {
+	return s.CopyShardFn(id, database, policy, newID)
+}
 func (s *TSDBStoreMock) CreateShard(database string, retentionPolicy string, shardID uint64, enabled bool) error {
 	return s.CreateShardFn(database, retentionPolicy, shardID, enabled)
 }
@@ -74,6 +80,9 @@ func (s *TSDBStoreMock) DeleteSeries(database string, sources []influxql.Source,
 func (s *TSDBStoreMock) DeleteShard(shardID uint64) error {
 	return s.DeleteShardFn(shardID)
 }
+func (s *TSDBStoreMock) DatabaseDiskSize(database string) (int64, error) {
+	return s.DatabaseDiskSizeFn(database)
+}
 func (s *TSDBStoreMock) DiskSize() (int64, error) {
 	return s.DiskSizeFn()
 }
@@ -86,6 +95,9 @@ func (s *TSDBStoreMock) ImportShard(id uint64, r io.Reader) error {
 func (s *TSDBStoreMock) MeasurementNames(database string, cond influxql.Expr) ([][]byte, error) {
 	return s.MeasurementNamesFn(database, cond)
 }
+func (s *TSDBStoreMock) MoveShard(id uint64, database, policy string) error {
+	return s.MoveShardFn(id, database, policy)
+}
 func (s *TSDBStoreMock) MeasurementSeriesCounts(database string) (measuments int, series int) {
 	return s.MeasurementSeriesCountsFn(database)
 }
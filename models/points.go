@@ -53,6 +53,12 @@ func EnableUintSupport() {
 	enableUint64Support = true
 }
 
+// UintSupportEnabled returns true if uint support is enabled for the point
+// parser.
+func UintSupportEnabled() bool {
+	return enableUint64Support
+}
+
 // Point defines the values that will be written to the database.
 type Point interface {
 	// Name return the measurement name for the point.
@@ -1289,6 +1295,26 @@ func NewPoint(name string, tags Tags, fields Fields, t time.Time) (Point, error)
 	}, nil
 }
 
+// NewPointWithKey returns a new point from the key of its series, as returned
+// by MakeKey, and the text encoding of its fields, as returned by
+// Fields.MarshalBinary. The key is made once for the points of a series, and
+// the key and fields are not copied. Unlike NewPoint, the fields are not
+// checked.
+func NewPointWithKey(key, fields []byte, t time.Time) (Point, error) {
+	if len(fields) == 0 {
+		return nil, ErrPointMustHaveAField
+	} else if err := CheckTime(t); err != nil {
+		return nil, err
+	}
+
+	// Appending to the key of a point must not change the key of the others.
+	return &point{
+		key:    key[:len(key):len(key)],
+		time:   t,
+		fields: fields,
+	}, nil
+}
+
 // pointKey checks some basic requirements for valid points, and returns the
 // key, along with an possible error.
 func pointKey(measurement string, tags Tags, fields Fields, t time.Time) ([]byte, error) {
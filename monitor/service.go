@@ -128,6 +128,29 @@ func (m *Monitor) Open() error {
 	return nil
 }
 
+// Reload applies the store settings of c, restarting the storage of
+// statistics if the monitor is open.
+func (m *Monitor) Reload(c Config) error {
+	open := m.open()
+	if open {
+		if err := m.Close(); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	m.storeEnabled = c.StoreEnabled
+	m.storeDatabase = c.StoreDatabase
+	m.storeInterval = time.Duration(c.StoreInterval)
+	m.storeCreated = false
+	m.mu.Unlock()
+
+	if open {
+		return m.Open()
+	}
+	return nil
+}
+
 func (m *Monitor) Enabled() bool { return m.storeEnabled }
 
 func (m *Monitor) WritePoints(p models.Points) error {
@@ -73,6 +73,7 @@ func (t *Trace) MarshalBinary() ([]byte, error) {
 			Start:        sp.Start,
 			Labels:       labelsToWire(sp.Labels),
 			Fields:       fieldsToWire(sp.Fields),
+			Duration:     sp.Duration,
 		})
 	}
 
@@ -129,6 +130,7 @@ func (t *Trace) UnmarshalBinary(data []byte) error {
 			Start:        sp.Start,
 			Labels:       labels.New(sp.Labels...),
 			Fields:       wireToFields(sp.Fields),
+			Duration:     sp.Duration,
 		}
 	}
 
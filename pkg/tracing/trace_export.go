@@ -0,0 +1,201 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Formats a trace can be exported to.
+const (
+	// ChromeFormat is the JSON object format of the Chrome trace event format,
+	// which can be loaded in chrome://tracing or Perfetto.
+	ChromeFormat = "chrome"
+
+	// JaegerFormat is the JSON format returned by the Jaeger query service,
+	// which can be loaded in the Jaeger UI.
+	JaegerFormat = "jaeger"
+)
+
+// ValidFormat returns true if format is a format a trace can be exported to.
+func ValidFormat(format string) bool {
+	return format == ChromeFormat || format == JaegerFormat
+}
+
+// Spans returns the finished spans of the trace, sorted by start time.
+func (t *Trace) Spans() []RawSpan {
+	t.mu.Lock()
+	spans := make([]RawSpan, 0, len(t.spans))
+	for _, s := range t.spans {
+		spans = append(spans, s)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(spans, func(i, j int) bool {
+		if !spans[i].Start.Equal(spans[j].Start) {
+			return spans[i].Start.Before(spans[j].Start)
+		}
+		return spans[i].Name < spans[j].Name
+	})
+	return spans
+}
+
+// Export returns the trace encoded in format.
+func (t *Trace) Export(format string) ([]byte, error) {
+	switch format {
+	case ChromeFormat:
+		return t.MarshalChromeJSON()
+	case JaegerFormat:
+		return t.MarshalJaegerJSON()
+	default:
+		return nil, fmt.Errorf("unknown trace format %q", format)
+	}
+}
+
+type chromeEvent struct {
+	Name  string                 `json:"name"`
+	Phase string                 `json:"ph"`
+	Ts    float64                `json:"ts"`
+	Dur   float64                `json:"dur"`
+	Pid   int                    `json:"pid"`
+	Tid   int                    `json:"tid"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+// MarshalChromeJSON returns the trace in the Chrome trace event format.
+// Each span is a complete event, with its labels and fields as arguments.
+func (t *Trace) MarshalChromeJSON() ([]byte, error) {
+	spans := t.Spans()
+	events := make([]chromeEvent, 0, len(spans))
+	for _, s := range spans {
+		ev := chromeEvent{
+			Name:  s.Name,
+			Phase: "X",
+			Ts:    float64(s.Start.UnixNano()) / float64(time.Microsecond),
+			Dur:   float64(s.Duration) / float64(time.Microsecond),
+			Pid:   1,
+			Tid:   1,
+		}
+		if len(s.Labels) > 0 || len(s.Fields) > 0 {
+			ev.Args = make(map[string]interface{}, len(s.Labels)+len(s.Fields))
+			for _, l := range s.Labels {
+				ev.Args[l.Key] = l.Value
+			}
+			for _, f := range s.Fields {
+				v := f.Value()
+				if d, ok := v.(time.Duration); ok {
+					v = d.String()
+				}
+				ev.Args[f.Key()] = v
+			}
+		}
+		events = append(events, ev)
+	}
+
+	return json.Marshal(struct {
+		TraceEvents     []chromeEvent `json:"traceEvents"`
+		DisplayTimeUnit string        `json:"displayTimeUnit"`
+	}{events, "ns"})
+}
+
+type jaegerTrace struct {
+	TraceID   string                   `json:"traceID"`
+	Spans     []jaegerSpan             `json:"spans"`
+	Processes map[string]jaegerProcess `json:"processes"`
+}
+
+type jaegerSpan struct {
+	TraceID       string            `json:"traceID"`
+	SpanID        string            `json:"spanID"`
+	OperationName string            `json:"operationName"`
+	References    []jaegerReference `json:"references"`
+	StartTime     int64             `json:"startTime"`
+	Duration      int64             `json:"duration"`
+	Tags          []jaegerTag       `json:"tags"`
+	Logs          []struct{}        `json:"logs"`
+	ProcessID     string            `json:"processID"`
+}
+
+type jaegerReference struct {
+	RefType string `json:"refType"`
+	TraceID string `json:"traceID"`
+	SpanID  string `json:"spanID"`
+}
+
+type jaegerTag struct {
+	Key   string      `json:"key"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type jaegerProcess struct {
+	ServiceName string      `json:"serviceName"`
+	Tags        []jaegerTag `json:"tags"`
+}
+
+// MarshalJaegerJSON returns the trace in the JSON format of the Jaeger query
+// service. Labels and fields of spans are exported as tags.
+func (t *Trace) MarshalJaegerJSON() ([]byte, error) {
+	spans := t.Spans()
+
+	var traceID string
+	out := make([]jaegerSpan, 0, len(spans))
+	for _, s := range spans {
+		traceID = jaegerID(s.Context.TraceID)
+		js := jaegerSpan{
+			TraceID:       traceID,
+			SpanID:        jaegerID(s.Context.SpanID),
+			OperationName: s.Name,
+			References:    []jaegerReference{},
+			StartTime:     s.Start.UnixNano() / int64(time.Microsecond),
+			Duration:      int64(s.Duration / time.Microsecond),
+			Tags:          make([]jaegerTag, 0, len(s.Labels)+len(s.Fields)),
+			Logs:          []struct{}{},
+			ProcessID:     "p1",
+		}
+		if s.ParentSpanID != 0 {
+			js.References = append(js.References, jaegerReference{
+				RefType: "CHILD_OF",
+				TraceID: traceID,
+				SpanID:  jaegerID(s.ParentSpanID),
+			})
+		}
+
+		for _, l := range s.Labels {
+			js.Tags = append(js.Tags, jaegerTag{Key: l.Key, Type: "string", Value: l.Value})
+		}
+		for _, f := range s.Fields {
+			tag := jaegerTag{Key: f.Key()}
+			switch v := f.Value().(type) {
+			case bool:
+				tag.Type, tag.Value = "bool", v
+			case int64:
+				tag.Type, tag.Value = "int64", v
+			case uint64:
+				tag.Type, tag.Value = "int64", int64(v)
+			case float64:
+				tag.Type, tag.Value = "float64", v
+			default:
+				tag.Type, tag.Value = "string", fmt.Sprint(v)
+			}
+			js.Tags = append(js.Tags, tag)
+		}
+		out = append(out, js)
+	}
+
+	return json.Marshal(struct {
+		Data []jaegerTrace `json:"data"`
+	}{[]jaegerTrace{{
+		TraceID: traceID,
+		Spans:   out,
+		Processes: map[string]jaegerProcess{
+			"p1": {ServiceName: "influxdb", Tags: []jaegerTag{}},
+		},
+	}}})
+}
+
+// jaegerID formats a trace or span ID as Jaeger does.
+func jaegerID(id uint64) string {
+	return fmt.Sprintf("%016x", id)
+}
@@ -0,0 +1,127 @@
+package tracing_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/tracing"
+	"github.com/influxdata/influxdb/pkg/tracing/fields"
+)
+
+func newTestTrace() *tracing.Trace {
+	start := time.Unix(0, 1000000)
+	t, root := tracing.NewTrace("select", tracing.StartTime(start))
+	child := root.StartSpan("expression", tracing.StartTime(start.Add(time.Millisecond)))
+	child.SetLabels("expr", "mean(value)")
+	child.MergeFields(fields.Int64("blocks_read", 3), fields.Duration("read_time", time.Second))
+	child.Finish()
+	root.Finish()
+	return t
+}
+
+func TestTrace_MarshalChromeJSON(t *testing.T) {
+	b, err := newTestTrace().Export(tracing.ChromeFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		TraceEvents []struct {
+			Name string
+			Ph   string
+			Ts   float64
+			Dur  float64
+			Args map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out.TraceEvents) != 2 {
+		t.Fatalf("unexpected events: %s", b)
+	}
+	root, child := out.TraceEvents[0], out.TraceEvents[1]
+	if root.Name != "select" || root.Ph != "X" || root.Ts != 1000 || root.Dur <= 0 {
+		t.Fatalf("unexpected root event: %+v", root)
+	} else if child.Name != "expression" || child.Ts != 2000 {
+		t.Fatalf("unexpected child event: %+v", child)
+	} else if child.Args["expr"] != "mean(value)" || child.Args["blocks_read"] != float64(3) || child.Args["read_time"] != "1s" {
+		t.Fatalf("unexpected child args: %v", child.Args)
+	}
+}
+
+func TestTrace_MarshalJaegerJSON(t *testing.T) {
+	b, err := newTestTrace().Export(tracing.JaegerFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Data []struct {
+			TraceID string
+			Spans   []struct {
+				TraceID       string
+				SpanID        string
+				OperationName string
+				StartTime     int64
+				References    []struct{ RefType, TraceID, SpanID string }
+				Tags          []struct {
+					Key, Type string
+					Value     interface{}
+				}
+			}
+			Processes map[string]struct{ ServiceName string }
+		}
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out.Data) != 1 || len(out.Data[0].Spans) != 2 {
+		t.Fatalf("unexpected trace: %s", b)
+	}
+	tr := out.Data[0]
+	root, child := tr.Spans[0], tr.Spans[1]
+	if len(tr.TraceID) != 16 || root.TraceID != tr.TraceID || child.TraceID != tr.TraceID {
+		t.Fatalf("unexpected trace IDs: %s", b)
+	} else if root.OperationName != "select" || len(root.References) != 0 || root.StartTime != 1000 {
+		t.Fatalf("unexpected root span: %+v", root)
+	} else if len(child.References) != 1 || child.References[0].RefType != "CHILD_OF" || child.References[0].SpanID != root.SpanID {
+		t.Fatalf("unexpected child references: %+v", child.References)
+	} else if len(child.Tags) != 3 || child.Tags[1].Key != "blocks_read" || child.Tags[1].Type != "int64" {
+		t.Fatalf("unexpected child tags: %+v", child.Tags)
+	} else if tr.Processes["p1"].ServiceName != "influxdb" {
+		t.Fatalf("unexpected processes: %+v", tr.Processes)
+	}
+}
+
+func TestTrace_Export_UnknownFormat(t *testing.T) {
+	if _, err := newTestTrace().Export("zipkin"); err == nil || err.Error() != `unknown trace format "zipkin"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTrace_MarshalBinary_Duration(t *testing.T) {
+	tr := newTestTrace()
+	b, err := tr.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var other tracing.Trace
+	if err := other.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	exp, got := tr.Spans(), other.Spans()
+	if len(got) != len(exp) {
+		t.Fatalf("unexpected spans: got %d, exp %d", len(got), len(exp))
+	}
+	for i := range exp {
+		if got[i].Duration != exp[i].Duration || got[i].Duration == 0 {
+			t.Fatalf("unexpected duration of %s: got %s, exp %s", exp[i].Name, got[i].Duration, exp[i].Duration)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import fmt "fmt"
 import math "math"
 import _ "github.com/gogo/protobuf/gogoproto"
 import _ "github.com/gogo/protobuf/types"
+import _ "github.com/gogo/protobuf/types"
 
 import time "time"
 
@@ -97,12 +98,13 @@ func (m *SpanContext) GetSpanID() uint64 {
 }
 
 type Span struct {
-	Context      SpanContext `protobuf:"bytes,1,opt,name=context" json:"context"`
-	ParentSpanID uint64      `protobuf:"varint,2,opt,name=parent_span_id,json=parentSpanId,proto3" json:"parent_span_id,omitempty"`
-	Name         string      `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Start        time.Time   `protobuf:"bytes,4,opt,name=start_time,json=startTime,stdtime" json:"start_time"`
-	Labels       []string    `protobuf:"bytes,5,rep,name=labels" json:"labels,omitempty"`
-	Fields       []Field     `protobuf:"bytes,6,rep,name=fields" json:"fields"`
+	Context      SpanContext   `protobuf:"bytes,1,opt,name=context" json:"context"`
+	ParentSpanID uint64        `protobuf:"varint,2,opt,name=parent_span_id,json=parentSpanId,proto3" json:"parent_span_id,omitempty"`
+	Name         string        `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Start        time.Time     `protobuf:"bytes,4,opt,name=start_time,json=startTime,stdtime" json:"start_time"`
+	Labels       []string      `protobuf:"bytes,5,rep,name=labels" json:"labels,omitempty"`
+	Fields       []Field       `protobuf:"bytes,6,rep,name=fields" json:"fields"`
+	Duration     time.Duration `protobuf:"bytes,7,opt,name=duration,stdduration" json:"duration"`
 }
 
 func (m *Span) Reset()                    { *m = Span{} }
@@ -152,6 +154,13 @@ func (m *Span) GetFields() []Field {
 	return nil
 }
 
+func (m *Span) GetDuration() time.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
 type Trace struct {
 	Spans []*Span `protobuf:"bytes,1,rep,name=spans" json:"spans,omitempty"`
 }
@@ -402,6 +411,14 @@ func (m *Span) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	dAtA[i] = 0x3a
+	i++
+	i = encodeVarintBinary(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.Duration)))
+	n3, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Duration, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n3
 	return i, nil
 }
 
@@ -462,11 +479,11 @@ func (m *Field) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintBinary(dAtA, i, uint64(m.FieldType))
 	}
 	if m.Value != nil {
-		nn3, err := m.Value.MarshalTo(dAtA[i:])
+		nn4, err := m.Value.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += nn3
+		i += nn4
 	}
 	return i, nil
 }
@@ -551,6 +568,8 @@ func (m *Span) Size() (n int) {
 			n += 1 + l + sovBinary(uint64(l))
 		}
 	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.Duration)
+	n += 1 + l + sovBinary(uint64(l))
 	return n
 }
 
@@ -894,6 +913,36 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Duration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBinary
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBinary
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.Duration, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBinary(dAtA[iNdEx:])
@@ -1249,44 +1298,47 @@ var (
 func init() { proto.RegisterFile("binary.proto", fileDescriptorBinary) }
 
 var fileDescriptorBinary = []byte{
-	// 624 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x52, 0x41, 0x6f, 0xda, 0x4c,
-	0x10, 0xc5, 0xc1, 0x98, 0x78, 0x48, 0xf8, 0xcc, 0x7e, 0x4d, 0x85, 0x5c, 0x09, 0x5b, 0x44, 0xaa,
-	0xc8, 0xa1, 0x8e, 0x92, 0x46, 0xdc, 0xe3, 0xa0, 0xb4, 0x96, 0x22, 0xa8, 0x0c, 0xe9, 0xa1, 0x17,
-	0xb4, 0xc0, 0x42, 0xad, 0x1a, 0xaf, 0x65, 0x2f, 0x69, 0xf9, 0x07, 0x15, 0xa7, 0x9c, 0x7a, 0xe3,
-	0xd4, 0x43, 0xff, 0x4a, 0x8e, 0x3d, 0xf7, 0xe0, 0x56, 0xee, 0x1f, 0xa9, 0x76, 0x0d, 0x26, 0xed,
-	0xc5, 0x9a, 0x99, 0xf7, 0xe6, 0xbd, 0x9d, 0x27, 0xc3, 0xc1, 0xc8, 0x0b, 0x70, 0xb4, 0xb4, 0xc2,
-	0x88, 0x32, 0x8a, 0xe4, 0x8f, 0x5e, 0x44, 0xf4, 0x17, 0x33, 0x8f, 0xbd, 0x5f, 0x8c, 0xac, 0x31,
-	0x9d, 0x9f, 0xce, 0xe8, 0x8c, 0x9e, 0x0a, 0x70, 0xb4, 0x98, 0x8a, 0x4e, 0x34, 0xa2, 0xca, 0x96,
-	0x74, 0x63, 0x46, 0xe9, 0xcc, 0x27, 0x3b, 0x16, 0xf3, 0xe6, 0x24, 0x66, 0x78, 0x1e, 0x66, 0x84,
-	0xe6, 0x3b, 0xa8, 0xf4, 0x43, 0x1c, 0x5c, 0xd1, 0x80, 0x91, 0x4f, 0x0c, 0x3d, 0x87, 0x7d, 0x16,
-	0xe1, 0x31, 0x19, 0x7a, 0x93, 0xba, 0x64, 0x4a, 0x2d, 0xd9, 0xae, 0xa4, 0x89, 0x51, 0x1e, 0xf0,
-	0x99, 0xd3, 0x71, 0xcb, 0x02, 0x74, 0x26, 0xe8, 0x18, 0xca, 0x71, 0x88, 0x03, 0x4e, 0xdb, 0x13,
-	0x34, 0x48, 0x13, 0x43, 0xe1, 0x4a, 0x4e, 0xc7, 0x55, 0x38, 0xe4, 0x4c, 0x9a, 0x5f, 0xf6, 0x40,
-	0xe6, 0x23, 0x74, 0x06, 0xe5, 0x71, 0x66, 0x20, 0x44, 0x2b, 0xe7, 0x35, 0x8b, 0x1f, 0x63, 0x3d,
-	0x72, 0xb6, 0xe5, 0x87, 0xc4, 0x28, 0xb8, 0x5b, 0x1e, 0x6a, 0x43, 0x35, 0xc4, 0x11, 0x09, 0xd8,
-	0xf0, 0x6f, 0x1f, 0x2d, 0x4d, 0x8c, 0x83, 0x37, 0x02, 0xd9, 0xb8, 0x1d, 0x84, 0xbb, 0x6e, 0x82,
-	0x10, 0xc8, 0x01, 0x9e, 0x93, 0x7a, 0xd1, 0x94, 0x5a, 0xaa, 0x2b, 0x6a, 0x74, 0x03, 0x10, 0x33,
-	0x1c, 0xb1, 0x21, 0x3f, 0xbe, 0x2e, 0x8b, 0x17, 0xe8, 0x56, 0x96, 0x8c, 0xb5, 0x4d, 0xc6, 0x1a,
-	0x6c, 0x93, 0xb1, 0x6b, 0xfc, 0x29, 0x69, 0x62, 0x94, 0xfa, 0x7c, 0xeb, 0xfe, 0xa7, 0x21, 0xb9,
-	0xaa, 0x10, 0xe0, 0x14, 0xf4, 0x14, 0x14, 0x1f, 0x8f, 0x88, 0x1f, 0xd7, 0x4b, 0x66, 0xb1, 0xa5,
-	0xba, 0x9b, 0x0e, 0x9d, 0x80, 0x32, 0xf5, 0x88, 0x3f, 0x89, 0xeb, 0x8a, 0x59, 0x6c, 0x55, 0xce,
-	0x2b, 0xd9, 0x8d, 0xd7, 0x7c, 0xb6, 0xb9, 0x6e, 0x43, 0x68, 0x9e, 0x40, 0x49, 0x24, 0x8a, 0x4c,
-	0x28, 0xf1, 0xf3, 0xe2, 0xba, 0x24, 0x56, 0x60, 0x17, 0x8b, 0x9b, 0x01, 0xcd, 0x6f, 0x45, 0x28,
-	0x09, 0x09, 0xa4, 0x41, 0xf1, 0x03, 0x59, 0x8a, 0x00, 0x55, 0x97, 0x97, 0xe8, 0x0a, 0x40, 0x08,
-	0x0e, 0xd9, 0x32, 0x24, 0x22, 0x9f, 0xea, 0xf9, 0xd1, 0x23, 0xd7, 0xec, 0x3b, 0x58, 0x86, 0xc4,
-	0x3e, 0x4c, 0x13, 0x43, 0xcd, 0x5b, 0x57, 0x9d, 0x6e, 0x4b, 0x74, 0x06, 0x95, 0x60, 0x31, 0x27,
-	0x91, 0x37, 0x1e, 0xde, 0x61, 0x5f, 0xe4, 0xa6, 0xd9, 0xd5, 0x34, 0x31, 0xa0, 0x9b, 0x8d, 0xdf,
-	0x62, 0xff, 0x75, 0xc1, 0x85, 0x20, 0xef, 0x90, 0xc5, 0xf3, 0x8c, 0xbc, 0x60, 0x26, 0x36, 0x78,
-	0x9e, 0x6a, 0x66, 0xd0, 0x17, 0xd3, 0x6c, 0x41, 0x8d, 0xb7, 0x4d, 0xf3, 0x87, 0x04, 0x3b, 0x6f,
-	0x64, 0x80, 0xd2, 0x1f, 0xb8, 0x4e, 0xf7, 0x95, 0x56, 0xd0, 0xff, 0x5f, 0xad, 0xcd, 0xff, 0x72,
-	0x28, 0x5b, 0x47, 0xcf, 0x40, 0xb6, 0x7b, 0xbd, 0x1b, 0x4d, 0xd2, 0x6b, 0xab, 0xb5, 0x79, 0xb8,
-	0x3b, 0x82, 0x52, 0x1f, 0x35, 0x40, 0x71, 0xba, 0x83, 0x61, 0xfb, 0x42, 0xdb, 0xd3, 0xd1, 0x6a,
-	0x6d, 0x56, 0x73, 0xd8, 0x09, 0x58, 0xfb, 0x02, 0x99, 0x50, 0xbe, 0xdd, 0x10, 0x8a, 0xff, 0xc8,
-	0xdf, 0x7a, 0x82, 0x71, 0x0c, 0xfb, 0x9d, 0x5b, 0xf7, 0x72, 0xe0, 0xf4, 0xba, 0x9a, 0xac, 0x1f,
-	0xad, 0xd6, 0x66, 0x2d, 0xa7, 0x74, 0x16, 0x11, 0x66, 0x1e, 0x0d, 0x50, 0x13, 0xf6, 0xaf, 0x6f,
-	0x7a, 0x97, 0x42, 0x47, 0xd1, 0x9f, 0xac, 0xd6, 0xa6, 0x96, 0x93, 0xae, 0x7d, 0x8a, 0x59, 0xfb,
-	0x42, 0x97, 0x3f, 0x7f, 0x6d, 0x14, 0xec, 0x32, 0x94, 0xee, 0xb0, 0xbf, 0x20, 0xb6, 0xf6, 0x90,
-	0x36, 0xa4, 0xef, 0x69, 0x43, 0xfa, 0x95, 0x36, 0xa4, 0xfb, 0xdf, 0x8d, 0xc2, 0x48, 0x11, 0xff,
-	0xd6, 0xcb, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x10, 0xad, 0x27, 0x39, 0xc8, 0x03, 0x00, 0x00,
+	// 657 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x52, 0x4d, 0x6f, 0xda, 0x5a,
+	0x10, 0xc5, 0xc1, 0x18, 0x3c, 0x24, 0x3c, 0x73, 0xdf, 0xcb, 0x13, 0x75, 0x25, 0x6c, 0x11, 0xa9,
+	0x22, 0x8b, 0x3a, 0x4a, 0x1a, 0xb1, 0xad, 0xe2, 0xa0, 0xb4, 0x96, 0x22, 0xa8, 0x0c, 0xe9, 0xa2,
+	0x1b, 0x74, 0x81, 0x1b, 0x6a, 0xd5, 0xd8, 0x96, 0xb9, 0xa4, 0xe5, 0x1f, 0x54, 0xac, 0xb2, 0xec,
+	0x86, 0x55, 0x17, 0xfd, 0x2b, 0x59, 0x76, 0x57, 0xa9, 0x0b, 0xb7, 0x72, 0xff, 0x48, 0x75, 0xaf,
+	0x3f, 0x48, 0xd3, 0x8d, 0x75, 0x67, 0xce, 0x99, 0x99, 0x33, 0x67, 0x0c, 0xbb, 0x63, 0xc7, 0xc3,
+	0xe1, 0xca, 0x08, 0x42, 0x9f, 0xfa, 0x48, 0x7c, 0xef, 0x84, 0x44, 0x7d, 0x3a, 0x73, 0xe8, 0xdb,
+	0xe5, 0xd8, 0x98, 0xf8, 0xf3, 0xa3, 0x99, 0x3f, 0xf3, 0x8f, 0x38, 0x38, 0x5e, 0x5e, 0xf3, 0x88,
+	0x07, 0xfc, 0x95, 0x14, 0xa9, 0xda, 0xcc, 0xf7, 0x67, 0x2e, 0xd9, 0xb2, 0xa8, 0x33, 0x27, 0x0b,
+	0x8a, 0xe7, 0x41, 0x4a, 0x68, 0x3e, 0x24, 0x4c, 0x97, 0x21, 0xa6, 0x8e, 0xef, 0x25, 0x78, 0xeb,
+	0x0d, 0x54, 0x07, 0x01, 0xf6, 0xce, 0x7d, 0x8f, 0x92, 0x0f, 0x14, 0x3d, 0x81, 0x0a, 0x0d, 0xf1,
+	0x84, 0x8c, 0x9c, 0x69, 0x43, 0xd0, 0x85, 0xb6, 0x68, 0x56, 0xe3, 0x48, 0x2b, 0x0f, 0x59, 0xce,
+	0xea, 0xda, 0x65, 0x0e, 0x5a, 0x53, 0x74, 0x00, 0xe5, 0x45, 0x80, 0x3d, 0x46, 0xdb, 0xe1, 0x34,
+	0x88, 0x23, 0x4d, 0x62, 0x9d, 0xac, 0xae, 0x2d, 0x31, 0xc8, 0x9a, 0xb6, 0xbe, 0xed, 0x80, 0xc8,
+	0x52, 0xe8, 0x18, 0xca, 0x93, 0x64, 0x00, 0x6f, 0x5a, 0x3d, 0xa9, 0x1b, 0x6c, 0x59, 0xe3, 0xde,
+	0x64, 0x53, 0xbc, 0x8b, 0xb4, 0x82, 0x9d, 0xf1, 0x50, 0x07, 0x6a, 0x01, 0x0e, 0x89, 0x47, 0x47,
+	0x7f, 0xce, 0x51, 0xe2, 0x48, 0xdb, 0x7d, 0xc5, 0x91, 0x74, 0xda, 0x6e, 0xb0, 0x8d, 0xa6, 0x08,
+	0x81, 0xe8, 0xe1, 0x39, 0x69, 0x14, 0x75, 0xa1, 0x2d, 0xdb, 0xfc, 0x8d, 0x2e, 0x01, 0x16, 0x14,
+	0x87, 0x74, 0xc4, 0xcc, 0x69, 0x88, 0x5c, 0x81, 0x6a, 0x24, 0xc6, 0x18, 0x99, 0x31, 0xc6, 0x30,
+	0x73, 0xce, 0xac, 0x33, 0x29, 0x71, 0xa4, 0x95, 0x06, 0xac, 0xea, 0xf6, 0x87, 0x26, 0xd8, 0x32,
+	0x6f, 0xc0, 0x28, 0xe8, 0x7f, 0x90, 0x5c, 0x3c, 0x26, 0xee, 0xa2, 0x51, 0xd2, 0x8b, 0x6d, 0xd9,
+	0x4e, 0x23, 0x74, 0x08, 0xd2, 0xb5, 0x43, 0xdc, 0xe9, 0xa2, 0x21, 0xe9, 0xc5, 0x76, 0xf5, 0xa4,
+	0x9a, 0xec, 0x78, 0xc1, 0x72, 0xe9, 0x76, 0x29, 0x01, 0x3d, 0x87, 0x4a, 0x76, 0x86, 0x46, 0x99,
+	0xcb, 0x79, 0xf4, 0x97, 0x9c, 0x6e, 0x4a, 0x30, 0x2b, 0xac, 0xf4, 0x13, 0x13, 0x91, 0x17, 0xb5,
+	0x0e, 0xa1, 0xc4, 0x4f, 0x82, 0x74, 0x28, 0x31, 0x7f, 0x16, 0x0d, 0x81, 0xcf, 0x84, 0xad, 0xaf,
+	0x76, 0x02, 0xb4, 0xbe, 0x14, 0xa1, 0xc4, 0x35, 0x20, 0x05, 0x8a, 0xef, 0xc8, 0x8a, 0x5f, 0x40,
+	0xb6, 0xd9, 0x13, 0x9d, 0x03, 0x70, 0x45, 0x23, 0xba, 0x0a, 0x08, 0x37, 0xb8, 0x76, 0xb2, 0x7f,
+	0x4f, 0x76, 0xf2, 0x1d, 0xae, 0x02, 0x62, 0xee, 0xc5, 0x91, 0x26, 0xe7, 0xa1, 0x2d, 0x5f, 0x67,
+	0x4f, 0x74, 0x0c, 0x55, 0x6f, 0x39, 0x27, 0xa1, 0x33, 0x19, 0xdd, 0x60, 0x97, 0x1b, 0xaf, 0x98,
+	0xb5, 0x38, 0xd2, 0xa0, 0x97, 0xa4, 0x5f, 0x63, 0xf7, 0x65, 0xc1, 0x06, 0x2f, 0x8f, 0x90, 0xc1,
+	0x0e, 0x12, 0x3a, 0xde, 0x8c, 0x57, 0xb0, 0x83, 0xc8, 0xc9, 0x80, 0x01, 0xcf, 0x26, 0x05, 0xf2,
+	0x22, 0x0b, 0x5a, 0xdf, 0x05, 0xd8, 0xce, 0x46, 0x1a, 0x48, 0x83, 0xa1, 0x6d, 0xf5, 0x5e, 0x28,
+	0x05, 0xf5, 0xdf, 0xf5, 0x46, 0xff, 0x27, 0x87, 0x92, 0x72, 0xf4, 0x18, 0x44, 0xb3, 0xdf, 0xbf,
+	0x54, 0x04, 0xb5, 0xbe, 0xde, 0xe8, 0x7b, 0xdb, 0x25, 0x7c, 0xdf, 0x45, 0x4d, 0x90, 0xac, 0xde,
+	0x70, 0xd4, 0x39, 0x55, 0x76, 0x54, 0xb4, 0xde, 0xe8, 0xb5, 0x1c, 0xb6, 0x3c, 0xda, 0x39, 0x45,
+	0x3a, 0x94, 0xaf, 0x52, 0x42, 0xf1, 0x41, 0xfb, 0x2b, 0x87, 0x33, 0x0e, 0xa0, 0xd2, 0xbd, 0xb2,
+	0xcf, 0x86, 0x56, 0xbf, 0xa7, 0x88, 0xea, 0xfe, 0x7a, 0xa3, 0xd7, 0x73, 0x4a, 0x76, 0x35, 0xd4,
+	0x82, 0xca, 0xc5, 0x65, 0xff, 0x8c, 0xf7, 0x91, 0xd4, 0xff, 0xd6, 0x1b, 0x5d, 0xc9, 0x49, 0x17,
+	0xae, 0x8f, 0x69, 0xe7, 0x54, 0x15, 0x3f, 0x7e, 0x6e, 0x16, 0xcc, 0x32, 0x94, 0x6e, 0xb0, 0xbb,
+	0x24, 0xa6, 0x72, 0x17, 0x37, 0x85, 0xaf, 0x71, 0x53, 0xf8, 0x19, 0x37, 0x85, 0xdb, 0x5f, 0xcd,
+	0xc2, 0x58, 0xe2, 0x7f, 0xc3, 0xb3, 0xdf, 0x03, 0x00, 0x2b, 0xb7, 0x69, 0x20, 0x29, 0x04, 0x00,
+	0x00,
 }
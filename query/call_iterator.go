@@ -1273,6 +1273,90 @@ func newCumulativeSumIterator(input Iterator, opt IteratorOptions) (Iterator, er
 	}
 }
 
+// newTimeShiftIterator returns an iterator for operating on a time_shift() call.
+func newTimeShiftIterator(input Iterator, shift int64) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		return &floatTransformIterator{input: input, fn: func(p *FloatPoint) *FloatPoint {
+			p.Time = shiftTime(p.Time, shift)
+			return p
+		}}, nil
+	case IntegerIterator:
+		return &integerTransformIterator{input: input, fn: func(p *IntegerPoint) *IntegerPoint {
+			p.Time = shiftTime(p.Time, shift)
+			return p
+		}}, nil
+	case UnsignedIterator:
+		return &unsignedTransformIterator{input: input, fn: func(p *UnsignedPoint) *UnsignedPoint {
+			p.Time = shiftTime(p.Time, shift)
+			return p
+		}}, nil
+	case StringIterator:
+		return &stringTransformIterator{input: input, fn: func(p *StringPoint) *StringPoint {
+			p.Time = shiftTime(p.Time, shift)
+			return p
+		}}, nil
+	case BooleanIterator:
+		return &booleanTransformIterator{input: input, fn: func(p *BooleanPoint) *BooleanPoint {
+			p.Time = shiftTime(p.Time, shift)
+			return p
+		}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported time shift iterator type: %T", input)
+	}
+}
+
+// shiftTime moves t forward by shift. The time of points without a time is
+// not moved.
+func shiftTime(t, shift int64) int64 {
+	if t == influxql.MinTime {
+		return t
+	}
+	return t + shift
+}
+
+// newCorrelationIterator returns an iterator for operating on a corr() or a
+// covar() call. The input reads the two fields as auxiliary fields.
+func newCorrelationIterator(input Iterator, opt IteratorOptions, covariance bool) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, FloatPointEmitter) {
+			fn := NewFloatCorrelationReducer(covariance)
+			return fn, fn
+		}
+		return newFloatReduceFloatIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported correlation iterator type: %T", input)
+	}
+}
+
+// newLinearRegressionIterator returns an iterator for operating on a
+// linreg_slope(), linreg_intercept() or linreg_r2() call.
+func newLinearRegressionIterator(input Iterator, opt IteratorOptions, name string, interval Interval) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, FloatPointEmitter) {
+			fn := NewFloatLinearRegressionReducer(name, interval, opt)
+			return fn, fn
+		}
+		return newFloatReduceFloatIterator(input, opt, createFn), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, FloatPointEmitter) {
+			fn := NewFloatLinearRegressionReducer(name, interval, opt)
+			return fn, fn
+		}
+		return newIntegerReduceFloatIterator(input, opt, createFn), nil
+	case UnsignedIterator:
+		createFn := func() (UnsignedPointAggregator, FloatPointEmitter) {
+			fn := NewFloatLinearRegressionReducer(name, interval, opt)
+			return fn, fn
+		}
+		return newUnsignedReduceFloatIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported linear regression iterator type: %T", input)
+	}
+}
+
 // newHoltWintersIterator returns an iterator for operating on a holt_winters() call.
 func newHoltWintersIterator(input Iterator, opt IteratorOptions, h, m int, includeFitData bool, interval time.Duration) (Iterator, error) {
 	switch input := input.(type) {
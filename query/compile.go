@@ -73,6 +73,15 @@ type compiledStatement struct {
 	// Limit is the number of rows per series this query should be limited to.
 	Limit int
 
+	// TimeShift is the largest duration a time_shift() call in this statement
+	// or its subqueries shifts the data by. Shards for the shifted time range
+	// must be mapped in addition to the shards of the time range.
+	TimeShift time.Duration
+
+	// fieldTimeShift is the time shift of the fields of this statement,
+	// without the time shifts of its subqueries.
+	fieldTimeShift time.Duration
+
 	// HasTarget is true if this query is being written into a target.
 	HasTarget bool
 
@@ -164,6 +173,8 @@ func (c *compiledStatement) compile(stmt *influxql.SelectStatement) error {
 		return err
 	}
 
+	c.fieldTimeShift = c.TimeShift
+
 	// Look through the sources and compile each of the subqueries (if they exist).
 	// We do this after compiling the outside because subqueries may require
 	// inherited state.
@@ -270,6 +281,12 @@ func (c *compiledField) compileExpr(expr influxql.Expr) error {
 		case "holt_winters", "holt_winters_with_fit":
 			withFit := expr.Name == "holt_winters_with_fit"
 			return c.compileHoltWinters(expr.Args, withFit)
+		case "time_shift":
+			return c.compileTimeShift(expr.Args)
+		case "corr", "covar":
+			return c.compileCorrelation(expr)
+		case "linreg_slope", "linreg_intercept", "linreg_r2":
+			return c.compileLinearRegression(expr)
 		default:
 			return c.compileFunction(expr)
 		}
@@ -591,6 +608,76 @@ func (c *compiledField) compileHoltWinters(args []influxql.Expr, withFit bool) e
 	return c.compileExpr(call)
 }
 
+func (c *compiledField) compileCorrelation(expr *influxql.Call) error {
+	if exp, got := 2, len(expr.Args); got != exp {
+		return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+	}
+
+	for _, arg := range expr.Args {
+		if _, ok := arg.(*influxql.VarRef); !ok {
+			return fmt.Errorf("expected field arguments in %s()", expr.Name)
+		}
+	}
+	c.global.OnlySelectors = false
+	return nil
+}
+
+func (c *compiledField) compileLinearRegression(expr *influxql.Call) error {
+	max := 1
+	if expr.Name == "linreg_slope" {
+		max = 2
+	}
+	if min, got := 1, len(expr.Args); got > max || got < min {
+		if min == max {
+			return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, min, got)
+		}
+		return fmt.Errorf("invalid number of arguments for %s, expected at least %d but no more than %d, got %d", expr.Name, min, max, got)
+	}
+
+	// Retrieve the duration of the slope, if specified.
+	if len(expr.Args) == 2 {
+		switch arg1 := expr.Args[1].(type) {
+		case *influxql.DurationLiteral:
+			if arg1.Val <= 0 {
+				return fmt.Errorf("duration argument must be positive, got %s", influxql.FormatDuration(arg1.Val))
+			}
+		default:
+			return fmt.Errorf("second argument to %s must be a duration, got %T", expr.Name, expr.Args[1])
+		}
+	}
+	c.global.OnlySelectors = false
+
+	// Must be a variable reference, wildcard, or regexp.
+	return c.compileSymbol(expr.Name, expr.Args[0])
+}
+
+// compileTimeShift compiles a time_shift() call. Only aggregate and selector
+// calls can be shifted: raw fields, including the fields of a subquery, are
+// rejected.
+func (c *compiledField) compileTimeShift(args []influxql.Expr) error {
+	if exp, got := 2, len(args); got != exp {
+		return fmt.Errorf("invalid number of arguments for time_shift, expected %d, got %d", exp, got)
+	}
+
+	d, ok := args[1].(*influxql.DurationLiteral)
+	if !ok {
+		return fmt.Errorf("second argument to time_shift must be a duration, got %T", args[1])
+	} else if d.Val <= 0 {
+		return fmt.Errorf("duration argument must be positive, got %s", influxql.FormatDuration(d.Val))
+	}
+	if d.Val > c.global.TimeShift {
+		c.global.TimeShift = d.Val
+	}
+
+	call, ok := args[0].(*influxql.Call)
+	if !ok {
+		return errors.New("must use aggregate function with time_shift")
+	} else if call.Name == "time_shift" {
+		return errors.New("time_shift calls can not be nested")
+	}
+	return c.compileExpr(call)
+}
+
 func (c *compiledField) compileDistinct(args []influxql.Expr) error {
 	if len(args) == 0 {
 		return errors.New("distinct function requires at least one argument")
@@ -784,7 +871,16 @@ func (c *compiledStatement) subquery(stmt *influxql.SelectStatement) error {
 		subquery.Interval = c.Interval
 		subquery.InheritedInterval = true
 	}
-	return subquery.compile(stmt)
+	if err := subquery.compile(stmt); err != nil {
+		return err
+	}
+
+	// The subquery is read over the time range shifted by the fields of the
+	// parent, so its time shift adds to the time shift of the fields.
+	if shift := c.fieldTimeShift + subquery.TimeShift; shift > c.TimeShift {
+		c.TimeShift = shift
+	}
+	return nil
 }
 
 func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions) (PreparedStatement, error) {
@@ -824,6 +920,11 @@ func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions)
 		}
 	}
 
+	// Map the shards of the data read by time_shift() calls.
+	if c.TimeShift > 0 && timeRange.MinTime() > influxql.MinTime+int64(c.TimeShift) {
+		timeRange.Min = timeRange.Min.Add(-c.TimeShift)
+	}
+
 	// Create an iterator creator based on the shards in the cluster.
 	shards, err := shardMapper.MapShards(c.stmt.Sources, timeRange, sopt)
 	if err != nil {
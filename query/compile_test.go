@@ -80,6 +80,15 @@ func TestCompile_Success(t *testing.T) {
 		`SELECT max(value) FROM (SELECT value + total FROM cpu) WHERE time >= now() - 1m GROUP BY time(10s)`,
 		`SELECT value FROM cpu WHERE time >= '2000-01-01T00:00:00Z' AND time <= '2000-01-01T01:00:00Z'`,
 		`SELECT value FROM (SELECT value FROM cpu) ORDER BY time DESC`,
+		`SELECT corr(value, total) FROM cpu`,
+		`SELECT covar(value, total) FROM cpu WHERE time >= now() - 1h GROUP BY time(10m), host`,
+		`SELECT linreg_slope(value) FROM cpu`,
+		`SELECT linreg_slope(value, 1h), linreg_intercept(value), linreg_r2(value) FROM cpu WHERE time >= now() - 1d GROUP BY time(1h)`,
+		`SELECT linreg_slope(*) FROM cpu`,
+		`SELECT time_shift(mean(value), 7d) FROM cpu WHERE time >= now() - 1h GROUP BY time(10m)`,
+		`SELECT mean(value) - time_shift(mean(value), 1d) FROM cpu WHERE time >= now() - 1h GROUP BY time(10m), host`,
+		`SELECT time_shift(max(value), 1h) FROM cpu`,
+		`SELECT max(mean) FROM (SELECT time_shift(mean(value), 1d) FROM cpu) WHERE time >= now() - 1h GROUP BY time(10m)`,
 	} {
 		t.Run(tt, func(t *testing.T) {
 			stmt, err := influxql.ParseStatement(tt)
@@ -276,6 +285,20 @@ func TestCompile_Failures(t *testing.T) {
 		{s: `SELECT moving_average(max(), 2) FROM myseries where time < now() and time > now() - 1d group by time(1h)`, err: `invalid number of arguments for max, expected 1, got 0`},
 		{s: `SELECT moving_average(percentile(value), 2) FROM myseries where time < now() and time > now() - 1d group by time(1h)`, err: `invalid number of arguments for percentile, expected 2, got 1`},
 		{s: `SELECT moving_average(mean(value), 2) FROM myseries where time < now() and time > now() - 1d`, err: `moving_average aggregate requires a GROUP BY interval`},
+		{s: `SELECT corr(value) FROM cpu`, err: `invalid number of arguments for corr, expected 2, got 1`},
+		{s: `SELECT covar(value, 1) FROM cpu`, err: `expected field arguments in covar()`},
+		{s: `SELECT corr(value, total), value FROM cpu`, err: `mixing aggregate and non-aggregate queries is not supported`},
+		{s: `SELECT linreg_slope() FROM cpu`, err: `invalid number of arguments for linreg_slope, expected at least 1 but no more than 2, got 0`},
+		{s: `SELECT linreg_slope(value, 1) FROM cpu`, err: `second argument to linreg_slope must be a duration, got *influxql.IntegerLiteral`},
+		{s: `SELECT linreg_slope(value, 0s) FROM cpu`, err: `duration argument must be positive, got 0s`},
+		{s: `SELECT linreg_intercept(value, 1h) FROM cpu`, err: `invalid number of arguments for linreg_intercept, expected 1, got 2`},
+		{s: `SELECT linreg_r2(1) FROM cpu`, err: `expected field argument in linreg_r2()`},
+		{s: `SELECT time_shift(mean(value)) FROM cpu`, err: `invalid number of arguments for time_shift, expected 2, got 1`},
+		{s: `SELECT time_shift(mean(value), 1) FROM cpu`, err: `second argument to time_shift must be a duration, got *influxql.IntegerLiteral`},
+		{s: `SELECT time_shift(mean(value), -1d) FROM cpu`, err: `duration argument must be positive, got -1d`},
+		{s: `SELECT time_shift(value, 1d) FROM cpu`, err: `must use aggregate function with time_shift`},
+		{s: `SELECT time_shift(mean, 1d) FROM (SELECT mean(value) FROM cpu GROUP BY time(1h))`, err: `must use aggregate function with time_shift`},
+		{s: `SELECT time_shift(time_shift(mean(value), 1d), 1d) FROM cpu`, err: `time_shift calls can not be nested`},
 		{s: `SELECT cumulative_sum(field1), field1 FROM myseries`, err: `mixing aggregate and non-aggregate queries is not supported`},
 		{s: `SELECT cumulative_sum() from myseries`, err: `invalid number of arguments for cumulative_sum, expected 1, got 0`},
 		{s: `SELECT cumulative_sum(value) FROM myseries group by time(1h)`, err: `aggregate function required inside the call to cumulative_sum`},
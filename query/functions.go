@@ -1315,6 +1315,165 @@ func (r *UnsignedIntegralReducer) Close() error {
 	return nil
 }
 
+// linearStats accumulates the means, variances and covariance of pairs of
+// values in a single pass.
+type linearStats struct {
+	n            float64
+	meanX, meanY float64
+	sxx, syy     float64
+	sxy          float64
+}
+
+// add adds the pair x, y to the statistics.
+func (s *linearStats) add(x, y float64) {
+	s.n++
+	dx := x - s.meanX
+	s.meanX += dx / s.n
+	dy := y - s.meanY
+	s.meanY += dy / s.n
+	s.sxx += dx * (x - s.meanX)
+	s.syy += dy * (y - s.meanY)
+	s.sxy += dx * (y - s.meanY)
+}
+
+// FloatCorrelationReducer calculates the correlation or the covariance of
+// two fields of the aggregated points. The values of the fields are read from
+// the first two auxiliary fields of the points.
+type FloatCorrelationReducer struct {
+	covariance bool
+	stats      linearStats
+}
+
+// NewFloatCorrelationReducer creates a new FloatCorrelationReducer. It
+// calculates the sample covariance if covariance is true and the Pearson
+// correlation coefficient otherwise.
+func NewFloatCorrelationReducer(covariance bool) *FloatCorrelationReducer {
+	return &FloatCorrelationReducer{covariance: covariance}
+}
+
+// AggregateFloat aggregates a point into the reducer. Points without a value
+// for one of the fields are skipped.
+func (r *FloatCorrelationReducer) AggregateFloat(p *FloatPoint) {
+	if len(p.Aux) < 2 {
+		return
+	}
+	x, ok := auxFloat(p.Aux[0])
+	if !ok {
+		return
+	}
+	y, ok := auxFloat(p.Aux[1])
+	if !ok {
+		return
+	}
+	r.stats.add(x, y)
+}
+
+// Emit emits the correlation or the covariance of the aggregated points as a
+// single point. The value is null if there are less than two points, or if
+// one of the fields has the same value in all of the points for a
+// correlation.
+func (r *FloatCorrelationReducer) Emit() []FloatPoint {
+	s := &r.stats
+	if s.n < 2 {
+		return []FloatPoint{{Time: ZeroTime, Nil: true}}
+	} else if r.covariance {
+		return []FloatPoint{{Time: ZeroTime, Value: s.sxy / (s.n - 1)}}
+	} else if s.sxx == 0 || s.syy == 0 {
+		return []FloatPoint{{Time: ZeroTime, Nil: true}}
+	}
+	return []FloatPoint{{Time: ZeroTime, Value: s.sxy / math.Sqrt(s.sxx*s.syy)}}
+}
+
+// auxFloat returns a numeric auxiliary value as a float.
+func auxFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, !math.IsNaN(v)
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// FloatLinearRegressionReducer calculates the least squares regression line
+// of the values of the aggregated points over their time. Emit returns the
+// slope, the intercept or the coefficient of determination of the line
+// depending on the name of the function.
+type FloatLinearRegressionReducer struct {
+	name     string
+	interval Interval
+	start    int64
+	stats    linearStats
+	opt      IteratorOptions
+}
+
+// NewFloatLinearRegressionReducer creates a new FloatLinearRegressionReducer
+// for the linreg_slope, linreg_intercept or linreg_r2 function. The slope is
+// the change of the value per interval.
+func NewFloatLinearRegressionReducer(name string, interval Interval, opt IteratorOptions) *FloatLinearRegressionReducer {
+	return &FloatLinearRegressionReducer{name: name, interval: interval, opt: opt}
+}
+
+func (r *FloatLinearRegressionReducer) aggregate(t int64, value float64) {
+	if math.IsNaN(value) {
+		return
+	} else if r.stats.n == 0 {
+		r.start = t
+	}
+	// Use the time from the first point to keep the precision of the times.
+	r.stats.add(float64(t-r.start)/float64(r.interval.Duration), value)
+}
+
+// AggregateFloat aggregates a point into the reducer.
+func (r *FloatLinearRegressionReducer) AggregateFloat(p *FloatPoint) {
+	r.aggregate(p.Time, p.Value)
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *FloatLinearRegressionReducer) AggregateInteger(p *IntegerPoint) {
+	r.aggregate(p.Time, float64(p.Value))
+}
+
+// AggregateUnsigned aggregates a point into the reducer.
+func (r *FloatLinearRegressionReducer) AggregateUnsigned(p *UnsignedPoint) {
+	r.aggregate(p.Time, float64(p.Value))
+}
+
+// Emit emits the slope, the intercept or the coefficient of determination of
+// the regression line as a single point. The intercept is the value of the
+// line at the start of the window of the points, which is the time of the
+// emitted point: the start of the GROUP BY time() interval, or of the time
+// range of the query. It is the value at the time of the first point if the
+// time range has no start. The value is null if there are less than two
+// distinct times.
+func (r *FloatLinearRegressionReducer) Emit() []FloatPoint {
+	s := &r.stats
+	if s.n < 2 || s.sxx == 0 {
+		return []FloatPoint{{Time: ZeroTime, Nil: true}}
+	}
+
+	value := s.sxy / s.sxx
+	switch r.name {
+	case "linreg_intercept":
+		origin, _ := r.opt.Window(r.start)
+		if origin == influxql.MinTime {
+			origin = r.start
+		}
+		value = s.meanY + value*(float64(origin-r.start)/float64(r.interval.Duration)-s.meanX)
+	case "linreg_r2":
+		if s.syy == 0 {
+			// All of the values are on the line.
+			value = 1
+		} else {
+			value = s.sxy * s.sxy / (s.sxx * s.syy)
+		}
+	}
+	return []FloatPoint{{Time: ZeroTime, Value: value}}
+}
+
 type FloatTopReducer struct {
 	h *floatPointsByFunc
 }
@@ -393,6 +393,96 @@ func TestHoltWinters_MaxTime(t *testing.T) {
 //
 // The idea here is that 30 iterations should be enough to hit every possible
 // sequence at least once.
+func TestCorrelation(t *testing.T) {
+	points := []query.FloatPoint{
+		{Time: 1, Aux: []interface{}{float64(1), int64(2)}},
+		{Time: 2, Aux: []interface{}{float64(2), int64(4)}},
+		{Time: 3, Aux: []interface{}{float64(3), nil}},
+		{Time: 4, Aux: []interface{}{float64(3), int64(5)}},
+		{Time: 5, Aux: []interface{}{float64(4), int64(4)}},
+		{Time: 6, Aux: []interface{}{float64(5), int64(5)}},
+	}
+
+	for _, tt := range []struct {
+		covariance bool
+		exp        float64
+	}{
+		{covariance: false, exp: 6 / math.Sqrt(60)},
+		{covariance: true, exp: 1.5},
+	} {
+		r := query.NewFloatCorrelationReducer(tt.covariance)
+		for i := range points {
+			r.AggregateFloat(&points[i])
+		}
+		if p := r.Emit(); len(p) != 1 || p[0].Nil || !almostEqual(p[0].Value, tt.exp) {
+			t.Errorf("covariance=%v: unexpected points: %v", tt.covariance, p)
+		}
+	}
+
+	// Correlation is undefined if a field does not change.
+	r := query.NewFloatCorrelationReducer(false)
+	r.AggregateFloat(&query.FloatPoint{Aux: []interface{}{float64(1), float64(2)}})
+	r.AggregateFloat(&query.FloatPoint{Aux: []interface{}{float64(2), float64(2)}})
+	if p := r.Emit(); len(p) != 1 || !p[0].Nil {
+		t.Errorf("unexpected points: %v", p)
+	}
+}
+
+func TestLinearRegression(t *testing.T) {
+	points := []query.IntegerPoint{
+		{Time: int64(1 * time.Second), Value: 2},
+		{Time: int64(2 * time.Second), Value: 4},
+		{Time: int64(3 * time.Second), Value: 5},
+		{Time: int64(4 * time.Second), Value: 4},
+		{Time: int64(5 * time.Second), Value: 5},
+	}
+
+	// The time range of the query starts at the epoch.
+	opt := query.IteratorOptions{StartTime: 0, EndTime: influxql.MaxTime}
+	for _, tt := range []struct {
+		name     string
+		interval time.Duration
+		opt      query.IteratorOptions
+		exp      float64
+	}{
+		{name: "linreg_slope", interval: time.Second, opt: opt, exp: 0.6},
+		{name: "linreg_slope", interval: time.Minute, opt: opt, exp: 36},
+		{name: "linreg_intercept", interval: time.Second, opt: opt, exp: 2.2},
+		{name: "linreg_intercept", interval: time.Minute, opt: opt, exp: 2.2},
+		{name: "linreg_r2", interval: time.Second, opt: opt, exp: 0.6},
+
+		// The intercept is at the start of the GROUP BY time() interval.
+		{
+			name:     "linreg_intercept",
+			interval: time.Second,
+			opt: query.IteratorOptions{
+				Interval:  query.Interval{Duration: 10 * time.Second, Offset: 500 * time.Millisecond},
+				StartTime: influxql.MinTime,
+				EndTime:   influxql.MaxTime,
+			},
+			exp: 2.5,
+		},
+
+		// The intercept is at the first point without a start time.
+		{name: "linreg_intercept", interval: time.Second, opt: query.IteratorOptions{StartTime: influxql.MinTime, EndTime: influxql.MaxTime}, exp: 2.8},
+	} {
+		r := query.NewFloatLinearRegressionReducer(tt.name, query.Interval{Duration: tt.interval}, tt.opt)
+		for i := range points {
+			r.AggregateInteger(&points[i])
+		}
+		if p := r.Emit(); len(p) != 1 || p[0].Nil || !almostEqual(p[0].Value, tt.exp) {
+			t.Errorf("%s(%s): unexpected points: %v", tt.name, tt.interval, p)
+		}
+	}
+
+	// A single point has no regression line.
+	r := query.NewFloatLinearRegressionReducer("linreg_slope", query.Interval{Duration: time.Second}, opt)
+	r.AggregateFloat(&query.FloatPoint{Time: 1, Value: 1})
+	if p := r.Emit(); len(p) != 1 || !p[0].Nil {
+		t.Errorf("unexpected points: %v", p)
+	}
+}
+
 func TestSample_AllSamplesSeen(t *testing.T) {
 	ps := []query.FloatPoint{
 		{Time: 1, Value: 1},
@@ -993,6 +993,17 @@ func (opt IteratorOptions) IntegralInterval() Interval {
 	return Interval{Duration: time.Second}
 }
 
+// RegressionInterval returns the time interval of the slope of the
+// linreg_slope function.
+func (opt IteratorOptions) RegressionInterval() Interval {
+	// Use the interval on the linreg_slope() call, if specified.
+	if expr, ok := opt.Expr.(*influxql.Call); ok && len(expr.Args) == 2 {
+		return Interval{Duration: expr.Args[1].(*influxql.DurationLiteral).Val}
+	}
+
+	return Interval{Duration: time.Second}
+}
+
 // GetDimensions retrieves the dimensions for this query.
 func (opt IteratorOptions) GetDimensions() []string {
 	if len(opt.GroupBy) > 0 {
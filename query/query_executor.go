@@ -125,6 +125,10 @@ type ExecutionOptions struct {
 
 	// AbortCh is a channel that signals when results are no longer desired by the caller.
 	AbortCh <-chan struct{}
+
+	// TraceFormat, if set, captures a trace of each SELECT statement and
+	// returns it, exported in this format, with a result of the statement.
+	TraceFormat string
 }
 
 // ExecutionContext contains state that the query is currently executing with.
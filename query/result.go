@@ -91,17 +91,22 @@ type Result struct {
 	Messages    []*Message
 	Partial     bool
 	Err         error
+
+	// Trace holds the exported trace of the statement when tracing was
+	// requested with the TraceFormat execution option.
+	Trace json.RawMessage
 }
 
 // MarshalJSON encodes the result into JSON.
 func (r *Result) MarshalJSON() ([]byte, error) {
 	// Define a struct that outputs "error" as a string.
 	var o struct {
-		StatementID int           `json:"statement_id"`
-		Series      []*models.Row `json:"series,omitempty"`
-		Messages    []*Message    `json:"messages,omitempty"`
-		Partial     bool          `json:"partial,omitempty"`
-		Err         string        `json:"error,omitempty"`
+		StatementID int             `json:"statement_id"`
+		Series      []*models.Row   `json:"series,omitempty"`
+		Messages    []*Message      `json:"messages,omitempty"`
+		Partial     bool            `json:"partial,omitempty"`
+		Err         string          `json:"error,omitempty"`
+		Trace       json.RawMessage `json:"trace,omitempty"`
 	}
 
 	// Copy fields to output struct.
@@ -112,6 +117,7 @@ func (r *Result) MarshalJSON() ([]byte, error) {
 	if r.Err != nil {
 		o.Err = r.Err.Error()
 	}
+	o.Trace = r.Trace
 
 	return json.Marshal(&o)
 }
@@ -119,11 +125,12 @@ func (r *Result) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON decodes the data into the Result struct
 func (r *Result) UnmarshalJSON(b []byte) error {
 	var o struct {
-		StatementID int           `json:"statement_id"`
-		Series      []*models.Row `json:"series,omitempty"`
-		Messages    []*Message    `json:"messages,omitempty"`
-		Partial     bool          `json:"partial,omitempty"`
-		Err         string        `json:"error,omitempty"`
+		StatementID int             `json:"statement_id"`
+		Series      []*models.Row   `json:"series,omitempty"`
+		Messages    []*Message      `json:"messages,omitempty"`
+		Partial     bool            `json:"partial,omitempty"`
+		Err         string          `json:"error,omitempty"`
+		Trace       json.RawMessage `json:"trace,omitempty"`
 	}
 
 	err := json.Unmarshal(b, &o)
@@ -134,6 +141,7 @@ func (r *Result) UnmarshalJSON(b []byte) error {
 	r.Series = o.Series
 	r.Messages = o.Messages
 	r.Partial = o.Partial
+	r.Trace = o.Trace
 	if o.Err != "" {
 		r.Err = errors.New(o.Err)
 	}
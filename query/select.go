@@ -7,6 +7,7 @@ import (
 	"io"
 	"math"
 	"sort"
+	"time"
 
 	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/pkg/tracing"
@@ -191,32 +192,8 @@ func buildIterators(ctx context.Context, stmt *influxql.SelectStatement, ic Iter
 // buildAuxIterators creates a set of iterators from a single combined auxiliary iterator.
 func buildAuxIterators(ctx context.Context, fields influxql.Fields, ic IteratorCreator, sources influxql.Sources, opt IteratorOptions) ([]Iterator, error) {
 	// Create the auxiliary iterators for each source.
-	inputs := make([]Iterator, 0, len(sources))
-	if err := func() error {
-		for _, source := range sources {
-			switch source := source.(type) {
-			case *influxql.Measurement:
-				input, err := ic.CreateIterator(ctx, source, opt)
-				if err != nil {
-					return err
-				}
-				inputs = append(inputs, input)
-			case *influxql.SubQuery:
-				b := subqueryBuilder{
-					ic:   ic,
-					stmt: source.Statement,
-				}
-
-				input, err := b.buildAuxIterator(ctx, opt)
-				if err != nil {
-					return err
-				}
-				inputs = append(inputs, input)
-			}
-		}
-		return nil
-	}(); err != nil {
-		Iterators(inputs).Close()
+	inputs, err := buildSourceAuxIterators(ctx, ic, sources, opt)
+	if err != nil {
 		return nil, err
 	}
 
@@ -276,6 +253,40 @@ func buildAuxIterators(ctx context.Context, fields influxql.Fields, ic IteratorC
 	return itrs, nil
 }
 
+// buildSourceAuxIterators creates an iterator of the auxiliary fields of opt
+// for each source.
+func buildSourceAuxIterators(ctx context.Context, ic IteratorCreator, sources influxql.Sources, opt IteratorOptions) ([]Iterator, error) {
+	inputs := make([]Iterator, 0, len(sources))
+	if err := func() error {
+		for _, source := range sources {
+			switch source := source.(type) {
+			case *influxql.Measurement:
+				input, err := ic.CreateIterator(ctx, source, opt)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input)
+			case *influxql.SubQuery:
+				b := subqueryBuilder{
+					ic:   ic,
+					stmt: source.Statement,
+				}
+
+				input, err := b.buildAuxIterator(ctx, opt)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input)
+			}
+		}
+		return nil
+	}(); err != nil {
+		Iterators(inputs).Close()
+		return nil, err
+	}
+	return inputs, nil
+}
+
 // buildAuxIterator constructs an Iterator for an expression from an AuxIterator.
 func buildAuxIterator(expr influxql.Expr, aitr AuxIterator, opt IteratorOptions) (Iterator, error) {
 	switch expr := expr.(type) {
@@ -566,6 +577,30 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 			return newMovingAverageIterator(input, int(n.Val), opt)
 		}
 		panic(fmt.Sprintf("invalid series aggregate function: %s", expr.Name))
+	case "time_shift":
+		// Read the data of the shifted time range and move the points forward
+		// so they line up with the points of the unshifted time range.
+		shift := int64(expr.Args[1].(*influxql.DurationLiteral).Val)
+		if opt.StartTime > influxql.MinTime+shift {
+			opt.StartTime -= shift
+		} else {
+			opt.StartTime = influxql.MinTime
+		}
+		if opt.EndTime != influxql.MaxTime {
+			opt.EndTime -= shift
+		}
+		if !opt.Interval.IsZero() {
+			opt.Interval.Offset = (opt.Interval.Offset - time.Duration(shift)) % opt.Interval.Duration
+			if opt.Interval.Offset < 0 {
+				opt.Interval.Offset += opt.Interval.Duration
+			}
+		}
+
+		input, err := buildExprIterator(ctx, expr.Args[0], b.ic, b.sources, opt, b.selector, false)
+		if err != nil {
+			return nil, err
+		}
+		return newTimeShiftIterator(input, shift)
 	case "cumulative_sum":
 		opt.Ordered = true
 		input, err := buildExprIterator(ctx, expr.Args[0], b.ic, b.sources, opt, b.selector, false)
@@ -745,6 +780,19 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 				percentile = float64(arg.Val)
 			}
 			return newPercentileIterator(input, opt, percentile)
+		case "corr", "covar":
+			input, err := b.buildPairIterator(ctx, expr.Args[0].(*influxql.VarRef), expr.Args[1].(*influxql.VarRef), opt)
+			if err != nil {
+				return nil, err
+			}
+			return newCorrelationIterator(input, opt, expr.Name == "covar")
+		case "linreg_slope", "linreg_intercept", "linreg_r2":
+			input, err := buildExprIterator(ctx, expr.Args[0].(*influxql.VarRef), b.ic, b.sources, opt, false, false)
+			if err != nil {
+				return nil, err
+			}
+			interval := opt.RegressionInterval()
+			return newLinearRegressionIterator(input, opt, expr.Name, interval)
 		default:
 			return nil, fmt.Errorf("unsupported call: %s", expr.Name)
 		}
@@ -766,6 +814,28 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 	return itr, nil
 }
 
+// buildPairIterator creates an iterator that reads the fields x and y as the
+// auxiliary fields of its points.
+func (b *exprIteratorBuilder) buildPairIterator(ctx context.Context, x, y *influxql.VarRef, opt IteratorOptions) (Iterator, error) {
+	opt.Expr = nil
+	opt.Aux = []influxql.VarRef{*x, *y}
+
+	inputs, err := buildSourceAuxIterators(ctx, b.ic, b.sources, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	itr := NewMergeIterator(inputs, opt)
+	if itr == nil {
+		itr = &nilFloatIterator{}
+	}
+
+	if opt.InterruptCh != nil {
+		itr = NewInterruptIterator(itr, opt.InterruptCh)
+	}
+	return itr, nil
+}
+
 func (b *exprIteratorBuilder) buildBinaryExprIterator(ctx context.Context, expr *influxql.BinaryExpr) (Iterator, error) {
 	if rhs, ok := expr.RHS.(influxql.Literal); ok {
 		// The right hand side is a literal. It is more common to have the RHS be a literal,
@@ -3932,6 +3932,93 @@ func TestSelect_BinaryExpr_NilValues(t *testing.T) {
 	}
 }
 
+// Ensure a SELECT with time_shift() reads the shifted time range and returns
+// points aligned with the points of the unshifted time range.
+func TestSelect_TimeShift(t *testing.T) {
+	var timeRange influxql.TimeRange
+	shardMapper := ShardMapper{
+		MapShardsFn: func(sources influxql.Sources, tr influxql.TimeRange) query.ShardGroup {
+			timeRange = tr
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"value": influxql.Float,
+				},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					if m.Name != "cpu" {
+						t.Fatalf("unexpected source: %s", m.Name)
+					}
+
+					var points []query.FloatPoint
+					for _, p := range []query.FloatPoint{
+						{Name: "cpu", Time: 0 * Second, Value: 1},
+						{Name: "cpu", Time: 5 * Second, Value: 3},
+						{Name: "cpu", Time: 12 * Second, Value: 4},
+						{Name: "cpu", Time: 20 * Second, Value: 10},
+						{Name: "cpu", Time: 31 * Second, Value: 20},
+						{Name: "cpu", Time: 35 * Second, Value: 22},
+					} {
+						if p.Time >= opt.StartTime && p.Time <= opt.EndTime {
+							points = append(points, p)
+						}
+					}
+					return query.NewCallIterator(&FloatIterator{Points: points}, opt)
+				},
+			}
+		},
+	}
+
+	for _, test := range []struct {
+		Name      string
+		Statement string
+		MinTime   int64
+		Points    [][]query.Point
+	}{
+		{
+			Name:      "TimeShift",
+			Statement: `SELECT time_shift(mean(value), 20s) FROM cpu WHERE time >= '1970-01-01T00:00:20Z' AND time < '1970-01-01T00:00:40Z' GROUP BY time(10s)`,
+			MinTime:   0 * Second,
+			Points: [][]query.Point{
+				{&query.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 2, Aggregated: 2}},
+				{&query.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 4, Aggregated: 1}},
+			},
+		},
+		{
+			Name:      "Unaligned",
+			Statement: `SELECT time_shift(mean(value), 15s) FROM cpu WHERE time >= '1970-01-01T00:00:20Z' AND time < '1970-01-01T00:00:40Z' GROUP BY time(10s)`,
+			MinTime:   5 * Second,
+			Points: [][]query.Point{
+				{&query.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 3.5, Aggregated: 2}},
+				{&query.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 10, Aggregated: 1}},
+			},
+		},
+		{
+			Name:      "BinaryExpr",
+			Statement: `SELECT mean(value) - time_shift(mean(value), 20s) FROM cpu WHERE time >= '1970-01-01T00:00:20Z' AND time < '1970-01-01T00:00:40Z' GROUP BY time(10s)`,
+			MinTime:   0 * Second,
+			Points: [][]query.Point{
+				{&query.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 8, Aggregated: 1}},
+				{&query.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 17, Aggregated: 2}},
+			},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			stmt := MustParseSelectStatement(test.Statement)
+			itrs, _, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if min := timeRange.MinTime(); min != test.MinTime {
+				t.Errorf("unexpected shard min time: %d", min)
+			}
+
+			if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, test.Points); diff != "" {
+				t.Errorf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
 type ShardMapper struct {
 	MapShardsFn func(sources influxql.Sources, t influxql.TimeRange) query.ShardGroup
 }
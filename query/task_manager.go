@@ -173,16 +173,16 @@ func (t *TaskManager) AttachQuery(q *influxql.Query, database string, interrupt
 	}
 	t.queries[qid] = query
 
-	go t.waitForQuery(qid, query.closing, interrupt, query.monitorCh)
-	if t.LogQueriesAfter != 0 {
+	go t.waitForQuery(qid, t.QueryTimeout, query.closing, interrupt, query.monitorCh)
+	if logQueriesAfter := t.LogQueriesAfter; logQueriesAfter != 0 {
 		go query.monitor(func(closing <-chan struct{}) error {
-			timer := time.NewTimer(t.LogQueriesAfter)
+			timer := time.NewTimer(logQueriesAfter)
 			defer timer.Stop()
 
 			select {
 			case <-timer.C:
 				t.Logger.Warn(fmt.Sprintf("Detected slow query: %s (qid: %d, database: %s, threshold: %s)",
-					query.query, qid, query.database, t.LogQueriesAfter))
+					query.query, qid, query.database, logQueriesAfter))
 			case <-closing:
 			}
 			return nil
@@ -230,6 +230,16 @@ type QueryInfo struct {
 	Duration time.Duration `json:"duration"`
 }
 
+// SetLimits changes the query limits of the task manager. Running queries
+// keep the limits they were attached with.
+func (t *TaskManager) SetLimits(queryTimeout, logQueriesAfter time.Duration, maxConcurrentQueries int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.QueryTimeout = queryTimeout
+	t.LogQueriesAfter = logQueriesAfter
+	t.MaxConcurrentQueries = maxConcurrentQueries
+}
+
 // Queries returns a list of all running queries with information about them.
 func (t *TaskManager) Queries() []QueryInfo {
 	t.mu.RLock()
@@ -248,10 +258,10 @@ func (t *TaskManager) Queries() []QueryInfo {
 	return queries
 }
 
-func (t *TaskManager) waitForQuery(qid uint64, interrupt <-chan struct{}, closing <-chan struct{}, monitorCh <-chan error) {
+func (t *TaskManager) waitForQuery(qid uint64, timeout time.Duration, interrupt <-chan struct{}, closing <-chan struct{}, monitorCh <-chan error) {
 	var timerCh <-chan time.Time
-	if t.QueryTimeout != 0 {
-		timer := time.NewTimer(t.QueryTimeout)
+	if timeout != 0 {
+		timer := time.NewTimer(timeout)
 		timerCh = timer.C
 		defer timer.Stop()
 	}
@@ -276,6 +276,21 @@ func (s *Service) createInternalStorage() error {
 	return nil
 }
 
+// Reload applies the batching settings of c to the service. The number of
+// pending batches and the other settings require the service to be reopened.
+func (s *Service) Reload(c Config) {
+	d := c.WithDefaults()
+
+	s.mu.Lock()
+	s.Config.BatchSize, s.Config.BatchDuration = d.BatchSize, d.BatchDuration
+	batcher := s.batcher
+	s.mu.Unlock()
+
+	if batcher != nil {
+		batcher.SetLimits(d.BatchSize, time.Duration(d.BatchDuration))
+	}
+}
+
 // WithLogger sets the service's logger.
 func (s *Service) WithLogger(log zap.Logger) {
 	s.Logger = log.With(zap.String("service", "collectd"))
@@ -258,6 +258,21 @@ func (s *Service) createInternalStorage() error {
 	return nil
 }
 
+// Reload applies the batching settings of c to the service. The number of
+// pending batches and the other settings require the service to be reopened.
+func (s *Service) Reload(c Config) {
+	d := c.WithDefaults()
+
+	s.mu.Lock()
+	s.batchSize, s.batchTimeout = d.BatchSize, time.Duration(d.BatchTimeout)
+	batcher := s.batcher
+	s.mu.Unlock()
+
+	if batcher != nil {
+		batcher.SetLimits(d.BatchSize, time.Duration(d.BatchTimeout))
+	}
+}
+
 // WithLogger sets the logger on the service.
 func (s *Service) WithLogger(log zap.Logger) {
 	s.logger = log.With(
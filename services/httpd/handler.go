@@ -11,11 +11,13 @@ import (
 	"io/ioutil"
 	"log"
 	"math"
+	"mime"
 	"net/http"
 	"os"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -24,10 +26,12 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/columnar"
 	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/monitor"
 	"github.com/influxdata/influxdb/monitor/diagnostics"
+	"github.com/influxdata/influxdb/pkg/tracing"
 	"github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/prometheus/remote"
 	"github.com/influxdata/influxdb/query"
@@ -85,6 +89,8 @@ type Handler struct {
 		Authenticate(username, password string) (ui meta.User, err error)
 		User(username string) (meta.User, error)
 		AdminUserExists() bool
+		Data() meta.Data
+		ImportData(data *meta.Data, replace bool) error
 	}
 
 	QueryAuthorizer interface {
@@ -106,6 +112,7 @@ type Handler struct {
 		WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error
 	}
 
+	mu        sync.RWMutex
 	Config    *Config
 	Logger    zap.Logger
 	CLFLogger *log.Logger
@@ -154,6 +161,14 @@ func NewHandler(c Config) *Handler {
 			"prometheus-read", // Prometheus remote read
 			"POST", "/api/v1/prom/read", true, true, h.servePromRead,
 		},
+		Route{ // Meta store export
+			"meta-export",
+			"GET", "/meta", true, true, h.serveMetaExport,
+		},
+		Route{ // Meta store import
+			"meta-import",
+			"POST", "/meta", false, true, h.serveMetaImport,
+		},
 		Route{ // Ping
 			"ping",
 			"GET", "/ping", false, true, h.servePing,
@@ -232,6 +247,35 @@ func (h *Handler) Statistics(tags map[string]string) []models.Statistic {
 	}}
 }
 
+// config returns the current configuration of the handler.
+func (h *Handler) config() *Config {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.Config
+}
+
+// Reload applies the settings of c that can change while the handler is
+// running: auth-enabled, write-tracing, pprof-enabled, the HTTPS certificate
+// and key, max-row-limit, shared-secret, realm and max-body-size. Other
+// settings, such as log-enabled, are not changed. Requests in progress keep
+// the configuration they started with.
+func (h *Handler) Reload(c Config) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	config := *h.Config
+	config.AuthEnabled = c.AuthEnabled
+	config.WriteTracing = c.WriteTracing
+	config.PprofEnabled = c.PprofEnabled
+	config.HTTPSCertificate = c.HTTPSCertificate
+	config.HTTPSPrivateKey = c.HTTPSPrivateKey
+	config.MaxRowLimit = c.MaxRowLimit
+	config.SharedSecret = c.SharedSecret
+	config.Realm = c.Realm
+	config.MaxBodySize = c.MaxBodySize
+	h.Config = &config
+}
+
 // AddRoutes sets the provided routes on the handler.
 func (h *Handler) AddRoutes(routes ...Route) {
 	for _, r := range routes {
@@ -239,7 +283,7 @@ func (h *Handler) AddRoutes(routes ...Route) {
 
 		// If it's a handler func that requires authorization, wrap it in authentication
 		if hf, ok := r.HandlerFunc.(func(http.ResponseWriter, *http.Request, meta.User)); ok {
-			handler = authenticate(hf, h, h.Config.AuthEnabled)
+			handler = authenticate(hf, h)
 		}
 
 		// This is a normal handler signature and does not require authentication
@@ -253,7 +297,7 @@ func (h *Handler) AddRoutes(routes ...Route) {
 		}
 		handler = cors(handler)
 		handler = requestID(handler)
-		if h.Config.LogEnabled && r.LoggingEnabled {
+		if h.config().LogEnabled && r.LoggingEnabled {
 			handler = h.logging(handler, r.Name)
 		}
 		handler = h.recovery(handler, r.Name) // make sure recovery is always last
@@ -273,7 +317,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("X-Influxdb-Version", h.Version)
 	w.Header().Add("X-Influxdb-Build", h.BuildType)
 
-	if strings.HasPrefix(r.URL.Path, "/debug/pprof") && h.Config.PprofEnabled {
+	if strings.HasPrefix(r.URL.Path, "/debug/pprof") && h.config().PprofEnabled {
 		h.handleProfiles(w, r)
 	} else if strings.HasPrefix(r.URL.Path, "/debug/vars") {
 		h.serveExpvar(w, r)
@@ -300,6 +344,8 @@ func (h *Handler) writeHeader(w http.ResponseWriter, code int) {
 
 // serveQuery parses an incoming query and, if valid, executes the query.
 func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.User) {
+	config := h.config()
+
 	atomic.AddInt64(&h.stats.QueryRequests, 1)
 	defer func(start time.Time) {
 		atomic.AddInt64(&h.stats.QueryRequestDuration, time.Since(start).Nanoseconds())
@@ -384,7 +430,7 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 	}
 
 	// Check authorization.
-	if h.Config.AuthEnabled {
+	if config.AuthEnabled {
 		if err := h.QueryAuthorizer.AuthorizeQuery(user, q, db); err != nil {
 			if err, ok := err.(meta.ErrAuthorize); ok {
 				h.Logger.Info(fmt.Sprintf("Unauthorized request | user: %q | query: %q | database %q", err.User, err.Query.String(), err.Database))
@@ -406,14 +452,28 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 	// Parse whether this is an async command.
 	async := r.FormValue("async") == "true"
 
+	// Parse whether the trace of SELECT statements should be returned.
+	var traceFormat string
+	if r.FormValue("trace") == "true" {
+		traceFormat = tracing.ChromeFormat
+		if f := r.FormValue("trace_format"); f != "" {
+			if !tracing.ValidFormat(f) {
+				h.httpError(rw, fmt.Sprintf("unknown trace format %q", f), http.StatusBadRequest)
+				return
+			}
+			traceFormat = f
+		}
+	}
+
 	opts := query.ExecutionOptions{
-		Database:  db,
-		ChunkSize: chunkSize,
-		ReadOnly:  r.Method == "GET",
-		NodeID:    nodeID,
+		Database:    db,
+		ChunkSize:   chunkSize,
+		ReadOnly:    r.Method == "GET",
+		NodeID:      nodeID,
+		TraceFormat: traceFormat,
 	}
 
-	if h.Config.AuthEnabled {
+	if config.AuthEnabled {
 		// The current user determines the authorized actions.
 		opts.Authorizer = user
 	} else {
@@ -499,9 +559,9 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 		// default chunk size, then use chunking to process multiple blobs.
 		// Iterate through the series in this result to count the rows and
 		// truncate any rows we shouldn't return.
-		if h.Config.MaxRowLimit > 0 {
+		if config.MaxRowLimit > 0 {
 			for i, series := range r.Series {
-				n := h.Config.MaxRowLimit - rows
+				n := config.MaxRowLimit - rows
 				if n < len(series.Values) {
 					// We have reached the maximum number of values. Truncate
 					// the values within this row.
@@ -512,7 +572,7 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 				}
 				rows += len(series.Values)
 
-				if rows >= h.Config.MaxRowLimit {
+				if rows >= config.MaxRowLimit {
 					// Drop any remaining series since we have already reached the row limit.
 					if i < len(r.Series) {
 						r.Series = r.Series[:i+1]
@@ -556,12 +616,15 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 			cr.Series = append(cr.Series, r.Series...)
 			cr.Messages = append(cr.Messages, r.Messages...)
 			cr.Partial = r.Partial
+			if r.Trace != nil {
+				cr.Trace = r.Trace
+			}
 		} else {
 			resp.Results = append(resp.Results, r)
 		}
 
 		// Drop out of this loop and do not process further results when we hit the row limit.
-		if h.Config.MaxRowLimit > 0 && rows >= h.Config.MaxRowLimit {
+		if config.MaxRowLimit > 0 && rows >= config.MaxRowLimit {
 			// If the result is marked as partial, remove that partial marking
 			// here. While the series is partial and we would normally have
 			// tried to return the rest in the next chunk, we are not using
@@ -600,6 +663,8 @@ func (h *Handler) async(q *influxql.Query, results <-chan *query.Result) {
 
 // serveWrite receives incoming series data in line protocol format and writes it to the database.
 func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.User) {
+	config := h.config()
+
 	atomic.AddInt64(&h.stats.WriteRequests, 1)
 	atomic.AddInt64(&h.stats.ActiveWriteRequests, 1)
 	defer func(start time.Time) {
@@ -619,7 +684,7 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 		return
 	}
 
-	if h.Config.AuthEnabled {
+	if config.AuthEnabled {
 		if user == nil {
 			h.httpError(w, fmt.Sprintf("user is required to write to database %q", database), http.StatusForbidden)
 			return
@@ -632,8 +697,8 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 	}
 
 	body := r.Body
-	if h.Config.MaxBodySize > 0 {
-		body = truncateReader(body, int64(h.Config.MaxBodySize))
+	if config.MaxBodySize > 0 {
+		body = truncateReader(body, int64(config.MaxBodySize))
 	}
 
 	// Handle gzip decoding of the body
@@ -649,7 +714,7 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 
 	var bs []byte
 	if r.ContentLength > 0 {
-		if h.Config.MaxBodySize > 0 && r.ContentLength > int64(h.Config.MaxBodySize) {
+		if config.MaxBodySize > 0 && r.ContentLength > int64(config.MaxBodySize) {
 			h.httpError(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
 			return
 		}
@@ -667,7 +732,7 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 			return
 		}
 
-		if h.Config.WriteTracing {
+		if config.WriteTracing {
 			h.Logger.Info("Write handler unable to read bytes from request body")
 		}
 		h.httpError(w, err.Error(), http.StatusBadRequest)
@@ -675,11 +740,22 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 	}
 	atomic.AddInt64(&h.stats.WriteRequestBytesReceived, int64(buf.Len()))
 
-	if h.Config.WriteTracing {
+	if config.WriteTracing {
 		h.Logger.Info(fmt.Sprintf("Write body received by handler: %s", buf.Bytes()))
 	}
 
-	points, parseError := models.ParsePointsWithPrecision(buf.Bytes(), time.Now().UTC(), r.URL.Query().Get("precision"))
+	var points []models.Point
+	var parseError error
+	if typ, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); typ == columnar.ContentType {
+		var req columnar.WriteRequest
+		if err := req.Unmarshal(buf.Bytes()); err != nil {
+			h.httpError(w, fmt.Sprintf("invalid columnar write request: %s", err), http.StatusBadRequest)
+			return
+		}
+		points, parseError = req.Points(r.URL.Query().Get("precision"))
+	} else {
+		points, parseError = models.ParsePointsWithPrecision(buf.Bytes(), time.Now().UTC(), r.URL.Query().Get("precision"))
+	}
 	// Not points parsed correctly so return the error now
 	if parseError != nil && len(points) == 0 {
 		if parseError.Error() == "EOF" {
@@ -711,6 +787,18 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
 		h.httpError(w, err.Error(), http.StatusForbidden)
 		return
+	} else if influxdb.IsQuotaExceededError(err) {
+		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
+		h.httpError(w, err.Error(), http.StatusInsufficientStorage)
+		return
+	} else if err == influxdb.ErrReadReplica {
+		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
+		h.httpError(w, err.Error(), http.StatusForbidden)
+		return
+	} else if influxdb.IsReadOnlyError(err) {
+		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
+		h.httpError(w, err.Error(), http.StatusForbidden)
+		return
 	} else if werr, ok := err.(tsdb.PartialWriteError); ok {
 		atomic.AddInt64(&h.stats.PointsWrittenOK, int64(len(points)-werr.Dropped))
 		atomic.AddInt64(&h.stats.PointsWrittenDropped, int64(werr.Dropped))
@@ -738,6 +826,66 @@ func (h *Handler) serveOptions(w http.ResponseWriter, r *http.Request) {
 	h.writeHeader(w, http.StatusNoContent)
 }
 
+// serveMetaExport returns the meta store as JSON. The password hashes of the
+// users are omitted with the hashes=false parameter.
+func (h *Handler) serveMetaExport(w http.ResponseWriter, r *http.Request, user meta.User) {
+	if h.config().AuthEnabled && (user == nil || !user.IsAdmin()) {
+		h.httpError(w, "admin privileges required to export meta data", http.StatusForbidden)
+		return
+	}
+
+	data := h.MetaClient.Data()
+	if r.FormValue("hashes") == "false" {
+		data.RedactHashes()
+	}
+
+	var b []byte
+	var err error
+	if r.FormValue("pretty") == "true" {
+		b, err = json.MarshalIndent(&data, "", "    ")
+	} else {
+		b, err = json.Marshal(&data)
+	}
+	if err != nil {
+		h.httpError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// serveMetaImport imports the meta store from the JSON in the request body.
+// The databases and users are merged with the existing ones, or replace them
+// with the mode=replace parameter.
+func (h *Handler) serveMetaImport(w http.ResponseWriter, r *http.Request, user meta.User) {
+	if h.config().AuthEnabled && (user == nil || !user.IsAdmin()) {
+		h.httpError(w, "admin privileges required to import meta data", http.StatusForbidden)
+		return
+	}
+
+	var replace bool
+	switch mode := r.URL.Query().Get("mode"); mode {
+	case "", "merge":
+	case "replace":
+		replace = true
+	default:
+		h.httpError(w, fmt.Sprintf("unknown import mode %q", mode), http.StatusBadRequest)
+		return
+	}
+
+	var data meta.Data
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.httpError(w, fmt.Sprintf("invalid meta data: %s", err), http.StatusBadRequest)
+		return
+	}
+	if err := h.MetaClient.ImportData(&data, replace); err != nil {
+		h.httpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.writeHeader(w, http.StatusNoContent)
+}
+
 // servePing returns a simple response to let the client know the server is running.
 func (h *Handler) servePing(w http.ResponseWriter, r *http.Request) {
 	atomic.AddInt64(&h.stats.PingRequests, 1)
@@ -780,6 +928,8 @@ func convertToEpoch(r *query.Result, epoch string) {
 // servePromWrite receives data in the Prometheus remote write protocol and writes it
 // to the database
 func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user meta.User) {
+	config := h.config()
+
 	atomic.AddInt64(&h.stats.WriteRequests, 1)
 	atomic.AddInt64(&h.stats.ActiveWriteRequests, 1)
 	atomic.AddInt64(&h.stats.PromWriteRequests, 1)
@@ -800,7 +950,7 @@ func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user me
 		return
 	}
 
-	if h.Config.AuthEnabled {
+	if config.AuthEnabled {
 		if user == nil {
 			h.httpError(w, fmt.Sprintf("user is required to write to database %q", database), http.StatusForbidden)
 			return
@@ -813,13 +963,13 @@ func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user me
 	}
 
 	body := r.Body
-	if h.Config.MaxBodySize > 0 {
-		body = truncateReader(body, int64(h.Config.MaxBodySize))
+	if config.MaxBodySize > 0 {
+		body = truncateReader(body, int64(config.MaxBodySize))
 	}
 
 	var bs []byte
 	if r.ContentLength > 0 {
-		if h.Config.MaxBodySize > 0 && r.ContentLength > int64(h.Config.MaxBodySize) {
+		if config.MaxBodySize > 0 && r.ContentLength > int64(config.MaxBodySize) {
 			h.httpError(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
 			return
 		}
@@ -837,7 +987,7 @@ func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user me
 			return
 		}
 
-		if h.Config.WriteTracing {
+		if config.WriteTracing {
 			h.Logger.Info("Prom write handler unable to read bytes from request body")
 		}
 		h.httpError(w, err.Error(), http.StatusBadRequest)
@@ -845,7 +995,7 @@ func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user me
 	}
 	atomic.AddInt64(&h.stats.WriteRequestBytesReceived, int64(buf.Len()))
 
-	if h.Config.WriteTracing {
+	if config.WriteTracing {
 		h.Logger.Info(fmt.Sprintf("Prom write body received by handler: %s", buf.Bytes()))
 	}
 
@@ -864,7 +1014,7 @@ func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user me
 
 	points, err := prometheus.WriteRequestToPoints(&req)
 	if err != nil {
-		if h.Config.WriteTracing {
+		if config.WriteTracing {
 			h.Logger.Info(fmt.Sprintf("Prom write handler: %s", err.Error()))
 		}
 
@@ -894,6 +1044,18 @@ func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user me
 		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
 		h.httpError(w, err.Error(), http.StatusForbidden)
 		return
+	} else if influxdb.IsQuotaExceededError(err) {
+		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
+		h.httpError(w, err.Error(), http.StatusInsufficientStorage)
+		return
+	} else if err == influxdb.ErrReadReplica {
+		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
+		h.httpError(w, err.Error(), http.StatusForbidden)
+		return
+	} else if influxdb.IsReadOnlyError(err) {
+		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
+		h.httpError(w, err.Error(), http.StatusForbidden)
+		return
 	} else if werr, ok := err.(tsdb.PartialWriteError); ok {
 		atomic.AddInt64(&h.stats.PointsWrittenOK, int64(len(points)-werr.Dropped))
 		atomic.AddInt64(&h.stats.PointsWrittenDropped, int64(werr.Dropped))
@@ -912,6 +1074,8 @@ func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user me
 // servePromRead will convert a Prometheus remote read request into an InfluxQL query and
 // return data in Prometheus remote read protobuf format.
 func (h *Handler) servePromRead(w http.ResponseWriter, r *http.Request, user meta.User) {
+	config := h.config()
+
 	compressed, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		h.httpError(w, err.Error(), http.StatusInternalServerError)
@@ -939,7 +1103,7 @@ func (h *Handler) servePromRead(w http.ResponseWriter, r *http.Request, user met
 	}
 
 	// Check authorization.
-	if h.Config.AuthEnabled {
+	if config.AuthEnabled {
 		if err := h.QueryAuthorizer.AuthorizeQuery(user, q, db); err != nil {
 			if err, ok := err.(meta.ErrAuthorize); ok {
 				h.Logger.Info(fmt.Sprintf("Unauthorized request | user: %q | query: %q | database %q", err.User, err.Query.String(), err.Database))
@@ -955,7 +1119,7 @@ func (h *Handler) servePromRead(w http.ResponseWriter, r *http.Request, user met
 		ReadOnly:  true,
 	}
 
-	if h.Config.AuthEnabled {
+	if config.AuthEnabled {
 		// The current user determines the authorized actions.
 		opts.Authorizer = user
 	} else {
@@ -1248,7 +1412,7 @@ func (h *Handler) httpError(w http.ResponseWriter, errmsg string, code int) {
 	if code == http.StatusUnauthorized {
 		// If an unauthorized header will be sent back, add a WWW-Authenticate header
 		// as an authorization challenge.
-		w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=\"%s\"", h.Config.Realm))
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=\"%s\"", h.config().Realm))
 	} else if code/100 != 2 {
 		sz := math.Min(float64(len(errmsg)), 1024.0)
 		w.Header().Set("X-InfluxDB-Error", errmsg[:int(sz)])
@@ -1325,9 +1489,10 @@ func parseCredentials(r *http.Request) (*credentials, error) {
 //
 // There is one exception: if there are no users in the system, authentication is not required. This
 // is to facilitate bootstrapping of a system with authentication enabled.
-func authenticate(inner func(http.ResponseWriter, *http.Request, meta.User), h *Handler, requireAuthentication bool) http.Handler {
+func authenticate(inner func(http.ResponseWriter, *http.Request, meta.User), h *Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Return early if we are not authenticating
+		requireAuthentication := h.config().AuthEnabled
 		if !requireAuthentication {
 			inner(w, r, nil)
 			return
@@ -1363,7 +1528,7 @@ func authenticate(inner func(http.ResponseWriter, *http.Request, meta.User), h *
 					if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 						return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 					}
-					return []byte(h.Config.SharedSecret), nil
+					return []byte(h.config().SharedSecret), nil
 				}
 
 				// Parse and validate the token.
@@ -2,6 +2,7 @@ package httpd_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -19,6 +20,8 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/columnar"
 	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/internal"
 	"github.com/influxdata/influxdb/models"
@@ -311,6 +314,39 @@ func TestHandler_Query_Chunked(t *testing.T) {
 	}
 }
 
+// Ensure the handler requests traces and merges them into the statement's result.
+func TestHandler_Query_Trace(t *testing.T) {
+	h := NewHandler(false)
+	h.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx query.ExecutionContext) error {
+		if ctx.TraceFormat != "jaeger" {
+			t.Fatalf("unexpected trace format: %q", ctx.TraceFormat)
+		}
+		ctx.Results <- &query.Result{StatementID: 1, Series: models.Rows([]*models.Row{{Name: "series0"}})}
+		ctx.Results <- &query.Result{StatementID: 1, Trace: json.RawMessage(`{"data":[]}`)}
+		return nil
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewJSONRequest("GET", "/query?db=foo&q=SELECT+*+FROM+bar&trace=true&trace_format=jaeger", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if body := strings.TrimSpace(w.Body.String()); body != `{"results":[{"statement_id":1,"series":[{"name":"series0"}],"trace":{"data":[]}}]}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
+// Ensure the handler returns an error for an unknown trace format.
+func TestHandler_Query_Trace_ErrUnknownFormat(t *testing.T) {
+	h := NewHandler(false)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewJSONRequest("GET", "/query?db=foo&q=SELECT+*+FROM+bar&trace=true&trace_format=zipkin", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if body := strings.TrimSpace(w.Body.String()); body != `{"error":"unknown trace format \"zipkin\""}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
 // Ensure the handler can accept an async query.
 func TestHandler_Query_Async(t *testing.T) {
 	done := make(chan struct{})
@@ -675,7 +711,100 @@ func TestHandler_Ping(t *testing.T) {
 	}
 }
 
+// Ensure the handler exports the meta store as JSON.
+func TestHandler_MetaExport(t *testing.T) {
+	h := NewHandler(false)
+	h.MetaClient.DataFn = func() meta.Data {
+		return meta.Data{Users: []meta.UserInfo{{Name: "admin", Hash: "xxx", Admin: true}}}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewRequest("GET", "/meta", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if body := strings.TrimSpace(w.Body.String()); body != `{"cluster_id":0,"max_shard_group_id":0,"max_shard_id":0,"databases":[],"users":[{"name":"admin","hash":"xxx","admin":true,"privileges":{}}]}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewRequest("GET", "/meta?hashes=false", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if strings.Contains(w.Body.String(), "xxx") {
+		t.Fatalf("unexpected hash in body: %s", w.Body.String())
+	}
+}
+
+// Ensure the handler imports the meta store from JSON.
+func TestHandler_MetaImport(t *testing.T) {
+	h := NewHandler(false)
+	var replaced bool
+	h.MetaClient.ImportDataFn = func(data *meta.Data, replace bool) error {
+		if len(data.Databases) != 1 || data.Databases[0].Name != "db0" {
+			t.Fatalf("unexpected data: %+v", data)
+		}
+		replaced = replace
+		return nil
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewRequest("POST", "/meta?mode=replace", strings.NewReader(`{"databases":[{"name":"db0"}]}`)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d: %s", w.Code, w.Body.String())
+	} else if !replaced {
+		t.Fatal("expected data to be replaced")
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewRequest("POST", "/meta?mode=overwrite", strings.NewReader(`{}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if body := strings.TrimSpace(w.Body.String()); body != `{"error":"unknown import mode \"overwrite\""}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
+// Ensure the meta store can only be exported and imported by admin users.
+func TestHandler_Meta_ErrAdminRequired(t *testing.T) {
+	h := NewHandler(true)
+	h.MetaClient.AdminUserExistsFn = func() bool { return true }
+	h.MetaClient.AuthenticateFn = func(u, p string) (meta.User, error) {
+		return &meta.UserInfo{Name: u}, nil
+	}
+
+	for _, req := range []*http.Request{
+		MustNewRequest("GET", "/meta?u=user1&p=abc", nil),
+		MustNewRequest("POST", "/meta?u=user1&p=abc", strings.NewReader(`{}`)),
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("unexpected status for %s: %d", req.Method, w.Code)
+		}
+	}
+}
+
 // Ensure the handler returns the version correctly from the different endpoints.
+// Ensure a reload applies only the settings that can change while running.
+func TestHandler_Reload(t *testing.T) {
+	h := NewHandler(false)
+	bindAddress, logEnabled := h.Config.BindAddress, h.Config.LogEnabled
+
+	c := httpd.NewConfig()
+	c.AuthEnabled = true
+	c.MaxRowLimit = 5
+	c.SharedSecret = "new secret"
+	c.BindAddress = ":1234"
+	c.LogEnabled = !logEnabled
+	h.Reload(c)
+
+	if !h.Config.AuthEnabled || h.Config.MaxRowLimit != 5 || h.Config.SharedSecret != "new secret" {
+		t.Fatalf("reloadable settings not applied: %+v", h.Config)
+	} else if h.Config.BindAddress != bindAddress || h.Config.LogEnabled != logEnabled {
+		t.Fatalf("unexpected change of settings requiring a restart: %+v", h.Config)
+	}
+}
+
 func TestHandler_Version(t *testing.T) {
 	h := NewHandler(false)
 	h.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx query.ExecutionContext) error {
@@ -817,6 +946,107 @@ func TestHandler_Write_NegativeMaxBodySize(t *testing.T) {
 	}
 }
 
+// Ensure writes rejected by a database quota return an insufficient storage error.
+func TestHandler_Write_ErrQuotaExceeded(t *testing.T) {
+	h := NewHandler(false)
+	h.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{}
+	}
+	h.PointsWriter.WritePointsFn = func(_, _ string, _ models.ConsistencyLevel, _ meta.User, _ []models.Point) error {
+		return influxdb.QuotaExceededError{Database: "foo", Quota: "disk", Usage: 2048, Limit: 1024}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewRequest("POST", "/write?db=foo", strings.NewReader(`foo n=1`)))
+	if w.Code != http.StatusInsufficientStorage {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if body := strings.TrimSpace(w.Body.String()); body != `{"error":"database \"foo\" exceeded its disk quota: 2048 of 1024 bytes"}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
+// Ensure writes to a read replica are rejected.
+func TestHandler_Write_ErrReadReplica(t *testing.T) {
+	h := NewHandler(false)
+	h.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{}
+	}
+	h.PointsWriter.WritePointsFn = func(_, _ string, _ models.ConsistencyLevel, _ meta.User, _ []models.Point) error {
+		return influxdb.ErrReadReplica
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewRequest("POST", "/write?db=foo", strings.NewReader(`foo n=1`)))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if body := strings.TrimSpace(w.Body.String()); body != `{"error":"not permitted on a read replica, use the leader"}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
+func TestHandler_Write_ReadOnly(t *testing.T) {
+	h := NewHandler(false)
+	h.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{}
+	}
+	h.PointsWriter.WritePointsFn = func(_, _ string, _ models.ConsistencyLevel, _ meta.User, _ []models.Point) error {
+		return influxdb.ReadOnlyError{Database: "foo"}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewRequest("POST", "/write?db=foo", strings.NewReader(`foo n=1`)))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if body := strings.TrimSpace(w.Body.String()); body != `{"error":"database \"foo\" is read-only"}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
+func TestHandler_Write_Columnar(t *testing.T) {
+	req := columnar.WriteRequest{Series: []*columnar.Series{{
+		Measurement: "cpu",
+		Tags:        []*columnar.Tag{{Key: "host", Value: "server01"}},
+		Timestamps:  []int64{1, 2},
+		Columns:     []*columnar.Column{{Name: "value", FloatValues: []float64{1, 2}}},
+	}}}
+	b, err := req.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var points []models.Point
+	h := NewHandler(false)
+	h.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{}
+	}
+	h.PointsWriter.WritePointsFn = func(_, _ string, _ models.ConsistencyLevel, _ meta.User, p []models.Point) error {
+		points = p
+		return nil
+	}
+
+	r := MustNewRequest("POST", "/write?db=foo&precision=s", bytes.NewReader(b))
+	r.Header.Set("Content-Type", columnar.ContentType)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+	if len(points) != 2 || points[0].String() != "cpu,host=server01 value=1 1000000000" || points[1].String() != "cpu,host=server01 value=2 2000000000" {
+		t.Fatalf("unexpected points: %v", points)
+	}
+
+	// A malformed body is rejected.
+	r = MustNewRequest("POST", "/write?db=foo", bytes.NewReader([]byte{0x0a}))
+	r.Header.Set("Content-Type", columnar.ContentType)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if body := strings.TrimSpace(w.Body.String()); body != `{"error":"invalid columnar write request: unexpected EOF"}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
 // Ensure X-Forwarded-For header writes the correct log message.
 func TestHandler_XForwardedFor(t *testing.T) {
 	var buf bytes.Buffer
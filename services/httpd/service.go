@@ -10,6 +10,7 @@ import (
 	"path"
 	"runtime"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -58,6 +59,9 @@ type Service struct {
 	bindSocket         string
 	unixSocketListener net.Listener
 
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+
 	Handler *Handler
 
 	Logger zap.Logger
@@ -95,9 +99,12 @@ func (s *Service) Open() error {
 		if err != nil {
 			return err
 		}
+		s.mu.Lock()
+		s.certificate = &cert
+		s.mu.Unlock()
 
 		listener, err := tls.Listen("tcp", s.addr, &tls.Config{
-			Certificates: []tls.Certificate{cert},
+			GetCertificate: s.getCertificate,
 		})
 		if err != nil {
 			return err
@@ -176,6 +183,36 @@ func (s *Service) Close() error {
 	return nil
 }
 
+// Reload applies the settings of c that can change without reopening the
+// service: the TLS certificate and the settings of the handler. The current
+// certificate is kept if the new one cannot be loaded.
+func (s *Service) Reload(c Config) error {
+	if s.https {
+		key := c.HTTPSPrivateKey
+		if key == "" {
+			key = c.HTTPSCertificate
+		}
+		cert, err := tls.LoadX509KeyPair(c.HTTPSCertificate, key)
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.cert, s.key, s.certificate = c.HTTPSCertificate, key, &cert
+		s.mu.Unlock()
+	}
+
+	s.Handler.Reload(c)
+	return nil
+}
+
+// getCertificate returns the current TLS certificate of the service.
+func (s *Service) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.certificate, nil
+}
+
 // WithLogger sets the logger for the service.
 func (s *Service) WithLogger(log zap.Logger) {
 	s.Logger = log.With(zap.String("service", "httpd"))
@@ -356,6 +356,60 @@ func (c *Client) UpdateRetentionPolicy(database, name string, rpu *RetentionPoli
 	return nil
 }
 
+// UpdateDatabaseQuota updates the quotas of a database.
+func (c *Client) UpdateDatabaseQuota(database string, qu *DatabaseQuotaUpdate) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.UpdateDatabaseQuota(database, qu); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetDatabaseReadOnly marks a database read-only, or writable again.
+func (c *Client) SetDatabaseReadOnly(name string, readOnly bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetDatabaseReadOnly(name, readOnly); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetShardReadOnly marks a shard read-only, or writable again.
+func (c *Client) SetShardReadOnly(id uint64, readOnly bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetShardReadOnly(id, readOnly); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Users returns a slice of UserInfo representing the currently known users.
 func (c *Client) Users() []UserInfo {
 	c.mu.RLock()
@@ -672,6 +726,40 @@ func (c *Client) DropShard(id uint64) error {
 	return c.commit(data)
 }
 
+// MoveShardGroup moves a shard group to another database and retention policy.
+func (c *Client) MoveShardGroup(id uint64, database, policy string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.MoveShardGroup(id, database, policy); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// CopyShardGroup copies a shard group to another database and retention
+// policy, and returns the copy.
+func (c *Client) CopyShardGroup(id uint64, database, policy string) (*ShardGroupInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	sgi, err := data.CopyShardGroup(id, database, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.commit(data); err != nil {
+		return nil, err
+	}
+
+	return sgi, nil
+}
+
 // PruneShardGroups remove deleted shard groups from the data store.
 func (c *Client) PruneShardGroups() error {
 	var changed bool
@@ -861,6 +949,42 @@ func (c *Client) CreateContinuousQuery(database, name, query string) error {
 	return nil
 }
 
+// CreateMeasurementSchema declares the schema of a measurement on the given database.
+func (c *Client) CreateMeasurementSchema(database string, msi MeasurementSchemaInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.CreateMeasurementSchema(database, msi); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DropMeasurementSchema removes the schema of a measurement on the given database.
+func (c *Client) DropMeasurementSchema(database, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.DropMeasurementSchema(database, name); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DropContinuousQuery removes the continuous query with the given name on the given database.
 func (c *Client) DropContinuousQuery(database, name string) error {
 	c.mu.Lock()
@@ -918,6 +1042,7 @@ func (c *Client) DropSubscription(database, rp, name string) error {
 // SetData overwrites the underlying data in the meta store.
 func (c *Client) SetData(data *Data) error {
 	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	// reset the index so the commit will fire a change event
 	c.cacheData.Index = 0
@@ -926,11 +1051,27 @@ func (c *Client) SetData(data *Data) error {
 	d := data.Clone()
 	d.Index++
 
+	return c.commit(d)
+}
+
+// ImportData imports the databases and users of data into the meta store,
+// replacing the existing ones with replace or merging them otherwise.
+func (c *Client) ImportData(data *Data, replace bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := c.cacheData.Clone()
+
+	if err := d.Import(data, replace); err != nil {
+		return err
+	}
+
 	if err := c.commit(d); err != nil {
 		return err
 	}
 
-	c.mu.Unlock()
+	// Imported users may have new password hashes.
+	c.authCache = make(map[string]authUser)
 
 	return nil
 }
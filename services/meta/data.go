@@ -2,6 +2,7 @@ package meta
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"net/url"
 	"sort"
@@ -193,6 +194,7 @@ type RetentionPolicyUpdate struct {
 	Duration           *time.Duration
 	ReplicaN           *int
 	ShardGroupDuration *time.Duration
+	ReadOnly           *bool
 }
 
 // SetName sets the RetentionPolicyUpdate.Name.
@@ -207,6 +209,72 @@ func (rpu *RetentionPolicyUpdate) SetReplicaN(v int) { rpu.ReplicaN = &v }
 // SetShardGroupDuration sets the RetentionPolicyUpdate.ShardGroupDuration.
 func (rpu *RetentionPolicyUpdate) SetShardGroupDuration(v time.Duration) { rpu.ShardGroupDuration = &v }
 
+// SetReadOnly sets the RetentionPolicyUpdate.ReadOnly.
+func (rpu *RetentionPolicyUpdate) SetReadOnly(v bool) { rpu.ReadOnly = &v }
+
+// DatabaseQuotaUpdate represents database quotas to be updated.
+type DatabaseQuotaUpdate struct {
+	MaxDiskBytes *int64
+	MaxSeriesN   *int64
+}
+
+// SetMaxDiskBytes sets the DatabaseQuotaUpdate.MaxDiskBytes.
+func (qu *DatabaseQuotaUpdate) SetMaxDiskBytes(v int64) { qu.MaxDiskBytes = &v }
+
+// SetMaxSeriesN sets the DatabaseQuotaUpdate.MaxSeriesN.
+func (qu *DatabaseQuotaUpdate) SetMaxSeriesN(v int64) { qu.MaxSeriesN = &v }
+
+// UpdateDatabaseQuota updates the quotas of an existing database.
+// A quota of zero removes the quota.
+func (data *Data) UpdateDatabaseQuota(name string, qu *DatabaseQuotaUpdate) error {
+	di := data.Database(name)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(name)
+	}
+
+	if (qu.MaxDiskBytes != nil && *qu.MaxDiskBytes < 0) || (qu.MaxSeriesN != nil && *qu.MaxSeriesN < 0) {
+		return ErrInvalidQuota
+	}
+
+	if qu.MaxDiskBytes != nil {
+		di.MaxDiskBytes = *qu.MaxDiskBytes
+	}
+	if qu.MaxSeriesN != nil {
+		di.MaxSeriesN = *qu.MaxSeriesN
+	}
+	return nil
+}
+
+// SetDatabaseReadOnly marks an existing database read-only, or writable again.
+func (data *Data) SetDatabaseReadOnly(name string, readOnly bool) error {
+	di := data.Database(name)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(name)
+	}
+	di.ReadOnly = readOnly
+	return nil
+}
+
+// SetShardReadOnly marks an existing shard read-only, or writable again.
+func (data *Data) SetShardReadOnly(id uint64, readOnly bool) error {
+	for dbidx := range data.Databases {
+		dbi := &data.Databases[dbidx]
+		for rpidx := range dbi.RetentionPolicies {
+			rpi := &dbi.RetentionPolicies[rpidx]
+			for sgidx := range rpi.ShardGroups {
+				sgi := &rpi.ShardGroups[sgidx]
+				for shidx := range sgi.Shards {
+					if sgi.Shards[shidx].ID == id {
+						sgi.Shards[shidx].ReadOnly = readOnly
+						return nil
+					}
+				}
+			}
+		}
+	}
+	return ErrShardNotFound
+}
+
 // UpdateRetentionPolicy updates an existing retention policy.
 func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool) error {
 	// Find database.
@@ -253,6 +321,9 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 	if rpu.ShardGroupDuration != nil {
 		rpi.ShardGroupDuration = normalisedShardDuration(*rpu.ShardGroupDuration, rpi.Duration)
 	}
+	if rpu.ReadOnly != nil {
+		rpi.ReadOnly = *rpu.ReadOnly
+	}
 
 	if di.DefaultRetentionPolicy != rpi.Name && makeDefault {
 		di.DefaultRetentionPolicy = rpi.Name
@@ -406,6 +477,93 @@ func (data *Data) DeleteShardGroup(database, policy string, id uint64) error {
 	return ErrShardGroupNotFound
 }
 
+// MoveShardGroup moves a shard group, with its shards, to another database
+// and retention policy. The time range of the shard group must not overlap a
+// shard group of the retention policy.
+func (data *Data) MoveShardGroup(id uint64, database, policy string) error {
+	src, i := data.shardGroup(id)
+	if src == nil {
+		return ErrShardGroupNotFound
+	}
+	sgi := src.ShardGroups[i]
+
+	dst, err := data.shardGroupTarget(&sgi, database, policy)
+	if err != nil {
+		return err
+	}
+
+	src.ShardGroups = append(src.ShardGroups[:i], src.ShardGroups[i+1:]...)
+	dst.ShardGroups = append(dst.ShardGroups, sgi)
+	sort.Sort(ShardGroupInfos(dst.ShardGroups))
+	return nil
+}
+
+// CopyShardGroup copies a shard group to another database and retention
+// policy, and returns the copy. The copy has a new ID and new shards, in the
+// order of the shards of the shard group. The time range of the shard group
+// must not overlap a shard group of the retention policy.
+func (data *Data) CopyShardGroup(id uint64, database, policy string) (*ShardGroupInfo, error) {
+	src, i := data.shardGroup(id)
+	if src == nil {
+		return nil, ErrShardGroupNotFound
+	}
+	sgi := src.ShardGroups[i].clone()
+
+	dst, err := data.shardGroupTarget(&sgi, database, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	data.MaxShardGroupID++
+	sgi.ID = data.MaxShardGroupID
+	for i := range sgi.Shards {
+		data.MaxShardID++
+		sgi.Shards[i].ID = data.MaxShardID
+	}
+
+	dst.ShardGroups = append(dst.ShardGroups, sgi)
+	sort.Sort(ShardGroupInfos(dst.ShardGroups))
+	return &sgi, nil
+}
+
+// shardGroup returns the retention policy holding the shard group with the
+// given ID, and the index of the shard group in the policy. Deleted shard
+// groups are ignored.
+func (data *Data) shardGroup(id uint64) (*RetentionPolicyInfo, int) {
+	for dbidx := range data.Databases {
+		dbi := &data.Databases[dbidx]
+		for rpidx := range dbi.RetentionPolicies {
+			rpi := &dbi.RetentionPolicies[rpidx]
+			for i := range rpi.ShardGroups {
+				if rpi.ShardGroups[i].ID == id && !rpi.ShardGroups[i].Deleted() {
+					return rpi, i
+				}
+			}
+		}
+	}
+	return nil, 0
+}
+
+// shardGroupTarget returns the retention policy a shard group is moved or
+// copied to, if none of its shard groups overlaps the time range of sgi.
+func (data *Data) shardGroupTarget(sgi *ShardGroupInfo, database, policy string) (*RetentionPolicyInfo, error) {
+	rpi, err := data.RetentionPolicy(database, policy)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, influxdb.ErrRetentionPolicyNotFound(policy)
+	}
+
+	// Shard groups hold the range [start, end).
+	for i := range rpi.ShardGroups {
+		g := &rpi.ShardGroups[i]
+		if !g.Deleted() && g.Overlaps(sgi.StartTime, sgi.EndTime.Add(-1)) {
+			return nil, ErrShardGroupOverlap
+		}
+	}
+	return rpi, nil
+}
+
 // CreateContinuousQuery adds a named continuous query to a database.
 func (data *Data) CreateContinuousQuery(database, name, query string) error {
 	di := data.Database(database)
@@ -451,6 +609,46 @@ func (data *Data) DropContinuousQuery(database, name string) error {
 	return ErrContinuousQueryNotFound
 }
 
+// CreateMeasurementSchema declares the schema of a measurement.
+func (data *Data) CreateMeasurementSchema(database string, msi MeasurementSchemaInfo) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	if err := msi.validate(); err != nil {
+		return err
+	}
+
+	// Creating an identical schema is a no-op, but a different schema must
+	// be dropped before it is created again.
+	if other := di.MeasurementSchema(msi.Name); other != nil {
+		if proto.Equal(other.marshal(), msi.marshal()) {
+			return nil
+		}
+		return ErrMeasurementSchemaExists
+	}
+
+	di.MeasurementSchemas = append(di.MeasurementSchemas, msi.clone())
+	return nil
+}
+
+// DropMeasurementSchema removes the schema of a measurement.
+func (data *Data) DropMeasurementSchema(database, name string) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.MeasurementSchemas {
+		if di.MeasurementSchemas[i].Name == name {
+			di.MeasurementSchemas = append(di.MeasurementSchemas[:i], di.MeasurementSchemas[i+1:]...)
+			return nil
+		}
+	}
+	return ErrMeasurementSchemaNotFound
+}
+
 // validateURL returns an error if the URL does not have a port or uses a scheme other than UDP or HTTP.
 func validateURL(input string) error {
 	u, err := url.Parse(input)
@@ -782,6 +980,16 @@ type DatabaseInfo struct {
 	DefaultRetentionPolicy string
 	RetentionPolicies      []RetentionPolicyInfo
 	ContinuousQueries      []ContinuousQueryInfo
+
+	// MaxDiskBytes is the maximum size on disk of the database and
+	// MaxSeriesN the maximum number of series. Zero means no quota.
+	MaxDiskBytes int64
+	MaxSeriesN   int64
+
+	MeasurementSchemas []MeasurementSchemaInfo
+
+	// ReadOnly rejects writes to the database.
+	ReadOnly bool
 }
 
 // RetentionPolicy returns a retention policy by name.
@@ -801,6 +1009,17 @@ func (di DatabaseInfo) RetentionPolicy(name string) *RetentionPolicyInfo {
 	return nil
 }
 
+// MeasurementSchema returns the schema declared for a measurement, or nil if
+// the measurement has no schema.
+func (di DatabaseInfo) MeasurementSchema(name string) *MeasurementSchemaInfo {
+	for i := range di.MeasurementSchemas {
+		if di.MeasurementSchemas[i].Name == name {
+			return &di.MeasurementSchemas[i]
+		}
+	}
+	return nil
+}
+
 // ShardInfos returns a list of all shards' info for the database.
 func (di DatabaseInfo) ShardInfos() []ShardInfo {
 	shards := map[uint64]*ShardInfo{}
@@ -845,6 +1064,14 @@ func (di DatabaseInfo) clone() DatabaseInfo {
 		}
 	}
 
+	// Copy measurement schemas.
+	if di.MeasurementSchemas != nil {
+		other.MeasurementSchemas = make([]MeasurementSchemaInfo, len(di.MeasurementSchemas))
+		for i := range di.MeasurementSchemas {
+			other.MeasurementSchemas[i] = di.MeasurementSchemas[i].clone()
+		}
+	}
+
 	return other
 }
 
@@ -863,6 +1090,21 @@ func (di DatabaseInfo) marshal() *internal.DatabaseInfo {
 	for i := range di.ContinuousQueries {
 		pb.ContinuousQueries[i] = di.ContinuousQueries[i].marshal()
 	}
+
+	if di.MaxDiskBytes > 0 {
+		pb.MaxDiskBytes = proto.Int64(di.MaxDiskBytes)
+	}
+	if di.MaxSeriesN > 0 {
+		pb.MaxSeriesN = proto.Int64(di.MaxSeriesN)
+	}
+	if di.ReadOnly {
+		pb.ReadOnly = proto.Bool(true)
+	}
+
+	pb.MeasurementSchemas = make([]*internal.MeasurementSchemaInfo, len(di.MeasurementSchemas))
+	for i := range di.MeasurementSchemas {
+		pb.MeasurementSchemas[i] = di.MeasurementSchemas[i].marshal()
+	}
 	return pb
 }
 
@@ -870,6 +1112,9 @@ func (di DatabaseInfo) marshal() *internal.DatabaseInfo {
 func (di *DatabaseInfo) unmarshal(pb *internal.DatabaseInfo) {
 	di.Name = pb.GetName()
 	di.DefaultRetentionPolicy = pb.GetDefaultRetentionPolicy()
+	di.MaxDiskBytes = pb.GetMaxDiskBytes()
+	di.MaxSeriesN = pb.GetMaxSeriesN()
+	di.ReadOnly = pb.GetReadOnly()
 
 	if len(pb.GetRetentionPolicies()) > 0 {
 		di.RetentionPolicies = make([]RetentionPolicyInfo, len(pb.GetRetentionPolicies()))
@@ -884,6 +1129,13 @@ func (di *DatabaseInfo) unmarshal(pb *internal.DatabaseInfo) {
 			di.ContinuousQueries[i].unmarshal(x)
 		}
 	}
+
+	if len(pb.GetMeasurementSchemas()) > 0 {
+		di.MeasurementSchemas = make([]MeasurementSchemaInfo, len(pb.GetMeasurementSchemas()))
+		for i, x := range pb.GetMeasurementSchemas() {
+			di.MeasurementSchemas[i].unmarshal(x)
+		}
+	}
 }
 
 // RetentionPolicySpec represents the specification for a new retention policy.
@@ -978,6 +1230,9 @@ type RetentionPolicyInfo struct {
 	ShardGroupDuration time.Duration
 	ShardGroups        []ShardGroupInfo
 	Subscriptions      []SubscriptionInfo
+
+	// ReadOnly rejects writes to the retention policy.
+	ReadOnly bool
 }
 
 // NewRetentionPolicyInfo returns a new instance of RetentionPolicyInfo
@@ -1074,6 +1329,10 @@ func (rpi *RetentionPolicyInfo) marshal() *internal.RetentionPolicyInfo {
 		pb.Subscriptions[i] = sub.marshal()
 	}
 
+	if rpi.ReadOnly {
+		pb.ReadOnly = proto.Bool(true)
+	}
+
 	return pb
 }
 
@@ -1083,6 +1342,7 @@ func (rpi *RetentionPolicyInfo) unmarshal(pb *internal.RetentionPolicyInfo) {
 	rpi.ReplicaN = int(pb.GetReplicaN())
 	rpi.Duration = time.Duration(pb.GetDuration())
 	rpi.ShardGroupDuration = time.Duration(pb.GetShardGroupDuration())
+	rpi.ReadOnly = pb.GetReadOnly()
 
 	if len(pb.GetShardGroups()) > 0 {
 		rpi.ShardGroups = make([]ShardGroupInfo, len(pb.GetShardGroups()))
@@ -1284,6 +1544,9 @@ func (sgi *ShardGroupInfo) unmarshal(pb *internal.ShardGroupInfo) {
 type ShardInfo struct {
 	ID     uint64
 	Owners []ShardOwner
+
+	// ReadOnly rejects writes to the shard.
+	ReadOnly bool
 }
 
 // OwnedBy determines whether the shard's owner IDs includes nodeID.
@@ -1321,6 +1584,10 @@ func (si ShardInfo) marshal() *internal.ShardInfo {
 		pb.Owners[i] = si.Owners[i].marshal()
 	}
 
+	if si.ReadOnly {
+		pb.ReadOnly = proto.Bool(true)
+	}
+
 	return pb
 }
 
@@ -1337,6 +1604,7 @@ func (si *ShardInfo) UnmarshalBinary(buf []byte) error {
 // unmarshal deserializes from a protobuf representation.
 func (si *ShardInfo) unmarshal(pb *internal.ShardInfo) {
 	si.ID = pb.GetID()
+	si.ReadOnly = pb.GetReadOnly()
 
 	// If deprecated "OwnerIDs" exists then convert it to "Owners" format.
 	if len(pb.GetOwnerIDs()) > 0 {
@@ -1433,6 +1701,128 @@ func (cqi *ContinuousQueryInfo) unmarshal(pb *internal.ContinuousQueryInfo) {
 	cqi.Query = pb.GetQuery()
 }
 
+// Measurement schema modes.
+const (
+	// SchemaModeStrict drops points that do not match the schema.
+	SchemaModeStrict = "strict"
+
+	// SchemaModeWarn logs points that do not match the schema and writes them.
+	SchemaModeWarn = "warn"
+)
+
+// MeasurementSchemaInfo represents the schema declared for a measurement.
+type MeasurementSchemaInfo struct {
+	Name string
+	Mode string
+
+	// Tag keys allowed in addition to the required tags. Tag keys are only
+	// restricted if the schema lists tag keys or required tags.
+	TagKeys      []string
+	RequiredTags []string
+
+	Fields []FieldSchemaInfo
+}
+
+// FieldSchemaInfo represents a field declared by a measurement schema.
+type FieldSchemaInfo struct {
+	Name string
+	Type influxql.DataType
+}
+
+// Field returns the declared field with the given name, or nil.
+func (msi *MeasurementSchemaInfo) Field(name string) *FieldSchemaInfo {
+	for i := range msi.Fields {
+		if msi.Fields[i].Name == name {
+			return &msi.Fields[i]
+		}
+	}
+	return nil
+}
+
+// validate returns an error if the schema is invalid.
+func (msi *MeasurementSchemaInfo) validate() error {
+	if msi.Name == "" {
+		return ErrMeasurementNameRequired
+	} else if msi.Mode != SchemaModeStrict && msi.Mode != SchemaModeWarn {
+		return fmt.Errorf("unknown schema mode %q", msi.Mode)
+	} else if len(msi.Fields) == 0 {
+		return ErrMeasurementSchemaFieldsRequired
+	}
+
+	for i, f := range msi.Fields {
+		switch f.Type {
+		case influxql.Float, influxql.Integer, influxql.Unsigned, influxql.String, influxql.Boolean:
+		default:
+			return fmt.Errorf("field %q: invalid type %s", f.Name, f.Type)
+		}
+		for _, other := range msi.Fields[:i] {
+			if other.Name == f.Name {
+				return fmt.Errorf("field %q declared more than once", f.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// clone returns a deep copy of msi.
+func (msi MeasurementSchemaInfo) clone() MeasurementSchemaInfo {
+	other := msi
+	if msi.TagKeys != nil {
+		other.TagKeys = append([]string(nil), msi.TagKeys...)
+	}
+	if msi.RequiredTags != nil {
+		other.RequiredTags = append([]string(nil), msi.RequiredTags...)
+	}
+	if msi.Fields != nil {
+		other.Fields = append([]FieldSchemaInfo(nil), msi.Fields...)
+	}
+	return other
+}
+
+// marshal serializes to a protobuf representation.
+func (msi MeasurementSchemaInfo) marshal() *internal.MeasurementSchemaInfo {
+	pb := &internal.MeasurementSchemaInfo{
+		Name:         proto.String(msi.Name),
+		Mode:         proto.String(msi.Mode),
+		TagKeys:      msi.TagKeys,
+		RequiredTags: msi.RequiredTags,
+	}
+
+	pb.Fields = make([]*internal.FieldSchemaInfo, len(msi.Fields))
+	for i, f := range msi.Fields {
+		pb.Fields[i] = &internal.FieldSchemaInfo{
+			Name: proto.String(f.Name),
+			Type: proto.String(f.Type.String()),
+		}
+	}
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (msi *MeasurementSchemaInfo) unmarshal(pb *internal.MeasurementSchemaInfo) {
+	msi.Name = pb.GetName()
+	msi.Mode = pb.GetMode()
+	msi.TagKeys = pb.GetTagKeys()
+	msi.RequiredTags = pb.GetRequiredTags()
+
+	if len(pb.GetFields()) > 0 {
+		msi.Fields = make([]FieldSchemaInfo, len(pb.GetFields()))
+		for i, x := range pb.GetFields() {
+			msi.Fields[i] = FieldSchemaInfo{Name: x.GetName(), Type: parseFieldType(x.GetType())}
+		}
+	}
+}
+
+// parseFieldType returns the field type formatted as s by DataType.String.
+func parseFieldType(s string) influxql.DataType {
+	for _, typ := range []influxql.DataType{influxql.Float, influxql.Integer, influxql.Unsigned, influxql.String, influxql.Boolean} {
+		if typ.String() == s {
+			return typ
+		}
+	}
+	return influxql.Unknown
+}
+
 var _ query.Authorizer = (*UserInfo)(nil)
 
 // UserInfo represents metadata about a user in the system.
@@ -1,7 +1,9 @@
 package meta_test
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -215,6 +217,252 @@ func TestData_SetPrivilege(t *testing.T) {
 	}
 }
 
+func TestData_UpdateDatabaseQuota(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	// When the database does not exist, UpdateDatabaseQuota returns an error.
+	var qu meta.DatabaseQuotaUpdate
+	qu.SetMaxDiskBytes(1024)
+	if got, exp := data.UpdateDatabaseQuota("db1", &qu), influxdb.ErrDatabaseNotFound("db1"); got == nil || got.Error() != exp.Error() {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	if err := data.UpdateDatabaseQuota("db0", &qu); err != nil {
+		t.Fatal(err)
+	}
+
+	// Quotas that are not set are left unchanged.
+	qu = meta.DatabaseQuotaUpdate{}
+	qu.SetMaxSeriesN(100)
+	if err := data.UpdateDatabaseQuota("db0", &qu); err != nil {
+		t.Fatal(err)
+	} else if di := data.Database("db0"); di.MaxDiskBytes != 1024 || di.MaxSeriesN != 100 {
+		t.Fatalf("unexpected quotas: disk=%d series=%d", di.MaxDiskBytes, di.MaxSeriesN)
+	}
+
+	// Quotas survive a marshal round trip.
+	b, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other meta.Data
+	if err := other.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	} else if di := other.Database("db0"); di.MaxDiskBytes != 1024 || di.MaxSeriesN != 100 {
+		t.Fatalf("unexpected quotas after unmarshal: disk=%d series=%d", di.MaxDiskBytes, di.MaxSeriesN)
+	}
+
+	qu = meta.DatabaseQuotaUpdate{}
+	qu.SetMaxDiskBytes(-1)
+	if got, exp := data.UpdateDatabaseQuota("db0", &qu), meta.ErrInvalidQuota; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_ReadOnly(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	rp := &meta.RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, Duration: 24 * time.Hour}
+	if err := data.CreateRetentionPolicy("db0", rp, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateShardGroup("db0", "rp0", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	shardID := data.Database("db0").RetentionPolicy("rp0").ShardGroups[0].Shards[0].ID
+
+	if got, exp := data.SetDatabaseReadOnly("db1", true), influxdb.ErrDatabaseNotFound("db1"); got == nil || got.Error() != exp.Error() {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if got, exp := data.SetShardReadOnly(shardID+1, true), meta.ErrShardNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	var rpu meta.RetentionPolicyUpdate
+	rpu.SetReadOnly(true)
+	if err := data.SetDatabaseReadOnly("db0", true); err != nil {
+		t.Fatal(err)
+	} else if err := data.UpdateRetentionPolicy("db0", "rp0", &rpu, false); err != nil {
+		t.Fatal(err)
+	} else if err := data.SetShardReadOnly(shardID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// The read-only flags survive a marshal round trip.
+	b, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other meta.Data
+	if err := other.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	di := other.Database("db0")
+	if rpi := di.RetentionPolicy("rp0"); !di.ReadOnly || !rpi.ReadOnly || !rpi.ShardGroups[0].Shards[0].ReadOnly {
+		t.Fatalf("unexpected read-only flags: database=%v rp=%v shard=%v", di.ReadOnly, rpi.ReadOnly, rpi.ShardGroups[0].Shards[0].ReadOnly)
+	}
+
+	if err := other.SetShardReadOnly(shardID, false); err != nil {
+		t.Fatal(err)
+	} else if other.Database("db0").RetentionPolicy("rp0").ShardGroups[0].Shards[0].ReadOnly {
+		t.Fatal("expected shard to be writable")
+	}
+}
+
+func TestData_MoveShardGroup(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"rp0", "rp1"} {
+		rp := &meta.RetentionPolicyInfo{Name: name, ReplicaN: 1, Duration: 24 * time.Hour}
+		if err := data.CreateRetentionPolicy("db0", rp, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	if err := data.CreateShardGroup("db0", "rp0", now); err != nil {
+		t.Fatal(err)
+	}
+	sgi := data.Database("db0").RetentionPolicy("rp0").ShardGroups[0]
+
+	if got, exp := data.MoveShardGroup(sgi.ID+1, "db0", "rp1"), meta.ErrShardGroupNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	} else if got, exp := data.MoveShardGroup(sgi.ID, "db0", "rp2"), influxdb.ErrRetentionPolicyNotFound("rp2"); got == nil || got.Error() != exp.Error() {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	if err := data.MoveShardGroup(sgi.ID, "db0", "rp1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(data.Database("db0").RetentionPolicy("rp0").ShardGroups); n != 0 {
+		t.Fatalf("unexpected shard groups in rp0: %d", n)
+	}
+	if got := data.Database("db0").RetentionPolicy("rp1").ShardGroups; len(got) != 1 || !reflect.DeepEqual(got[0], sgi) {
+		t.Fatalf("unexpected shard groups in rp1: %v", got)
+	}
+
+	// A shard group cannot overlap a shard group of the target.
+	if err := data.CreateShardGroup("db0", "rp0", now); err != nil {
+		t.Fatal(err)
+	}
+	id := data.Database("db0").RetentionPolicy("rp0").ShardGroups[0].ID
+	if got, exp := data.MoveShardGroup(id, "db0", "rp1"), meta.ErrShardGroupOverlap; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_CopyShardGroup(t *testing.T) {
+	data := meta.Data{}
+	for _, name := range []string{"db0", "db1"} {
+		if err := data.CreateDatabase(name); err != nil {
+			t.Fatal(err)
+		}
+		rp := &meta.RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, Duration: 24 * time.Hour}
+		if err := data.CreateRetentionPolicy(name, rp, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := data.CreateShardGroup("db0", "rp0", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	sgi := data.Database("db0").RetentionPolicy("rp0").ShardGroups[0]
+
+	copied, err := data.CopyShardGroup(sgi.ID, "db1", "rp0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.ID == sgi.ID || copied.Shards[0].ID == sgi.Shards[0].ID {
+		t.Fatalf("unexpected IDs of copy: group=%d shard=%d", copied.ID, copied.Shards[0].ID)
+	} else if !copied.StartTime.Equal(sgi.StartTime) || !copied.EndTime.Equal(sgi.EndTime) {
+		t.Fatalf("unexpected time range of copy: %s - %s", copied.StartTime, copied.EndTime)
+	}
+	if got := data.Database("db1").RetentionPolicy("rp0").ShardGroups; len(got) != 1 || !reflect.DeepEqual(got[0], *copied) {
+		t.Fatalf("unexpected shard groups in db1: %v", got)
+	}
+	if got := data.Database("db0").RetentionPolicy("rp0").ShardGroups; len(got) != 1 || !reflect.DeepEqual(got[0], sgi) {
+		t.Fatalf("unexpected shard groups in db0: %v", got)
+	}
+
+	if _, err := data.CopyShardGroup(sgi.ID, "db1", "rp0"); err != meta.ErrShardGroupOverlap {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestData_CreateMeasurementSchema(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	msi := meta.MeasurementSchemaInfo{
+		Name:         "cpu",
+		Mode:         meta.SchemaModeStrict,
+		RequiredTags: []string{"host"},
+		Fields:       []meta.FieldSchemaInfo{{Name: "value", Type: influxql.Float}},
+	}
+	if got, exp := data.CreateMeasurementSchema("db1", msi), influxdb.ErrDatabaseNotFound("db1"); got == nil || got.Error() != exp.Error() {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if err := data.CreateMeasurementSchema("db0", msi); err != nil {
+		t.Fatal(err)
+	}
+
+	// Creating an identical schema is a no-op, a different one is an error.
+	if err := data.CreateMeasurementSchema("db0", msi); err != nil {
+		t.Fatal(err)
+	}
+	other := msi
+	other.Mode = meta.SchemaModeWarn
+	if got, exp := data.CreateMeasurementSchema("db0", other), meta.ErrMeasurementSchemaExists; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// Schemas survive a marshal round trip.
+	b, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clone meta.Data
+	if err := clone.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	} else if got := clone.Database("db0").MeasurementSchema("cpu"); !reflect.DeepEqual(got, &msi) {
+		t.Fatalf("unexpected schema after unmarshal: %+v", got)
+	}
+
+	if err := data.DropMeasurementSchema("db0", "cpu"); err != nil {
+		t.Fatal(err)
+	} else if data.Database("db0").MeasurementSchema("cpu") != nil {
+		t.Fatal("expected schema to be dropped")
+	}
+	if got, exp := data.DropMeasurementSchema("db0", "cpu"), meta.ErrMeasurementSchemaNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_CreateMeasurementSchema_Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		msi meta.MeasurementSchemaInfo
+		err error
+	}{
+		{msi: meta.MeasurementSchemaInfo{Mode: meta.SchemaModeStrict, Fields: []meta.FieldSchemaInfo{{Name: "value", Type: influxql.Float}}}, err: meta.ErrMeasurementNameRequired},
+		{msi: meta.MeasurementSchemaInfo{Name: "cpu", Mode: meta.SchemaModeStrict}, err: meta.ErrMeasurementSchemaFieldsRequired},
+	} {
+		data := meta.Data{}
+		if err := data.CreateDatabase("db0"); err != nil {
+			t.Fatal(err)
+		}
+		if got := data.CreateMeasurementSchema("db0", tt.msi); got != tt.err {
+			t.Errorf("unexpected error for %+v: got %v, exp %v", tt.msi, got, tt.err)
+		}
+	}
+}
+
 func TestUserInfo_AuthorizeDatabase(t *testing.T) {
 	emptyUser := &meta.UserInfo{}
 	if !emptyUser.AuthorizeDatabase(influxql.NoPrivileges, "anydb") {
@@ -229,3 +477,147 @@ func TestUserInfo_AuthorizeDatabase(t *testing.T) {
 		t.Fatalf("expected admin to be authorized but it wasn't")
 	}
 }
+
+func TestData_MarshalJSON(t *testing.T) {
+	data := &meta.Data{
+		ClusterID: 100,
+		Databases: []meta.DatabaseInfo{{
+			Name:                   "db0",
+			DefaultRetentionPolicy: "rp0",
+			RetentionPolicies: []meta.RetentionPolicyInfo{{
+				Name:               "rp0",
+				ReplicaN:           1,
+				Duration:           7 * 24 * time.Hour,
+				ShardGroupDuration: 24 * time.Hour,
+				ShardGroups: []meta.ShardGroupInfo{{
+					ID:          1,
+					StartTime:   time.Unix(0, 0).UTC(),
+					EndTime:     time.Unix(86400, 0).UTC(),
+					TruncatedAt: time.Unix(3600, 0).UTC(),
+					Shards:      []meta.ShardInfo{{ID: 2, Owners: []meta.ShardOwner{{NodeID: 1}}}},
+				}},
+				Subscriptions: []meta.SubscriptionInfo{{Name: "sub0", Mode: "ANY", Destinations: []string{"udp://h:9090"}}},
+			}},
+			ContinuousQueries: []meta.ContinuousQueryInfo{{Name: "cq0", Query: "CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO cnt FROM cpu GROUP BY time(1h) END"}},
+		}},
+		Users: []meta.UserInfo{
+			{Name: "admin", Hash: "xxx", Admin: true},
+			{Name: "reader", Hash: "yyy", Privileges: map[string]influxql.Privilege{"db0": influxql.ReadPrivilege}},
+		},
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`"duration":"168h0m0s"`,
+		`"truncated_at":"1970-01-01T01:00:00Z"`,
+		`"owners":[1]`,
+		`"privileges":{"db0":"READ"}`,
+	} {
+		if !strings.Contains(string(b), s) {
+			t.Fatalf("expected %s in %s", s, b)
+		}
+	}
+
+	var other meta.Data
+	if err := json.Unmarshal(b, &other); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(other.Databases, data.Databases) {
+		t.Fatalf("unexpected databases:\ngot %+v\nexp %+v", other.Databases, data.Databases)
+	} else if !reflect.DeepEqual(other.Users, data.Users) {
+		t.Fatalf("unexpected users:\ngot %+v\nexp %+v", other.Users, data.Users)
+	} else if !other.AdminUserExists() {
+		t.Fatal("expected admin user to exist")
+	} else if other.MaxShardGroupID != 1 || other.MaxShardID != 2 {
+		t.Fatalf("unexpected max IDs: %d, %d", other.MaxShardGroupID, other.MaxShardID)
+	}
+
+	other.RedactHashes()
+	if b, err := json.Marshal(&other); err != nil {
+		t.Fatal(err)
+	} else if strings.Contains(string(b), `"hash"`) {
+		t.Fatalf("unexpected hash in %s", b)
+	}
+}
+
+func TestData_Import(t *testing.T) {
+	newData := func() *meta.Data {
+		return &meta.Data{
+			Databases: []meta.DatabaseInfo{{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{{
+					Name:        "rp0",
+					ShardGroups: []meta.ShardGroupInfo{{ID: 1, Shards: []meta.ShardInfo{{ID: 1}}}},
+				}},
+			}},
+			Users:           []meta.UserInfo{{Name: "u0", Hash: "xxx"}},
+			MaxShardGroupID: 1,
+			MaxShardID:      1,
+		}
+	}
+	other := &meta.Data{
+		Databases: []meta.DatabaseInfo{
+			{
+				Name:              "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "rp1", ShardGroups: []meta.ShardGroupInfo{{ID: 5, Shards: []meta.ShardInfo{{ID: 7}}}}}},
+				ContinuousQueries: []meta.ContinuousQueryInfo{{Name: "cq0"}},
+			},
+			{Name: "db1"},
+		},
+		Users: []meta.UserInfo{{Name: "u0", Admin: true}, {Name: "u1", Hash: "yyy"}},
+	}
+
+	t.Run("Merge", func(t *testing.T) {
+		data := newData()
+		if err := data.Import(other, false); err != nil {
+			t.Fatal(err)
+		}
+		if len(data.Databases) != 2 || len(data.Database("db0").RetentionPolicies) != 2 || len(data.Database("db0").ContinuousQueries) != 1 {
+			t.Fatalf("unexpected databases: %+v", data.Databases)
+		} else if len(data.Users) != 2 || data.Users[0].Admin || data.Users[0].Hash != "xxx" || data.Users[1].Hash != "yyy" {
+			t.Fatalf("unexpected users: %+v", data.Users)
+		} else if data.MaxShardGroupID != 5 || data.MaxShardID != 7 {
+			t.Fatalf("unexpected max IDs: %d, %d", data.MaxShardGroupID, data.MaxShardID)
+		}
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		data := newData()
+		data.DropShard(1)
+		if err := data.Import(other, true); err != nil {
+			t.Fatal(err)
+		}
+		if len(data.Databases) != 2 || data.Database("db0").RetentionPolicy("rp0") != nil {
+			t.Fatalf("unexpected databases: %+v", data.Databases)
+		} else if len(data.Users) != 2 || !data.Users[0].Admin || data.Users[0].Hash != "xxx" {
+			t.Fatalf("unexpected users: %+v", data.Users)
+		} else if !data.AdminUserExists() {
+			t.Fatal("expected admin user to exist")
+		}
+	})
+
+	t.Run("ErrReplaceDropsShard", func(t *testing.T) {
+		data := newData()
+		if err := data.Import(other, true); err == nil || err.Error() != "shard 1 of db0.rp0 is not in the imported data: drop it before replacing" {
+			t.Fatalf("unexpected error: %v", err)
+		} else if len(data.Databases) != 1 || data.Database("db0").RetentionPolicy("rp0") == nil {
+			t.Fatalf("unexpected databases after failed import: %+v", data.Databases)
+		}
+	})
+
+	t.Run("ErrDuplicateShard", func(t *testing.T) {
+		data := newData()
+		dup := &meta.Data{Databases: []meta.DatabaseInfo{{
+			Name:              "db1",
+			RetentionPolicies: []meta.RetentionPolicyInfo{{Name: "rp0", ShardGroups: []meta.ShardGroupInfo{{ID: 2, Shards: []meta.ShardInfo{{ID: 1}}}}}},
+		}}}
+		if err := data.Import(dup, false); err == nil || err.Error() != "shard 1 exists more than once" {
+			t.Fatalf("unexpected error: %v", err)
+		} else if len(data.Databases) != 1 {
+			t.Fatalf("unexpected databases after failed import: %+v", data.Databases)
+		}
+	})
+}
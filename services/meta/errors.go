@@ -25,6 +25,9 @@ var (
 
 	// ErrInvalidName is returned when attempting to create a database or retention policy with an invalid name
 	ErrInvalidName = errors.New("invalid name")
+
+	// ErrInvalidQuota is returned when setting a negative database quota.
+	ErrInvalidQuota = errors.New("quota must not be negative")
 )
 
 var (
@@ -74,6 +77,13 @@ var (
 	// ErrShardGroupNotFound is returned when mutating a shard group that doesn't exist.
 	ErrShardGroupNotFound = errors.New("shard group not found")
 
+	// ErrShardGroupOverlap is returned when moving or copying a shard group
+	// to a retention policy with a shard group in the same time range.
+	ErrShardGroupOverlap = errors.New("shard group overlaps a shard group of the retention policy")
+
+	// ErrShardNotFound is returned when mutating a shard that doesn't exist.
+	ErrShardNotFound = errors.New("shard not found")
+
 	// ErrShardNotReplicated is returned if the node requested to be dropped has
 	// the last copy of a shard present and the force keyword was not used
 	ErrShardNotReplicated = errors.New("shard not replicated")
@@ -87,6 +97,24 @@ var (
 	ErrContinuousQueryNotFound = errors.New("continuous query not found")
 )
 
+var (
+	// ErrMeasurementNameRequired is returned when creating a measurement
+	// schema without a measurement name.
+	ErrMeasurementNameRequired = errors.New("measurement name required")
+
+	// ErrMeasurementSchemaExists is returned when creating a measurement
+	// schema that differs from the existing schema of the measurement.
+	ErrMeasurementSchemaExists = errors.New("measurement schema already exists")
+
+	// ErrMeasurementSchemaNotFound is returned when removing a measurement
+	// schema that doesn't exist.
+	ErrMeasurementSchemaNotFound = errors.New("measurement schema not found")
+
+	// ErrMeasurementSchemaFieldsRequired is returned when creating a
+	// measurement schema without fields.
+	ErrMeasurementSchemaFieldsRequired = errors.New("measurement schema requires at least one field")
+)
+
 var (
 	// ErrSubscriptionExists is returned when creating an already existing subscription.
 	ErrSubscriptionExists = errors.New("subscription already exists")
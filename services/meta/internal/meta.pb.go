@@ -317,11 +317,15 @@ func (m *NodeInfo) GetTCPHost() string {
 }
 
 type DatabaseInfo struct {
-	Name                   *string                `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	DefaultRetentionPolicy *string                `protobuf:"bytes,2,req,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
-	RetentionPolicies      []*RetentionPolicyInfo `protobuf:"bytes,3,rep,name=RetentionPolicies" json:"RetentionPolicies,omitempty"`
-	ContinuousQueries      []*ContinuousQueryInfo `protobuf:"bytes,4,rep,name=ContinuousQueries" json:"ContinuousQueries,omitempty"`
-	XXX_unrecognized       []byte                 `json:"-"`
+	Name                   *string                  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	DefaultRetentionPolicy *string                  `protobuf:"bytes,2,req,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
+	RetentionPolicies      []*RetentionPolicyInfo   `protobuf:"bytes,3,rep,name=RetentionPolicies" json:"RetentionPolicies,omitempty"`
+	ContinuousQueries      []*ContinuousQueryInfo   `protobuf:"bytes,4,rep,name=ContinuousQueries" json:"ContinuousQueries,omitempty"`
+	MaxDiskBytes           *int64                   `protobuf:"varint,5,opt,name=MaxDiskBytes" json:"MaxDiskBytes,omitempty"`
+	MaxSeriesN             *int64                   `protobuf:"varint,6,opt,name=MaxSeriesN" json:"MaxSeriesN,omitempty"`
+	MeasurementSchemas     []*MeasurementSchemaInfo `protobuf:"bytes,7,rep,name=MeasurementSchemas" json:"MeasurementSchemas,omitempty"`
+	ReadOnly               *bool                    `protobuf:"varint,8,opt,name=ReadOnly" json:"ReadOnly,omitempty"`
+	XXX_unrecognized       []byte                   `json:"-"`
 }
 
 func (m *DatabaseInfo) Reset()                    { *m = DatabaseInfo{} }
@@ -357,6 +361,34 @@ func (m *DatabaseInfo) GetContinuousQueries() []*ContinuousQueryInfo {
 	return nil
 }
 
+func (m *DatabaseInfo) GetMaxDiskBytes() int64 {
+	if m != nil && m.MaxDiskBytes != nil {
+		return *m.MaxDiskBytes
+	}
+	return 0
+}
+
+func (m *DatabaseInfo) GetMaxSeriesN() int64 {
+	if m != nil && m.MaxSeriesN != nil {
+		return *m.MaxSeriesN
+	}
+	return 0
+}
+
+func (m *DatabaseInfo) GetMeasurementSchemas() []*MeasurementSchemaInfo {
+	if m != nil {
+		return m.MeasurementSchemas
+	}
+	return nil
+}
+
+func (m *DatabaseInfo) GetReadOnly() bool {
+	if m != nil && m.ReadOnly != nil {
+		return *m.ReadOnly
+	}
+	return false
+}
+
 type RetentionPolicySpec struct {
 	Name               *string `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
 	Duration           *int64  `protobuf:"varint,2,opt,name=Duration" json:"Duration,omitempty"`
@@ -405,6 +437,7 @@ type RetentionPolicyInfo struct {
 	ReplicaN           *uint32             `protobuf:"varint,4,req,name=ReplicaN" json:"ReplicaN,omitempty"`
 	ShardGroups        []*ShardGroupInfo   `protobuf:"bytes,5,rep,name=ShardGroups" json:"ShardGroups,omitempty"`
 	Subscriptions      []*SubscriptionInfo `protobuf:"bytes,6,rep,name=Subscriptions" json:"Subscriptions,omitempty"`
+	ReadOnly           *bool               `protobuf:"varint,7,opt,name=ReadOnly" json:"ReadOnly,omitempty"`
 	XXX_unrecognized   []byte              `json:"-"`
 }
 
@@ -455,6 +488,13 @@ func (m *RetentionPolicyInfo) GetSubscriptions() []*SubscriptionInfo {
 	return nil
 }
 
+func (m *RetentionPolicyInfo) GetReadOnly() bool {
+	if m != nil && m.ReadOnly != nil {
+		return *m.ReadOnly
+	}
+	return false
+}
+
 type ShardGroupInfo struct {
 	ID               *uint64      `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	StartTime        *int64       `protobuf:"varint,2,req,name=StartTime" json:"StartTime,omitempty"`
@@ -516,6 +556,7 @@ type ShardInfo struct {
 	ID               *uint64       `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	OwnerIDs         []uint64      `protobuf:"varint,2,rep,name=OwnerIDs" json:"OwnerIDs,omitempty"`
 	Owners           []*ShardOwner `protobuf:"bytes,3,rep,name=Owners" json:"Owners,omitempty"`
+	ReadOnly         *bool         `protobuf:"varint,4,opt,name=ReadOnly" json:"ReadOnly,omitempty"`
 	XXX_unrecognized []byte        `json:"-"`
 }
 
@@ -545,6 +586,13 @@ func (m *ShardInfo) GetOwners() []*ShardOwner {
 	return nil
 }
 
+func (m *ShardInfo) GetReadOnly() bool {
+	if m != nil && m.ReadOnly != nil {
+		return *m.ReadOnly
+	}
+	return false
+}
+
 type SubscriptionInfo struct {
 	Name             *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Mode             *string  `protobuf:"bytes,2,req,name=Mode" json:"Mode,omitempty"`
@@ -620,6 +668,78 @@ func (m *ContinuousQueryInfo) GetQuery() string {
 	return ""
 }
 
+type MeasurementSchemaInfo struct {
+	Name             *string            `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Mode             *string            `protobuf:"bytes,2,req,name=Mode" json:"Mode,omitempty"`
+	TagKeys          []string           `protobuf:"bytes,3,rep,name=TagKeys" json:"TagKeys,omitempty"`
+	RequiredTags     []string           `protobuf:"bytes,4,rep,name=RequiredTags" json:"RequiredTags,omitempty"`
+	Fields           []*FieldSchemaInfo `protobuf:"bytes,5,rep,name=Fields" json:"Fields,omitempty"`
+	XXX_unrecognized []byte             `json:"-"`
+}
+
+func (m *MeasurementSchemaInfo) Reset()         { *m = MeasurementSchemaInfo{} }
+func (m *MeasurementSchemaInfo) String() string { return proto.CompactTextString(m) }
+func (*MeasurementSchemaInfo) ProtoMessage()    {}
+
+func (m *MeasurementSchemaInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *MeasurementSchemaInfo) GetMode() string {
+	if m != nil && m.Mode != nil {
+		return *m.Mode
+	}
+	return ""
+}
+
+func (m *MeasurementSchemaInfo) GetTagKeys() []string {
+	if m != nil {
+		return m.TagKeys
+	}
+	return nil
+}
+
+func (m *MeasurementSchemaInfo) GetRequiredTags() []string {
+	if m != nil {
+		return m.RequiredTags
+	}
+	return nil
+}
+
+func (m *MeasurementSchemaInfo) GetFields() []*FieldSchemaInfo {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+type FieldSchemaInfo struct {
+	Name             *string `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Type             *string `protobuf:"bytes,2,req,name=Type" json:"Type,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *FieldSchemaInfo) Reset()         { *m = FieldSchemaInfo{} }
+func (m *FieldSchemaInfo) String() string { return proto.CompactTextString(m) }
+func (*FieldSchemaInfo) ProtoMessage()    {}
+
+func (m *FieldSchemaInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *FieldSchemaInfo) GetType() string {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return ""
+}
+
 type UserInfo struct {
 	Name             *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Hash             *string          `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
@@ -1797,6 +1917,8 @@ func init() {
 	proto.RegisterType((*SubscriptionInfo)(nil), "meta.SubscriptionInfo")
 	proto.RegisterType((*ShardOwner)(nil), "meta.ShardOwner")
 	proto.RegisterType((*ContinuousQueryInfo)(nil), "meta.ContinuousQueryInfo")
+	proto.RegisterType((*MeasurementSchemaInfo)(nil), "meta.MeasurementSchemaInfo")
+	proto.RegisterType((*FieldSchemaInfo)(nil), "meta.FieldSchemaInfo")
 	proto.RegisterType((*UserInfo)(nil), "meta.UserInfo")
 	proto.RegisterType((*UserPrivilege)(nil), "meta.UserPrivilege")
 	proto.RegisterType((*Command)(nil), "meta.Command")
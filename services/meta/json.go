@@ -0,0 +1,488 @@
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+)
+
+// The JSON representation of Data. Durations are formatted as Go durations,
+// times as RFC 3339 and privileges as in InfluxQL.
+type (
+	jsonData struct {
+		ClusterID       uint64         `json:"cluster_id"`
+		MaxShardGroupID uint64         `json:"max_shard_group_id"`
+		MaxShardID      uint64         `json:"max_shard_id"`
+		Databases       []jsonDatabase `json:"databases"`
+		Users           []jsonUser     `json:"users"`
+	}
+
+	jsonDatabase struct {
+		Name                   string                `json:"name"`
+		DefaultRetentionPolicy string                `json:"default_retention_policy"`
+		RetentionPolicies      []jsonRetentionPolicy `json:"retention_policies"`
+		ContinuousQueries      []jsonContinuousQuery `json:"continuous_queries"`
+		MaxDiskBytes           int64                 `json:"max_disk_bytes,omitempty"`
+		MaxSeriesN             int64                 `json:"max_series,omitempty"`
+		MeasurementSchemas     []jsonSchema          `json:"measurement_schemas,omitempty"`
+		ReadOnly               bool                  `json:"read_only,omitempty"`
+	}
+
+	jsonRetentionPolicy struct {
+		Name               string             `json:"name"`
+		Duration           string             `json:"duration"`
+		ShardGroupDuration string             `json:"shard_group_duration"`
+		Replication        int                `json:"replication"`
+		ShardGroups        []jsonShardGroup   `json:"shard_groups"`
+		Subscriptions      []jsonSubscription `json:"subscriptions"`
+		ReadOnly           bool               `json:"read_only,omitempty"`
+	}
+
+	jsonShardGroup struct {
+		ID          uint64      `json:"id"`
+		StartTime   time.Time   `json:"start_time"`
+		EndTime     time.Time   `json:"end_time"`
+		DeletedAt   *time.Time  `json:"deleted_at,omitempty"`
+		TruncatedAt *time.Time  `json:"truncated_at,omitempty"`
+		Shards      []jsonShard `json:"shards"`
+	}
+
+	jsonShard struct {
+		ID       uint64   `json:"id"`
+		Owners   []uint64 `json:"owners"`
+		ReadOnly bool     `json:"read_only,omitempty"`
+	}
+
+	jsonSubscription struct {
+		Name         string   `json:"name"`
+		Mode         string   `json:"mode"`
+		Destinations []string `json:"destinations"`
+	}
+
+	jsonContinuousQuery struct {
+		Name  string `json:"name"`
+		Query string `json:"query"`
+	}
+
+	jsonSchema struct {
+		Name         string      `json:"name"`
+		Mode         string      `json:"mode"`
+		TagKeys      []string    `json:"tag_keys,omitempty"`
+		RequiredTags []string    `json:"required_tags,omitempty"`
+		Fields       []jsonField `json:"fields"`
+	}
+
+	jsonField struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+
+	jsonUser struct {
+		Name       string            `json:"name"`
+		Hash       string            `json:"hash,omitempty"`
+		Admin      bool              `json:"admin"`
+		Privileges map[string]string `json:"privileges"`
+	}
+)
+
+// MarshalJSON encodes the metadata to JSON.
+func (data *Data) MarshalJSON() ([]byte, error) {
+	v := jsonData{
+		ClusterID:       data.ClusterID,
+		MaxShardGroupID: data.MaxShardGroupID,
+		MaxShardID:      data.MaxShardID,
+		Databases:       make([]jsonDatabase, 0, len(data.Databases)),
+		Users:           make([]jsonUser, 0, len(data.Users)),
+	}
+
+	for _, di := range data.Databases {
+		db := jsonDatabase{
+			Name:                   di.Name,
+			DefaultRetentionPolicy: di.DefaultRetentionPolicy,
+			RetentionPolicies:      make([]jsonRetentionPolicy, 0, len(di.RetentionPolicies)),
+			ContinuousQueries:      make([]jsonContinuousQuery, 0, len(di.ContinuousQueries)),
+			MaxDiskBytes:           di.MaxDiskBytes,
+			MaxSeriesN:             di.MaxSeriesN,
+			ReadOnly:               di.ReadOnly,
+		}
+		for _, rpi := range di.RetentionPolicies {
+			rp := jsonRetentionPolicy{
+				Name:               rpi.Name,
+				Duration:           rpi.Duration.String(),
+				ShardGroupDuration: rpi.ShardGroupDuration.String(),
+				Replication:        rpi.ReplicaN,
+				ShardGroups:        make([]jsonShardGroup, 0, len(rpi.ShardGroups)),
+				Subscriptions:      make([]jsonSubscription, 0, len(rpi.Subscriptions)),
+				ReadOnly:           rpi.ReadOnly,
+			}
+			for _, sgi := range rpi.ShardGroups {
+				sg := jsonShardGroup{
+					ID:        sgi.ID,
+					StartTime: sgi.StartTime.UTC(),
+					EndTime:   sgi.EndTime.UTC(),
+					Shards:    make([]jsonShard, 0, len(sgi.Shards)),
+				}
+				if sgi.Deleted() {
+					t := sgi.DeletedAt.UTC()
+					sg.DeletedAt = &t
+				}
+				if sgi.Truncated() {
+					t := sgi.TruncatedAt.UTC()
+					sg.TruncatedAt = &t
+				}
+				for _, si := range sgi.Shards {
+					sh := jsonShard{ID: si.ID, Owners: make([]uint64, 0, len(si.Owners)), ReadOnly: si.ReadOnly}
+					for _, so := range si.Owners {
+						sh.Owners = append(sh.Owners, so.NodeID)
+					}
+					sg.Shards = append(sg.Shards, sh)
+				}
+				rp.ShardGroups = append(rp.ShardGroups, sg)
+			}
+			for _, sub := range rpi.Subscriptions {
+				rp.Subscriptions = append(rp.Subscriptions, jsonSubscription(sub))
+			}
+			db.RetentionPolicies = append(db.RetentionPolicies, rp)
+		}
+		for _, cqi := range di.ContinuousQueries {
+			db.ContinuousQueries = append(db.ContinuousQueries, jsonContinuousQuery(cqi))
+		}
+		for _, msi := range di.MeasurementSchemas {
+			ms := jsonSchema{
+				Name:         msi.Name,
+				Mode:         msi.Mode,
+				TagKeys:      msi.TagKeys,
+				RequiredTags: msi.RequiredTags,
+				Fields:       make([]jsonField, 0, len(msi.Fields)),
+			}
+			for _, f := range msi.Fields {
+				ms.Fields = append(ms.Fields, jsonField{Name: f.Name, Type: f.Type.String()})
+			}
+			db.MeasurementSchemas = append(db.MeasurementSchemas, ms)
+		}
+		v.Databases = append(v.Databases, db)
+	}
+
+	for _, ui := range data.Users {
+		u := jsonUser{
+			Name:       ui.Name,
+			Hash:       ui.Hash,
+			Admin:      ui.Admin,
+			Privileges: make(map[string]string, len(ui.Privileges)),
+		}
+		for db, p := range ui.Privileges {
+			u.Privileges[db] = p.String()
+		}
+		v.Users = append(v.Users, u)
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes the metadata from JSON.
+func (data *Data) UnmarshalJSON(b []byte) error {
+	var v jsonData
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	other := Data{
+		ClusterID:       v.ClusterID,
+		MaxShardGroupID: v.MaxShardGroupID,
+		MaxShardID:      v.MaxShardID,
+	}
+
+	for _, db := range v.Databases {
+		if db.Name == "" {
+			return ErrDatabaseNameRequired
+		}
+		if db.MaxDiskBytes < 0 || db.MaxSeriesN < 0 {
+			return ErrInvalidQuota
+		}
+		di := DatabaseInfo{
+			Name:                   db.Name,
+			DefaultRetentionPolicy: db.DefaultRetentionPolicy,
+			MaxDiskBytes:           db.MaxDiskBytes,
+			MaxSeriesN:             db.MaxSeriesN,
+			ReadOnly:               db.ReadOnly,
+		}
+		for _, rp := range db.RetentionPolicies {
+			if rp.Name == "" {
+				return ErrRetentionPolicyNameRequired
+			}
+			rpi := RetentionPolicyInfo{Name: rp.Name, ReplicaN: rp.Replication, ReadOnly: rp.ReadOnly}
+
+			var err error
+			if rpi.Duration, err = time.ParseDuration(rp.Duration); err != nil {
+				return fmt.Errorf("retention policy %q on database %q: invalid duration: %s", rp.Name, db.Name, err)
+			}
+			if rpi.ShardGroupDuration, err = time.ParseDuration(rp.ShardGroupDuration); err != nil {
+				return fmt.Errorf("retention policy %q on database %q: invalid shard group duration: %s", rp.Name, db.Name, err)
+			}
+
+			for _, sg := range rp.ShardGroups {
+				sgi := ShardGroupInfo{ID: sg.ID, StartTime: sg.StartTime, EndTime: sg.EndTime}
+				if sg.DeletedAt != nil {
+					sgi.DeletedAt = *sg.DeletedAt
+				}
+				if sg.TruncatedAt != nil {
+					sgi.TruncatedAt = *sg.TruncatedAt
+				}
+				for _, sh := range sg.Shards {
+					si := ShardInfo{ID: sh.ID, ReadOnly: sh.ReadOnly}
+					for _, id := range sh.Owners {
+						si.Owners = append(si.Owners, ShardOwner{NodeID: id})
+					}
+					sgi.Shards = append(sgi.Shards, si)
+				}
+				rpi.ShardGroups = append(rpi.ShardGroups, sgi)
+			}
+			for _, sub := range rp.Subscriptions {
+				rpi.Subscriptions = append(rpi.Subscriptions, SubscriptionInfo(sub))
+			}
+			di.RetentionPolicies = append(di.RetentionPolicies, rpi)
+		}
+		for _, cq := range db.ContinuousQueries {
+			di.ContinuousQueries = append(di.ContinuousQueries, ContinuousQueryInfo(cq))
+		}
+		for _, ms := range db.MeasurementSchemas {
+			msi := MeasurementSchemaInfo{Name: ms.Name, Mode: ms.Mode, TagKeys: ms.TagKeys, RequiredTags: ms.RequiredTags}
+			for _, f := range ms.Fields {
+				msi.Fields = append(msi.Fields, FieldSchemaInfo{Name: f.Name, Type: parseFieldType(f.Type)})
+			}
+			if err := msi.validate(); err != nil {
+				return fmt.Errorf("measurement schema %q on database %q: %s", ms.Name, db.Name, err)
+			}
+			di.MeasurementSchemas = append(di.MeasurementSchemas, msi)
+		}
+		other.Databases = append(other.Databases, di)
+	}
+
+	for _, u := range v.Users {
+		if u.Name == "" {
+			return ErrUsernameRequired
+		}
+		ui := UserInfo{Name: u.Name, Hash: u.Hash, Admin: u.Admin}
+		if len(u.Privileges) > 0 {
+			ui.Privileges = make(map[string]influxql.Privilege, len(u.Privileges))
+		}
+		for db, s := range u.Privileges {
+			p, err := parsePrivilege(s)
+			if err != nil {
+				return fmt.Errorf("user %q on database %q: %s", u.Name, db, err)
+			}
+			ui.Privileges[db] = p
+		}
+		other.Users = append(other.Users, ui)
+	}
+
+	if err := other.checkIDs(); err != nil {
+		return err
+	}
+	other.adminUserExists = other.hasAdminUser()
+
+	*data = other
+	return nil
+}
+
+// parsePrivilege returns the privilege formatted as s by Privilege.String.
+func parsePrivilege(s string) (influxql.Privilege, error) {
+	for _, p := range []influxql.Privilege{influxql.NoPrivileges, influxql.ReadPrivilege, influxql.WritePrivilege, influxql.AllPrivileges} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown privilege %q", s)
+}
+
+// RedactHashes removes the password hashes of the users.
+func (data *Data) RedactHashes() {
+	for i := range data.Users {
+		data.Users[i].Hash = ""
+	}
+}
+
+// Import imports the databases and users of other. With replace, the
+// databases and users are replaced by those of other. Otherwise the databases,
+// retention policies, shard groups, continuous queries, subscriptions and
+// users of other that do not exist are added, and existing ones are left
+// unchanged. Users of other without a password hash keep the hash of the
+// existing user of the same name. An error is returned, and data is left
+// unchanged, if shard groups or shards of different retention policies would
+// have the same ID.
+//
+// Replace refuses to drop shards: the files of a shard of data are not
+// removed from the TSDB store by the import, so each shard of data must be
+// in other, in the same database and retention policy. Shards to drop must
+// be dropped first, with DROP SHARD, DROP RETENTION POLICY or DROP DATABASE.
+func (data *Data) Import(other *Data, replace bool) error {
+	other = other.Clone()
+	d := data.Clone()
+
+	if replace {
+		if err := d.checkShardsKept(other); err != nil {
+			return err
+		}
+		for i := range other.Users {
+			if ui := d.user(other.Users[i].Name); ui != nil && other.Users[i].Hash == "" {
+				other.Users[i].Hash = ui.Hash
+			}
+		}
+		d.Databases, d.Users = other.Databases, other.Users
+	} else {
+		for _, odi := range other.Databases {
+			di := d.Database(odi.Name)
+			if di == nil {
+				d.Databases = append(d.Databases, odi)
+				continue
+			}
+
+			for _, orpi := range odi.RetentionPolicies {
+				rpi := di.RetentionPolicy(orpi.Name)
+				if rpi == nil {
+					di.RetentionPolicies = append(di.RetentionPolicies, orpi)
+					continue
+				}
+				for _, sgi := range orpi.ShardGroups {
+					if !rpi.hasShardGroup(sgi.ID) {
+						rpi.ShardGroups = append(rpi.ShardGroups, sgi)
+					}
+				}
+				sort.Sort(ShardGroupInfos(rpi.ShardGroups))
+				for _, sub := range orpi.Subscriptions {
+					if !rpi.hasSubscription(sub.Name) {
+						rpi.Subscriptions = append(rpi.Subscriptions, sub)
+					}
+				}
+			}
+			if di.DefaultRetentionPolicy == "" {
+				di.DefaultRetentionPolicy = odi.DefaultRetentionPolicy
+			}
+
+			for _, cqi := range odi.ContinuousQueries {
+				if !di.hasContinuousQuery(cqi.Name) {
+					di.ContinuousQueries = append(di.ContinuousQueries, cqi)
+				}
+			}
+
+			for _, msi := range odi.MeasurementSchemas {
+				if di.MeasurementSchema(msi.Name) == nil {
+					di.MeasurementSchemas = append(di.MeasurementSchemas, msi)
+				}
+			}
+		}
+
+		for _, ui := range other.Users {
+			if d.user(ui.Name) == nil {
+				d.Users = append(d.Users, ui)
+			}
+		}
+	}
+
+	if err := d.checkIDs(); err != nil {
+		return err
+	}
+	if other.MaxShardGroupID > d.MaxShardGroupID {
+		d.MaxShardGroupID = other.MaxShardGroupID
+	}
+	if other.MaxShardID > d.MaxShardID {
+		d.MaxShardID = other.MaxShardID
+	}
+	d.adminUserExists = d.hasAdminUser()
+
+	*data = *d
+	return nil
+}
+
+// checkIDs returns an error if shard groups or shards have the same ID, and
+// raises the maximum shard group and shard IDs to the largest IDs in use.
+func (data *Data) checkIDs() error {
+	groups := make(map[uint64]bool)
+	shards := make(map[uint64]bool)
+	for _, di := range data.Databases {
+		for _, rpi := range di.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if groups[sgi.ID] {
+					return fmt.Errorf("shard group %d exists more than once", sgi.ID)
+				}
+				groups[sgi.ID] = true
+				if sgi.ID > data.MaxShardGroupID {
+					data.MaxShardGroupID = sgi.ID
+				}
+
+				for _, si := range sgi.Shards {
+					if shards[si.ID] {
+						return fmt.Errorf("shard %d exists more than once", si.ID)
+					}
+					shards[si.ID] = true
+					if si.ID > data.MaxShardID {
+						data.MaxShardID = si.ID
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkShardsKept returns an error if a shard of data is not in other, in the
+// same database and retention policy.
+func (data *Data) checkShardsKept(other *Data) error {
+	for _, di := range data.Databases {
+		for _, rpi := range di.RetentionPolicies {
+			var orpi *RetentionPolicyInfo
+			if odi := other.Database(di.Name); odi != nil {
+				orpi = odi.RetentionPolicy(rpi.Name)
+			}
+			for _, sgi := range rpi.ShardGroups {
+				for _, si := range sgi.Shards {
+					if orpi == nil || !orpi.hasShard(si.ID) {
+						return fmt.Errorf("shard %d of %s.%s is not in the imported data: drop it before replacing", si.ID, di.Name, rpi.Name)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (rpi *RetentionPolicyInfo) hasShard(id uint64) bool {
+	for _, sgi := range rpi.ShardGroups {
+		for _, si := range sgi.Shards {
+			if si.ID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (rpi *RetentionPolicyInfo) hasShardGroup(id uint64) bool {
+	for _, sgi := range rpi.ShardGroups {
+		if sgi.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (rpi *RetentionPolicyInfo) hasSubscription(name string) bool {
+	for _, sub := range rpi.Subscriptions {
+		if sub.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (di *DatabaseInfo) hasContinuousQuery(name string) bool {
+	for _, cqi := range di.ContinuousQueries {
+		if cqi.Name == name {
+			return true
+		}
+	}
+	return false
+}
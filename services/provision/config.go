@@ -0,0 +1,43 @@
+package provision
+
+import (
+	"fmt"
+
+	"github.com/influxdata/influxdb/monitor/diagnostics"
+)
+
+// Config represents the configuration for the provisioning service.
+type Config struct {
+	// File is the path of the provisioning file. No provisioning is done if empty.
+	File string `toml:"file"`
+
+	// DryRun logs the statements required to reconcile the meta store with
+	// the provisioning file instead of executing them. The statements are
+	// only logged by a running server.
+	DryRun bool `toml:"dry-run"`
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{}
+}
+
+// Validate returns an error if the Config is invalid. The provisioning file,
+// if set, is read and validated.
+func (c Config) Validate() error {
+	if c.File == "" {
+		return nil
+	}
+	if _, err := ParseFile(c.File); err != nil {
+		return fmt.Errorf("invalid provisioning file: %s", err)
+	}
+	return nil
+}
+
+// Diagnostics returns a diagnostics representation of a subset of the Config.
+func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"file":    c.File,
+		"dry-run": c.DryRun,
+	}), nil
+}
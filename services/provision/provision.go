@@ -0,0 +1,433 @@
+// Package provision reconciles the databases, retention policies, continuous
+// queries, subscriptions and users of the meta store with a provisioning file.
+package provision // import "github.com/influxdata/influxdb/services/provision"
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/services/meta"
+)
+
+// File represents the content of a provisioning file.
+type File struct {
+	Databases []Database `toml:"database"`
+	Users     []User     `toml:"user"`
+}
+
+// Database describes a database and the objects it contains.
+type Database struct {
+	Name string `toml:"name"`
+
+	// ContinuousQueries are CREATE CONTINUOUS QUERY statements on the database.
+	ContinuousQueries []string `toml:"continuous-queries"`
+
+	RetentionPolicies []RetentionPolicy `toml:"retention-policy"`
+	Subscriptions     []Subscription    `toml:"subscription"`
+}
+
+// RetentionPolicy describes a retention policy. Durations use the InfluxQL
+// duration syntax, with "INF" as an infinite duration. Unset settings are left
+// unchanged on an existing retention policy.
+type RetentionPolicy struct {
+	Name          string `toml:"name"`
+	Duration      string `toml:"duration"`
+	ShardDuration string `toml:"shard-duration"`
+	Replication   int    `toml:"replication"`
+	Default       bool   `toml:"default"`
+}
+
+// Subscription describes a subscription on a retention policy.
+type Subscription struct {
+	Name            string   `toml:"name"`
+	RetentionPolicy string   `toml:"retention-policy"`
+	Mode            string   `toml:"mode"`
+	Destinations    []string `toml:"destinations"`
+}
+
+// User describes a user. The password is only used when creating the user.
+// Privileges map database names to READ, WRITE, ALL or NONE.
+type User struct {
+	Name       string            `toml:"name"`
+	Password   string            `toml:"password"`
+	Admin      bool              `toml:"admin"`
+	Privileges map[string]string `toml:"privileges"`
+}
+
+// ParseFile reads and validates the provisioning file at path.
+func ParseFile(path string) (*File, error) {
+	var f File
+	md, err := toml.DecodeFile(path, &f)
+	if err != nil {
+		return nil, err
+	}
+	if keys := md.Undecoded(); len(keys) > 0 {
+		return nil, fmt.Errorf("unknown setting %q", keys[0].String())
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Validate returns an error if the file is invalid.
+func (f *File) Validate() error {
+	dbs := make(map[string]bool)
+	for _, db := range f.Databases {
+		if db.Name == "" {
+			return errors.New("database name required")
+		} else if dbs[db.Name] {
+			return fmt.Errorf("database %q provisioned more than once", db.Name)
+		}
+		dbs[db.Name] = true
+
+		rps := make(map[string]bool)
+		var defaultRP string
+		for _, rp := range db.RetentionPolicies {
+			if rp.Name == "" {
+				return fmt.Errorf("retention policy name required on database %q", db.Name)
+			} else if rps[rp.Name] {
+				return fmt.Errorf("retention policy %q provisioned more than once on database %q", rp.Name, db.Name)
+			}
+			rps[rp.Name] = true
+
+			if rp.Default {
+				if defaultRP != "" {
+					return fmt.Errorf("retention policies %q and %q are both default on database %q", defaultRP, rp.Name, db.Name)
+				}
+				defaultRP = rp.Name
+			}
+			if _, _, err := rp.durations(); err != nil {
+				return fmt.Errorf("retention policy %q on database %q: %s", rp.Name, db.Name, err)
+			}
+			if rp.Replication < 0 {
+				return fmt.Errorf("retention policy %q on database %q: replication must be positive", rp.Name, db.Name)
+			}
+		}
+
+		for _, s := range db.ContinuousQueries {
+			if _, err := continuousQuery(db.Name, s); err != nil {
+				return err
+			}
+		}
+
+		subs := make(map[string]bool)
+		for _, sub := range db.Subscriptions {
+			if sub.Name == "" || sub.RetentionPolicy == "" {
+				return fmt.Errorf("subscription name and retention policy required on database %q", db.Name)
+			}
+			key := sub.RetentionPolicy + "." + sub.Name
+			if subs[key] {
+				return fmt.Errorf("subscription %q provisioned more than once on %q.%q", sub.Name, db.Name, sub.RetentionPolicy)
+			}
+			subs[key] = true
+
+			if m := sub.mode(); m != "ANY" && m != "ALL" {
+				return fmt.Errorf("subscription %q: unknown mode %q", sub.Name, sub.Mode)
+			} else if len(sub.Destinations) == 0 {
+				return fmt.Errorf("subscription %q: destinations required", sub.Name)
+			}
+		}
+	}
+
+	users := make(map[string]bool)
+	for _, u := range f.Users {
+		if u.Name == "" {
+			return errors.New("user name required")
+		} else if users[u.Name] {
+			return fmt.Errorf("user %q provisioned more than once", u.Name)
+		}
+		users[u.Name] = true
+
+		for db, p := range u.Privileges {
+			if _, err := parsePrivilege(p); err != nil {
+				return fmt.Errorf("user %q on database %q: %s", u.Name, db, err)
+			}
+		}
+	}
+	return nil
+}
+
+// durations returns the parsed duration and shard duration of rp, which are
+// nil when not set.
+func (rp RetentionPolicy) durations() (d, sgd *time.Duration, err error) {
+	if rp.Duration != "" {
+		v, err := parseDuration(rp.Duration)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid duration: %s", err)
+		} else if v != 0 && v < meta.MinRetentionPolicyDuration {
+			return nil, nil, fmt.Errorf("duration must be at least %s", meta.MinRetentionPolicyDuration)
+		}
+		d = &v
+	}
+	if rp.ShardDuration != "" {
+		v, err := parseDuration(rp.ShardDuration)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid shard-duration: %s", err)
+		} else if v < meta.MinRetentionPolicyDuration {
+			return nil, nil, fmt.Errorf("shard-duration must be at least %s", meta.MinRetentionPolicyDuration)
+		}
+		sgd = &v
+	}
+	return d, sgd, nil
+}
+
+// mode returns the upper-cased mode of the subscription, ANY if not set.
+func (s Subscription) mode() string {
+	if s.Mode == "" {
+		return "ANY"
+	}
+	return strings.ToUpper(s.Mode)
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if strings.ToUpper(s) == "INF" {
+		return 0, nil
+	}
+	return influxql.ParseDuration(s)
+}
+
+func parsePrivilege(s string) (influxql.Privilege, error) {
+	switch strings.ToUpper(s) {
+	case "READ":
+		return influxql.ReadPrivilege, nil
+	case "WRITE":
+		return influxql.WritePrivilege, nil
+	case "ALL":
+		return influxql.AllPrivileges, nil
+	case "NONE":
+		return influxql.NoPrivileges, nil
+	default:
+		return 0, fmt.Errorf("unknown privilege %q", s)
+	}
+}
+
+// continuousQuery parses s as a CREATE CONTINUOUS QUERY statement on database.
+func continuousQuery(database, s string) (*influxql.CreateContinuousQueryStatement, error) {
+	stmt, err := influxql.ParseStatement(s)
+	if err != nil {
+		return nil, fmt.Errorf("continuous query on database %q: %s", database, err)
+	}
+	cq, ok := stmt.(*influxql.CreateContinuousQueryStatement)
+	if !ok {
+		return nil, fmt.Errorf("continuous query on database %q: not a CREATE CONTINUOUS QUERY statement: %s", database, s)
+	} else if cq.Database != database {
+		return nil, fmt.Errorf("continuous query %q is on database %q, not %q", cq.Name, cq.Database, database)
+	}
+	return cq, nil
+}
+
+// Plan returns the statements that reconcile the databases and users of the
+// meta store with f. Objects of the meta store not described by f are left
+// unchanged. Continuous queries and subscriptions that differ from f are
+// dropped and created again. Planning against the result of executing the
+// statements returns no statements.
+func Plan(f *File, dbs []meta.DatabaseInfo, users []meta.UserInfo) ([]influxql.Statement, error) {
+	var stmts []influxql.Statement
+	for _, db := range f.Databases {
+		var di *meta.DatabaseInfo
+		for i := range dbs {
+			if dbs[i].Name == db.Name {
+				di = &dbs[i]
+				break
+			}
+		}
+		if di == nil {
+			di = &meta.DatabaseInfo{Name: db.Name}
+			stmts = append(stmts, &influxql.CreateDatabaseStatement{Name: db.Name})
+		}
+
+		for _, rp := range db.RetentionPolicies {
+			s, err := planRetentionPolicy(di, rp)
+			if err != nil {
+				return nil, err
+			} else if s != nil {
+				stmts = append(stmts, s)
+			}
+		}
+
+		defaultRP := di.DefaultRetentionPolicy
+		for _, rp := range db.RetentionPolicies {
+			if rp.Default {
+				defaultRP = rp.Name
+			}
+		}
+
+		for _, s := range db.ContinuousQueries {
+			cq, err := continuousQuery(db.Name, s)
+			if err != nil {
+				return nil, err
+			}
+			normalizeContinuousQuery(cq, defaultRP)
+
+			var cqi *meta.ContinuousQueryInfo
+			for i := range di.ContinuousQueries {
+				if di.ContinuousQueries[i].Name == cq.Name {
+					cqi = &di.ContinuousQueries[i]
+					break
+				}
+			}
+			if cqi != nil && cqi.Query == cq.String() {
+				continue
+			} else if cqi != nil {
+				stmts = append(stmts, &influxql.DropContinuousQueryStatement{Name: cq.Name, Database: db.Name})
+			}
+			stmts = append(stmts, cq)
+		}
+
+		for _, sub := range db.Subscriptions {
+			var si *meta.SubscriptionInfo
+			if rpi := di.RetentionPolicy(sub.RetentionPolicy); rpi != nil {
+				for i := range rpi.Subscriptions {
+					if rpi.Subscriptions[i].Name == sub.Name {
+						si = &rpi.Subscriptions[i]
+						break
+					}
+				}
+			}
+			if si != nil && si.Mode == sub.mode() && reflect.DeepEqual(si.Destinations, sub.Destinations) {
+				continue
+			} else if si != nil {
+				stmts = append(stmts, &influxql.DropSubscriptionStatement{
+					Name:            sub.Name,
+					Database:        db.Name,
+					RetentionPolicy: sub.RetentionPolicy,
+				})
+			}
+			stmts = append(stmts, &influxql.CreateSubscriptionStatement{
+				Name:            sub.Name,
+				Database:        db.Name,
+				RetentionPolicy: sub.RetentionPolicy,
+				Destinations:    sub.Destinations,
+				Mode:            sub.mode(),
+			})
+		}
+	}
+
+	for _, u := range f.Users {
+		s, err := planUser(u, users)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s...)
+	}
+	return stmts, nil
+}
+
+// normalizeContinuousQuery qualifies the measurements of cq with its database
+// and defaultRP, as done when executing the statement, so that it compares
+// equal to the query stored in the meta store.
+func normalizeContinuousQuery(cq *influxql.CreateContinuousQueryStatement, defaultRP string) {
+	influxql.WalkFunc(cq, func(n influxql.Node) {
+		if m, ok := n.(*influxql.Measurement); ok {
+			if m.Database == "" {
+				m.Database = cq.Database
+			}
+			if m.RetentionPolicy == "" {
+				m.RetentionPolicy = defaultRP
+			}
+		}
+	})
+}
+
+// planRetentionPolicy returns the statement creating or altering rp on di, or
+// nil if the retention policy is up to date.
+func planRetentionPolicy(di *meta.DatabaseInfo, rp RetentionPolicy) (influxql.Statement, error) {
+	d, sgd, err := rp.durations()
+	if err != nil {
+		return nil, fmt.Errorf("retention policy %q on database %q: %s", rp.Name, di.Name, err)
+	}
+
+	rpi := di.RetentionPolicy(rp.Name)
+	if rpi == nil {
+		stmt := &influxql.CreateRetentionPolicyStatement{
+			Name:        rp.Name,
+			Database:    di.Name,
+			Replication: 1,
+			Default:     rp.Default,
+		}
+		if d != nil {
+			stmt.Duration = *d
+		}
+		if sgd != nil {
+			stmt.ShardGroupDuration = *sgd
+		}
+		if rp.Replication > 0 {
+			stmt.Replication = rp.Replication
+		}
+		return stmt, nil
+	}
+
+	stmt := &influxql.AlterRetentionPolicyStatement{Name: rp.Name, Database: di.Name}
+	changed := false
+	if d != nil && *d != rpi.Duration {
+		stmt.Duration, changed = d, true
+	}
+	if sgd != nil && *sgd != rpi.ShardGroupDuration {
+		stmt.ShardGroupDuration, changed = sgd, true
+	}
+	if rp.Replication > 0 && rp.Replication != rpi.ReplicaN {
+		stmt.Replication, changed = &rp.Replication, true
+	}
+	if rp.Default && di.DefaultRetentionPolicy != rp.Name {
+		stmt.Default, changed = true, true
+	}
+	if !changed {
+		return nil, nil
+	}
+	return stmt, nil
+}
+
+// planUser returns the statements creating u or updating its privileges.
+func planUser(u User, users []meta.UserInfo) ([]influxql.Statement, error) {
+	var ui *meta.UserInfo
+	for i := range users {
+		if users[i].Name == u.Name {
+			ui = &users[i]
+			break
+		}
+	}
+
+	var stmts []influxql.Statement
+	if ui == nil {
+		if u.Password == "" {
+			return nil, fmt.Errorf("password required to create user %q", u.Name)
+		}
+		ui = &meta.UserInfo{Name: u.Name, Admin: u.Admin}
+		stmts = append(stmts, &influxql.CreateUserStatement{Name: u.Name, Password: u.Password, Admin: u.Admin})
+	} else if ui.Admin != u.Admin {
+		if u.Admin {
+			stmts = append(stmts, &influxql.GrantAdminStatement{User: u.Name})
+		} else {
+			stmts = append(stmts, &influxql.RevokeAdminStatement{User: u.Name})
+		}
+	}
+
+	dbs := make([]string, 0, len(u.Privileges))
+	for db := range u.Privileges {
+		dbs = append(dbs, db)
+	}
+	sort.Strings(dbs)
+
+	for _, db := range dbs {
+		p, err := parsePrivilege(u.Privileges[db])
+		if err != nil {
+			return nil, fmt.Errorf("user %q on database %q: %s", u.Name, db, err)
+		} else if ui.Privileges[db] == p {
+			continue
+		}
+
+		if p == influxql.NoPrivileges {
+			stmts = append(stmts, &influxql.RevokeStatement{Privilege: influxql.AllPrivileges, On: db, User: u.Name})
+		} else {
+			stmts = append(stmts, &influxql.GrantStatement{Privilege: p, On: db, User: u.Name})
+		}
+	}
+	return stmts, nil
+}
@@ -0,0 +1,267 @@
+package provision_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/services/provision"
+)
+
+const testFile = `
+[[database]]
+  name = "db0"
+  continuous-queries = [
+    "CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT mean(value) INTO rp1.cpu_1h FROM cpu GROUP BY time(1h) END",
+  ]
+
+  [[database.retention-policy]]
+    name = "rp0"
+    duration = "14d"
+    shard-duration = "1d"
+    default = true
+
+  [[database.retention-policy]]
+    name = "rp1"
+    duration = "INF"
+
+  [[database.subscription]]
+    name = "sub0"
+    retention-policy = "rp0"
+    destinations = ["udp://localhost:9090"]
+
+[[user]]
+  name = "admin"
+  password = "secret"
+  admin = true
+
+[[user]]
+  name = "writer"
+  password = "secret"
+  privileges = { db0 = "write" }
+`
+
+func TestParseFile(t *testing.T) {
+	path := MustWriteFile(testFile)
+	defer os.Remove(path)
+
+	f, err := provision.ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.Databases) != 1 || len(f.Users) != 2 {
+		t.Fatalf("unexpected file: %+v", f)
+	}
+	db := f.Databases[0]
+	if db.Name != "db0" || len(db.RetentionPolicies) != 2 || len(db.ContinuousQueries) != 1 || len(db.Subscriptions) != 1 {
+		t.Fatalf("unexpected database: %+v", db)
+	} else if rp := db.RetentionPolicies[0]; rp.Name != "rp0" || rp.Duration != "14d" || rp.ShardDuration != "1d" || !rp.Default {
+		t.Fatalf("unexpected retention policy: %+v", rp)
+	} else if u := f.Users[1]; u.Name != "writer" || u.Admin || u.Privileges["db0"] != "write" {
+		t.Fatalf("unexpected user: %+v", u)
+	}
+}
+
+func TestParseFile_Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		s   string
+		err string
+	}{
+		{s: `[[database]]`, err: `database name required`},
+		{s: `[[database]]
+name = "db0"
+[[database]]
+name = "db0"`, err: `database "db0" provisioned more than once`},
+		{s: `[[database]]
+name = "db0"
+retention = "1d"`, err: `unknown setting "database.retention"`},
+		{s: `[[database]]
+name = "db0"
+[[database.retention-policy]]
+name = "rp0"
+duration = "10m"`, err: `retention policy "rp0" on database "db0": duration must be at least 1h0m0s`},
+		{s: `[[database]]
+name = "db0"
+[[database.retention-policy]]
+name = "rp0"
+default = true
+[[database.retention-policy]]
+name = "rp1"
+default = true`, err: `retention policies "rp0" and "rp1" are both default on database "db0"`},
+		{s: `[[database]]
+name = "db0"
+continuous-queries = ["SELECT * FROM cpu"]`, err: `continuous query on database "db0": not a CREATE CONTINUOUS QUERY statement: SELECT * FROM cpu`},
+		{s: `[[database]]
+name = "db0"
+continuous-queries = ["CREATE CONTINUOUS QUERY cq0 ON db1 BEGIN SELECT mean(value) INTO cpu_1h FROM cpu GROUP BY time(1h) END"]`, err: `continuous query "cq0" is on database "db1", not "db0"`},
+		{s: `[[database]]
+name = "db0"
+[[database.subscription]]
+name = "sub0"
+retention-policy = "rp0"
+mode = "SOME"
+destinations = ["udp://localhost:9090"]`, err: `subscription "sub0": unknown mode "SOME"`},
+		{s: `[[user]]
+name = "u"
+privileges = { db0 = "admin" }`, err: `user "u" on database "db0": unknown privilege "admin"`},
+	} {
+		path := MustWriteFile(tt.s)
+		_, err := provision.ParseFile(path)
+		os.Remove(path)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("unexpected error for %q: got %v, exp %s", tt.s, err, tt.err)
+		}
+	}
+}
+
+func TestPlan_Create(t *testing.T) {
+	f := MustParseFile(testFile)
+
+	stmts, err := provision.Plan(f, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{
+		`CREATE DATABASE db0`,
+		`CREATE RETENTION POLICY rp0 ON db0 DURATION 2w REPLICATION 1 SHARD DURATION 1d DEFAULT`,
+		`CREATE RETENTION POLICY rp1 ON db0 DURATION 0s REPLICATION 1`,
+		`CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT mean(value) INTO db0.rp1.cpu_1h FROM db0.rp0.cpu GROUP BY time(1h) END`,
+		`CREATE SUBSCRIPTION sub0 ON db0.rp0 DESTINATIONS ANY 'udp://localhost:9090'`,
+		`CREATE USER admin WITH PASSWORD [REDACTED] WITH ALL PRIVILEGES`,
+		`CREATE USER writer WITH PASSWORD [REDACTED]`,
+		`GRANT WRITE ON db0 TO writer`,
+	}
+	if got := statements(stmts); strings.Join(got, "\n") != strings.Join(exp, "\n") {
+		t.Fatalf("unexpected statements:\n%s\n\nexp:\n%s", strings.Join(got, "\n"), strings.Join(exp, "\n"))
+	}
+}
+
+func TestPlan_UpToDate(t *testing.T) {
+	f := MustParseFile(testFile)
+
+	stmts, err := provision.Plan(f, provisionedDatabases(), provisionedUsers())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(stmts) != 0 {
+		t.Fatalf("unexpected statements: %v", statements(stmts))
+	}
+}
+
+func TestPlan_Update(t *testing.T) {
+	f := MustParseFile(testFile)
+
+	dbs := provisionedDatabases()
+	dbs[0].DefaultRetentionPolicy = "autogen"
+	dbs[0].RetentionPolicies[0].Duration = 7 * 24 * time.Hour
+	dbs[0].RetentionPolicies = dbs[0].RetentionPolicies[:2]
+	dbs[0].RetentionPolicies[0].Subscriptions[0].Destinations = []string{"udp://localhost:9091"}
+	dbs[0].ContinuousQueries[0].Query = "CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT max(value) INTO db0.rp1.cpu_1h FROM db0.rp0.cpu GROUP BY time(1h) END"
+
+	users := provisionedUsers()
+	users[0].Admin = false
+	users[1].Privileges["db0"] = influxql.ReadPrivilege
+	users[1].Privileges["db1"] = influxql.AllPrivileges
+
+	stmts, err := provision.Plan(f, dbs, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{
+		`ALTER RETENTION POLICY rp0 ON db0 DURATION 2w DEFAULT`,
+		`DROP CONTINUOUS QUERY cq0 ON db0`,
+		`CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT mean(value) INTO db0.rp1.cpu_1h FROM db0.rp0.cpu GROUP BY time(1h) END`,
+		`DROP SUBSCRIPTION sub0 ON db0.rp0`,
+		`CREATE SUBSCRIPTION sub0 ON db0.rp0 DESTINATIONS ANY 'udp://localhost:9090'`,
+		`GRANT ALL PRIVILEGES TO admin`,
+		`GRANT WRITE ON db0 TO writer`,
+	}
+	if got := statements(stmts); strings.Join(got, "\n") != strings.Join(exp, "\n") {
+		t.Fatalf("unexpected statements:\n%s\n\nexp:\n%s", strings.Join(got, "\n"), strings.Join(exp, "\n"))
+	}
+}
+
+func TestPlan_ErrPasswordRequired(t *testing.T) {
+	f := &provision.File{Users: []provision.User{{Name: "u"}}}
+
+	if _, err := provision.Plan(f, nil, nil); err == nil || err.Error() != `password required to create user "u"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stmts, err := provision.Plan(f, nil, []meta.UserInfo{{Name: "u"}}); err != nil {
+		t.Fatal(err)
+	} else if len(stmts) != 0 {
+		t.Fatalf("unexpected statements: %v", statements(stmts))
+	}
+}
+
+// provisionedDatabases returns the databases of the meta store after
+// provisioning testFile.
+func provisionedDatabases() []meta.DatabaseInfo {
+	return []meta.DatabaseInfo{{
+		Name:                   "db0",
+		DefaultRetentionPolicy: "rp0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{
+			{
+				Name:               "rp0",
+				ReplicaN:           1,
+				Duration:           14 * 24 * time.Hour,
+				ShardGroupDuration: 24 * time.Hour,
+				Subscriptions: []meta.SubscriptionInfo{
+					{Name: "sub0", Mode: "ANY", Destinations: []string{"udp://localhost:9090"}},
+				},
+			},
+			{Name: "rp1", ReplicaN: 1, ShardGroupDuration: 7 * 24 * time.Hour},
+			{Name: "autogen", ReplicaN: 1, ShardGroupDuration: 7 * 24 * time.Hour},
+		},
+		ContinuousQueries: []meta.ContinuousQueryInfo{
+			{Name: "cq0", Query: "CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT mean(value) INTO db0.rp1.cpu_1h FROM db0.rp0.cpu GROUP BY time(1h) END"},
+		},
+	}}
+}
+
+// provisionedUsers returns the users of the meta store after provisioning testFile.
+func provisionedUsers() []meta.UserInfo {
+	return []meta.UserInfo{
+		{Name: "admin", Admin: true},
+		{Name: "writer", Privileges: map[string]influxql.Privilege{"db0": influxql.WritePrivilege}},
+	}
+}
+
+func statements(stmts []influxql.Statement) []string {
+	a := make([]string, len(stmts))
+	for i, stmt := range stmts {
+		a[i] = stmt.String()
+	}
+	return a
+}
+
+// MustWriteFile writes s to a temporary file and returns its path. Panic on error.
+func MustWriteFile(s string) string {
+	f, err := ioutil.TempFile("", "influxdb-provision-")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(s); err != nil {
+		panic(err)
+	}
+	return f.Name()
+}
+
+// MustParseFile parses s as a provisioning file. Panic on error.
+func MustParseFile(s string) *provision.File {
+	path := MustWriteFile(s)
+	defer os.Remove(path)
+
+	f, err := provision.ParseFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,99 @@
+package provision
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/uber-go/zap"
+)
+
+// Service applies the provisioning file when opened and reloaded.
+type Service struct {
+	mu     sync.Mutex
+	config Config
+
+	MetaClient interface {
+		Databases() []meta.DatabaseInfo
+		Users() []meta.UserInfo
+	}
+
+	QueryExecutor interface {
+		ExecuteQuery(q *influxql.Query, opt query.ExecutionOptions, closing chan struct{}) <-chan *query.Result
+	}
+
+	Logger zap.Logger
+}
+
+// NewService returns a new instance of Service.
+func NewService(c Config) *Service {
+	return &Service{
+		config: c,
+		Logger: zap.New(zap.NullEncoder()),
+	}
+}
+
+// WithLogger sets the logger for the service.
+func (s *Service) WithLogger(log zap.Logger) {
+	s.Logger = log.With(zap.String("service", "provision"))
+}
+
+// Open applies the provisioning file.
+func (s *Service) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.provision()
+}
+
+// Close closes the service.
+func (s *Service) Close() error { return nil }
+
+// Reload applies the provisioning file of c.
+func (s *Service) Reload(c Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = c
+	return s.provision()
+}
+
+// provision executes the statements reconciling the meta store with the
+// provisioning file, or logs them in dry-run mode.
+func (s *Service) provision() error {
+	if s.config.File == "" {
+		return nil
+	}
+
+	f, err := ParseFile(s.config.File)
+	if err != nil {
+		return fmt.Errorf("provision %s: %s", s.config.File, err)
+	}
+	stmts, err := Plan(f, s.MetaClient.Databases(), s.MetaClient.Users())
+	if err != nil {
+		return fmt.Errorf("provision %s: %s", s.config.File, err)
+	}
+
+	if len(stmts) == 0 {
+		s.Logger.Info(fmt.Sprintf("Provisioning file %s is up to date", s.config.File))
+		return nil
+	} else if s.config.DryRun {
+		s.Logger.Info(fmt.Sprintf("Provisioning file %s requires %d changes (dry run)", s.config.File, len(stmts)))
+		for _, stmt := range stmts {
+			s.Logger.Info(fmt.Sprintf("+ %s", stmt))
+		}
+		return nil
+	}
+
+	closing := make(chan struct{})
+	defer close(closing)
+
+	results := s.QueryExecutor.ExecuteQuery(&influxql.Query{Statements: stmts}, query.ExecutionOptions{}, closing)
+	for r := range results {
+		if r.Err != nil {
+			return fmt.Errorf("provision %s: %s: %s", s.config.File, stmts[r.StatementID], r.Err)
+		}
+		s.Logger.Info(fmt.Sprintf("Provisioned: %s", stmts[r.StatementID]))
+	}
+	return nil
+}
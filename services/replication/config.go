@@ -0,0 +1,81 @@
+package replication
+
+import (
+	"errors"
+	"time"
+
+	"github.com/influxdata/influxdb/monitor/diagnostics"
+	"github.com/influxdata/influxdb/toml"
+)
+
+const (
+	// DefaultDir is the default directory of the replication log and of the
+	// state of a follower.
+	DefaultDir = "/var/lib/influxdb/replication"
+
+	// DefaultMaxLogSize is the default size of the replication log kept for
+	// followers that are behind.
+	DefaultMaxLogSize = 1024 * 1024 * 1024
+
+	// DefaultRetryInterval is the default interval between attempts of a
+	// follower to reconnect to its leader.
+	DefaultRetryInterval = 5 * time.Second
+)
+
+// Config represents the configuration of the replication service.
+type Config struct {
+	// Enabled records every write in the replication log and serves it to the
+	// followers of this node.
+	Enabled bool `toml:"enabled"`
+
+	// Dir is the directory of the replication log and of the state of a follower.
+	Dir string `toml:"dir"`
+
+	// MaxLogSize is the size of the replication log kept for followers.
+	MaxLogSize toml.Size `toml:"max-log-size"`
+
+	// Leader is the bind address of the node to replicate. If set, this
+	// node is a read-only follower of the leader.
+	Leader string `toml:"leader"`
+
+	// RetryInterval is the interval between attempts to reconnect to the leader.
+	RetryInterval toml.Duration `toml:"retry-interval"`
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Dir:           DefaultDir,
+		MaxLogSize:    toml.Size(DefaultMaxLogSize),
+		RetryInterval: toml.Duration(DefaultRetryInterval),
+	}
+}
+
+// Validate returns an error if the Config is invalid.
+func (c Config) Validate() error {
+	if !c.Enabled && c.Leader == "" {
+		return nil
+	}
+
+	if c.Dir == "" {
+		return errors.New("replication dir must be specified")
+	}
+	if c.Enabled && c.MaxLogSize <= 0 {
+		return errors.New("max-log-size must be positive")
+	}
+	if c.Leader != "" && c.RetryInterval <= 0 {
+		return errors.New("retry-interval must be positive")
+	}
+	return nil
+}
+
+// Diagnostics returns a diagnostics representation of a subset of the Config.
+func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"enabled":        c.Enabled,
+		"dir":            c.Dir,
+		"max-log-size":   c.MaxLogSize,
+		"leader":         c.Leader,
+		"retry-interval": c.RetryInterval,
+	}), nil
+}
@@ -0,0 +1,403 @@
+package replication
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tcp"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/uber-go/zap"
+)
+
+const (
+	// stateFile is the name of the file holding the state of a follower.
+	stateFile = "follower.json"
+
+	// saveInterval is the interval at which a follower saves the index of
+	// the last entry it applied.
+	saveInterval = time.Second
+)
+
+// Follower replicates the meta store and the writes of a leader.
+type Follower struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	closing chan struct{}
+	conn    net.Conn
+
+	// Leader is the bind address of the leader.
+	Leader string
+
+	// RetryInterval is the interval between attempts to reconnect to the leader.
+	RetryInterval time.Duration
+
+	MetaClient interface {
+		SetData(data *meta.Data) error
+	}
+
+	PointsWriter interface {
+		WritePointsPrivileged(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error
+	}
+
+	TSDBStore interface {
+		Databases() []string
+		ShardIDs() []uint64
+		Shard(id uint64) *tsdb.Shard
+		DeleteDatabase(name string) error
+		DeleteRetentionPolicy(database, name string) error
+		DeleteShard(id uint64) error
+		DeleteSeries(database string, sources []influxql.Source, condition influxql.Expr) error
+		DeleteMeasurement(database, name string) error
+		MoveShard(id uint64, database, retentionPolicy string) error
+		CopyShard(id uint64, database, retentionPolicy string, newID uint64) error
+	}
+
+	Logger zap.Logger
+
+	path  string
+	state followerState
+	saved time.Time
+	stats *FollowerStatistics
+}
+
+// followerState is the state of a follower saved between restarts.
+type followerState struct {
+	Leader string `json:"leader"`
+	Index  uint64 `json:"index"`
+}
+
+// NewFollower returns a new instance of Follower.
+func NewFollower(c Config) *Follower {
+	return &Follower{
+		Leader:        c.Leader,
+		RetryInterval: time.Duration(c.RetryInterval),
+		Logger:        zap.New(zap.NullEncoder()),
+		path:          filepath.Join(c.Dir, stateFile),
+		stats:         &FollowerStatistics{},
+	}
+}
+
+// Open loads the state of the follower and starts replicating the leader.
+func (f *Follower) Open() error {
+	f.Logger.Info(fmt.Sprintf("Starting replication from %s", f.Leader))
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+	if b, err := ioutil.ReadFile(f.path); err == nil {
+		if err := json.Unmarshal(b, &f.state); err != nil {
+			return fmt.Errorf("read replication state: %s", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	// Entries of another leader do not apply.
+	if f.state.Leader != f.Leader {
+		f.state = followerState{Leader: f.Leader}
+	}
+	atomic.StoreInt64(&f.stats.AppliedIndex, int64(f.state.Index))
+
+	f.closing = make(chan struct{})
+	f.wg.Add(1)
+	go f.run()
+	return nil
+}
+
+// Close stops replicating the leader and saves the state of the follower.
+func (f *Follower) Close() error {
+	if f.closing == nil {
+		return nil
+	}
+	close(f.closing)
+
+	f.mu.Lock()
+	if f.conn != nil {
+		f.conn.Close()
+	}
+	f.mu.Unlock()
+
+	f.wg.Wait()
+	return f.saveState()
+}
+
+// WithLogger sets the logger on the follower.
+func (f *Follower) WithLogger(log zap.Logger) {
+	f.Logger = log.With(zap.String("service", "replication"))
+}
+
+// FollowerStatistics keeps statistics related to the replication of a leader.
+type FollowerStatistics struct {
+	Connected     int64
+	AppliedIndex  int64
+	AppliedTime   int64
+	LeaderIndex   int64
+	EntriesFailed int64
+}
+
+// Statistics returns statistics for periodic monitoring. The lag is the
+// number of entries of the leader not applied yet, and the age of the last
+// applied entry while there are such entries.
+func (f *Follower) Statistics(tags map[string]string) []models.Statistic {
+	applied := atomic.LoadInt64(&f.stats.AppliedIndex)
+	leader := atomic.LoadInt64(&f.stats.LeaderIndex)
+
+	var lagEntries int64
+	var lagSeconds float64
+	if leader > applied {
+		lagEntries = leader - applied
+		if t := atomic.LoadInt64(&f.stats.AppliedTime); t > 0 {
+			lagSeconds = time.Since(time.Unix(0, t)).Seconds()
+		}
+	}
+
+	return []models.Statistic{{
+		Name: "replication",
+		Tags: models.NewTags(map[string]string{"leader": f.Leader}).Merge(tags).Map(),
+		Values: map[string]interface{}{
+			statConnected:     atomic.LoadInt64(&f.stats.Connected),
+			statAppliedIndex:  applied,
+			statLeaderIndex:   leader,
+			statLagEntries:    lagEntries,
+			statLagSeconds:    lagSeconds,
+			statEntriesFailed: atomic.LoadInt64(&f.stats.EntriesFailed),
+		},
+	}}
+}
+
+// run replicates the leader, reconnecting after failures.
+func (f *Follower) run() {
+	defer f.wg.Done()
+
+	for {
+		if err := f.replicate(); err != nil {
+			f.Logger.Info(fmt.Sprintf("replication from %s failed: %s", f.Leader, err))
+		}
+
+		select {
+		case <-f.closing:
+			return
+		case <-time.After(f.RetryInterval):
+		}
+	}
+}
+
+// replicate connects to the leader and applies the meta store and the writes
+// it sends until the connection fails or the follower is closed.
+func (f *Follower) replicate() error {
+	conn, err := tcp.Dial("tcp", f.Leader, MuxHeader)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	f.mu.Lock()
+	select {
+	case <-f.closing:
+		f.mu.Unlock()
+		return nil
+	default:
+	}
+	f.conn = conn
+	f.mu.Unlock()
+
+	// Resume after the last applied entry, or start at the oldest entry of
+	// the log of the leader.
+	var req Request
+	if f.state.Index > 0 {
+		req.Index = f.state.Index + 1
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("encode replication request: %s", err)
+	}
+
+	atomic.StoreInt64(&f.stats.Connected, 1)
+	defer atomic.StoreInt64(&f.stats.Connected, 0)
+	defer f.saveState()
+
+	r := bufio.NewReader(conn)
+	for {
+		typ, index, t, data, err := readFrame(r)
+		if err != nil {
+			select {
+			case <-f.closing:
+				return nil
+			default:
+				return err
+			}
+		}
+
+		switch typ {
+		case frameMeta:
+			var md meta.Data
+			if err := md.UnmarshalBinary(data); err != nil {
+				return fmt.Errorf("unmarshal meta: %s", err)
+			}
+			if err := f.MetaClient.SetData(&md); err != nil {
+				return fmt.Errorf("set meta: %s", err)
+			} else if err := f.dropShards(&md); err != nil {
+				return fmt.Errorf("drop shards: %s", err)
+			}
+			atomic.StoreInt64(&f.stats.LeaderIndex, int64(index))
+		case frameWrite:
+			if err := f.applyEntry(data); err != nil {
+				return fmt.Errorf("apply entry %d: %s", index, err)
+			}
+			f.state.Index = index
+			atomic.StoreInt64(&f.stats.AppliedIndex, int64(index))
+			atomic.StoreInt64(&f.stats.AppliedTime, t.UnixNano())
+			if atomic.LoadInt64(&f.stats.LeaderIndex) < int64(index) {
+				atomic.StoreInt64(&f.stats.LeaderIndex, int64(index))
+			}
+			if time.Since(f.saved) >= saveInterval {
+				if err := f.saveState(); err != nil {
+					return err
+				}
+			}
+		case frameHeartbeat:
+			atomic.StoreInt64(&f.stats.LeaderIndex, int64(index))
+		case frameError:
+			return errors.New(string(data))
+		default:
+			return fmt.Errorf("unknown replication frame type: %d", typ)
+		}
+	}
+}
+
+// dropShards deletes the local shards that are not in the meta store of the
+// leader, because their database, retention policy or shard was dropped, or
+// their shard group expired, on the leader.
+func (f *Follower) dropShards(data *meta.Data) error {
+	for _, name := range f.TSDBStore.Databases() {
+		if data.Database(name) == nil {
+			f.Logger.Info(fmt.Sprintf("dropping database %s, dropped by the leader", name))
+			if err := f.TSDBStore.DeleteDatabase(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	shards := make(map[uint64]bool)
+	for _, dbi := range data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					shards[si.ID] = true
+				}
+			}
+		}
+	}
+
+	for _, id := range f.TSDBStore.ShardIDs() {
+		sh := f.TSDBStore.Shard(id)
+		if sh == nil || shards[id] {
+			continue
+		}
+
+		if dbi := data.Database(sh.Database()); dbi != nil && dbi.RetentionPolicy(sh.RetentionPolicy()) == nil {
+			f.Logger.Info(fmt.Sprintf("dropping retention policy %s.%s, dropped by the leader", sh.Database(), sh.RetentionPolicy()))
+			if err := f.TSDBStore.DeleteRetentionPolicy(sh.Database(), sh.RetentionPolicy()); err != nil {
+				return err
+			}
+			continue
+		}
+		f.Logger.Info(fmt.Sprintf("dropping shard %d, dropped by the leader", id))
+		if err := f.TSDBStore.DeleteShard(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEntry applies an entry of the log of the leader. Entries are applied
+// again after a restart, from the last saved index, so applying an entry
+// twice has no further effect.
+func (f *Follower) applyEntry(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty entry")
+	}
+
+	switch typ, data := data[0], data[1:]; typ {
+	case entryWrite:
+		return f.applyWrite(data)
+	case entryDeleteSeries:
+		database, stmt, err := decodeDeleteSeries(data)
+		if err != nil {
+			return err
+		}
+		return f.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
+	case entryDeleteMeasurement:
+		database, name, err := decodeDeleteMeasurement(data)
+		if err != nil {
+			return err
+		}
+		return f.TSDBStore.DeleteMeasurement(database, name)
+	case entryMoveShard:
+		id, database, retentionPolicy, _, err := decodeShard(data, false)
+		if err != nil {
+			return err
+		}
+		return f.TSDBStore.MoveShard(id, database, retentionPolicy)
+	case entryCopyShard:
+		id, database, retentionPolicy, newID, err := decodeShard(data, true)
+		if err != nil {
+			return err
+		} else if f.TSDBStore.Shard(newID) != nil {
+			// The copy was already applied.
+			return nil
+		}
+		return f.TSDBStore.CopyShard(id, database, retentionPolicy, newID)
+	default:
+		return fmt.Errorf("unknown entry type: %d", typ)
+	}
+}
+
+// applyWrite writes the points of a write entry. Points dropped by a partial
+// write were dropped by the leader as well, so the entry is still applied.
+func (f *Follower) applyWrite(data []byte) error {
+	database, retentionPolicy, points, err := decodeWrite(data)
+	if err != nil {
+		return err
+	}
+
+	err = f.PointsWriter.WritePointsPrivileged(database, retentionPolicy, models.ConsistencyLevelOne, points)
+	if _, ok := err.(tsdb.PartialWriteError); ok {
+		atomic.AddInt64(&f.stats.EntriesFailed, 1)
+		f.Logger.Info(fmt.Sprintf("replicated write to database %s: %s", database, err))
+		return nil
+	}
+	return err
+}
+
+// saveState saves the index of the last entry applied by the follower.
+func (f *Follower) saveState() error {
+	b, err := json.Marshal(f.state)
+	if err != nil {
+		return err
+	}
+
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return err
+	}
+	f.saved = time.Now()
+	return nil
+}
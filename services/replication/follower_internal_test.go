@@ -0,0 +1,194 @@
+package replication
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/uber-go/zap"
+)
+
+// Ensure the deletes, moves and copies of the log are applied by a follower.
+func TestFollower_ApplyEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxdb-replication-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := NewLog(dir)
+	if err := l.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	cond := influxql.MustParseExpr(`host = 'a' AND time < '2017-01-01T00:00:00Z'`)
+	sources := influxql.Sources{&influxql.Measurement{Name: "cpu"}}
+	if err := l.AppendDeleteSeries("db0", sources, cond); err != nil {
+		t.Fatal(err)
+	} else if err := l.AppendDeleteMeasurement("db0", "mem"); err != nil {
+		t.Fatal(err)
+	} else if err := l.AppendMoveShard(1, "db0", "rp1"); err != nil {
+		t.Fatal(err)
+	} else if err := l.AppendCopyShard(2, "db1", "rp0", 3); err != nil {
+		t.Fatal(err)
+	} else if err := l.AppendCopyShard(2, "db1", "rp0", 4); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &TSDBStore{shards: map[uint64]*tsdb.Shard{4: newShard(4, "db1", "rp0")}}
+	f := &Follower{TSDBStore: s, Logger: zap.New(zap.NullEncoder())}
+	r := l.NewReader(0)
+	defer r.Close()
+	for {
+		e, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if e == nil {
+			break
+		}
+		if err := f.applyEntry(e.Data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The copy to the existing shard 4 was already applied.
+	exp := []string{
+		`DeleteSeries db0 cpu host = 'a' AND time < '2017-01-01T00:00:00Z'`,
+		`DeleteMeasurement db0 mem`,
+		`MoveShard 1 db0 rp1`,
+		`CopyShard 2 db1 rp0 3`,
+	}
+	if !reflect.DeepEqual(s.calls, exp) {
+		t.Fatalf("unexpected calls:\ngot %q\nexp %q", s.calls, exp)
+	}
+
+	if err := f.applyEntry([]byte{0xff}); err == nil || err.Error() != "unknown entry type: 255" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a follower drops the local databases, retention policies and shards
+// that are not in the meta store of the leader.
+func TestFollower_DropShards(t *testing.T) {
+	s := &TSDBStore{
+		databases: []string{"db0", "db1"},
+		shards: map[uint64]*tsdb.Shard{
+			1: newShard(1, "db0", "rp0"),
+			2: newShard(2, "db0", "rp0"),
+			3: newShard(3, "db0", "rp0"),
+			4: newShard(4, "db0", "rp1"),
+			5: newShard(5, "db1", "rp0"),
+		},
+	}
+
+	// Shard 2 was dropped, the group of shard 3 expired, rp1 and db1 were
+	// dropped.
+	data := &meta.Data{
+		Databases: []meta.DatabaseInfo{{
+			Name: "db0",
+			RetentionPolicies: []meta.RetentionPolicyInfo{{
+				Name: "rp0",
+				ShardGroups: []meta.ShardGroupInfo{
+					{ID: 1, Shards: []meta.ShardInfo{{ID: 1}}},
+					{ID: 3, Shards: []meta.ShardInfo{{ID: 3}}, DeletedAt: time.Now()},
+				},
+			}},
+		}},
+	}
+
+	f := &Follower{TSDBStore: s, Logger: zap.New(zap.NullEncoder())}
+	if err := f.dropShards(data); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{
+		`DeleteDatabase db1`,
+		`DeleteShard 2`,
+		`DeleteShard 3`,
+		`DeleteRetentionPolicy db0 rp1`,
+	}
+	if !reflect.DeepEqual(s.calls, exp) {
+		t.Fatalf("unexpected calls:\ngot %q\nexp %q", s.calls, exp)
+	}
+}
+
+// TSDBStore is a mock of the store of a follower recording the calls to it.
+type TSDBStore struct {
+	databases []string
+	shards    map[uint64]*tsdb.Shard
+	calls     []string
+}
+
+func (s *TSDBStore) Databases() []string { return s.databases }
+
+func (s *TSDBStore) ShardIDs() []uint64 {
+	ids := make([]uint64, 0, len(s.shards))
+	for id := uint64(0); len(ids) < len(s.shards); id++ {
+		if s.shards[id] != nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (s *TSDBStore) Shard(id uint64) *tsdb.Shard { return s.shards[id] }
+
+func (s *TSDBStore) DeleteDatabase(name string) error {
+	s.calls = append(s.calls, fmt.Sprintf("DeleteDatabase %s", name))
+	for id, sh := range s.shards {
+		if sh.Database() == name {
+			delete(s.shards, id)
+		}
+	}
+	return nil
+}
+
+func (s *TSDBStore) DeleteRetentionPolicy(database, name string) error {
+	s.calls = append(s.calls, fmt.Sprintf("DeleteRetentionPolicy %s %s", database, name))
+	for id, sh := range s.shards {
+		if sh.Database() == database && sh.RetentionPolicy() == name {
+			delete(s.shards, id)
+		}
+	}
+	return nil
+}
+
+func (s *TSDBStore) DeleteShard(id uint64) error {
+	s.calls = append(s.calls, fmt.Sprintf("DeleteShard %d", id))
+	delete(s.shards, id)
+	return nil
+}
+
+func (s *TSDBStore) DeleteSeries(database string, sources []influxql.Source, condition influxql.Expr) error {
+	s.calls = append(s.calls, fmt.Sprintf("DeleteSeries %s %s %s", database, influxql.Sources(sources), condition))
+	return nil
+}
+
+func (s *TSDBStore) DeleteMeasurement(database, name string) error {
+	s.calls = append(s.calls, fmt.Sprintf("DeleteMeasurement %s %s", database, name))
+	return nil
+}
+
+func (s *TSDBStore) MoveShard(id uint64, database, retentionPolicy string) error {
+	s.calls = append(s.calls, fmt.Sprintf("MoveShard %d %s %s", id, database, retentionPolicy))
+	return nil
+}
+
+func (s *TSDBStore) CopyShard(id uint64, database, retentionPolicy string, newID uint64) error {
+	s.calls = append(s.calls, fmt.Sprintf("CopyShard %d %s %s %d", id, database, retentionPolicy, newID))
+	return nil
+}
+
+// newShard returns an unopened shard of the database and retention policy.
+func newShard(id uint64, database, retentionPolicy string) *tsdb.Shard {
+	path := filepath.Join("data", database, retentionPolicy, fmt.Sprint(id))
+	return tsdb.NewShard(id, path, path, tsdb.NewEngineOptions())
+}
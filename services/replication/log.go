@@ -0,0 +1,588 @@
+package replication
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+)
+
+const (
+	// DefaultSegmentSize is the size of a segment file of the log after which
+	// a new segment is started.
+	DefaultSegmentSize = 16 * 1024 * 1024
+
+	// segmentExt is the extension of the segment files of the log.
+	segmentExt = ".log"
+
+	// entryHeaderSize is the size of the header of an entry: its index, time,
+	// data length and data checksum.
+	entryHeaderSize = 8 + 8 + 4 + 4
+)
+
+// Types of the entries of the log, stored in the first byte of their data.
+const (
+	// entryWrite holds a write of points.
+	entryWrite byte = iota + 1
+
+	// entryDeleteSeries holds a DELETE or DROP SERIES statement.
+	entryDeleteSeries
+
+	// entryDeleteMeasurement holds the deletion of a measurement.
+	entryDeleteMeasurement
+
+	// entryMoveShard holds a move of a shard to another retention policy.
+	entryMoveShard
+
+	// entryCopyShard holds a copy of a shard to a new shard.
+	entryCopyShard
+)
+
+var (
+	// ErrLogTruncated is returned when reading entries that were removed
+	// from the log.
+	ErrLogTruncated = errors.New("entries are no longer in the replication log")
+
+	// ErrLogClosed is returned when appending to a closed log.
+	ErrLogClosed = errors.New("replication log closed")
+)
+
+// Entry represents an entry of the replication log.
+type Entry struct {
+	Index uint64
+	Time  time.Time
+	Data  []byte
+}
+
+// Log is the durable log of the writes replicated to the followers of a
+// node. Entries are numbered from 1 and stored in segment files named by
+// the index of their first entry. The oldest segments are removed once the
+// log is larger than MaxSize.
+//
+// Appends are synced to disk in groups: the entries written while a sync is
+// in progress are synced together by the next one.
+type Log struct {
+	mu          sync.RWMutex
+	path        string
+	segments    []*segment
+	active      *os.File
+	lastIndex   uint64 // index of the last entry written
+	syncedIndex uint64 // index of the last entry synced to disk
+	changed     chan struct{}
+
+	// syncMu serializes the syncs of the active segment.
+	syncMu sync.Mutex
+
+	MaxSize     int64
+	SegmentSize int64
+}
+
+// segment represents a segment file of the log.
+type segment struct {
+	index uint64 // index of the first entry of the segment
+	path  string
+	size  int64
+}
+
+// NewLog returns a new instance of Log stored in path.
+func NewLog(path string) *Log {
+	return &Log{
+		path:        path,
+		changed:     make(chan struct{}),
+		MaxSize:     DefaultMaxLogSize,
+		SegmentSize: DefaultSegmentSize,
+	}
+}
+
+// Open opens the log, creating it if it does not exist. An incomplete entry
+// at the end of the log, left by a crash, is removed.
+func (l *Log) Open() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(l.path, 0700); err != nil {
+		return err
+	}
+
+	names, err := filepath.Glob(filepath.Join(l.path, "*"+segmentExt))
+	if err != nil {
+		return err
+	}
+	l.segments = nil
+	for _, name := range names {
+		index, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(name), segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		fi, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		l.segments = append(l.segments, &segment{index: index, path: name, size: fi.Size()})
+	}
+	sort.Slice(l.segments, func(i, j int) bool { return l.segments[i].index < l.segments[j].index })
+
+	if len(l.segments) == 0 {
+		return l.createSegment(1)
+	}
+
+	// Find the last entry of the log.
+	seg := l.segments[len(l.segments)-1]
+	n, size, err := scanSegment(seg.path)
+	if err != nil {
+		return err
+	}
+	l.lastIndex = seg.index + n - 1
+	l.syncedIndex = l.lastIndex
+
+	f, err := os.OpenFile(seg.path, os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	if size < seg.size {
+		if err := f.Truncate(size); err != nil {
+			f.Close()
+			return err
+		}
+		seg.size = size
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+	l.active = f
+	return nil
+}
+
+// Close closes the log.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active == nil {
+		return nil
+	}
+	err := l.active.Close()
+	l.active = nil
+	return err
+}
+
+// LastIndex returns the index of the last entry of the log synced to disk.
+func (l *Log) LastIndex() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.syncedIndex
+}
+
+// Changed returns a channel that is closed when entries are appended to the
+// log and synced to disk.
+func (l *Log) Changed() <-chan struct{} {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.changed
+}
+
+// AppendWrite appends a write of points to a database and retention policy
+// to the log.
+func (l *Log) AppendWrite(database, retentionPolicy string, points []models.Point) error {
+	_, err := l.Append(encodeWrite(database, retentionPolicy, points))
+	return err
+}
+
+// AppendDeleteSeries appends a deletion of the series of sources matching
+// condition to the log.
+func (l *Log) AppendDeleteSeries(database string, sources influxql.Sources, condition influxql.Expr) error {
+	stmt := &influxql.DeleteSeriesStatement{Sources: sources, Condition: condition}
+	b := appendString([]byte{entryDeleteSeries}, database)
+	_, err := l.Append(appendString(b, stmt.String()))
+	return err
+}
+
+// AppendDeleteMeasurement appends a deletion of a measurement to the log.
+func (l *Log) AppendDeleteMeasurement(database, name string) error {
+	b := appendString([]byte{entryDeleteMeasurement}, database)
+	_, err := l.Append(appendString(b, name))
+	return err
+}
+
+// AppendMoveShard appends a move of a shard to a database and retention
+// policy to the log.
+func (l *Log) AppendMoveShard(id uint64, database, retentionPolicy string) error {
+	b := appendUvarint([]byte{entryMoveShard}, id)
+	b = appendString(b, database)
+	_, err := l.Append(appendString(b, retentionPolicy))
+	return err
+}
+
+// AppendCopyShard appends a copy of a shard to a new shard of a database and
+// retention policy to the log.
+func (l *Log) AppendCopyShard(id uint64, database, retentionPolicy string, newID uint64) error {
+	b := appendUvarint([]byte{entryCopyShard}, id)
+	b = appendString(b, database)
+	b = appendString(b, retentionPolicy)
+	_, err := l.Append(appendUvarint(b, newID))
+	return err
+}
+
+// Append appends an entry with data to the log, and returns its index. The
+// entry is synced to disk before Append returns.
+func (l *Log) Append(data []byte) (uint64, error) {
+	index, err := l.write(data)
+	if err != nil {
+		return 0, err
+	} else if err := l.sync(index); err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// write writes an entry with data to the active segment, and returns its
+// index. The entry is not synced to disk.
+func (l *Log) write(data []byte) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active == nil {
+		return 0, ErrLogClosed
+	}
+
+	seg := l.segments[len(l.segments)-1]
+	if seg.size >= l.SegmentSize {
+		// Sync the entries of the segment before it is closed.
+		if err := l.active.Sync(); err != nil {
+			return 0, err
+		} else if err := l.active.Close(); err != nil {
+			return 0, err
+		}
+		if err := l.createSegment(l.lastIndex + 1); err != nil {
+			return 0, err
+		}
+		seg = l.segments[len(l.segments)-1]
+	}
+
+	index := l.lastIndex + 1
+	b := make([]byte, entryHeaderSize+len(data))
+	binary.BigEndian.PutUint64(b[0:8], index)
+	binary.BigEndian.PutUint64(b[8:16], uint64(time.Now().UnixNano()))
+	binary.BigEndian.PutUint32(b[16:20], uint32(len(data)))
+	binary.BigEndian.PutUint32(b[20:24], crc32.ChecksumIEEE(data))
+	copy(b[entryHeaderSize:], data)
+
+	if _, err := l.active.Write(b); err != nil {
+		return 0, err
+	}
+	seg.size += int64(len(b))
+	l.lastIndex = index
+
+	if err := l.trim(); err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// sync syncs the active segment to disk unless the entry at index was
+// already synced by another append. The entries written while a sync is in
+// progress wait for it, and are synced together by the next one.
+func (l *Log) sync(index uint64) error {
+	l.syncMu.Lock()
+	defer l.syncMu.Unlock()
+
+	// The read lock keeps the active segment open, and new entries out of
+	// it, during the sync.
+	l.mu.RLock()
+	if l.syncedIndex >= index {
+		l.mu.RUnlock()
+		return nil
+	} else if l.active == nil {
+		l.mu.RUnlock()
+		return ErrLogClosed
+	}
+	last := l.lastIndex
+	err := l.active.Sync()
+	l.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last > l.syncedIndex {
+		l.syncedIndex = last
+		close(l.changed)
+		l.changed = make(chan struct{})
+	}
+	return nil
+}
+
+// createSegment creates a segment starting at index and makes it the active
+// segment. This method assumes l's mutex is already locked.
+func (l *Log) createSegment(index uint64) error {
+	path := filepath.Join(l.path, fmt.Sprintf("%020d%s", index, segmentExt))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	l.segments = append(l.segments, &segment{index: index, path: path})
+	l.active = f
+	return nil
+}
+
+// trim removes the oldest segments while the log is larger than MaxSize. The
+// active segment is never removed. This method assumes l's mutex is already locked.
+func (l *Log) trim() error {
+	var size int64
+	for _, seg := range l.segments {
+		size += seg.size
+	}
+
+	for size > l.MaxSize && len(l.segments) > 1 {
+		if err := os.Remove(l.segments[0].path); err != nil {
+			return err
+		}
+		size -= l.segments[0].size
+		l.segments = l.segments[1:]
+	}
+	return nil
+}
+
+// NewReader returns a reader of the entries of the log starting at index,
+// or at the oldest entry of the log if index is 0.
+func (l *Log) NewReader(index uint64) *Reader {
+	return &Reader{l: l, next: index}
+}
+
+// Reader reads the entries of a log in order.
+type Reader struct {
+	l    *Log
+	next uint64
+
+	f   *os.File
+	r   *bufio.Reader
+	seg uint64 // index of the first entry of the open segment
+}
+
+// Next returns the next entry of the log, or nil if there are no more
+// entries yet. ErrLogTruncated is returned if the next entry was removed
+// from the log.
+func (r *Reader) Next() (*Entry, error) {
+	r.l.mu.RLock()
+	first, last := r.l.segments[0].index, r.l.syncedIndex
+	if r.next == 0 {
+		r.next = first
+	}
+	var seg segment
+	for _, s := range r.l.segments {
+		if s.index > r.next {
+			break
+		}
+		seg = *s
+	}
+	r.l.mu.RUnlock()
+
+	if r.next > last {
+		return nil, nil
+	} else if r.next < first {
+		return nil, ErrLogTruncated
+	}
+
+	if r.f == nil || r.seg != seg.index {
+		if err := r.open(seg); err != nil {
+			return nil, err
+		}
+	}
+
+	e, err := readEntry(r.r)
+	if err != nil {
+		return nil, err
+	} else if e.Index != r.next {
+		return nil, fmt.Errorf("unexpected entry %d in replication log, expected %d", e.Index, r.next)
+	}
+	r.next++
+	return e, nil
+}
+
+// open opens seg and skips its entries before the next entry of r.
+func (r *Reader) open(seg segment) error {
+	r.Close()
+
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return err
+	}
+	r.f, r.r, r.seg = f, bufio.NewReader(f), seg.index
+
+	for i := seg.index; i < r.next; i++ {
+		if _, err := readEntry(r.r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the reader.
+func (r *Reader) Close() error {
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f, r.r = nil, nil
+	return err
+}
+
+// readEntry reads an entry from r.
+func readEntry(r io.Reader) (*Entry, error) {
+	var hdr [entryHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+
+	e := &Entry{
+		Index: binary.BigEndian.Uint64(hdr[0:8]),
+		Time:  time.Unix(0, int64(binary.BigEndian.Uint64(hdr[8:16]))),
+		Data:  make([]byte, binary.BigEndian.Uint32(hdr[16:20])),
+	}
+	if _, err := io.ReadFull(r, e.Data); err != nil {
+		return nil, err
+	} else if crc32.ChecksumIEEE(e.Data) != binary.BigEndian.Uint32(hdr[20:24]) {
+		return nil, fmt.Errorf("checksum mismatch of entry %d in replication log", e.Index)
+	}
+	return e, nil
+}
+
+// scanSegment returns the number of complete entries of the segment file
+// in path, and their size.
+func scanSegment(path string) (n uint64, size int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		e, err := readEntry(r)
+		if err != nil {
+			// The rest of the segment is an incomplete entry.
+			return n, size, nil
+		}
+		n++
+		size += int64(entryHeaderSize + len(e.Data))
+	}
+}
+
+// encodeWrite returns the entry data of a write of points to a database and
+// retention policy. The points are encoded as line protocol.
+func encodeWrite(database, retentionPolicy string, points []models.Point) []byte {
+	b := make([]byte, 0, 64*len(points))
+	b = append(b, entryWrite)
+	b = appendString(b, database)
+	b = appendString(b, retentionPolicy)
+	for _, p := range points {
+		b = p.AppendString(b)
+		b = append(b, '\n')
+	}
+	return b
+}
+
+// decodeWrite decodes the entry data of a write, without its type.
+func decodeWrite(b []byte) (database, retentionPolicy string, points []models.Point, err error) {
+	if database, b, err = readString(b); err != nil {
+		return "", "", nil, err
+	} else if retentionPolicy, b, err = readString(b); err != nil {
+		return "", "", nil, err
+	}
+	points, err = models.ParsePoints(b)
+	return database, retentionPolicy, points, err
+}
+
+// decodeDeleteSeries decodes the entry data of a deletion of series, without
+// its type.
+func decodeDeleteSeries(b []byte) (database string, stmt *influxql.DeleteSeriesStatement, err error) {
+	var s string
+	if database, b, err = readString(b); err != nil {
+		return "", nil, err
+	} else if s, _, err = readString(b); err != nil {
+		return "", nil, err
+	}
+
+	q, err := influxql.ParseStatement(s)
+	if err != nil {
+		return "", nil, err
+	}
+	stmt, ok := q.(*influxql.DeleteSeriesStatement)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid delete entry: %s", s)
+	}
+	return database, stmt, nil
+}
+
+// decodeDeleteMeasurement decodes the entry data of a deletion of a
+// measurement, without its type.
+func decodeDeleteMeasurement(b []byte) (database, name string, err error) {
+	if database, b, err = readString(b); err != nil {
+		return "", "", err
+	}
+	name, _, err = readString(b)
+	return database, name, err
+}
+
+// decodeShard decodes the entry data of a move or a copy of a shard, without
+// its type. newID is only decoded for a copy.
+func decodeShard(b []byte, copy bool) (id uint64, database, retentionPolicy string, newID uint64, err error) {
+	if id, b, err = readUvarint(b); err != nil {
+		return 0, "", "", 0, err
+	} else if database, b, err = readString(b); err != nil {
+		return 0, "", "", 0, err
+	} else if retentionPolicy, b, err = readString(b); err != nil {
+		return 0, "", "", 0, err
+	}
+	if copy {
+		if newID, _, err = readUvarint(b); err != nil {
+			return 0, "", "", 0, err
+		}
+	}
+	return id, database, retentionPolicy, newID, nil
+}
+
+// appendUvarint appends v to b as a varint.
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// readUvarint reads a varint from b, and returns the rest of b.
+func readUvarint(b []byte) (uint64, []byte, error) {
+	v, i := binary.Uvarint(b)
+	if i <= 0 {
+		return 0, nil, errors.New("invalid entry")
+	}
+	return v, b[i:], nil
+}
+
+// appendString appends s to b, prefixed by its length.
+func appendString(b []byte, s string) []byte {
+	return append(appendUvarint(b, uint64(len(s))), s...)
+}
+
+// readString reads a string prefixed by its length from b, and returns the
+// rest of b.
+func readString(b []byte) (string, []byte, error) {
+	n, i := binary.Uvarint(b)
+	if i <= 0 || uint64(len(b)-i) < n {
+		return "", nil, errors.New("invalid entry")
+	}
+	return string(b[i : i+int(n)]), b[i+int(n):], nil
+}
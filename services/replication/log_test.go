@@ -0,0 +1,205 @@
+package replication_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/services/replication"
+)
+
+func TestLog_AppendRead(t *testing.T) {
+	l := MustOpenLog()
+	defer l.Close()
+
+	for _, data := range []string{"a", "bb", "ccc"} {
+		if _, err := l.Append([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := l.LastIndex(); n != 3 {
+		t.Fatalf("unexpected last index: %d", n)
+	}
+
+	// A reader from 0 starts at the oldest entry.
+	if got, exp := readAll(t, l.NewReader(0)), []string{"a", "bb", "ccc"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected entries: got %v, exp %v", got, exp)
+	}
+	if got, exp := readAll(t, l.NewReader(2)), []string{"bb", "ccc"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected entries: got %v, exp %v", got, exp)
+	}
+}
+
+func TestLog_Reopen(t *testing.T) {
+	l := MustOpenLog()
+	defer l.Close()
+
+	if _, err := l.Append([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Append an incomplete entry, as left by a crash.
+	path := filepath.Join(l.Path, "00000000000000000001.log")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 0})
+	f.Close()
+
+	if err := l.Open(); err != nil {
+		t.Fatal(err)
+	} else if n := l.LastIndex(); n != 1 {
+		t.Fatalf("unexpected last index: %d", n)
+	}
+	if index, err := l.Append([]byte("b")); err != nil {
+		t.Fatal(err)
+	} else if index != 2 {
+		t.Fatalf("unexpected index: %d", index)
+	}
+	if got, exp := readAll(t, l.NewReader(0)), []string{"a", "b"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected entries: got %v, exp %v", got, exp)
+	}
+}
+
+func TestLog_Trim(t *testing.T) {
+	l := NewLog()
+	l.SegmentSize = 64
+	l.MaxSize = 160
+	if err := l.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// Entries are 24 bytes of header and 16 bytes of data: each segment holds
+	// two entries and only the last two segments fit in 160 bytes.
+	r := l.NewReader(1)
+	for i := 0; i < 10; i++ {
+		if _, err := l.Append([]byte("0123456789abcdef")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := r.Next(); err != replication.ErrLogTruncated {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := readAll(t, l.NewReader(0)); len(got) != 4 {
+		t.Fatalf("unexpected number of entries: %d", len(got))
+	}
+}
+
+// Ensure concurrent appends are all synced and readable, in index order.
+func TestLog_AppendConcurrent(t *testing.T) {
+	l := NewLog()
+	l.SegmentSize = 256
+	if err := l.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	const n = 100
+	var wg sync.WaitGroup
+	indexes := make(chan uint64, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			index, err := l.Append([]byte(strconv.Itoa(i)))
+			if err != nil {
+				t.Error(err)
+				return
+			} else if last := l.LastIndex(); last < index {
+				t.Errorf("entry %d not synced: last index %d", index, last)
+			}
+			indexes <- index
+		}(i)
+	}
+	wg.Wait()
+	close(indexes)
+
+	seen := make(map[uint64]bool)
+	for index := range indexes {
+		if seen[index] {
+			t.Fatalf("duplicate index: %d", index)
+		}
+		seen[index] = true
+	}
+	if got := l.LastIndex(); got != n {
+		t.Fatalf("unexpected last index: %d", got)
+	}
+
+	// Entries are read in index order after the log is reopened.
+	if err := l.Log.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := l.Open(); err != nil {
+		t.Fatal(err)
+	}
+	r := l.NewReader(0)
+	defer r.Close()
+	for i := uint64(1); i <= n; i++ {
+		if e, err := r.Next(); err != nil {
+			t.Fatal(err)
+		} else if e == nil || e.Index != i {
+			t.Fatalf("unexpected entry %d: %v", i, e)
+		}
+	}
+}
+
+// Log is a test wrapper of replication.Log.
+type Log struct {
+	*replication.Log
+	Path string
+}
+
+// NewLog returns a log in a temporary directory.
+func NewLog() *Log {
+	path := MustTempDir()
+	return &Log{Log: replication.NewLog(path), Path: path}
+}
+
+// MustOpenLog returns an open log in a temporary directory. Panic on error.
+func MustOpenLog() *Log {
+	l := NewLog()
+	if err := l.Open(); err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// Close closes the log and removes its directory.
+func (l *Log) Close() error {
+	defer os.RemoveAll(l.Path)
+	return l.Log.Close()
+}
+
+// MustTempDir returns a temporary directory. Panic on error.
+func MustTempDir() string {
+	dir, err := ioutil.TempDir("", "influxdb-replication-")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+// readAll returns the data of the entries of r.
+func readAll(t *testing.T, r *replication.Reader) []string {
+	defer r.Close()
+
+	var a []string
+	for {
+		e, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if e == nil {
+			return a
+		}
+		a = append(a, string(e.Data))
+	}
+}
@@ -0,0 +1,295 @@
+// Package replication replicates the meta store and the writes of a node to
+// read-only followers.
+package replication // import "github.com/influxdata/influxdb/services/replication"
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/uber-go/zap"
+)
+
+const (
+	// MuxHeader is the header byte used for the TCP muxer.
+	MuxHeader = 4
+
+	// heartbeatInterval is the interval at which the leader reports its last
+	// log index to an idle follower.
+	heartbeatInterval = time.Second
+)
+
+// Frame types sent by the leader to a follower.
+const (
+	// frameMeta holds the meta store of the leader.
+	frameMeta byte = iota + 1
+
+	// frameWrite holds an entry of the replication log.
+	frameWrite
+
+	// frameHeartbeat holds the last index of the replication log.
+	frameHeartbeat
+
+	// frameError holds an error message, after which the connection is closed.
+	frameError
+)
+
+// The keys for statistics generated by the "replication" module.
+const (
+	statFollowers     = "followers"
+	statEntriesSent   = "entriesSent"
+	statLastIndex     = "lastIndex"
+	statAppliedIndex  = "appliedIndex"
+	statLeaderIndex   = "leaderIndex"
+	statLagEntries    = "lagEntries"
+	statLagSeconds    = "lagSeconds"
+	statConnected     = "connected"
+	statEntriesFailed = "entriesFailed"
+)
+
+// Request represents the request of a follower to replicate the log of the
+// leader starting at Index, or at the oldest entry of the log if Index is 0.
+type Request struct {
+	Index uint64
+}
+
+// Service records the writes of the node in the replication log and serves
+// the log and the meta store to followers.
+type Service struct {
+	wg      sync.WaitGroup
+	closing chan struct{}
+
+	Log *Log
+
+	MetaClient interface {
+		Data() meta.Data
+		WaitForDataChanged() chan struct{}
+	}
+
+	Listener net.Listener
+	Logger   zap.Logger
+
+	stats *LeaderStatistics
+}
+
+// NewService returns a new instance of Service.
+func NewService(c Config) *Service {
+	log := NewLog(c.Dir)
+	log.MaxSize = int64(c.MaxLogSize)
+	return &Service{
+		Log:    log,
+		Logger: zap.New(zap.NullEncoder()),
+		stats:  &LeaderStatistics{},
+	}
+}
+
+// Open opens the replication log and starts serving followers.
+func (s *Service) Open() error {
+	s.Logger.Info("Starting replication service")
+
+	if err := s.Log.Open(); err != nil {
+		return fmt.Errorf("open replication log: %s", err)
+	}
+
+	s.closing = make(chan struct{})
+	s.wg.Add(1)
+	go s.serve()
+	return nil
+}
+
+// Close closes the listener and the replication log.
+func (s *Service) Close() error {
+	if s.Listener != nil {
+		s.Listener.Close()
+	}
+	if s.closing != nil {
+		close(s.closing)
+	}
+	s.wg.Wait()
+	return s.Log.Close()
+}
+
+// WithLogger sets the logger on the service.
+func (s *Service) WithLogger(log zap.Logger) {
+	s.Logger = log.With(zap.String("service", "replication"))
+}
+
+// LeaderStatistics keeps statistics related to the followers of a leader.
+type LeaderStatistics struct {
+	Followers   int64
+	EntriesSent int64
+}
+
+// Statistics returns statistics for periodic monitoring.
+func (s *Service) Statistics(tags map[string]string) []models.Statistic {
+	return []models.Statistic{{
+		Name: "replication",
+		Tags: tags,
+		Values: map[string]interface{}{
+			statFollowers:   atomic.LoadInt64(&s.stats.Followers),
+			statEntriesSent: atomic.LoadInt64(&s.stats.EntriesSent),
+			statLastIndex:   int64(s.Log.LastIndex()),
+		},
+	}}
+}
+
+// serve serves followers from the listener.
+func (s *Service) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.Listener.Accept()
+		if err != nil && strings.Contains(err.Error(), "connection closed") {
+			s.Logger.Info("replication listener closed")
+			return
+		} else if err != nil {
+			s.Logger.Info(fmt.Sprint("error accepting replication request: ", err.Error()))
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(conn net.Conn) {
+			defer s.wg.Done()
+			defer conn.Close()
+			if err := s.handleConn(conn); err != nil {
+				s.Logger.Info(fmt.Sprintf("replication to %s stopped: %s", conn.RemoteAddr(), err))
+			}
+		}(conn)
+	}
+}
+
+// handleConn streams the meta store and the replication log to a follower.
+// This is run in a separate goroutine.
+func (s *Service) handleConn(conn net.Conn) error {
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return fmt.Errorf("read request: %s", err)
+	}
+
+	atomic.AddInt64(&s.stats.Followers, 1)
+	defer atomic.AddInt64(&s.stats.Followers, -1)
+
+	w := bufio.NewWriter(conn)
+	if last := s.Log.LastIndex(); req.Index > last+1 {
+		err := fmt.Errorf("follower requested entry %d, the replication log ends at %d", req.Index, last)
+		writeFrame(w, frameError, 0, time.Now(), []byte(err.Error()))
+		w.Flush()
+		return err
+	}
+	s.Logger.Info(fmt.Sprintf("replicating to %s from entry %d", conn.RemoteAddr(), req.Index))
+
+	// Detect followers closing the connection.
+	done := make(chan struct{})
+	go func() {
+		io.Copy(ioutil.Discard, conn)
+		close(done)
+	}()
+
+	r := s.Log.NewReader(req.Index)
+	defer r.Close()
+
+	var metaChanged chan struct{}
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		// Wait for changes before reading the log, so no append is missed.
+		changed := s.Log.Changed()
+
+		// The meta store is sent before the writes that follow a change of
+		// it, so the shard groups of the writes exist on the follower.
+		select {
+		case <-metaChanged:
+			metaChanged = nil
+		default:
+		}
+		if metaChanged == nil {
+			metaChanged = s.MetaClient.WaitForDataChanged()
+			data := s.MetaClient.Data()
+			b, err := data.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			if err := writeFrame(w, frameMeta, s.Log.LastIndex(), time.Now(), b); err != nil {
+				return err
+			}
+		}
+
+		e, err := r.Next()
+		if err == ErrLogTruncated {
+			writeFrame(w, frameError, 0, time.Now(), []byte(err.Error()))
+			w.Flush()
+			return err
+		} else if err != nil {
+			return err
+		} else if e != nil {
+			if err := writeFrame(w, frameWrite, e.Index, e.Time, e.Data); err != nil {
+				return err
+			}
+			atomic.AddInt64(&s.stats.EntriesSent, 1)
+
+			// Report the end of the log to followers catching up.
+			select {
+			case <-heartbeat.C:
+				if err := writeFrame(w, frameHeartbeat, s.Log.LastIndex(), time.Now(), nil); err != nil {
+					return err
+				}
+			default:
+			}
+			continue
+		}
+
+		// The follower is up to date.
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		select {
+		case <-changed:
+		case <-metaChanged:
+		case <-heartbeat.C:
+			if err := writeFrame(w, frameHeartbeat, s.Log.LastIndex(), time.Now(), nil); err != nil {
+				return err
+			}
+		case <-done:
+			return nil
+		case <-s.closing:
+			return nil
+		}
+	}
+}
+
+// writeFrame writes a frame of type typ to w.
+func writeFrame(w io.Writer, typ byte, index uint64, t time.Time, data []byte) error {
+	var hdr [1 + 8 + 8 + 4]byte
+	hdr[0] = typ
+	binary.BigEndian.PutUint64(hdr[1:9], index)
+	binary.BigEndian.PutUint64(hdr[9:17], uint64(t.UnixNano()))
+	binary.BigEndian.PutUint32(hdr[17:21], uint32(len(data)))
+	if _, err := w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// readFrame reads a frame from r.
+func readFrame(r io.Reader) (typ byte, index uint64, t time.Time, data []byte, err error) {
+	var hdr [1 + 8 + 8 + 4]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, 0, time.Time{}, nil, err
+	}
+	data = make([]byte, binary.BigEndian.Uint32(hdr[17:21]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, 0, time.Time{}, nil, err
+	}
+	return hdr[0], binary.BigEndian.Uint64(hdr[1:9]), time.Unix(0, int64(binary.BigEndian.Uint64(hdr[9:17]))), data, nil
+}
@@ -49,6 +49,7 @@ type Service struct {
 	NewPointsWriter func(u url.URL) (PointsWriter, error)
 	Logger          zap.Logger
 	update          chan struct{}
+	reload          chan struct{}
 	stats           *Statistics
 	points          chan *coordinator.WritePointsRequest
 	wg              sync.WaitGroup
@@ -89,6 +90,7 @@ func (s *Service) Open() error {
 
 	s.closing = make(chan struct{})
 	s.update = make(chan struct{})
+	s.reload = make(chan struct{})
 	s.points = make(chan *coordinator.WritePointsRequest, 100)
 
 	s.wg.Add(2)
@@ -184,6 +186,31 @@ func (s *Service) Update() error {
 	}
 }
 
+// Reload applies the settings of c to the service and recreates the
+// subscriptions with them. Enabling or disabling the service requires a restart.
+func (s *Service) Reload(c Config) error {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+
+	s.subMu.Lock()
+	c.Enabled = s.conf.Enabled
+	s.conf = c
+	s.subMu.Unlock()
+
+	if closed {
+		return nil
+	}
+
+	// signal reload
+	select {
+	case s.reload <- struct{}{}:
+		return nil
+	case <-s.closing:
+		return errors.New("service closed cannot reload")
+	}
+}
+
 func (s *Service) createSubscription(se subEntry, mode string, destinations []string) (PointsWriter, error) {
 	var bm BalanceMode
 	switch mode {
@@ -238,6 +265,11 @@ func (s *Service) run() {
 		select {
 		case <-s.update:
 			s.updateSubs(&wg)
+		case <-s.reload:
+			// Close the existing subscriptions so they are recreated with
+			// the new settings.
+			s.close(&wg)
+			s.updateSubs(&wg)
 		case p, ok := <-s.points:
 			if !ok {
 				// Close out all chanWriters
@@ -441,3 +441,61 @@ func TestService_WaitForDataChanged(t *testing.T) {
 
 	close(dataChanged)
 }
+
+func TestService_Reload(t *testing.T) {
+	dataChanged := make(chan struct{})
+	ms := MetaClient{}
+	ms.WaitForDataChangedFn = func() chan struct{} {
+		return dataChanged
+	}
+	ms.DatabasesFn = func() []meta.DatabaseInfo {
+		return []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						Subscriptions: []meta.SubscriptionInfo{
+							{Name: "s0", Mode: "ANY", Destinations: []string{"udp://h0:9093"}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	urls := make(chan url.URL, 2)
+	newPointsWriter := func(u url.URL) (subscriber.PointsWriter, error) {
+		urls <- u
+		return Subscription{WritePointsFn: func(p *coordinator.WritePointsRequest) error { return nil }}, nil
+	}
+
+	s := subscriber.NewService(subscriber.NewConfig())
+	s.MetaClient = ms
+	s.NewPointsWriter = newPointsWriter
+	s.Open()
+	defer s.Close()
+
+	select {
+	case <-urls:
+	case <-time.After(10 * time.Millisecond):
+		t.Fatal("expected url")
+	}
+
+	// Reloading recreates the subscription.
+	c := subscriber.NewConfig()
+	c.WriteConcurrency = 1
+	if err := s.Reload(c); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case u := <-urls:
+		if u.String() != "udp://h0:9093" {
+			t.Fatalf("unexpected url: %s", u.String())
+		}
+	case <-time.After(10 * time.Millisecond):
+		t.Fatal("expected subscription to be recreated")
+	}
+	close(dataChanged)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/stress/report"
 )
 
 const backoffInterval = time.Duration(500 * time.Millisecond)
@@ -368,7 +369,7 @@ func (c *BasicClient) send(b []byte) (response, error) {
 	resp, err := post(c.Addresses[c.addrId], "", bytes.NewBuffer(b))
 	t.StopTimer()
 	if err != nil {
-		return response{Timer: t}, err
+		return response{Time: time.Now(), Timer: t, Err: err}, err
 	}
 
 	r := response{
@@ -450,7 +451,7 @@ func (b *BasicQueryClient) Query(cmd Query) (response, error) {
 	t.StopTimer()
 
 	if err != nil {
-		return response{Timer: t}, err
+		return response{Time: time.Now(), Timer: t, Err: err}, err
 	}
 
 	// Needs actual response type
@@ -615,6 +616,7 @@ func (b *BasicClient) BasicWriteHandler(rs <-chan response, wt *Timer) {
 	fail := 0
 
 	s := time.Duration(0)
+	h := report.NewHistogram()
 
 	for t := range rs {
 
@@ -627,6 +629,7 @@ func (b *BasicClient) BasicWriteHandler(rs <-chan response, wt *Timer) {
 		}
 
 		s += t.Timer.Elapsed()
+		h.Record(t.Timer.Elapsed())
 
 	}
 
@@ -638,6 +641,7 @@ func (b *BasicClient) BasicWriteHandler(rs <-chan response, wt *Timer) {
 	fmt.Printf("	Success: %v\n", success)
 	fmt.Printf("	Fail: %v\n", fail)
 	fmt.Printf("Average Response Time: %v\n", s/time.Duration(n))
+	fmt.Printf("Response Time Percentiles: p50=%v p90=%v p99=%v p99.9=%v\n", h.Percentile(50), h.Percentile(90), h.Percentile(99), h.Percentile(99.9))
 	fmt.Printf("Points Per Second: %v\n\n", int(float64(n)*float64(b.BatchSize)/float64(wt.Elapsed().Seconds())))
 }
 
@@ -645,9 +649,11 @@ func (b *BasicClient) BasicWriteHandler(rs <-chan response, wt *Timer) {
 func (b *BasicQueryClient) BasicReadHandler(r <-chan response, rt *Timer) {
 	n := 0
 	s := time.Duration(0)
+	h := report.NewHistogram()
 	for t := range r {
 		n++
 		s += t.Timer.Elapsed()
+		h.Record(t.Timer.Elapsed())
 	}
 
 	if n == 0 {
@@ -655,7 +661,24 @@ func (b *BasicQueryClient) BasicReadHandler(r <-chan response, rt *Timer) {
 	}
 
 	fmt.Printf("Total Queries: %v\n", n)
-	fmt.Printf("Average Query Response Time: %v\n\n", s/time.Duration(n))
+	fmt.Printf("Average Query Response Time: %v\n", s/time.Duration(n))
+	fmt.Printf("Query Response Time Percentiles: p50=%v p90=%v p99=%v p99.9=%v\n\n", h.Percentile(50), h.Percentile(90), h.Percentile(99), h.Percentile(99.9))
+}
+
+// ReportHandler returns a handler recording the responses in the statement
+// of r with the name and type. Each successful request wrote n points.
+func ReportHandler(r *report.Report, name, typ string, n int) func(rs <-chan response, t *Timer) {
+	s := r.Statement(name, typ)
+	return func(rs <-chan response, t *Timer) {
+		for resp := range rs {
+			failed := resp.Err != nil || resp.Resp != nil && resp.Resp.StatusCode/100 != 2
+			points := n
+			if failed {
+				points = 0
+			}
+			s.Record(resp.Time, resp.Timer.Elapsed(), points, failed)
+		}
+	}
 }
 
 func (o *outputConfig) HTTPHandler(method string) func(r <-chan response, rt *Timer) {
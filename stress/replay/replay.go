@@ -0,0 +1,307 @@
+// Package replay replays recorded traffic against an InfluxDB server.
+//
+// Writes are replayed from files of line protocol and queries from the HTTP
+// access log of a server. Requests are sent at the recorded time of their
+// points or log lines, relative to the start of the recording and scaled by
+// the speed of the replay. Requests run concurrently like they did when they
+// were recorded.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/stress/report"
+)
+
+const (
+	// DefaultBatchSize is the default maximum number of points of a replayed write.
+	DefaultBatchSize = 5000
+
+	// DefaultBatchInterval is the default maximum recorded time spanned by
+	// the points of a replayed write.
+	DefaultBatchInterval = time.Second
+
+	// DefaultConcurrency is the number of requests in flight when replaying
+	// as fast as possible without a concurrency limit.
+	DefaultConcurrency = 10
+)
+
+// Replayer replays writes and queries against a server.
+type Replayer struct {
+	// Addr is the URL of the server.
+	Addr     string
+	Username string
+	Password string
+
+	// Database and RetentionPolicy are the target of the writes. Queries run
+	// against the database of their log line.
+	Database        string
+	RetentionPolicy string
+
+	// Precision is the precision of the timestamps of the line protocol.
+	Precision string
+
+	// BatchSize and BatchInterval are the maximum number of points of a
+	// write, and the maximum recorded time spanned by its points.
+	BatchSize     int
+	BatchInterval time.Duration
+
+	// Speed scales the recorded time: 2 replays twice as fast as recorded.
+	// Requests are sent as fast as possible when it is 0.
+	Speed float64
+
+	// Concurrency limits the number of requests in flight, unlimited when 0.
+	Concurrency int
+
+	client *http.Client
+	sem    chan struct{}
+
+	mu     sync.Mutex
+	report *Report
+}
+
+// NewReplayer returns a replayer of traffic against the server at addr.
+func NewReplayer(addr string) *Replayer {
+	return &Replayer{
+		Addr:          addr,
+		BatchSize:     DefaultBatchSize,
+		BatchInterval: DefaultBatchInterval,
+		Speed:         1,
+		client:        &http.Client{},
+	}
+}
+
+// Replay replays the line protocol of each of writes and the access log of
+// queries concurrently, and returns a report of the replay once all the
+// requests have completed. The writes of each reader are replayed in order.
+func (r *Replayer) Replay(writes []io.Reader, queries io.Reader) (*Report, error) {
+	r.sem = nil
+	if n := r.Concurrency; n > 0 || r.Speed <= 0 {
+		if n <= 0 {
+			n = DefaultConcurrency
+		}
+		r.sem = make(chan struct{}, n)
+	}
+
+	// Read the first request of each source to find the start and the end of
+	// the recording.
+	var ws []*writeSource
+	var firsts []*writeBatch
+	var start time.Time
+	for _, rd := range writes {
+		s := newWriteSource(rd, r.Precision, r.BatchSize, r.BatchInterval)
+		b, err := s.Next()
+		if err != nil {
+			return nil, err
+		} else if b == nil {
+			continue
+		}
+		ws, firsts = append(ws, s), append(firsts, b)
+		if start.IsZero() || b.Time.Before(start) {
+			start = b.Time
+		}
+	}
+
+	var qs *querySource
+	var firstQuery *queryEntry
+	if queries != nil {
+		qs = newQuerySource(queries)
+		e, err := qs.Next()
+		if err != nil {
+			return nil, err
+		}
+		firstQuery = e
+		if e != nil && (start.IsZero() || e.Time.Before(start)) {
+			start = e.Time
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(ws)+1)
+	now := time.Now()
+	r.report = &Report{Report: report.New(now)}
+	r.report.Writes()
+	var end time.Time
+	recorded := func(t time.Time) {
+		r.mu.Lock()
+		if t.After(end) {
+			end = t
+		}
+		r.mu.Unlock()
+	}
+
+	for i := range ws {
+		wg.Add(1)
+		go func(s *writeSource, b *writeBatch) {
+			defer wg.Done()
+			var err error
+			for ; b != nil && err == nil; b, err = s.Next() {
+				r.wait(now, b.Time.Sub(start))
+				recorded(b.Time)
+				b := b
+				r.do(&wg, func() { r.write(b) })
+			}
+			errs <- err
+		}(ws[i], firsts[i])
+	}
+
+	if qs != nil {
+		wg.Add(1)
+		go func(e *queryEntry) {
+			defer wg.Done()
+			var err error
+			for ; e != nil && err == nil; e, err = qs.Next() {
+				r.wait(now, e.Time.Sub(start))
+				recorded(e.Time)
+				e := e
+				r.do(&wg, func() { r.query(e) })
+			}
+			errs <- err
+		}(firstQuery)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	r.report.Duration = time.Since(now)
+	r.report.Recorded = end.Sub(start)
+	if qs != nil {
+		r.report.SkippedQueries = qs.skipped
+	}
+	for _, s := range r.report.Statements {
+		s.Duration = r.report.Duration
+	}
+	r.report.sort()
+
+	for err := range errs {
+		if err != nil {
+			return r.report, err
+		}
+	}
+	return r.report, nil
+}
+
+// wait waits until the recorded offset d, scaled by the speed, has elapsed
+// since start.
+func (r *Replayer) wait(start time.Time, d time.Duration) {
+	if r.Speed <= 0 {
+		return
+	}
+	time.Sleep(time.Until(start.Add(time.Duration(float64(d) / r.Speed))))
+}
+
+// do runs fn in a goroutine, once a request can be in flight.
+func (r *Replayer) do(wg *sync.WaitGroup, fn func()) {
+	if r.sem != nil {
+		r.sem <- struct{}{}
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fn()
+		if r.sem != nil {
+			<-r.sem
+		}
+	}()
+}
+
+// write sends a batch of points and records its latency.
+func (r *Replayer) write(b *writeBatch) {
+	params := url.Values{"db": {r.Database}, "precision": {r.Precision}}
+	if r.RetentionPolicy != "" {
+		params.Set("rp", r.RetentionPolicy)
+	}
+
+	t := time.Now()
+	err := r.send("POST", "/write", params, bytes.NewReader(b.Data), http.StatusNoContent, nil)
+	d := time.Since(t)
+
+	n := b.Points
+	if err != nil {
+		n = 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Writes().Record(time.Now(), d, n, err != nil)
+}
+
+// query runs a query and records its latency under its shape.
+func (r *Replayer) query(e *queryEntry) {
+	params := url.Values{"q": {e.Query}}
+	for k, v := range e.Params {
+		params[k] = v
+	}
+
+	t := time.Now()
+	err := r.send("GET", "/query", params, nil, http.StatusOK, checkQueryResponse)
+	d := time.Since(t)
+
+	shape := QueryShape(e.Query)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Statement(shape, "query").Record(time.Now(), d, 0, err != nil)
+}
+
+// send sends a request and reads its response, which is checked by check if
+// it is not nil.
+func (r *Replayer) send(method, path string, params url.Values, body io.Reader, status int, check func(io.Reader) error) error {
+	req, err := http.NewRequest(method, strings.TrimSuffix(r.Addr, "/")+path+"?"+params.Encode(), body)
+	if err != nil {
+		return err
+	}
+	if r.Username != "" {
+		req.SetBasicAuth(r.Username, r.Password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+	} else if check != nil {
+		return check(resp.Body)
+	}
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	return err
+}
+
+// checkQueryResponse reads a query response, which may be chunked, and
+// returns the first error of its results.
+func checkQueryResponse(rd io.Reader) error {
+	dec := json.NewDecoder(rd)
+	for {
+		var resp struct {
+			Results []struct {
+				Error string `json:"error"`
+			} `json:"results"`
+			Error string `json:"error"`
+		}
+		if err := dec.Decode(&resp); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if resp.Error != "" {
+			return fmt.Errorf("query error: %s", resp.Error)
+		}
+		for _, res := range resp.Results {
+			if res.Error != "" {
+				return fmt.Errorf("query error: %s", res.Error)
+			}
+		}
+	}
+}
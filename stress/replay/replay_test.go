@@ -0,0 +1,127 @@
+package replay
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReplayer_Replay(t *testing.T) {
+	var mu sync.Mutex
+	var points int
+	queries := make(map[string]string)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/write":
+			if db := r.URL.Query().Get("db"); db != "db0" {
+				t.Errorf("unexpected database: %s", db)
+			}
+			b, _ := ioutil.ReadAll(r.Body)
+			points += bytes.Count(b, []byte("\n"))
+			w.WriteHeader(http.StatusNoContent)
+		case "/query":
+			queries[r.URL.Query().Get("q")] = r.URL.Query().Get("db")
+			if strings.Contains(r.URL.Query().Get("q"), "mem") {
+				io.WriteString(w, `{"results":[{"statement_id":0,"error":"measurement not found"}]}`)
+				return
+			}
+			io.WriteString(w, `{"results":[{"statement_id":0}]}`)
+		}
+	}))
+	defer s.Close()
+
+	writes := []io.Reader{
+		strings.NewReader("cpu value=1 1489572000\ncpu value=2 1489572001\ncpu value=3 1489572002\n"),
+		strings.NewReader("mem value=1 1489572000\n"),
+	}
+	log := `127.0.0.1 - - [15/Mar/2017:10:00:01 +0000] "GET /query?db=db0&q=SELECT+value+FROM+cpu+WHERE+time+%3E+1s HTTP/1.1" 200 120 "-" "curl/7.47.0" 7b2d0a5a-0960-11e7-8001-000000000000 1200
+127.0.0.1 - - [15/Mar/2017:10:00:02 +0000] "GET /query?db=db0&q=SELECT+value+FROM+cpu+WHERE+time+%3E+2s HTTP/1.1" 200 120 "-" "curl/7.47.0" 7b2d0a5a-0960-11e7-8001-000000000000 1200
+127.0.0.1 - - [15/Mar/2017:10:00:02 +0000] "GET /query?db=db1&q=SELECT+value+FROM+mem HTTP/1.1" 200 120 "-" "curl/7.47.0" 7b2d0a5a-0960-11e7-8001-000000000000 1200
+127.0.0.1 - - [15/Mar/2017:10:00:03 +0000] "POST /write?db=db0 HTTP/1.1" 204 0 "-" "curl/7.47.0" 7b2d0a5a-0960-11e7-8001-000000000000 1200
+`
+
+	r := NewReplayer(s.URL)
+	r.Database = "db0"
+	r.Precision = "s"
+	r.BatchSize = 2
+	r.BatchInterval = time.Minute
+	r.Speed = 0
+	report, err := r.Replay(writes, strings.NewReader(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if points != 4 {
+		t.Fatalf("got %d points, exp 4", points)
+	} else if exp := map[string]string{
+		"SELECT value FROM cpu WHERE time > 1s": "db0",
+		"SELECT value FROM cpu WHERE time > 2s": "db0",
+		"SELECT value FROM mem":                 "db1",
+	}; len(queries) != len(exp) {
+		t.Fatalf("unexpected queries: %v", queries)
+	} else {
+		for q, db := range exp {
+			if queries[q] != db {
+				t.Fatalf("unexpected database of query %q: %q", q, queries[q])
+			}
+		}
+	}
+
+	if w := report.Writes(); w.Requests != 3 || w.Points != 4 || w.Errors != 0 || w.Histogram().Count() != 3 {
+		t.Fatalf("unexpected write stats: %+v", w)
+	} else if report.Recorded != 2*time.Second {
+		t.Fatalf("unexpected recorded time: %s", report.Recorded)
+	} else if report.SkippedQueries != 1 {
+		t.Fatalf("got %d skipped queries, exp 1", report.SkippedQueries)
+	}
+
+	shapes := report.Queries()
+	if len(shapes) != 2 {
+		t.Fatalf("got %d query shapes, exp 2", len(shapes))
+	} else if q := shapes[0]; q.Name != "SELECT value FROM cpu WHERE time > ?" || q.Requests != 2 || q.Errors != 0 {
+		t.Fatalf("unexpected query stats: %+v", q)
+	} else if q := shapes[1]; q.Name != "SELECT value FROM mem" || q.Requests != 1 || q.Errors != 1 {
+		t.Fatalf("unexpected query stats: %+v", q)
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, "text"); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(buf.String(), "SELECT value FROM cpu WHERE time > ?") {
+		t.Fatalf("unexpected report:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := report.Write(&buf, "csv"); err != nil {
+		t.Fatal(err)
+	} else if !strings.HasPrefix(buf.String(), "name,type,") || !strings.Contains(buf.String(), "\nwrites,write,3,0,4,") {
+		t.Fatalf("unexpected report:\n%s", buf.String())
+	}
+}
+
+func TestReplayer_Replay_Speed(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+
+	r := NewReplayer(s.URL)
+	r.Database = "db0"
+	r.Precision = "ms"
+	r.Speed = 10
+	now := time.Now()
+	if _, err := r.Replay([]io.Reader{strings.NewReader("cpu value=1 0\ncpu value=1 2000\n")}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(now); d < 200*time.Millisecond {
+		t.Fatalf("replayed 2s at 10x speed in %s", d)
+	}
+}
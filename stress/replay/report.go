@@ -0,0 +1,83 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/influxdb/stress/report"
+)
+
+// Report is the result of a replay. Its statements are the replayed writes,
+// and the replayed queries of each query shape by decreasing count.
+type Report struct {
+	*report.Report
+
+	// Recorded is the span of the recorded time that was replayed.
+	Recorded time.Duration
+
+	// SkippedQueries is the number of lines of the query log that were not
+	// read-only queries.
+	SkippedQueries int
+}
+
+// Writes returns the statistics of the replayed writes.
+func (r *Report) Writes() *report.Statement {
+	return r.Statement("writes", "write")
+}
+
+// Queries returns the statistics of the replayed queries of each shape.
+func (r *Report) Queries() []*report.Statement {
+	var a []*report.Statement
+	for _, s := range r.Statements {
+		if s.Type == "query" {
+			a = append(a, s)
+		}
+	}
+	return a
+}
+
+// sort sorts the query shapes of the report by decreasing count.
+func (r *Report) sort() {
+	sort.SliceStable(r.Statements, func(i, j int) bool {
+		a, b := r.Statements[i], r.Statements[j]
+		if a.Type != b.Type {
+			return a.Type == "write"
+		}
+		return a.Requests > b.Requests
+	})
+}
+
+// Write writes the report to w in format.
+func (r *Report) Write(w io.Writer, format string) error {
+	if format == report.TextFormat || format == "" {
+		return r.WriteText(w)
+	}
+	return r.Report.Write(w, format)
+}
+
+// WriteText writes the report as text to w.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Replayed %s of recorded traffic in %s\n\n", r.Recorded, r.Duration)
+
+	ws := r.Writes()
+	fmt.Fprintf(tw, "Writes:\t%d requests, %d points, %d errors, %.0f points/s\n", ws.Requests, ws.Points, ws.Errors, ws.PointsPerSecond())
+	if h := ws.Histogram(); h.Count() > 0 {
+		fmt.Fprintf(tw, "Latency:\tp50=%s p90=%s p99=%s p99.9=%s max=%s\n", h.Percentile(50), h.Percentile(90), h.Percentile(99), h.Percentile(99.9), h.Max())
+	}
+
+	queries := r.Queries()
+	fmt.Fprintf(tw, "\nQueries:\t%d shapes, %d skipped log lines\n\n", len(queries), r.SkippedQueries)
+	if len(queries) > 0 {
+		fmt.Fprintln(tw, "count\terrors\tp50\tp90\tp99\tp99.9\tmax\tshape")
+		for _, q := range queries {
+			h := q.Histogram()
+			fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", q.Requests, q.Errors, h.Percentile(50), h.Percentile(90), h.Percentile(99), h.Percentile(99.9), h.Max(), q.Name)
+		}
+	}
+
+	return tw.Flush()
+}
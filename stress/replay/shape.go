@@ -0,0 +1,33 @@
+package replay
+
+import (
+	"strings"
+
+	"github.com/influxdata/influxdb/influxql"
+)
+
+// QueryShape returns the shape of a query: the query with its literals, such
+// as numbers, strings and durations, replaced by '?'. Queries that differ only
+// by their literals, like the same dashboard query over different time ranges,
+// have the same shape.
+func QueryShape(q string) string {
+	var words []string
+	s := influxql.NewScanner(strings.NewReader(q))
+	for {
+		tok, _, lit := s.Scan()
+		switch tok {
+		case influxql.EOF:
+			return strings.Join(words, " ")
+		case influxql.WS, influxql.COMMENT:
+		case influxql.NUMBER, influxql.INTEGER, influxql.STRING, influxql.BADSTRING, influxql.DURATIONVAL, influxql.TRUE, influxql.FALSE:
+			words = append(words, "?")
+		case influxql.IDENT:
+			words = append(words, influxql.QuoteIdent(lit))
+		case influxql.BOUNDPARAM, influxql.ILLEGAL, influxql.BADESCAPE:
+			words = append(words, lit)
+		default:
+			// Keywords are in upper case.
+			words = append(words, tok.String())
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+)
+
+// writeBatch is a batch of lines of line protocol recorded at Time.
+type writeBatch struct {
+	Time   time.Time
+	Data   []byte
+	Points int
+}
+
+// writeSource reads batches of line protocol from a reader. The time of a
+// batch is the timestamp of its first point.
+type writeSource struct {
+	r         *bufio.Reader
+	precision int64
+	size      int
+	interval  time.Duration
+
+	// The next line and its time, read ahead of the batch it belongs to.
+	line []byte
+	time time.Time
+	err  error
+}
+
+// newWriteSource returns a source of batches of at most size points, and
+// spanning at most interval of recorded time.
+func newWriteSource(r io.Reader, precision string, size int, interval time.Duration) *writeSource {
+	s := &writeSource{
+		r:         bufio.NewReaderSize(r, 1024*1024),
+		precision: models.GetPrecisionMultiplier(precision),
+		size:      size,
+		interval:  interval,
+	}
+	s.readLine()
+	return s
+}
+
+// Next returns the next batch, or nil at the end of the reader.
+func (s *writeSource) Next() (*writeBatch, error) {
+	if s.line == nil {
+		return nil, s.err
+	}
+
+	b := &writeBatch{Time: s.time}
+	for {
+		b.Data = append(b.Data, s.line...)
+		b.Data = append(b.Data, '\n')
+		b.Points++
+		s.readLine()
+
+		if s.line == nil || b.Points >= s.size || s.time.Sub(b.Time) >= s.interval {
+			return b, nil
+		}
+	}
+}
+
+// readLine reads the next line of line protocol and its time. Lines without
+// a timestamp keep the time of the previous line.
+func (s *writeSource) readLine() {
+	for {
+		line, err := s.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			s.line, s.err = nil, err
+			return
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 && line[0] != '#' {
+			if ts, ok := lineTimestamp(line); ok {
+				s.time = time.Unix(0, ts*s.precision)
+			}
+			s.line = line
+			return
+		} else if err == io.EOF {
+			s.line = nil
+			return
+		}
+	}
+}
+
+// lineTimestamp returns the timestamp of a line of line protocol. The
+// timestamp is the last word of a line; a string field value at the end of a
+// line ends with a quote and is not mistaken for a timestamp.
+func lineTimestamp(line []byte) (int64, bool) {
+	i := bytes.LastIndexByte(line, ' ')
+	if i <= 0 || line[i-1] == '\\' {
+		return 0, false
+	}
+	ts, err := strconv.ParseInt(string(line[i+1:]), 10, 64)
+	return ts, err == nil
+}
+
+// queryEntry is a query recorded at Time, with the other parameters of its
+// request such as the database.
+type queryEntry struct {
+	Time   time.Time
+	Query  string
+	Params url.Values
+}
+
+// accessLogLine matches the time and request of a line of the HTTP access log.
+var accessLogLine = regexp.MustCompile(`^\S+ \S+ \S+ \[([^\]]+)\] "(GET|POST) (\S+) [^"]*"`)
+
+// querySource reads the queries of an HTTP access log. Requests other than
+// read-only queries with the query in the URL are skipped.
+type querySource struct {
+	r       *bufio.Reader
+	skipped int
+}
+
+func newQuerySource(r io.Reader) *querySource {
+	return &querySource{r: bufio.NewReader(r)}
+}
+
+// Next returns the next query, or nil at the end of the reader.
+func (s *querySource) Next() (*queryEntry, error) {
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		} else if line == "" && err == io.EOF {
+			return nil, nil
+		}
+
+		if e := parseAccessLogLine(line); e != nil {
+			return e, nil
+		} else if strings.TrimSpace(line) != "" {
+			s.skipped++
+		}
+	}
+}
+
+// parseAccessLogLine returns the query of a line of the access log, or nil if
+// the line is not a read-only query.
+func parseAccessLogLine(line string) *queryEntry {
+	m := accessLogLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+
+	t, err := time.Parse("02/Jan/2006:15:04:05 -0700", m[1])
+	if err != nil {
+		return nil
+	}
+	u, err := url.Parse(m[3])
+	if err != nil || u.Path != "/query" {
+		return nil
+	}
+
+	params := u.Query()
+	q := params.Get("q")
+	if q == "" || !readOnly(q) {
+		return nil
+	}
+	// The password is redacted by the log, so the credentials of the replay
+	// are used instead.
+	params.Del("q")
+	params.Del("u")
+	params.Del("p")
+	return &queryEntry{Time: t, Query: q, Params: params}
+}
+
+// readOnly returns true if the statements of q only read data. Replaying
+// statements that change the database would change the results of the others.
+func readOnly(q string) bool {
+	query, err := influxql.ParseQuery(q)
+	if err != nil {
+		return false
+	}
+	for _, stmt := range query.Statements {
+		privs, err := stmt.RequiredPrivileges()
+		if err != nil {
+			return false
+		}
+		for _, p := range privs {
+			if p.Admin || p.Privilege != influxql.ReadPrivilege {
+				return false
+			}
+		}
+	}
+	return true
+}
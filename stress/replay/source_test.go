@@ -0,0 +1,119 @@
+package replay
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineTimestamp(t *testing.T) {
+	for _, tt := range []struct {
+		line string
+		ts   int64
+		ok   bool
+	}{
+		{line: `cpu value=1 10`, ts: 10, ok: true},
+		{line: `cpu,host=a value=1,x=2i 1500000000000000000`, ts: 1500000000000000000, ok: true},
+		{line: `cpu value=1`},
+		{line: `cpu value="a 10"`},
+		{line: `cpu value=1\ 10`},
+		{line: `10`},
+	} {
+		ts, ok := lineTimestamp([]byte(tt.line))
+		if ts != tt.ts || ok != tt.ok {
+			t.Errorf("%s: got (%d, %v), exp (%d, %v)", tt.line, ts, ok, tt.ts, tt.ok)
+		}
+	}
+}
+
+func TestWriteSource(t *testing.T) {
+	data := `# comment
+cpu value=1 0
+cpu value=2 1
+
+cpu value=3
+cpu value=4 2
+cpu value=5 10
+cpu value=6 11`
+
+	s := newWriteSource(strings.NewReader(data), "s", 3, 5*time.Second)
+	var got []string
+	var times []int64
+	for {
+		b, err := s.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if b == nil {
+			break
+		}
+		got = append(got, string(b.Data))
+		times = append(times, b.Time.Unix())
+		if n := strings.Count(string(b.Data), "\n"); n != b.Points {
+			t.Fatalf("got %d points, exp %d", b.Points, n)
+		}
+	}
+
+	exp := []string{
+		"cpu value=1 0\ncpu value=2 1\ncpu value=3\n",
+		"cpu value=4 2\n",
+		"cpu value=5 10\ncpu value=6 11\n",
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected batches:\ngot %q\nexp %q", got, exp)
+	} else if !reflect.DeepEqual(times, []int64{0, 2, 10}) {
+		t.Fatalf("unexpected times: %v", times)
+	}
+}
+
+func TestParseAccessLogLine(t *testing.T) {
+	e := parseAccessLogLine(`127.0.0.1 - admin [15/Mar/2017:10:00:01 +0000] "GET /query?db=db0&epoch=s&p=%5BREDACTED%5D&q=SELECT+mean%28value%29+FROM+cpu+WHERE+time+%3E+now%28%29+-+1h&u=admin HTTP/1.1" 200 120 "-" "curl/7.47.0" 7b2d0a5a-0960-11e7-8001-000000000000 1200`)
+	if e == nil {
+		t.Fatal("expected a query")
+	}
+	if exp := time.Date(2017, 3, 15, 10, 0, 1, 0, time.UTC); !e.Time.Equal(exp) {
+		t.Errorf("unexpected time: %s", e.Time)
+	}
+	if exp := "SELECT mean(value) FROM cpu WHERE time > now() - 1h"; e.Query != exp {
+		t.Errorf("unexpected query: %s", e.Query)
+	}
+	if exp := "db=db0&epoch=s"; e.Params.Encode() != exp {
+		t.Errorf("unexpected params: %s", e.Params.Encode())
+	}
+
+	for _, line := range []string{
+		`127.0.0.1 - - [15/Mar/2017:10:00:01 +0000] "POST /write?db=db0 HTTP/1.1" 204 0 "-" "curl/7.47.0" 7b2d0a5a-0960-11e7-8001-000000000000 1200`,
+		`127.0.0.1 - - [15/Mar/2017:10:00:01 +0000] "GET /query?q=DROP+DATABASE+db0 HTTP/1.1" 200 0 "-" "curl/7.47.0" 7b2d0a5a-0960-11e7-8001-000000000000 1200`,
+		`127.0.0.1 - - [15/Mar/2017:10:00:01 +0000] "GET /query?q=SELECT+value+INTO+cpu2+FROM+cpu HTTP/1.1" 200 0 "-" "curl/7.47.0" 7b2d0a5a-0960-11e7-8001-000000000000 1200`,
+		`127.0.0.1 - - [15/Mar/2017:10:00:01 +0000] "POST /query HTTP/1.1" 200 0 "-" "curl/7.47.0" 7b2d0a5a-0960-11e7-8001-000000000000 1200`,
+		`[httpd] 127.0.0.1 - - [15/Mar/2017:10:00:01] "GET /query?q=SHOW+DATABASES HTTP/1.1"`,
+	} {
+		if e := parseAccessLogLine(line); e != nil {
+			t.Errorf("unexpected query %q of line %s", e.Query, line)
+		}
+	}
+}
+
+func TestQueryShape(t *testing.T) {
+	for _, tt := range []struct {
+		q   string
+		exp string
+	}{
+		{
+			q:   `SELECT mean(value) FROM cpu WHERE host = 'a' AND time > now() - 1h GROUP BY time(10s)`,
+			exp: `SELECT mean ( value ) FROM cpu WHERE host = ? AND time > now ( ) - ? GROUP BY time ( ? )`,
+		},
+		{
+			q:   `select mean("value") from "cpu" where time > 1500000000s`,
+			exp: `SELECT mean ( value ) FROM cpu WHERE time > ?`,
+		},
+		{
+			q:   `SELECT value FROM cpu WHERE host = $host LIMIT 10`,
+			exp: `SELECT value FROM cpu WHERE host = $host LIMIT ?`,
+		},
+	} {
+		if got := QueryShape(tt.q); got != tt.exp {
+			t.Errorf("%s:\ngot %s\nexp %s", tt.q, got, tt.exp)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package report
+
+import (
+	"fmt"
+	"time"
+)
+
+// Compare compares the statements of r with the statements of the same name
+// and type of base, and returns a description of each regression: a latency
+// percentile or a throughput worse by more than threshold, a fraction of the
+// base, or more errors than the base.
+func (r *Report) Compare(base *Report, threshold float64) []string {
+	r.summarize()
+	base.summarize()
+
+	var regressions []string
+	for _, s := range r.Statements {
+		var b *Statement
+		for _, bs := range base.Statements {
+			if bs.Name == s.Name && bs.Type == s.Type {
+				b = bs
+				break
+			}
+		}
+		if b == nil {
+			continue
+		}
+
+		for _, l := range []struct {
+			name    string
+			d, base time.Duration
+		}{
+			{"p50", s.Latency.P50, b.Latency.P50},
+			{"p90", s.Latency.P90, b.Latency.P90},
+			{"p99", s.Latency.P99, b.Latency.P99},
+		} {
+			if float64(l.d) > float64(l.base)*(1+threshold) {
+				regressions = append(regressions, fmt.Sprintf("%s %s: %s latency %s, was %s", s.Type, s.Name, l.name, l.d, l.base))
+			}
+		}
+
+		if pps, bpps := s.PointsPerSecond(), b.PointsPerSecond(); pps < bpps*(1-threshold) {
+			regressions = append(regressions, fmt.Sprintf("%s %s: %.0f points/s, was %.0f", s.Type, s.Name, pps, bpps))
+		}
+		if s.Errors > b.Errors {
+			regressions = append(regressions, fmt.Sprintf("%s %s: %d errors, was %d", s.Type, s.Name, s.Errors, b.Errors))
+		}
+	}
+	return regressions
+}
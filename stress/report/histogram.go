@@ -0,0 +1,180 @@
+package report
+
+import (
+	"math"
+	"time"
+)
+
+// subBucketBits is the number of bits of precision of the recorded values.
+// Values are rounded to 2^(subBucketBits-1) equal sub buckets between powers
+// of two, which bounds the error of the percentiles to 1%.
+const subBucketBits = 8
+
+const (
+	subBucketCount = 1 << subBucketBits
+	subBucketHalf  = subBucketCount / 2
+)
+
+// Histogram is a histogram of latencies in the style of HDR histograms. Its
+// buckets grow exponentially, and each is split in linear sub buckets, so it
+// records latencies from nanoseconds to hours with a relative error below 1%
+// in a few kilobytes.
+//
+// Histogram is not safe for concurrent use.
+type Histogram struct {
+	counts []int64
+	count  int64
+	sum    float64
+	min    int64
+	max    int64
+}
+
+// NewHistogram returns an empty histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{}
+}
+
+// bucketIndex returns the index of the count of v.
+func bucketIndex(v int64) int {
+	if v < subBucketCount {
+		return int(v)
+	}
+	var shift uint
+	for v>>shift >= subBucketCount {
+		shift++
+	}
+	return int(shift)*subBucketHalf + int(v>>shift)
+}
+
+// bucketMax returns the highest value of the bucket at index i.
+func bucketMax(i int) int64 {
+	if i < subBucketCount {
+		return int64(i)
+	}
+	shift := i/subBucketHalf - 1
+	sub := int64(i - shift*subBucketHalf)
+	return (sub+1)<<uint(shift) - 1
+}
+
+// Record records a latency. Negative latencies are recorded as 0.
+func (h *Histogram) Record(d time.Duration) {
+	v := int64(d)
+	if v < 0 {
+		v = 0
+	}
+
+	i := bucketIndex(v)
+	if i >= len(h.counts) {
+		counts := make([]int64, i+1)
+		copy(counts, h.counts)
+		h.counts = counts
+	}
+	h.counts[i]++
+
+	if h.count == 0 || v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+	h.count++
+	h.sum += float64(v)
+}
+
+// Merge adds the latencies recorded by other to h.
+func (h *Histogram) Merge(other *Histogram) {
+	if other.count == 0 {
+		return
+	}
+	if len(other.counts) > len(h.counts) {
+		counts := make([]int64, len(other.counts))
+		copy(counts, h.counts)
+		h.counts = counts
+	}
+	for i, n := range other.counts {
+		h.counts[i] += n
+	}
+
+	if h.count == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+	h.count += other.count
+	h.sum += other.sum
+}
+
+// Count returns the number of recorded latencies.
+func (h *Histogram) Count() int64 { return h.count }
+
+// Min returns the lowest recorded latency.
+func (h *Histogram) Min() time.Duration { return time.Duration(h.min) }
+
+// Max returns the highest recorded latency.
+func (h *Histogram) Max() time.Duration { return time.Duration(h.max) }
+
+// Mean returns the mean of the recorded latencies.
+func (h *Histogram) Mean() time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	return time.Duration(h.sum/float64(h.count) + 0.5)
+}
+
+// Percentile returns the latency which p percent of the recorded latencies do
+// not exceed, within the precision of the histogram.
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	// The rank of the percentile, from 1 to the count. The rank is rounded
+	// down when it is within the error of the float of an integer.
+	rank := int64(math.Ceil(p/100*float64(h.count) - 1e-9))
+	if rank < 1 {
+		rank = 1
+	} else if rank > h.count {
+		rank = h.count
+	}
+
+	var n int64
+	for i, c := range h.counts {
+		if n += c; n >= rank {
+			v := bucketMax(i)
+			if v > h.max {
+				v = h.max
+			} else if v < h.min {
+				v = h.min
+			}
+			return time.Duration(v)
+		}
+	}
+	return time.Duration(h.max)
+}
+
+// Latency returns the summary of the recorded latencies.
+func (h *Histogram) Latency() Latency {
+	return Latency{
+		Count: h.count,
+		Min:   h.Min(),
+		Mean:  h.Mean(),
+		P50:   h.Percentile(50),
+		P90:   h.Percentile(90),
+		P99:   h.Percentile(99),
+		P999:  h.Percentile(99.9),
+		Max:   h.Max(),
+	}
+}
+
+// Latency is the summary of a histogram of latencies.
+type Latency struct {
+	Count int64         `json:"count"`
+	Min   time.Duration `json:"min_ns"`
+	Mean  time.Duration `json:"mean_ns"`
+	P50   time.Duration `json:"p50_ns"`
+	P90   time.Duration `json:"p90_ns"`
+	P99   time.Duration `json:"p99_ns"`
+	P999  time.Duration `json:"p999_ns"`
+	Max   time.Duration `json:"max_ns"`
+}
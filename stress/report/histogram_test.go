@@ -0,0 +1,80 @@
+package report
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestHistogram_Percentile(t *testing.T) {
+	h := NewHistogram()
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	for _, tt := range []struct {
+		p   float64
+		exp time.Duration
+	}{
+		{p: 0, exp: 1 * time.Millisecond},
+		{p: 50, exp: 500 * time.Millisecond},
+		{p: 90, exp: 900 * time.Millisecond},
+		{p: 99, exp: 990 * time.Millisecond},
+		{p: 99.9, exp: 999 * time.Millisecond},
+		{p: 100, exp: 1000 * time.Millisecond},
+	} {
+		got := h.Percentile(tt.p)
+		if got < tt.exp || float64(got) > float64(tt.exp)*1.01 {
+			t.Errorf("p%v: got %s, exp %s within 1%%", tt.p, got, tt.exp)
+		}
+	}
+
+	if h.Count() != 1000 {
+		t.Errorf("unexpected count: %d", h.Count())
+	} else if h.Min() != time.Millisecond {
+		t.Errorf("unexpected min: %s", h.Min())
+	} else if h.Max() != time.Second {
+		t.Errorf("unexpected max: %s", h.Max())
+	} else if h.Mean() != 500500*time.Microsecond {
+		t.Errorf("unexpected mean: %s", h.Mean())
+	}
+}
+
+func TestHistogram_Precision(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	h := NewHistogram()
+	values := make([]int, 10000)
+	for i := range values {
+		values[i] = int(rnd.ExpFloat64() * float64(10*time.Millisecond))
+		h.Record(time.Duration(values[i]))
+	}
+	sort.Ints(values)
+
+	for _, p := range []float64{50, 90, 99, 99.9} {
+		exp := values[int(p/100*float64(len(values))+0.5)-1]
+		if got := h.Percentile(p); float64(got) < float64(exp) || float64(got) > float64(exp)*1.01 {
+			t.Errorf("p%v: got %d, exp %d within 1%%", p, got, exp)
+		}
+	}
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	a, b := NewHistogram(), NewHistogram()
+	a.Record(time.Millisecond)
+	b.Record(time.Microsecond)
+	b.Record(time.Hour)
+	a.Merge(b)
+
+	if a.Count() != 3 || a.Min() != time.Microsecond || a.Max() != time.Hour {
+		t.Fatalf("unexpected histogram: %+v", a.Latency())
+	} else if p := a.Percentile(50); p < time.Millisecond || p > time.Millisecond*101/100 {
+		t.Fatalf("unexpected p50: %s", p)
+	}
+}
+
+func TestHistogram_Empty(t *testing.T) {
+	if l := NewHistogram().Latency(); l != (Latency{}) {
+		t.Fatalf("unexpected latency: %+v", l)
+	}
+}
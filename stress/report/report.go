@@ -0,0 +1,247 @@
+// Package report collects the results of stress tests: histograms of the
+// latencies and timelines of the throughput of each statement of a test. A
+// report is written as text, or as JSON or CSV to compare the results of
+// different runs.
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// DefaultInterval is the default interval of the samples of the timelines.
+const DefaultInterval = time.Second
+
+// Formats of a report.
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+	CSVFormat  = "csv"
+)
+
+// Report is the report of a stress test.
+type Report struct {
+	// Start is the start of the test and of the timelines.
+	Start time.Time `json:"start"`
+
+	// Duration is the duration of the test.
+	Duration time.Duration `json:"duration_ns"`
+
+	// Interval is the interval of the samples of the timelines.
+	Interval time.Duration `json:"interval_ns"`
+
+	Statements []*Statement `json:"statements"`
+}
+
+// New returns a report of a test started at start.
+func New(start time.Time) *Report {
+	return &Report{Start: start, Interval: DefaultInterval}
+}
+
+// Statement returns the statement of the report with the name and type,
+// which is added if the report does not have it.
+func (r *Report) Statement(name, typ string) *Statement {
+	for _, s := range r.Statements {
+		if s.Name == name && s.Type == typ {
+			return s
+		}
+	}
+	s := &Statement{Name: name, Type: typ, report: r}
+	r.Statements = append(r.Statements, s)
+	return s
+}
+
+// Statement is the result of a statement of a test, like a write or a query
+// run repeatedly.
+//
+// Statement is not safe for concurrent use.
+type Statement struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Requests int    `json:"requests"`
+	Errors   int    `json:"errors"`
+	Points   int    `json:"points"`
+
+	// Duration is the time the statement ran, the duration of the test if it
+	// is not set.
+	Duration time.Duration `json:"duration_ns"`
+
+	// Latency is the summary of the histogram of the statement, set when the
+	// report is written.
+	Latency Latency `json:"latency"`
+
+	// Timeline is the number of requests, points and errors of each interval
+	// of the test.
+	Timeline []Sample `json:"timeline"`
+
+	report *Report
+	hist   *Histogram
+}
+
+// Sample is the number of requests, points and errors of an interval.
+type Sample struct {
+	Time     time.Time `json:"time"`
+	Requests int       `json:"requests"`
+	Points   int       `json:"points"`
+	Errors   int       `json:"errors"`
+}
+
+// Record records a request completed at t, that took latency and wrote or
+// returned n points.
+func (s *Statement) Record(t time.Time, latency time.Duration, n int, failed bool) {
+	s.Requests++
+	s.Points += n
+	if failed {
+		s.Errors++
+	}
+	s.Histogram().Record(latency)
+
+	interval := s.report.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	i := 0
+	if d := t.Sub(s.report.Start); d > 0 {
+		i = int(d / interval)
+	}
+	for len(s.Timeline) <= i {
+		s.Timeline = append(s.Timeline, Sample{Time: s.report.Start.Add(time.Duration(len(s.Timeline)) * interval)})
+	}
+	sample := &s.Timeline[i]
+	sample.Requests++
+	sample.Points += n
+	if failed {
+		sample.Errors++
+	}
+}
+
+// Histogram returns the histogram of the latencies of the statement.
+func (s *Statement) Histogram() *Histogram {
+	if s.hist == nil {
+		s.hist = NewHistogram()
+	}
+	return s.hist
+}
+
+// PointsPerSecond returns the number of points per second of the statement.
+func (s *Statement) PointsPerSecond() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Points) / s.Duration.Seconds()
+}
+
+// summarize sets the latencies of the statements from their histograms, and
+// their duration to the duration of the test if it is not set.
+func (r *Report) summarize() {
+	for _, s := range r.Statements {
+		if s.hist != nil {
+			s.Latency = s.hist.Latency()
+		}
+		if s.Duration == 0 {
+			s.Duration = r.Duration
+		}
+	}
+}
+
+// Write writes the report to w in format.
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case TextFormat, "":
+		return r.WriteText(w)
+	case JSONFormat:
+		return r.WriteJSON(w)
+	case CSVFormat:
+		return r.WriteCSV(w)
+	default:
+		return fmt.Errorf("unknown report format: %q", format)
+	}
+}
+
+// WriteText writes a table of the statements of the report to w.
+func (r *Report) WriteText(w io.Writer) error {
+	r.summarize()
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\ttype\trequests\terrors\tpoints\tpoints/s\tmean\tp50\tp90\tp99\tp99.9\tmax")
+	for _, s := range r.Statements {
+		l := s.Latency
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%.0f\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.Name, s.Type, s.Requests, s.Errors, s.Points, s.PointsPerSecond(),
+			l.Mean, l.P50, l.P90, l.P99, l.P999, l.Max)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the report to w as indented JSON, with a line for each
+// value so reports of different runs can be compared with diff.
+func (r *Report) WriteJSON(w io.Writer) error {
+	r.summarize()
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// ReadJSON reads a report written by WriteJSON.
+func ReadJSON(rd io.Reader) (*Report, error) {
+	r := &Report{}
+	if err := json.NewDecoder(rd).Decode(r); err != nil {
+		return nil, err
+	}
+	for _, s := range r.Statements {
+		s.report = r
+	}
+	return r, nil
+}
+
+// WriteCSV writes a row of the summary of each statement of the report to w.
+func (r *Report) WriteCSV(w io.Writer) error {
+	r.summarize()
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"name", "type", "requests", "errors", "points", "points_per_second", "duration_ns",
+		"min_ns", "mean_ns", "p50_ns", "p90_ns", "p99_ns", "p999_ns", "max_ns",
+	})
+	for _, s := range r.Statements {
+		l := s.Latency
+		cw.Write([]string{
+			s.Name, s.Type, strconv.Itoa(s.Requests), strconv.Itoa(s.Errors), strconv.Itoa(s.Points),
+			strconv.FormatFloat(s.PointsPerSecond(), 'f', 0, 64), formatDuration(s.Duration),
+			formatDuration(l.Min), formatDuration(l.Mean), formatDuration(l.P50), formatDuration(l.P90),
+			formatDuration(l.P99), formatDuration(l.P999), formatDuration(l.Max),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTimelineCSV writes a row of each sample of the timelines of the
+// statements of the report to w.
+func (r *Report) WriteTimelineCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"name", "type", "time", "requests", "points", "errors"})
+	for _, s := range r.Statements {
+		for _, sample := range s.Timeline {
+			cw.Write([]string{
+				s.Name, s.Type, sample.Time.UTC().Format(time.RFC3339Nano),
+				strconv.Itoa(sample.Requests), strconv.Itoa(sample.Points), strconv.Itoa(sample.Errors),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatDuration(d time.Duration) string {
+	return strconv.FormatInt(int64(d), 10)
+}
@@ -0,0 +1,130 @@
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestReport() *Report {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := New(start)
+	r.Duration = 3 * time.Second
+
+	w := r.Statement("insert", "write")
+	w.Record(start.Add(500*time.Millisecond), 10*time.Millisecond, 100, false)
+	w.Record(start.Add(700*time.Millisecond), 20*time.Millisecond, 100, false)
+	w.Record(start.Add(2500*time.Millisecond), 30*time.Millisecond, 0, true)
+
+	q := r.Statement("select", "query")
+	q.Record(start.Add(time.Second), time.Millisecond, 0, false)
+	return r
+}
+
+func TestReport_Statement(t *testing.T) {
+	r := newTestReport()
+	if s := r.Statement("insert", "write"); s != r.Statements[0] {
+		t.Fatal("expected the existing statement")
+	} else if s.Requests != 3 || s.Errors != 1 || s.Points != 200 {
+		t.Fatalf("unexpected statement: %+v", s)
+	}
+
+	exp := []Sample{
+		{Time: r.Start, Requests: 2, Points: 200},
+		{Time: r.Start.Add(time.Second)},
+		{Time: r.Start.Add(2 * time.Second), Requests: 1, Errors: 1},
+	}
+	if got := r.Statements[0].Timeline; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected timeline:\ngot %+v\nexp %+v", got, exp)
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	r := newTestReport()
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other.Statements) != 2 {
+		t.Fatalf("got %d statements, exp 2", len(other.Statements))
+	}
+	s := other.Statements[0]
+	if s.Name != "insert" || s.Type != "write" || s.Requests != 3 || s.Duration != 3*time.Second || len(s.Timeline) != 3 {
+		t.Fatalf("unexpected statement: %+v", s)
+	} else if s.Latency != r.Statements[0].Latency || s.Latency.Count != 3 || s.Latency.Max != 30*time.Millisecond {
+		t.Fatalf("unexpected latency: %+v", s.Latency)
+	}
+}
+
+func TestReport_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestReport().WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if exp := "name,type,requests,errors,points,points_per_second,duration_ns,min_ns,mean_ns,p50_ns,p90_ns,p99_ns,p999_ns,max_ns"; lines[0] != exp {
+		t.Fatalf("unexpected header: %s", lines[0])
+	} else if len(lines) != 3 {
+		t.Fatalf("got %d lines, exp 3", len(lines))
+	} else if !strings.HasPrefix(lines[1], "insert,write,3,1,200,67,3000000000,10000000,20000000,") {
+		t.Fatalf("unexpected row: %s", lines[1])
+	}
+
+	buf.Reset()
+	if err := newTestReport().WriteTimelineCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	exp := `name,type,time,requests,points,errors
+insert,write,2017-01-01T00:00:00Z,2,200,0
+insert,write,2017-01-01T00:00:01Z,0,0,0
+insert,write,2017-01-01T00:00:02Z,1,0,1
+select,query,2017-01-01T00:00:00Z,0,0,0
+select,query,2017-01-01T00:00:01Z,1,0,0
+`
+	if buf.String() != exp {
+		t.Fatalf("unexpected timeline:\n%s", buf.String())
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestReport().Write(&buf, TextFormat); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(buf.String(), "p99.9") {
+		t.Fatalf("unexpected report:\n%s", buf.String())
+	}
+
+	if err := newTestReport().Write(&buf, "xml"); err == nil || err.Error() != `unknown report format: "xml"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReport_Compare(t *testing.T) {
+	base := newTestReport()
+	if regressions := newTestReport().Compare(base, 0.1); len(regressions) != 0 {
+		t.Fatalf("unexpected regressions: %v", regressions)
+	}
+
+	r := newTestReport()
+	r.Duration = 6 * time.Second
+	w := r.Statement("insert", "write")
+	w.Record(r.Start, time.Second, 0, true)
+	regressions := r.Compare(base, 0.1)
+	exp := []string{
+		"write insert: p90 latency 1s, was 30ms",
+		"write insert: p99 latency 1s, was 30ms",
+		"write insert: 33 points/s, was 67",
+		"write insert: 2 errors, was 1",
+	}
+	if !reflect.DeepEqual(regressions, exp) {
+		t.Fatalf("unexpected regressions:\ngot %q\nexp %q", regressions, exp)
+	}
+}
@@ -146,6 +146,7 @@ type response struct {
 	Resp  *http.Response
 	Time  time.Time
 	Timer *Timer
+	Err   error
 }
 
 // Success returns true if the request
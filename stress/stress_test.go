@@ -14,6 +14,7 @@ import (
 
 	"github.com/influxdata/influxdb/client/v2"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/stress/report"
 )
 
 func TestTimer_StartTimer(t *testing.T) {
@@ -422,6 +423,26 @@ func TestBasicQueryClient_Query(t *testing.T) {
 
 }
 
+func TestReportHandler(t *testing.T) {
+	rep := report.New(time.Now())
+	fn := ReportHandler(rep, "write", "write", 10)
+
+	rs := make(chan response, 3)
+	rs <- response{Resp: &http.Response{StatusCode: 204}, Time: time.Now(), Timer: &Timer{start: time.Unix(0, 0), end: time.Unix(0, 5)}}
+	rs <- response{Resp: &http.Response{StatusCode: 500}, Time: time.Now(), Timer: &Timer{start: time.Unix(0, 0), end: time.Unix(0, 7)}}
+	rs <- response{Time: time.Now(), Timer: &Timer{start: time.Unix(0, 0), end: time.Unix(0, 9)}, Err: fmt.Errorf("timeout")}
+	close(rs)
+	fn(rs, NewTimer())
+
+	s := rep.Statements[0]
+	if s.Requests != 3 || s.Errors != 2 || s.Points != 10 {
+		t.Errorf("unexpected statement: %+v", s)
+	}
+	if max := s.Histogram().Max(); max != 9 {
+		t.Errorf("unexpected max latency: %v", max)
+	}
+}
+
 /// config.go
 func Test_NewConfigWithFile(t *testing.T) {
 	c, err := NewConfig("stress.toml")
@@ -6,12 +6,14 @@ import (
 	"time"
 
 	influx "github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/stress/report"
 	"github.com/influxdata/influxdb/stress/v2/stress_client"
 	"github.com/influxdata/influxdb/stress/v2/stressql"
 )
 
 // RunStress takes a configFile and kicks off the stress test
-func RunStress(file string) {
+// It returns the report of the statements of the test
+func RunStress(file string) *report.Report {
 
 	// Spin up the Client
 	s := stressClient.NewStressTest()
@@ -34,10 +36,14 @@ func RunStress(file string) {
 	s.ResultsChan <- resp
 	resp.Tracer.Wait()
 
+	s.Report.Duration = time.Since(s.Report.Start)
+
 	// Compile all Reports
 	for _, stmt := range stmts {
 		fmt.Println(stmt.Report(s))
 	}
+
+	return s.Report
 }
 
 func blankResponse() stressClient.Response {
@@ -16,10 +16,21 @@ type Function struct {
 	Fn       string
 	Argument int
 	Count    int
+
+	// Args are the arguments of the function after Argument
+	Args []float64
 }
 
 // NewStringer creates a new Stringer
 func (f *Function) NewStringer(series int) Stringer {
+	// Generators return a new value for every point, so they are not cycled
+	switch f.Fn {
+	case "zipf":
+		return NewZipfFunc(f.Type, f.Argument, f.Args)
+	case "churn":
+		return NewChurnFunc(f.Type, f.Argument, f.Args)
+	}
+
 	var fn Stringer
 	switch f.Type {
 	case "int":
@@ -137,6 +148,80 @@ func NewIntFunc(fn string, arg int) Stringer {
 	}
 }
 
+// formatInt formats an integer generated for the datatype typ
+func formatInt(typ string, v int) string {
+	switch typ {
+	case "int":
+		return fmt.Sprintf("%vi", v)
+	case "float", "str":
+		return fmt.Sprintf("%v", v)
+	default:
+		return "STRINGER ERROR"
+	}
+}
+
+// NewZipfFunc creates a new stringer returning values from 0 to n-1 with a
+// Zipfian distribution: value k is returned with a probability proportional
+// to (k+1)^-s. The exponent s is the first of args, 1.1 by default, and must
+// be greater than 1.
+func NewZipfFunc(typ string, n int, args []float64) Stringer {
+	s := 1.1
+	if len(args) > 0 {
+		s = args[0]
+	}
+	if n <= 0 || s <= 1 {
+		return func() string { return "ZIPF ERROR" }
+	}
+
+	z := rand.NewZipf(rand.New(rand.NewSource(rand.Int63())), s, 1, uint64(n-1))
+	return func() string {
+		return formatInt(typ, int(z.Uint64()))
+	}
+}
+
+// NewChurnFunc creates a new stringer returning the IDs of n members that are
+// replaced by a new member, with a new ID, once they have been returned
+// lifetime times. Lifetime is the first of args. The ages of the first members
+// are staggered so members are replaced steadily, like containers that come
+// and go.
+func NewChurnFunc(typ string, n int, args []float64) Stringer {
+	if n <= 0 || len(args) == 0 || args[0] < 1 {
+		return func() string { return "CHURN ERROR" }
+	}
+	lifetime := int(args[0])
+
+	ids := make([]int, n)
+	ages := make([]int, n)
+	for i := range ids {
+		ids[i] = i
+		ages[i] = i * lifetime / n
+	}
+	next := n
+
+	i := 0
+	return func() string {
+		if ages[i] >= lifetime {
+			ids[i], ages[i] = next, 0
+			next++
+		}
+		id := ids[i]
+		ages[i]++
+		i = (i + 1) % n
+		return formatInt(typ, id)
+	}
+}
+
+// chance returns key followed by the value of fn with probability p, and an
+// empty string otherwise
+func chance(p float64, key string, fn Stringer) Stringer {
+	return func() string {
+		if rand.Float64() >= p {
+			return ""
+		}
+		return key + fn()
+	}
+}
+
 // nTimes will return the previous return value of a function
 // n-many times before calling the function again
 func nTimes(n int, fn Stringer) Stringer {
@@ -1,6 +1,7 @@
 package statement
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -97,6 +98,55 @@ func spoofTime() int64 {
 	return int64(8)
 }
 
+func TestNewZipfStringer(t *testing.T) {
+	function := &Function{Type: "int", Fn: "zipf", Argument: 100, Args: []float64{1.5}, Count: 100}
+	zipfStringer := function.NewStringer(10)
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		counts[zipfStringer()]++
+	}
+	if len(counts) > 100 {
+		t.Errorf("Expected at most 100 values\nGot: %v\n", len(counts))
+	}
+	// The first value is the most frequent
+	for v, n := range counts {
+		if v != "0i" && n >= counts["0i"] {
+			t.Errorf("Expected 0i to be more frequent than %v\nGot: %v and %v\n", v, counts["0i"], n)
+		}
+	}
+}
+
+func TestNewChurnStringer(t *testing.T) {
+	function := &Function{Type: "str", Fn: "churn", Argument: 4, Args: []float64{3}, Count: 4}
+	churnStringer := function.NewStringer(10)
+	var got []string
+	for i := 0; i < 12; i++ {
+		got = append(got, churnStringer())
+	}
+	// Each member is replaced after 3 values, the first ones after fewer
+	expected := []string{"0", "1", "2", "3", "0", "1", "2", "4", "0", "1", "5", "4"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected: %v\nGot: %v\n", expected, got)
+	}
+}
+
+func TestChance(t *testing.T) {
+	fn := chance(0.5, ",free=", func() string { return "1" })
+	n := 0
+	for i := 0; i < 1000; i++ {
+		switch s := fn(); s {
+		case ",free=1":
+			n++
+		case "":
+		default:
+			t.Fatalf("Expected: ,free=1 or empty\nGot: %v\n", s)
+		}
+	}
+	if n < 400 || n > 600 {
+		t.Errorf("Expected about 500 values\nGot: %v\n", n)
+	}
+}
+
 func newStrRandFunction() *Function {
 	return &Function{
 		Type:     "str",
@@ -200,6 +200,12 @@ func (i *InsertStatement) Report(s *stressClient.StressTest) string {
 	ir.successfulWrites = countSuccesses(ir.columns, ir.values)
 	ir.avgRequestBytes = numberBytes(ir.columns, ir.values)
 
+	st := s.Report.Statement(i.Name, "write")
+	recordResults(st, ir.columns, ir.values, s.BatchSize)
+	st.Points = i.Timestamp.Count
+	st.Duration = i.runtime
+	ir.latency = st.Histogram().Latency()
+
 	return ir.String()
 }
 
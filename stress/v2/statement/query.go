@@ -122,6 +122,11 @@ func (i *QueryStatement) Report(s *stressClient.StressTest) string {
 	qr.successfulReads = countSuccesses(qr.columns, qr.values)
 	qr.responseBytes = numberBytes(qr.columns, qr.values)
 
+	st := s.Report.Statement(i.Name, "query")
+	recordResults(st, qr.columns, qr.values, 0)
+	st.Duration = i.runtime
+	qr.latency = st.Histogram().Latency()
+
 	return qr.String()
 }
 
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	influx "github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/stress/report"
 )
 
 // TODO: Refactor this file to utilize a common interface
@@ -24,6 +25,7 @@ type insertReport struct {
 	avgResponseTime    time.Duration
 	stdDevResponseTime time.Duration
 	percentile         time.Duration
+	latency            report.Latency
 
 	columns []string
 	values  [][]interface{}
@@ -36,6 +38,7 @@ func (ir *insertReport) String() string {
   Resp Time Average:                   %v
   Resp Time Standard Deviation:        %v
   95th Percentile Write Response:      %v
+  Resp Time Percentiles:               p50=%v p90=%v p99=%v p99.9=%v
   Average Request Bytes:               %v
   Successful Write Reqs:               %v
   Retries:                             %v`
@@ -46,6 +49,7 @@ func (ir *insertReport) String() string {
 		ir.avgResponseTime,
 		ir.stdDevResponseTime,
 		ir.percentile,
+		ir.latency.P50, ir.latency.P90, ir.latency.P99, ir.latency.P999,
 		ir.avgRequestBytes,
 		ir.successfulWrites,
 		ir.numRetries)
@@ -72,6 +76,7 @@ type queryReport struct {
 	avgResponseTime     time.Duration
 	stdDevResponseTime  time.Duration
 	percentile          time.Duration
+	latency             report.Latency
 
 	columns []string
 	values  [][]interface{}
@@ -83,6 +88,7 @@ func (qr *queryReport) String() string {
   Resp Time Average:                   %v
   Resp Time Standard Deviation:        %v
   95th Percentile Read Response:       %v
+  Resp Time Percentiles:               p50=%v p90=%v p99=%v p99.9=%v
   Query Resp Bytes Average:            %v bytes
   Successful Queries:                  %v`
 
@@ -91,6 +97,7 @@ func (qr *queryReport) String() string {
 		qr.avgResponseTime,
 		qr.stdDevResponseTime,
 		qr.percentile,
+		qr.latency.P50, qr.latency.P90, qr.latency.P99, qr.latency.P999,
 		qr.responseBytes,
 		qr.successfulReads)
 }
@@ -211,6 +218,34 @@ func responseTimes(columns []string, values [][]interface{}) (rs ResponseTimes)
 	return rs
 }
 
+// Records the requests of a full set of results in the statement of a report,
+// n points for each successful request
+func recordResults(st *report.Statement, columns []string, values [][]interface{}, n int) {
+	timeIndex := getColumnIndex("time", columns)
+	statusIndex := getColumnIndex("status_code", columns)
+	respIndex := getColumnIndex("response_time_ns", columns)
+	for _, val := range values {
+		t, err := time.Parse(time.RFC3339Nano, fmt.Sprint(val[timeIndex]))
+		if err != nil {
+			log.Fatalf("Error parsing result time\n  time: %v\n  error: %v\n", val[timeIndex], err)
+		}
+		status, err := val[statusIndex].(json.Number).Int64()
+		if err != nil {
+			log.Fatalf("Error coercing json.Number to Int64\n  json.Number:%v\n  error: %v\n", val[statusIndex], err)
+		}
+		respTime, err := val[respIndex].(json.Number).Int64()
+		if err != nil {
+			log.Fatalf("Error coercing json.Number to Int64\n  json.Number:%v\n  error: %v\n", val[respIndex], err)
+		}
+
+		if status == 204 || status == 200 {
+			st.Record(t, time.Duration(respTime), n, false)
+		} else {
+			st.Record(t, time.Duration(respTime), 0, true)
+		}
+	}
+}
+
 // Returns the 95th perecntile response time
 func percentile(rs ResponseTimes) time.Duration {
 	sort.Sort(rs)
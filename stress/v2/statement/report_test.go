@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/influxdata/influxdb/stress/report"
 )
 
 func TestInsertReportString(t *testing.T) {
@@ -15,6 +17,7 @@ func TestInsertReportString(t *testing.T) {
   Resp Time Average:                   %v
   Resp Time Standard Deviation:        %v
   95th Percentile Write Response:      %v
+  Resp Time Percentiles:               p50=%v p90=%v p99=%v p99.9=%v
   Average Request Bytes:               %v
   Successful Write Reqs:               %v
   Retries:                             %v`
@@ -24,6 +27,7 @@ func TestInsertReportString(t *testing.T) {
 		ir.avgResponseTime,
 		ir.stdDevResponseTime,
 		ir.percentile,
+		ir.latency.P50, ir.latency.P90, ir.latency.P99, ir.latency.P999,
 		ir.avgRequestBytes,
 		ir.successfulWrites,
 		ir.numRetries)
@@ -48,6 +52,7 @@ func TestQueryReportString(t *testing.T) {
   Resp Time Average:                   %v
   Resp Time Standard Deviation:        %v
   95th Percentile Read Response:       %v
+  Resp Time Percentiles:               p50=%v p90=%v p99=%v p99.9=%v
   Query Resp Bytes Average:            %v bytes
   Successful Queries:                  %v`
 	expected := fmt.Sprintf(tmplString,
@@ -55,6 +60,7 @@ func TestQueryReportString(t *testing.T) {
 		qr.avgResponseTime,
 		qr.stdDevResponseTime,
 		qr.percentile,
+		qr.latency.P50, qr.latency.P90, qr.latency.P99, qr.latency.P999,
 		qr.responseBytes,
 		qr.successfulReads)
 	got := qr.String()
@@ -100,6 +106,7 @@ func newTestInsertReport() *insertReport {
 		avgResponseTime:    time.Duration(int64(20000)),
 		stdDevResponseTime: time.Duration(int64(20000)),
 		percentile:         time.Duration(int64(20000)),
+		latency:            report.Latency{P50: 10000, P90: 15000, P99: 20000, P999: 30000},
 	}
 }
 
@@ -112,6 +119,7 @@ func newTestQueryReport() *queryReport {
 		avgResponseTime:     139082,
 		stdDevResponseTime:  29487,
 		percentile:          8273491,
+		latency:             report.Latency{P50: 139000, P90: 8000000, P99: 8300000, P999: 9000000},
 	}
 }
 
@@ -173,6 +181,27 @@ func TestResponseTimes(t *testing.T) {
 	}
 }
 
+func TestRecordResults(t *testing.T) {
+	columns := []string{"time", "response_time_ns", "status_code"}
+	values := [][]interface{}{
+		[]interface{}{"2017-01-01T00:00:00.5Z", json.Number("100"), json.Number("204")},
+		[]interface{}{"2017-01-01T00:00:01.5Z", json.Number("300"), json.Number("500")},
+	}
+	r := report.New(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))
+	st := r.Statement("foo_name", "write")
+	recordResults(st, columns, values, 10)
+
+	if st.Requests != 2 || st.Errors != 1 || st.Points != 10 {
+		t.Errorf("unexpected statement: %+v", st)
+	}
+	if len(st.Timeline) != 2 || st.Timeline[0].Points != 10 || st.Timeline[1].Errors != 1 {
+		t.Errorf("unexpected timeline: %+v", st.Timeline)
+	}
+	if max := st.Histogram().Max(); max != 300 {
+		t.Errorf("expected: %v\ngot: %v\n", time.Duration(300), max)
+	}
+}
+
 func TestPercentile(t *testing.T) {
 	rs := createTestResponseTimes()
 	expected := time.Duration(21)
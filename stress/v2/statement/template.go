@@ -4,6 +4,12 @@ package statement
 type Template struct {
 	Tags     []string
 	Function *Function
+
+	// Chance is the probability that an optional field is written, or 0 if
+	// the field is always written. The comma and key of an optional field
+	// are part of the template, in Key.
+	Chance float64
+	Key    string
 }
 
 // Templates are a collection of Template
@@ -15,9 +21,12 @@ func (t Templates) Init(seriesCount int) Stringers {
 	for i, tmp := range t {
 		if len(tmp.Tags) == 0 {
 			arr[i] = tmp.Function.NewStringer(seriesCount)
-			continue
+		} else {
+			arr[i] = tmp.NewTagFunc()
+		}
+		if tmp.Chance > 0 {
+			arr[i] = chance(tmp.Chance, tmp.Key, arr[i])
 		}
-		arr[i] = tmp.NewTagFunc()
 	}
 	return arr
 }
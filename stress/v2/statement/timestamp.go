@@ -2,6 +2,7 @@ package statement
 
 import (
 	"log"
+	"math/rand"
 	"time"
 )
 
@@ -9,7 +10,15 @@ import (
 type Timestamp struct {
 	Count    int
 	Duration time.Duration
-	Jitter   bool
+
+	// Jitter moves each point back in time by up to Jitter, so points are
+	// written out of order
+	Jitter time.Duration
+
+	// Late is the fraction of points written late, moved back in time by up
+	// to LateBy
+	Late   float64
+	LateBy time.Duration
 }
 
 // Time returns the next timestamp needed by the InsertStatement
@@ -27,7 +36,25 @@ func (t *Timestamp) Time(startDate string, series int, precision string) func()
 		log.Fatalf("Error parsing start time from StartDate\n  string: %v\n  error: %v\n", startDate, err)
 	}
 
-	return nextTime(start, t.Duration, series, precision)
+	next := nextTime(start, t.Duration, series, precision)
+	if t.Jitter <= 0 && (t.Late <= 0 || t.LateBy <= 0) {
+		return next
+	}
+
+	unit := int64(time.Nanosecond)
+	if precision == "s" {
+		unit = int64(time.Second)
+	}
+	return func() int64 {
+		var d int64
+		if t.Jitter > 0 {
+			d += rand.Int63n(int64(t.Jitter))
+		}
+		if t.LateBy > 0 && rand.Float64() < t.Late {
+			d += 1 + rand.Int63n(int64(t.LateBy))
+		}
+		return next() - d/unit
+	}
 }
 
 func nextTime(ti time.Time, step time.Duration, series int, precision string) func() int64 {
@@ -21,11 +21,38 @@ func TestTimestampTime(t *testing.T) {
 	}
 }
 
+func TestTimestampTimeJitterLate(t *testing.T) {
+	tstp := &Timestamp{Count: 1000, Duration: 10 * time.Second, Jitter: 5 * time.Second}
+	function := tstp.Time("2016-01-01", 1, "s")
+	start := int64(1451606400)
+	for i := int64(0); i < 1000; i++ {
+		exp := start + i*10
+		if got := function(); got > exp || got <= exp-5 {
+			t.Fatalf("expected: a time between %v and %v\ngot: %v\n", exp-5, exp, got)
+		}
+	}
+
+	tstp = &Timestamp{Count: 1000, Duration: 10 * time.Second, Late: 0.1, LateBy: time.Hour}
+	function = tstp.Time("2016-01-01", 1, "s")
+	late := 0
+	for i := int64(0); i < 1000; i++ {
+		exp := start + i*10
+		if got := function(); got < exp {
+			late++
+			if got < exp-3600 {
+				t.Fatalf("expected: a time after %v\ngot: %v\n", exp-3600, got)
+			}
+		}
+	}
+	if late < 50 || late > 150 {
+		t.Errorf("expected: about 100 late points\ngot: %v\n", late)
+	}
+}
+
 func newTestTimestamp() *Timestamp {
 	duration, _ := time.ParseDuration("10s")
 	return &Timestamp{
 		Count:    5001,
 		Duration: duration,
-		Jitter:   false,
 	}
 }
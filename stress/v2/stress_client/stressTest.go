@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	influx "github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/stress/report"
 )
 
 // NewStressTest creates the backend for the stress test
@@ -32,6 +34,7 @@ func NewStressTest() *StressTest {
 		ResultsChan:   responseCh,
 		communes:      make(map[string]*commune),
 		TestID:        randStr(10),
+		Report:        report.New(time.Now()),
 	}
 
 	// Start the client service
@@ -60,6 +63,7 @@ func NewTestStressTest() (*StressTest, chan Package, chan Directive) {
 
 		communes: make(map[string]*commune),
 		TestID:   randStr(10),
+		Report:   report.New(time.Now()),
 	}
 
 	return s, packageCh, directiveCh
@@ -83,6 +87,9 @@ type StressTest struct {
 	ResultsChan   chan Response
 	communes      map[string]*commune
 	ResultsClient influx.Client
+
+	// Report collects the results of the statements as they are reported
+	Report *report.Report
 }
 
 // SendPackage is the public facing API for to send Queries and Points
@@ -208,9 +208,9 @@ func (s *Scanner) scanNumber() (tok Token, lit string) {
 	for {
 		if ch := s.read(); ch == eof {
 			break
-		} else if ch == 'n' || ch == 's' || ch == 'm' {
+		} else if isDurationUnit(ch) {
 			_, _ = buf.WriteRune(ch)
-			return DURATIONVAL, buf.String()
+			return s.scanDuration(&buf)
 		} else if !isDigit(ch) {
 			s.unread()
 			break
@@ -222,6 +222,26 @@ func (s *Scanner) scanNumber() (tok Token, lit string) {
 	return NUMBER, buf.String()
 }
 
+func isDurationUnit(ch rune) bool {
+	return ch == 'n' || ch == 'u' || ch == 'µ' || ch == 'm' || ch == 's' || ch == 'h'
+}
+
+// scanDuration scans the rest of a duration, like the "s" of "100ms" or the
+// "30m" of "1h30m"
+func (s *Scanner) scanDuration(buf *bytes.Buffer) (tok Token, lit string) {
+	for {
+		if ch := s.read(); ch == eof {
+			break
+		} else if !isDurationUnit(ch) && !isDigit(ch) {
+			s.unread()
+			break
+		} else {
+			_, _ = buf.WriteRune(ch)
+		}
+	}
+	return DURATIONVAL, buf.String()
+}
+
 /////////////////////////////////
 // PARSER ///////////////////////
 /////////////////////////////////
@@ -376,6 +396,20 @@ func (p *Parser) ParseInsertStatement() (*statement.InsertStatement, error) {
 				return nil, err
 			}
 
+			// The comma and key of an optional field are written with its value
+			if expr.Chance > 0 {
+				if inTags {
+					return nil, fmt.Errorf("Error parsing Insert Statement\n  Tags can not be optional\n")
+				}
+				tmpl := strings.TrimSuffix(stmt.TemplateString, "%v")
+				i := strings.LastIndexAny(tmpl, ", ")
+				if i < 0 || tmpl[i] != ',' {
+					return nil, fmt.Errorf("Error parsing Insert Statement\n  The first field can not be optional\n")
+				}
+				expr.Key = tmpl[i:]
+				stmt.TemplateString = tmpl[:i] + "%v"
+			}
+
 			// Add template to parsed select statement
 			stmt.Templates = append(stmt.Templates, expr)
 
@@ -420,8 +454,19 @@ func (p *Parser) ParseTemplate() (*statement.Template, error) {
 		// Scan to start loop
 		tok, lit := p.scanIgnoreWhitespace()
 
-		// If the tok == IDENT explicit tags are passed. Add them to the list of tags
-		if tok == IDENT {
+		// The probability of an optional field follows its function
+		if tok == IDENT && lit == "chance" && tmplt.Function != nil {
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			if len(args) != 1 || args[0] <= 0 || args[0] > 1 {
+				return nil, fmt.Errorf("Error parsing Insert template\n  Expected: chance(probability) with a probability between 0 and 1\n")
+			}
+			tmplt.Chance = args[0]
+
+			// If the tok == IDENT explicit tags are passed. Add them to the list of tags
+		} else if tok == IDENT {
 			tmplt.Tags = append(tmplt.Tags, lit)
 
 			// Different flavors of functions
@@ -569,37 +614,41 @@ func (p *Parser) ParseFunction() (*statement.Function, error) {
 	_, lit = p.scanIgnoreWhitespace()
 	fn.Fn = lit
 
-	tok, lit := p.scanIgnoreWhitespace()
-	if tok != LPAREN {
-		return nil, fmt.Errorf("Error parsing Insert template function\n  Expected: LPAREN\n  Found: %v\n", lit)
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
 	}
-
-	tok, lit = p.scanIgnoreWhitespace()
-	if tok != NUMBER {
-		return nil, fmt.Errorf("Error parsing Insert template function\n  Expected: NUMBER\n  Found: %v\n", lit)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Error parsing Insert template function\n  Expected: NUMBER\n  Found: )\n")
 	}
 
-	// Parse out the integer
-	i, err := strconv.ParseInt(lit, 10, 64)
-
-	if err != nil {
-		log.Fatalf("Error parsing integer in Insert template function:\n  string: %v\n  error: %v\n", lit, err)
+	fn.Argument = int(args[0])
+	if len(args) > 1 {
+		fn.Args = args[1:]
 	}
 
-	fn.Argument = int(i)
-
-	tok, _ = p.scanIgnoreWhitespace()
-	if tok != RPAREN {
-		return nil, fmt.Errorf("Error parsing Insert template function\n  Expected: RPAREN\n  Found: %v\n", lit)
+	switch fn.Fn {
+	case "zipf":
+		if fn.Argument <= 0 || len(fn.Args) > 1 || len(fn.Args) == 1 && fn.Args[0] <= 1 {
+			return nil, fmt.Errorf("Error parsing Insert template function\n  Expected: zipf(n) or zipf(n, s) with n > 0 and s > 1\n")
+		}
+	case "churn":
+		if fn.Argument <= 0 || len(fn.Args) != 1 || fn.Args[0] < 1 {
+			return nil, fmt.Errorf("Error parsing Insert template function\n  Expected: churn(n, lifetime) with n > 0 and lifetime > 0\n")
+		}
+	default:
+		if len(fn.Args) > 0 {
+			return nil, fmt.Errorf("Error parsing Insert template function\n  Expected: %v(NUMBER)\n", fn.Fn)
+		}
 	}
 
-	tok, lit = p.scanIgnoreWhitespace()
+	tok, lit := p.scanIgnoreWhitespace()
 	if tok != NUMBER {
 		return nil, fmt.Errorf("Error parsing Insert template function\n  Expected: NUMBER\n  Found: %v\n", lit)
 	}
 
 	// Parse out the integer
-	i, err = strconv.ParseInt(lit, 10, 64)
+	i, err := strconv.ParseInt(lit, 10, 64)
 
 	if err != nil {
 		log.Fatalf("Error parsing integer in Insert template function:\n  string: %v\n  error: %v\n", lit, err)
@@ -643,9 +692,101 @@ func (p *Parser) ParseTimestamp() (*statement.Timestamp, error) {
 
 	ts.Duration = dur
 
+	// Out of order and late points
+	for {
+		tok, lit = p.scanIgnoreWhitespace()
+		if tok == EOF {
+			break
+		} else if tok != IDENT {
+			return nil, fmt.Errorf("Error parsing Insert timestamp\n  Expected: jitter or late\n  Found: %v\n", lit)
+		}
+
+		switch lit {
+		case "jitter":
+			args, err := p.parseDurationArgs()
+			if err != nil {
+				return nil, err
+			}
+			if len(args) != 1 {
+				return nil, fmt.Errorf("Error parsing Insert timestamp\n  Expected: jitter(DURATION)\n")
+			}
+			ts.Jitter = time.Duration(args[0])
+		case "late":
+			args, err := p.parseDurationArgs()
+			if err != nil {
+				return nil, err
+			}
+			if len(args) != 2 || args[0] <= 0 || args[0] > 1 || args[1] < 1 {
+				return nil, fmt.Errorf("Error parsing Insert timestamp\n  Expected: late(fraction, DURATION) with a fraction between 0 and 1\n")
+			}
+			ts.Late = args[0]
+			ts.LateBy = time.Duration(args[1])
+		default:
+			return nil, fmt.Errorf("Error parsing Insert timestamp\n  Expected: jitter or late\n  Found: %v\n", lit)
+		}
+	}
+
 	return ts, nil
 }
 
+// parseArgs parses the numbers between parentheses of a function
+func (p *Parser) parseArgs() ([]float64, error) {
+	return p.parseFuncArgs(false)
+}
+
+// parseDurationArgs parses the numbers and durations, in nanoseconds, between
+// parentheses of a function
+func (p *Parser) parseDurationArgs() ([]float64, error) {
+	return p.parseFuncArgs(true)
+}
+
+func (p *Parser) parseFuncArgs(durations bool) ([]float64, error) {
+	if tok, lit := p.scanIgnoreWhitespace(); tok != LPAREN {
+		return nil, fmt.Errorf("Error parsing function arguments\n  Expected: LPAREN\n  Found: %v\n", lit)
+	}
+
+	var args []float64
+	for {
+		tok, lit := p.scanIgnoreWhitespace()
+		if tok == RPAREN && len(args) == 0 {
+			return args, nil
+		}
+
+		switch {
+		case tok == DURATIONVAL && durations:
+			d, err := time.ParseDuration(lit)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing function arguments\n  Bad duration: %v\n", lit)
+			}
+			args = append(args, float64(d))
+		case tok == NUMBER:
+			// A NUMBER, PERIOD, NUMBER sequence is a decimal number
+			if tok, _ := p.scan(); tok == PERIOD {
+				tok, frac := p.scan()
+				if tok != NUMBER {
+					return nil, fmt.Errorf("Error parsing function arguments\n  Expected: NUMBER\n  Found: %v\n", frac)
+				}
+				lit += "." + frac
+			} else {
+				p.unscan()
+			}
+			f, err := strconv.ParseFloat(lit, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing function arguments\n  Bad number: %v\n", lit)
+			}
+			args = append(args, f)
+		default:
+			return nil, fmt.Errorf("Error parsing function arguments\n  Expected: NUMBER\n  Found: %v\n", lit)
+		}
+
+		if tok, lit := p.scanIgnoreWhitespace(); tok == RPAREN {
+			return args, nil
+		} else if tok != COMMA {
+			return nil, fmt.Errorf("Error parsing function arguments\n  Expected: COMMA or RPAREN\n  Found: %v\n", lit)
+		}
+	}
+}
+
 func (p *Parser) scan() (tok Token, lit string) {
 	// If we have a token on the buffer, then return it.
 	if p.buf.n != 0 {
@@ -129,6 +129,41 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		{
+			s: "INSERT churn\ncontainer,\nid=[str churn(100, 60) 100],region=[int zipf(10, 1.5) 10]\nbusy=[int zipf(1000) 0],free=[float rand(10) 0 chance(0.25)],used=[int inc(0) 0]\n100000 10s jitter(30s) late(0.01, 2h)",
+			stmt: &statement.InsertStatement{
+				Name:           "churn",
+				TemplateString: "container,id=%v,region=%v busy=%v%v,used=%v %v",
+				TagCount:       2,
+				Templates: []*statement.Template{
+					&statement.Template{
+						Function: &statement.Function{Type: "str", Fn: "churn", Argument: 100, Args: []float64{60}, Count: 100},
+					},
+					&statement.Template{
+						Function: &statement.Function{Type: "int", Fn: "zipf", Argument: 10, Args: []float64{1.5}, Count: 10},
+					},
+					&statement.Template{
+						Function: &statement.Function{Type: "int", Fn: "zipf", Argument: 1000, Count: 0},
+					},
+					&statement.Template{
+						Function: &statement.Function{Type: "float", Fn: "rand", Argument: 10, Count: 0},
+						Chance:   0.25,
+						Key:      ",free=",
+					},
+					&statement.Template{
+						Function: &statement.Function{Type: "int", Fn: "inc", Argument: 0, Count: 0},
+					},
+				},
+				Timestamp: &statement.Timestamp{
+					Count:    100000,
+					Duration: time.Duration(10 * time.Second),
+					Jitter:   30 * time.Second,
+					Late:     0.01,
+					LateBy:   2 * time.Hour,
+				},
+			},
+		},
+
 		{
 			skip: true, // Expected error not working
 			s:    "INSERT\ncpu,\nhost=[us-west|us-east|eu-north],server_id=[str rand(7) 1000]\nbusy=[int rand(1000) 100],free=[float rand(10) 0]\n100000 10s",
@@ -241,3 +276,48 @@ func TestParser_ParseStatement(t *testing.T) {
 	}
 
 }
+
+func TestParser_ParseInsertStatement_Errors(t *testing.T) {
+	for _, tst := range []struct {
+		s   string
+		err string
+	}{
+		{
+			s:   "INSERT cpu\ncpu,\nhost=[str rand(7) 10 chance(0.5)]\nbusy=[int rand(1000) 0]\n100 10s",
+			err: "Tags can not be optional",
+		},
+		{
+			s:   "INSERT cpu\ncpu,\nhost=[str rand(7) 10]\nbusy=[int rand(1000) 0 chance(0.5)]\n100 10s",
+			err: "The first field can not be optional",
+		},
+		{
+			s:   "INSERT cpu\ncpu,\nhost=[str rand(7) 10]\nbusy=[int rand(1000) 0],free=[int rand(1000) 0 chance(2)]\n100 10s",
+			err: "chance(probability)",
+		},
+		{
+			s:   "INSERT cpu\ncpu,\nhost=[str zipf(10, 1) 10]\nbusy=[int rand(1000) 0]\n100 10s",
+			err: "zipf(n)",
+		},
+		{
+			s:   "INSERT cpu\ncpu,\nhost=[str churn(10) 10]\nbusy=[int rand(1000) 0]\n100 10s",
+			err: "churn(n, lifetime)",
+		},
+		{
+			s:   "INSERT cpu\ncpu,\nhost=[str rand(7, 2) 10]\nbusy=[int rand(1000) 0]\n100 10s",
+			err: "rand(NUMBER)",
+		},
+		{
+			s:   "INSERT cpu\ncpu,\nhost=[str rand(7) 10]\nbusy=[int rand(1000) 0]\n100 10s late(0.5)",
+			err: "late(fraction, DURATION)",
+		},
+		{
+			s:   "INSERT cpu\ncpu,\nhost=[str rand(7) 10]\nbusy=[int rand(1000) 0]\n100 10s early(1s)",
+			err: "Expected: jitter or late",
+		},
+	} {
+		_, err := newParserFromString(tst.s).Parse()
+		if err == nil || !strings.Contains(err.Error(), tst.err) {
+			t.Errorf("%s:\nexpected error containing: %v\ngot: %v\n", tst.s, tst.err, err)
+		}
+	}
+}
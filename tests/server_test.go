@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -14,6 +17,7 @@ import (
 
 	"github.com/influxdata/influxdb/coordinator"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/toml"
 	"github.com/influxdata/influxdb/tsdb"
 )
 
@@ -1174,6 +1178,553 @@ func TestServer_Query_Count(t *testing.T) {
 	}
 }
 
+// Ensure a reload applies the query limits and reports the changes requiring a restart.
+func TestServer_Reload(t *testing.T) {
+	t.Parallel()
+	s := OpenServer(NewConfig())
+	defer s.Close()
+
+	local, ok := s.(*LocalServer)
+	if !ok {
+		t.Skip("Skipping.  Cannot reload the configuration remotely")
+	}
+
+	test := NewTest("db0", "rp0")
+	test.writes = Writes{
+		&Write{data: `cpu,host=server01 value=1.0 0`},
+		&Write{data: `cpu,host=server02 value=1.0 0`},
+	}
+	if err := test.init(s); err != nil {
+		t.Fatalf("test init failed: %s", err)
+	}
+
+	c := *local.Config
+	meta := *c.Meta
+	c.Meta = &meta
+	c.Coordinator.MaxSelectSeriesN = 1
+	c.Data.CacheMaxMemorySize = c.Data.CacheMaxMemorySize * 2
+
+	restart, err := local.Server.Reload(&c)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(restart, []string{"data.cache-max-memory-size"}) {
+		t.Fatalf("unexpected settings requiring a restart: %v", restart)
+	}
+
+	query := &Query{
+		command: `SELECT COUNT(value) FROM db0.rp0.cpu`,
+		exp:     `{"results":[{"statement_id":0,"error":"max-select-series limit exceeded: (2/1)"}]}`,
+	}
+	if err := query.Execute(s); err != nil {
+		t.Fatal(query.Error(err))
+	} else if !query.success() {
+		t.Fatal(query.failureMessage())
+	}
+
+	// The next reload is compared with the applied settings: the settings
+	// requiring a restart are still reported, and the query limit is reverted.
+	c.Coordinator.MaxSelectSeriesN = 0
+	restart, err = local.Server.Reload(&c)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(restart, []string{"data.cache-max-memory-size"}) {
+		t.Fatalf("unexpected settings requiring a restart: %v", restart)
+	} else if c.Data.CacheMaxMemorySize != local.Config.Data.CacheMaxMemorySize*2 {
+		t.Fatal("reload changed the config")
+	}
+
+	query.exp = `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","count"],"values":[["1970-01-01T00:00:00Z",2]]}]}]}`
+	if err := query.Execute(s); err != nil {
+		t.Fatal(query.Error(err))
+	} else if !query.success() {
+		t.Fatal(query.failureMessage())
+	}
+
+	// A reload with a provisioning file that cannot be loaded changes nothing.
+	c.Coordinator.MaxSelectSeriesN = 1
+	c.Provision.File = filepath.Join(os.TempDir(), "influxdb-provision-missing.toml")
+	if _, err := local.Server.Reload(&c); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := query.Execute(s); err != nil {
+		t.Fatal(query.Error(err))
+	} else if !query.success() {
+		t.Fatal(query.failureMessage())
+	}
+}
+
+// Ensure the server applies the provisioning file when opened and reloaded.
+func TestServer_Provision(t *testing.T) {
+	t.Parallel()
+
+	f, err := ioutil.TempFile("", "influxdb-provision-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	writeFile := func(duration string) {
+		if err := ioutil.WriteFile(f.Name(), []byte(fmt.Sprintf(`
+[[database]]
+  name = "db0"
+  continuous-queries = ["CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT mean(value) INTO cpu_1h FROM cpu GROUP BY time(1h) END"]
+
+  [[database.retention-policy]]
+    name = "rp0"
+    duration = %q
+    shard-duration = "1d"
+    default = true
+
+[[user]]
+  name = "reader"
+  password = "secret"
+  privileges = { db0 = "READ" }
+`, duration)), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("7d")
+
+	config := NewConfig()
+	config.Provision.File = f.Name()
+	s := OpenServer(config)
+	defer s.Close()
+
+	local, ok := s.(*LocalServer)
+	if !ok {
+		t.Skip("Skipping.  Cannot provision remotely")
+	}
+
+	queries := func(duration string) []*Query {
+		return []*Query{
+			{
+				command: `SHOW RETENTION POLICIES ON db0`,
+				exp:     fmt.Sprintf(`{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["autogen","0s","168h0m0s",1,false],["rp0","%s","24h0m0s",1,true]]}]}]}`, duration),
+			},
+			{
+				command: `SHOW CONTINUOUS QUERIES`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"name":"db0","columns":["name","query"],"values":[["cq0","CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT mean(value) INTO db0.rp0.cpu_1h FROM db0.rp0.cpu GROUP BY time(1h) END"]]}]}]}`,
+			},
+			{
+				command: `SHOW GRANTS FOR reader`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["database","privilege"],"values":[["db0","READ"]]}]}]}`,
+			},
+		}
+	}
+	for _, query := range queries("168h0m0s") {
+		if err := query.Execute(s); err != nil {
+			t.Fatal(query.Error(err))
+		} else if !query.success() {
+			t.Fatal(query.failureMessage())
+		}
+	}
+
+	// A dry run leaves the changed retention policy unchanged.
+	writeFile("14d")
+	c := *local.Config
+	c.Provision.DryRun = true
+	if _, err := local.Server.Reload(&c); err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range queries("168h0m0s") {
+		if err := query.Execute(s); err != nil {
+			t.Fatal(query.Error(err))
+		} else if !query.success() {
+			t.Fatal(query.failureMessage())
+		}
+	}
+
+	// Reloading applies the changes and is idempotent.
+	c.Provision.DryRun = false
+	for i := 0; i < 2; i++ {
+		if _, err := local.Server.Reload(&c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, query := range queries("336h0m0s") {
+		if err := query.Execute(s); err != nil {
+			t.Fatal(query.Error(err))
+		} else if !query.success() {
+			t.Fatal(query.failureMessage())
+		}
+	}
+}
+
+// Ensure the meta store can be exported and imported as JSON.
+func TestServer_MetaExportImport(t *testing.T) {
+	t.Parallel()
+	s := OpenServer(NewConfig())
+	defer s.Close()
+
+	if err := s.CreateDatabaseAndRetentionPolicy("db0", newRetentionPolicySpec("rp0", 1, 0), true); err != nil {
+		t.Fatal(err)
+	}
+
+	test := NewTest("db0", "rp0")
+	test.addQueries([]*Query{
+		&Query{
+			name:    "export meta",
+			command: `EXPORT META WITHOUT HASHES`,
+			exp:     `{"results":[{"statement_id":0,"series":[{"name":"meta","columns":["json"],"values":[["{\"cluster_id\":%d,\"max_shard_group_id\":0,\"max_shard_id\":0,\"databases\":[{\"name\":\"db0\",\"default_retention_policy\":\"rp0\",\"retention_policies\":[{\"name\":\"autogen\",\"duration\":\"0s\",\"shard_group_duration\":\"168h0m0s\",\"replication\":1,\"shard_groups\":[],\"subscriptions\":[]},{\"name\":\"rp0\",\"duration\":\"0s\",\"shard_group_duration\":\"168h0m0s\",\"replication\":1,\"shard_groups\":[],\"subscriptions\":[]}],\"continuous_queries\":[]}],\"users\":[]}"]]}]}]}`,
+		},
+		&Query{
+			name:    "import meta",
+			command: `IMPORT META '{"databases":[{"name":"db1","retention_policies":[{"name":"rp1","duration":"24h0m0s","shard_group_duration":"1h0m0s","replication":1}]}]}'`,
+			exp:     `{"results":[{"statement_id":0}]}`,
+		},
+		&Query{
+			name:    "show imported retention policies",
+			command: `SHOW RETENTION POLICIES ON db1`,
+			exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["rp1","24h0m0s","1h0m0s",1,false]]}]}]}`,
+		},
+		&Query{
+			name:    "import invalid meta",
+			command: `IMPORT META '{"databases":[{"name":""}]}'`,
+			exp:     `{"results":[{"statement_id":0,"error":"invalid meta data: database name required"}]}`,
+		},
+	}...)
+
+	if local, ok := s.(*LocalServer); ok {
+		test.queries[0].exp = fmt.Sprintf(test.queries[0].exp, local.MetaClient.ClusterID())
+	} else {
+		t.Skip("Skipping.  Cannot read the cluster ID remotely")
+	}
+
+	for _, query := range test.queries {
+		t.Run(query.name, func(t *testing.T) {
+			if err := query.Execute(s); err != nil {
+				t.Error(query.Error(err))
+			} else if !query.success() {
+				t.Error(query.failureMessage())
+			}
+		})
+	}
+
+	// Replacing the meta store does not drop shards.
+	s.MustWrite("db0", "rp0", `cpu value=1 1000000000`, nil)
+	query := &Query{
+		name:    "replace meta dropping a shard",
+		command: `IMPORT META '{"databases":[{"name":"db1"}]}' REPLACE`,
+		exp:     `{"results":[{"statement_id":0,"error":"shard 1 of db0.rp0 is not in the imported data: drop it before replacing"}]}`,
+	}
+	if err := query.Execute(s); err != nil {
+		t.Error(query.Error(err))
+	} else if !query.success() {
+		t.Error(query.failureMessage())
+	}
+}
+
+// Ensure writes are rejected once a database exceeds its quotas.
+func TestServer_DatabaseQuota(t *testing.T) {
+	t.Parallel()
+	config := NewConfig()
+	config.Coordinator.QuotaCheckInterval = 0
+	s := OpenServer(config)
+	defer s.Close()
+
+	if _, ok := s.(*RemoteServer); ok {
+		t.Skip("Skipping.  Cannot modify QuotaCheckInterval remotely")
+	}
+
+	if err := s.CreateDatabaseAndRetentionPolicy("db0", newRetentionPolicySpec("rp0", 1, 0), true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=1\ncpu,host=server02 value=1", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if res, err := s.Query(`ALTER DATABASE db0 SERIES QUOTA 1`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=2", nil); err == nil || !strings.Contains(err.Error(), `database \"db0\" exceeded its series quota: 2 of 1 series`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The usage is reported with the quotas.
+	res, err := s.Query(`SHOW QUOTAS ON db0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Results []struct {
+			Series []struct {
+				Columns []string
+				Values  [][]interface{}
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(res), &resp); err != nil {
+		t.Fatal(err)
+	} else if len(resp.Results) != 1 || len(resp.Results[0].Series) != 1 || len(resp.Results[0].Series[0].Values) != 1 {
+		t.Fatalf("unexpected results: %s", res)
+	}
+	if row := resp.Results[0].Series[0].Values[0]; row[0] != "db0" || row[1].(float64) <= 0 || row[2] != 0.0 || row[3] != 2.0 || row[4] != 1.0 {
+		t.Fatalf("unexpected quotas: %v", row)
+	}
+
+	// Removing the quota accepts writes again.
+	if _, err := s.Query(`ALTER DATABASE db0 SERIES QUOTA 0`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=2", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure the server rejects writes to read-only databases, retention policies and shards.
+func TestServer_ReadOnly(t *testing.T) {
+	t.Parallel()
+	s := OpenServer(NewConfig())
+	defer s.Close()
+
+	if err := s.CreateDatabaseAndRetentionPolicy("db0", newRetentionPolicySpec("rp0", 1, 0), true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=1 0", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if res, err := s.Query(`ALTER DATABASE db0 READ ONLY`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=2 1", nil); err == nil || !strings.Contains(err.Error(), `database \"db0\" is read-only`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res, err := s.QueryWithParams(`DROP SERIES FROM cpu`, url.Values{"db": []string{"db0"}}); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0,"error":"database \"db0\" is read-only"}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	// Queries are still served.
+	if res, err := s.Query(`SELECT value FROM db0.rp0.cpu`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[["1970-01-01T00:00:00Z",1]]}]}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	if _, err := s.Query(`ALTER DATABASE db0 READ WRITE`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Query(`ALTER RETENTION POLICY rp0 ON db0 READ ONLY`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=2 1", nil); err == nil || !strings.Contains(err.Error(), `retention policy \"rp0\" on database \"db0\" is read-only`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := s.Query(`ALTER RETENTION POLICY rp0 ON db0 READ WRITE`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Query(`ALTER SHARD 1 READ ONLY`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=2 1", nil); err == nil || !strings.Contains(err.Error(), `shard 1 of database \"db0\" is read-only`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := s.Query(`ALTER SHARD 1 READ WRITE`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=2 1", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure the server can move and copy shards between retention policies.
+func TestServer_MoveShard(t *testing.T) {
+	t.Parallel()
+	s := OpenServer(NewConfig())
+	defer s.Close()
+
+	if err := s.CreateDatabaseAndRetentionPolicy("db0", newRetentionPolicySpec("rp0", 1, 0), true); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateDatabaseAndRetentionPolicy("db0", newRetentionPolicySpec("rp1", 1, 0), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateDatabaseAndRetentionPolicy("db1", newRetentionPolicySpec("rp0", 1, 0), true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=1 0", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if res, err := s.Query(`MOVE SHARD 1 TO db0.rp1`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+	if res, err := s.Query(`SELECT value FROM db0.rp0.cpu`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+	if res, err := s.Query(`SELECT value FROM db0.rp1.cpu`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[["1970-01-01T00:00:00Z",1]]}]}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	if res, err := s.Query(`COPY SHARD 1 TO db1.rp0`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+	if res, err := s.Query(`SELECT value FROM db1.rp0.cpu`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[["1970-01-01T00:00:00Z",1]]}]}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	// The copy is independent of the original shard.
+	if _, err := s.Write("db1", "rp0", "cpu,host=server01 value=2 1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if res, err := s.Query(`SELECT count(value) FROM db0.rp1.cpu`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","count"],"values":[["1970-01-01T00:00:00Z",1]]}]}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	// A shard group cannot overlap a shard group of the target.
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=3 0", nil); err != nil {
+		t.Fatal(err)
+	}
+	if res, err := s.Query(`MOVE SHARD 3 TO db0.rp1`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0,"error":"shard group overlaps a shard group of the retention policy"}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+}
+
+// Ensure the server enforces measurement schemas on writes.
+func TestServer_MeasurementSchema(t *testing.T) {
+	t.Parallel()
+	s := OpenServer(NewConfig())
+	defer s.Close()
+
+	if err := s.CreateDatabaseAndRetentionPolicy("db0", newRetentionPolicySpec("rp0", 1, 0), true); err != nil {
+		t.Fatal(err)
+	}
+
+	if res, err := s.Query(`CREATE SCHEMA cpu ON db0 REQUIRED TAGS host FIELDS value float`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	if res, err := s.Query(`SHOW SCHEMAS ON db0`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0,"series":[{"columns":["measurement","mode","tag_keys","required_tags","fields"],"values":[["cpu","strict","","host","value float"]]}]}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	// Points violating the schema are dropped, the others are written.
+	if _, err := s.Write("db0", "rp0", "cpu,host=server01 value=1 0\ncpu value=2 1\ncpu,host=server01 value=3i 2", nil); err == nil || !strings.Contains(err.Error(), `partial write: schema violation: measurement \"cpu\" requires tag \"host\" dropped=2`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res, err := s.Query(`SELECT value FROM db0.rp0.cpu`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[["1970-01-01T00:00:00Z",1]]}]}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+
+	// Once dropped, the schema is no longer enforced.
+	if _, err := s.Query(`DROP SCHEMA cpu ON db0`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write("db0", "rp0", "cpu value=2 1", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure a read replica replicates the meta store and the writes of its leader.
+func TestServer_ReadReplica(t *testing.T) {
+	t.Parallel()
+	c := NewConfig()
+	c.Replication.Enabled = true
+	c.Replication.Dir = MustTempFile()
+	defer os.RemoveAll(c.Replication.Dir)
+	leader := OpenServer(c)
+	defer leader.Close()
+
+	ls, ok := leader.(*LocalServer)
+	if !ok {
+		t.Skip("Skipping.  Cannot replicate a remote server")
+	}
+
+	if err := leader.CreateDatabaseAndRetentionPolicy("db0", newRetentionPolicySpec("rp0", 1, 0), true); err != nil {
+		t.Fatal(err)
+	}
+	// Writes before the follower starts are replicated as it catches up.
+	leader.MustWrite("db0", "rp0", "cpu,host=server01 value=1 0", nil)
+
+	fc := NewConfig()
+	fc.Replication.Dir = MustTempFile()
+	fc.Replication.Leader = ls.Listener.Addr().String()
+	fc.Replication.RetryInterval = toml.Duration(10 * time.Millisecond)
+	defer os.RemoveAll(fc.Replication.Dir)
+	follower := OpenServer(fc)
+	defer follower.Close()
+
+	leader.MustWrite("db0", "rp0", "cpu,host=server02 value=2 1", nil)
+
+	waitForResults := func(command, exp string) {
+		var res string
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			var err error
+			if res, err = follower.Query(command); err != nil {
+				t.Fatal(err)
+			} else if res == exp {
+				return
+			}
+		}
+		t.Fatalf("unexpected results for %s: %s", command, res)
+	}
+	waitForResults(`SELECT * FROM db0.rp0.cpu`, `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","host","value"],"values":[["1970-01-01T00:00:00Z","server01",1],["1970-01-01T00:00:00.000000001Z","server02",2]]}]}]}`)
+
+	// Changes of the meta store are replicated.
+	if _, err := leader.Query(`CREATE DATABASE db1`); err != nil {
+		t.Fatal(err)
+	}
+	waitForResults(`SHOW DATABASES`, `{"results":[{"statement_id":0,"series":[{"name":"databases","columns":["name"],"values":[["db0"],["db1"]]}]}]}`)
+
+	// Deletes and drops are replicated.
+	leader.MustWrite("db1", "autogen", "mem value=1 0", nil)
+	waitForResults(`SELECT * FROM db1.autogen.mem`, `{"results":[{"statement_id":0,"series":[{"name":"mem","columns":["time","value"],"values":[["1970-01-01T00:00:00Z",1]]}]}]}`)
+	if _, err := leader.QueryWithParams(`DELETE FROM cpu WHERE host = 'server01'`, url.Values{"db": []string{"db0"}}); err != nil {
+		t.Fatal(err)
+	} else if _, err := leader.Query(`DROP DATABASE db1`); err != nil {
+		t.Fatal(err)
+	}
+	waitForResults(`SELECT * FROM db0.rp0.cpu`, `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","host","value"],"values":[["1970-01-01T00:00:00.000000001Z","server02",2]]}]}]}`)
+	waitForResults(`SHOW DATABASES`, `{"results":[{"statement_id":0,"series":[{"name":"databases","columns":["name"],"values":[["db0"]]}]}]}`)
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if dbs := follower.(*LocalServer).TSDBStore.Databases(); reflect.DeepEqual(dbs, []string{"db0"}) {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("unexpected local databases: %v", dbs)
+		}
+	}
+
+	// The follower only serves reads.
+	if _, err := follower.Write("db0", "rp0", "cpu value=3 2", nil); err == nil || !strings.Contains(err.Error(), "not permitted on a read replica") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res, err := follower.Query(`DROP DATABASE db1`); err != nil {
+		t.Fatal(err)
+	} else if exp := `{"results":[{"statement_id":0,"error":"not permitted on a read replica, use the leader"}]}`; res != exp {
+		t.Fatalf("unexpected results: %s", res)
+	}
+}
+
 // Ensure the server can limit concurrent series.
 func TestServer_Query_MaxSelectSeriesN(t *testing.T) {
 	t.Parallel()
@@ -3034,6 +3585,102 @@ test,t=b y=3i 3000000000
 	}
 }
 
+func TestServer_Query_TimeShift(t *testing.T) {
+	t.Parallel()
+	s := OpenServer(NewConfig())
+	defer s.Close()
+
+	test := NewTest("db0", "rp0")
+	test.writes = Writes{
+		&Write{data: `cpu,host=a value=1 0
+cpu,host=a value=3 3600000000000
+cpu,host=a value=5 604800000000000
+cpu,host=a value=9 608400000000000
+`},
+	}
+
+	test.addQueries([]*Query{
+		&Query{
+			name:    "compare with the previous week",
+			command: `SELECT mean(value) AS value, time_shift(mean(value), 7d) AS last_week, mean(value) - time_shift(mean(value), 7d) AS diff FROM db0.rp0.cpu WHERE time >= '1970-01-08T00:00:00Z' AND time < '1970-01-08T02:00:00Z' GROUP BY time(1h), host`,
+			exp:     `{"results":[{"statement_id":0,"series":[{"name":"cpu","tags":{"host":"a"},"columns":["time","value","last_week","diff"],"values":[["1970-01-08T00:00:00Z",5,1,4],["1970-01-08T01:00:00Z",9,3,6]]}]}]}`,
+		},
+	}...)
+
+	for i, query := range test.queries {
+		t.Run(query.name, func(t *testing.T) {
+			if i == 0 {
+				if err := test.init(s); err != nil {
+					t.Fatalf("test init failed: %s", err)
+				}
+			}
+			if query.skip {
+				t.Skipf("SKIP:: %s", query.name)
+			}
+			if err := query.Execute(s); err != nil {
+				t.Error(query.Error(err))
+			} else if !query.success() {
+				t.Error(query.failureMessage())
+			}
+		})
+	}
+}
+
+func TestServer_Query_Statistics(t *testing.T) {
+	t.Parallel()
+	s := OpenServer(NewConfig())
+	defer s.Close()
+
+	test := NewTest("db0", "rp0")
+	test.writes = Writes{
+		&Write{data: `cpu,host=a x=1,y=2i 0
+cpu,host=a x=2,y=4i 1000000000
+cpu,host=a x=3,y=6i 2000000000
+cpu,host=a x=1,y=1i 10000000000
+cpu,host=a x=2,y=3i 11000000000
+cpu,host=b x=1,y=3i 0
+cpu,host=b x=2,y=2i 1000000000
+cpu,host=b x=3,y=1i 2000000000
+`},
+	}
+
+	test.addQueries([]*Query{
+		&Query{
+			name:    "correlation and linear regression grouped by time and host",
+			command: `SELECT corr(x, y), covar(x, y), linreg_slope(y), linreg_intercept(y), linreg_r2(y) FROM db0.rp0.cpu WHERE time >= 0s AND time < 20s GROUP BY time(10s), host fill(none)`,
+			exp:     `{"results":[{"statement_id":0,"series":[{"name":"cpu","tags":{"host":"a"},"columns":["time","corr","covar","linreg_slope","linreg_intercept","linreg_r2"],"values":[["1970-01-01T00:00:00Z",1,2,2,2,1],["1970-01-01T00:00:10Z",1,1,2,1,1]]},{"name":"cpu","tags":{"host":"b"},"columns":["time","corr","covar","linreg_slope","linreg_intercept","linreg_r2"],"values":[["1970-01-01T00:00:00Z",-1,-1,-1,3,1]]}]}]}`,
+		},
+		&Query{
+			name:    "slope per minute",
+			command: `SELECT linreg_slope(y, 1m) FROM db0.rp0.cpu WHERE host = 'b'`,
+			exp:     `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","linreg_slope"],"values":[["1970-01-01T00:00:00Z",-60]]}]}]}`,
+		},
+		&Query{
+			name:    "intercept at the start of the time range",
+			command: `SELECT linreg_intercept(y) FROM db0.rp0.cpu WHERE host = 'b' AND time >= 1s`,
+			exp:     `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","linreg_intercept"],"values":[["1970-01-01T00:00:01Z",2]]}]}]}`,
+		},
+	}...)
+
+	for i, query := range test.queries {
+		t.Run(query.name, func(t *testing.T) {
+			if i == 0 {
+				if err := test.init(s); err != nil {
+					t.Fatalf("test init failed: %s", err)
+				}
+			}
+			if query.skip {
+				t.Skipf("SKIP:: %s", query.name)
+			}
+			if err := query.Execute(s); err != nil {
+				t.Error(query.Error(err))
+			} else if !query.success() {
+				t.Error(query.failureMessage())
+			}
+		})
+	}
+}
+
 func TestServer_Query_MathWithFill(t *testing.T) {
 	t.Parallel()
 	s := OpenServer(NewConfig())
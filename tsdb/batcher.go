@@ -16,10 +16,11 @@ type PointBatcher struct {
 	size     int
 	duration time.Duration
 
-	stop  chan struct{}
-	in    chan models.Point
-	out   chan []models.Point
-	flush chan struct{}
+	stop   chan struct{}
+	in     chan models.Point
+	out    chan []models.Point
+	flush  chan struct{}
+	limits chan batcherLimits
 
 	wg *sync.WaitGroup
 }
@@ -36,9 +37,16 @@ func NewPointBatcher(sz int, bp int, d time.Duration) *PointBatcher {
 		in:       make(chan models.Point, bp*sz),
 		out:      make(chan []models.Point),
 		flush:    make(chan struct{}),
+		limits:   make(chan batcherLimits),
 	}
 }
 
+// batcherLimits holds the batching size and timeout of a PointBatcher.
+type batcherLimits struct {
+	size     int
+	duration time.Duration
+}
+
 // PointBatcherStats are the statistics each batcher tracks.
 type PointBatcherStats struct {
 	BatchTotal   uint64 // Total count of batches transmitted.
@@ -109,6 +117,10 @@ func (b *PointBatcher) Start() {
 			case <-b.flush:
 				emit()
 
+			case l := <-b.limits:
+				emit()
+				b.size, b.duration = l.size, l.duration
+
 			case <-timer.C:
 				atomic.AddUint64(&b.stats.TimeoutTotal, 1)
 				emit()
@@ -129,6 +141,22 @@ func (b *PointBatcher) Stop() {
 	b.wg.Wait()
 }
 
+// SetLimits changes the batching size and timeout of the batcher. The batch
+// in progress is emitted with the previous limits. The maximum number of
+// pending batches cannot be changed.
+func (b *PointBatcher) SetLimits(sz int, d time.Duration) {
+	// If not running, there is no batch in progress.
+	if b.wg == nil {
+		b.size, b.duration = sz, d
+		return
+	}
+
+	select {
+	case b.limits <- batcherLimits{size: sz, duration: d}:
+	case <-b.stop:
+	}
+}
+
 // In returns the channel to which points should be written.
 func (b *PointBatcher) In() chan<- models.Point {
 	return b.in
@@ -77,6 +77,30 @@ func TestBatch_Timeout(t *testing.T) {
 	checkPointBatcherStats(t, batcher, -1, batchSize, 0, 1)
 }
 
+// TestBatch_SetLimits ensures that a batcher emits the batch in progress and
+// uses the new size after its limits are changed.
+func TestBatch_SetLimits(t *testing.T) {
+	batcher := tsdb.NewPointBatcher(5, 0, time.Hour)
+	batcher.Start()
+
+	var p models.Point
+	batcher.In() <- p
+	go batcher.SetLimits(2, time.Hour)
+	if batch := <-batcher.Out(); len(batch) != 1 {
+		t.Fatalf("received batch has incorrect length exp 1, got %d", len(batch))
+	}
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			batcher.In() <- p
+		}
+	}()
+	if batch := <-batcher.Out(); len(batch) != 2 {
+		t.Fatalf("received batch has incorrect length exp 2, got %d", len(batch))
+	}
+	batcher.Stop()
+}
+
 // TestBatch_Flush ensures that a batcher generates a batch when flushed
 func TestBatch_Flush(t *testing.T) {
 	batchSize := 2
@@ -29,6 +29,8 @@ var (
 	ErrShardNotFound = fmt.Errorf("shard not found")
 	// ErrStoreClosed is returned when trying to use a closed Store.
 	ErrStoreClosed = fmt.Errorf("store is closed")
+	// ErrShardMoving is returned when trying to write to a shard being moved.
+	ErrShardMoving = fmt.Errorf("shard is being moved")
 )
 
 // Statistics gathered by the store.
@@ -51,6 +53,10 @@ type Store struct {
 	// shards is a map of shard IDs to the associated Shard.
 	shards map[uint64]*Shard
 
+	// moving is the set of IDs of the shards being moved, which are not in
+	// shards while they are moved.
+	moving map[uint64]struct{}
+
 	EngineOptions EngineOptions
 
 	baseLogger zap.Logger
@@ -380,6 +386,8 @@ func (s *Store) CreateShard(database, retentionPolicy string, shardID uint64, en
 	// Shard already exists.
 	if _, ok := s.shards[shardID]; ok {
 		return nil
+	} else if _, ok := s.moving[shardID]; ok {
+		return ErrShardMoving
 	}
 
 	// Create the db and retention policy directories if they don't exist.
@@ -418,6 +426,118 @@ func (s *Store) CreateShard(database, retentionPolicy string, shardID uint64, en
 	return nil
 }
 
+// MoveShard moves a shard to another database and retention policy. The
+// shard is closed, its directories are renamed and it is opened again. It
+// does not return an error if the shard does not exist.
+//
+// The shard is removed from the store while it is moved, so it is not
+// queried, and writes to it return ErrShardMoving.
+func (s *Store) MoveShard(shardID uint64, database, retentionPolicy string) error {
+	path := filepath.Join(s.path, database, retentionPolicy, strconv.FormatUint(shardID, 10))
+	walPath := filepath.Join(s.EngineOptions.Config.WALDir, database, retentionPolicy, strconv.FormatUint(shardID, 10))
+
+	s.mu.Lock()
+	sh := s.shards[shardID]
+	if sh == nil || path == sh.path {
+		s.mu.Unlock()
+		return nil
+	}
+	delete(s.shards, shardID)
+	if s.moving == nil {
+		s.moving = make(map[uint64]struct{})
+	}
+	s.moving[shardID] = struct{}{}
+	s.mu.Unlock()
+
+	// Remove the shard from the index of its database before closing it.
+	sh.UnloadIndex()
+	err := sh.Close()
+	if err == nil {
+		if err = renameDir(sh.path, path); err == nil {
+			if err = renameDir(sh.walPath, walPath); err != nil {
+				renameDir(path, sh.path)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.moving, shardID)
+
+	// Reopen the shard where it was if it could not be moved.
+	if err != nil {
+		if err := s.openShard(sh.database, shardID, sh.path, sh.walPath); err != nil {
+			s.Logger.Info(fmt.Sprintf("failed to reopen shard %d: %s", shardID, err))
+		}
+		return err
+	}
+	return s.openShard(database, shardID, path, walPath)
+}
+
+// CopyShard copies a shard to a new shard of another database and retention
+// policy. The TSM files of the shard are hard linked into the new shard. It
+// does not return an error if the shard does not exist.
+func (s *Store) CopyShard(shardID uint64, database, retentionPolicy string, newShardID uint64) error {
+	sh := s.Shard(shardID)
+	if sh == nil {
+		return nil
+	} else if s.Shard(newShardID) != nil {
+		return fmt.Errorf("shard %d already exists", newShardID)
+	}
+
+	tmpPath, err := sh.CreateSnapshot()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.path, database, retentionPolicy, strconv.FormatUint(newShardID, 10))
+	walPath := filepath.Join(s.EngineOptions.Config.WALDir, database, retentionPolicy, strconv.FormatUint(newShardID, 10))
+	if err := renameDir(tmpPath, path); err != nil {
+		os.RemoveAll(tmpPath)
+		return err
+	} else if err := os.MkdirAll(walPath, 0700); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.openShard(database, newShardID, path, walPath)
+}
+
+// openShard opens the shard in path and adds it to the store. As when the
+// store is loaded, shards without an index directory use the inmem index.
+// This method assumes s's mutex is already locked.
+func (s *Store) openShard(database string, shardID uint64, path, walPath string) error {
+	idx, err := s.createIndexIfNotExists(database)
+	if err != nil {
+		return err
+	}
+
+	opt := s.EngineOptions
+	opt.InmemIndex = idx
+	if _, err := os.Stat(filepath.Join(path, "index")); os.IsNotExist(err) {
+		opt.IndexVersion = "inmem"
+	}
+
+	shard := NewShard(shardID, path, walPath, opt)
+	shard.WithLogger(s.baseLogger)
+	if err := shard.Open(); err != nil {
+		return err
+	}
+
+	s.shards[shardID] = shard
+	s.databases[database] = struct{}{}
+	return nil
+}
+
+// renameDir renames the directory src to dst, creating the parent of dst.
+func renameDir(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	return os.Rename(src, dst)
+}
+
 // CreateShardSnapShot will create a hard link to the underlying shard and return a path.
 // The caller is responsible for cleaning up (removing) the file path returned.
 func (s *Store) CreateShardSnapshot(id uint64) (string, error) {
@@ -714,6 +834,25 @@ func (s *Store) DiskSize() (int64, error) {
 	return size, nil
 }
 
+// DatabaseDiskSize returns the size on disk of all the shards of the
+// provided database in bytes, including the WAL.
+func (s *Store) DatabaseDiskSize(database string) (int64, error) {
+	var size int64
+
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	for _, sh := range shards {
+		sz, err := sh.DiskSize()
+		if err != nil {
+			return 0, err
+		}
+		size += sz
+	}
+	return size, nil
+}
+
 func (s *Store) estimateCardinality(dbName string, getSketches func(*Shard) (estimator.Sketch, estimator.Sketch, error)) (int64, error) {
 	var (
 		ss estimator.Sketch // Sketch estimating number of items.
@@ -934,7 +1073,11 @@ func (s *Store) WriteToShard(shardID uint64, points []models.Point) error {
 
 	sh := s.shards[shardID]
 	if sh == nil {
+		_, moving := s.moving[shardID]
 		s.mu.RUnlock()
+		if moving {
+			return ErrShardMoving
+		}
 		return ErrShardNotFound
 	}
 	s.mu.RUnlock()
@@ -167,6 +167,82 @@ func TestStore_DeleteShard(t *testing.T) {
 	}
 }
 
+// Ensure the store can move a shard to another database while it is written to.
+func TestStore_MoveShard(t *testing.T) {
+	t.Parallel()
+
+	test := func(index string) {
+		s := MustOpenStore(index)
+		defer s.Close()
+
+		s.MustCreateShardWithData("db0", "rp0", 1,
+			`cpu,host=serverA value=1 0`,
+			`cpu,host=serverB value=2 10`,
+		)
+
+		// Write to the shard while it is moved.
+		done := make(chan struct{})
+		errs := make(chan error, 1)
+		go func() {
+			defer close(errs)
+			for i := 0; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				pt := models.MustNewPoint("mem", models.NewTags(map[string]string{"host": "serverA"}), map[string]interface{}{"value": float64(i)}, time.Unix(int64(i%100), 0))
+				if err := s.WriteToShard(1, []models.Point{pt}); err != nil && err != tsdb.ErrShardMoving {
+					errs <- err
+					return
+				}
+			}
+		}()
+
+		err := s.MoveShard(1, "db1", "rp1")
+		close(done)
+		if werr := <-errs; werr != nil {
+			t.Fatalf("unexpected write error: %s", werr)
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		sh := s.Shard(1)
+		if sh == nil {
+			t.Fatal("expected shard")
+		} else if got, exp := sh.Database(), "db1"; got != exp {
+			t.Fatalf("unexpected database: got %s, exp %s", got, exp)
+		} else if got, exp := sh.Path(), filepath.Join(s.Path(), "db1", "rp1", "1"); got != exp {
+			t.Fatalf("unexpected path: got %s, exp %s", got, exp)
+		} else if _, err := os.Stat(filepath.Join(s.Path(), "db0", "rp0", "1")); !os.IsNotExist(err) {
+			t.Fatalf("expected old shard directory to be removed: %v", err)
+		}
+
+		// The shard accepts writes after the move.
+		s.MustWriteToShardString(1, `cpu,host=serverC value=3 20`)
+
+		// Reopen the store and verify the shard is still in its new place.
+		if err := s.Reopen(); err != nil {
+			t.Fatal(err)
+		} else if sh := s.Shard(1); sh == nil {
+			t.Fatal("expected shard after reopen")
+		} else if got, exp := sh.Database(), "db1"; got != exp {
+			t.Fatalf("unexpected database after reopen: got %s, exp %s", got, exp)
+		}
+
+		if names, err := s.MeasurementNames("db1", nil); err != nil {
+			t.Fatal(err)
+		} else if len(names) == 0 || string(names[0]) != "cpu" {
+			t.Fatalf("unexpected measurements: %s", names)
+		}
+	}
+
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) { test(index) })
+	}
+}
+
 // Ensure the store can create a snapshot to a shard.
 func TestStore_CreateShardSnapShot(t *testing.T) {
 	t.Parallel()